@@ -47,8 +47,14 @@ type SimulatedBackend struct {
 // NewSimulatedBackend creates a new binding backend using a simulated blockchain
 // for testing purposes.
 func NewSimulatedBackend(alloc core.GenesisAlloc) *SimulatedBackend {
+	return NewSimulatedBackendWithConfig(params.AllKonsensusProtocolChanges, alloc)
+}
+
+// NewSimulatedBackendWithConfig creates a new binding backend using a simulated
+// blockchain with the given chain configuration.
+func NewSimulatedBackendWithConfig(config *params.ChainConfig, alloc core.GenesisAlloc) *SimulatedBackend {
 	database := kcoindb.NewMemDatabase()
-	genesis := core.Genesis{Config: params.AllKonsensusProtocolChanges, Alloc: alloc}
+	genesis := core.Genesis{Config: config, Alloc: alloc}
 	genesis.MustCommit(database)
 	blockchain, _ := core.NewBlockChain(database, nil, genesis.Config, konsensus.NewFaker(), vm.Config{})
 
@@ -371,14 +377,15 @@ type callmsg struct {
 	kowala.CallMsg
 }
 
-func (m callmsg) From() common.Address { return m.CallMsg.From }
-func (m callmsg) Nonce() uint64        { return 0 }
-func (m callmsg) CheckNonce() bool     { return false }
-func (m callmsg) To() *common.Address  { return m.CallMsg.To }
-func (m callmsg) GasPrice() *big.Int   { return m.CallMsg.GasPrice }
-func (m callmsg) Gas() uint64          { return m.CallMsg.Gas }
-func (m callmsg) Value() *big.Int      { return m.CallMsg.Value }
-func (m callmsg) Data() []byte         { return m.CallMsg.Data }
+func (m callmsg) From() common.Address  { return m.CallMsg.From }
+func (m callmsg) Payer() common.Address { return m.CallMsg.From }
+func (m callmsg) Nonce() uint64         { return 0 }
+func (m callmsg) CheckNonce() bool      { return false }
+func (m callmsg) To() *common.Address   { return m.CallMsg.To }
+func (m callmsg) GasPrice() *big.Int    { return m.CallMsg.GasPrice }
+func (m callmsg) Gas() uint64           { return m.CallMsg.Gas }
+func (m callmsg) Value() *big.Int       { return m.CallMsg.Value }
+func (m callmsg) Data() []byte          { return m.CallMsg.Data }
 
 // filterBackend implements filters.Backend to support filtering for logs without
 // taking bloom-bits acceleration structures into account.
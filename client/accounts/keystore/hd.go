@@ -0,0 +1,323 @@
+package keystore
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kowala-tech/kcoin/client/accounts"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/math"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/pborman/uuid"
+	"github.com/tyler-smith/go-bip39"
+)
+
+var (
+	ErrNoHDWallet      = errors.New("no HD wallet for given address")
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+	errInvalidSeed  = errors.New("seed yields an invalid master key")
+	errInvalidChild = errors.New("derivation path yields an invalid key")
+)
+
+const (
+	// hdDirName is the subdirectory of the key directory holding the HD wallet
+	// seed files. The account cache skips directories, so it doesn't pick them
+	// up as key files.
+	hdDirName = "hd"
+
+	// mnemonicEntropyBits is the entropy of the generated mnemonics, encoded as
+	// 24 words.
+	mnemonicEntropyBits = 256
+)
+
+// masterKeySalt is the HMAC key deriving the master key of a BIP-32 tree.
+var masterKeySalt = []byte("Bitcoin seed")
+
+// hdWalletJSON is the on-disk format of an HD wallet. The mnemonic entropy is
+// encrypted like the private key of a key file, while the accounts derived from
+// it are listed in clear to find the wallet of an account without decrypting it.
+type hdWalletJSON struct {
+	Id       string          `json:"id"`
+	Crypto   cryptoJSON      `json:"crypto"`
+	Accounts []hdAccountJSON `json:"accounts"`
+	Version  int             `json:"version"`
+}
+
+// hdAccountJSON is an account derived from an HD wallet.
+type hdAccountJSON struct {
+	Address common.Address `json:"address"`
+	Path    string         `json:"path"`
+}
+
+// NewMnemonic generates a new random BIP-39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// DeriveKey derives the private key at the given BIP-32 path from the seed of a
+// BIP-39 mnemonic.
+func DeriveKey(mnemonic string, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+	return deriveKey(seed, path)
+}
+
+// deriveKey derives the private key at the given path of the BIP-32 tree
+// generated from a seed.
+func deriveKey(seed []byte, path accounts.DerivationPath) (*ecdsa.PrivateKey, error) {
+	curveN := crypto.S256().Params().N
+
+	mac := hmac.New(sha512.New, masterKeySalt)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	if key.Sign() == 0 || key.Cmp(curveN) >= 0 {
+		return nil, errInvalidSeed
+	}
+	for _, index := range path {
+		var data []byte
+		if index >= 0x80000000 {
+			data = append([]byte{0}, math.PaddedBigBytes(key, 32)...)
+		} else {
+			priv, err := crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&priv.PublicKey)
+		}
+		data = append(data, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(data[len(data)-4:], index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(curveN) >= 0 {
+			return nil, errInvalidChild
+		}
+		key = tweak.Add(tweak, key).Mod(tweak, curveN)
+		if key.Sign() == 0 {
+			return nil, errInvalidChild
+		}
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(math.PaddedBigBytes(key, 32))
+}
+
+// NewHDWallet generates a new BIP-39 mnemonic, stores it into the key directory
+// encrypted with the passphrase and derives its first account under the default
+// derivation path. The mnemonic is returned to be backed up by the user.
+func (ks *KeyStore) NewHDWallet(passphrase string) (string, accounts.Account, error) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		return "", accounts.Account{}, err
+	}
+	account, err := ks.ImportMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return "", accounts.Account{}, err
+	}
+	return mnemonic, account, nil
+}
+
+// ImportMnemonic stores the given BIP-39 mnemonic into the key directory,
+// encrypted with the passphrase, and derives its first account under the
+// default derivation path. Importing a mnemonic already in the keystore returns
+// its first account.
+func (ks *KeyStore) ImportMnemonic(mnemonic, passphrase string) (accounts.Account, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	entropy, err := bip39.EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return accounts.Account{}, ErrInvalidMnemonic
+	}
+	ks.hdLock.Lock()
+	defer ks.hdLock.Unlock()
+
+	path := accounts.DefaultBaseDerivationPath
+	priv, err := DeriveKey(mnemonic, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer zeroKey(priv)
+
+	file, wallet, err := ks.findHDWallet(crypto.PubkeyToAddress(priv.PublicKey))
+	switch err {
+	case ErrNoHDWallet:
+		N, P := ks.scryptParams()
+		cryptoStruct, err := EncryptDataV3(entropy, []byte(passphrase), N, P)
+		if err != nil {
+			return accounts.Account{}, err
+		}
+		id := uuid.NewRandom()
+		file = ks.storage.JoinPath(filepath.Join(hdDirName, id.String()+".json"))
+		wallet = &hdWalletJSON{Id: id.String(), Crypto: cryptoStruct, Version: version}
+	case nil:
+		if _, err := DecryptDataV3(wallet.Crypto, passphrase); err != nil {
+			return accounts.Account{}, err
+		}
+	default:
+		return accounts.Account{}, err
+	}
+	return ks.storeHDKey(file, wallet, priv, path, passphrase)
+}
+
+// DeriveHD derives a new account from the HD wallet of the given account and
+// stores its key into the key directory, encrypted with the passphrase of the
+// wallet. If path is nil, the account following the last one derived under the
+// default derivation path is.
+func (ks *KeyStore) DeriveHD(a accounts.Account, path accounts.DerivationPath, passphrase string) (accounts.Account, error) {
+	ks.hdLock.Lock()
+	defer ks.hdLock.Unlock()
+
+	file, wallet, err := ks.findHDWallet(a.Address)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	mnemonic, err := decryptMnemonic(wallet, passphrase)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	if path == nil {
+		path = wallet.nextPath()
+	}
+	priv, err := DeriveKey(mnemonic, path)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	defer zeroKey(priv)
+
+	return ks.storeHDKey(file, wallet, priv, path, passphrase)
+}
+
+// ExportMnemonic decrypts the mnemonic of the HD wallet of the given account.
+func (ks *KeyStore) ExportMnemonic(a accounts.Account, passphrase string) (string, error) {
+	_, wallet, err := ks.findHDWallet(a.Address)
+	if err != nil {
+		return "", err
+	}
+	return decryptMnemonic(wallet, passphrase)
+}
+
+// storeHDKey stores a key derived from an HD wallet into the key directory,
+// unless it is already there, and records it into the wallet.
+func (ks *KeyStore) storeHDKey(file string, wallet *hdWalletJSON, priv *ecdsa.PrivateKey, path accounts.DerivationPath, passphrase string) (accounts.Account, error) {
+	var (
+		address = crypto.PubkeyToAddress(priv.PublicKey)
+		account accounts.Account
+		err     error
+	)
+	if ks.cache.hasAddress(address) {
+		account, err = ks.Find(accounts.Account{Address: address})
+	} else {
+		account, err = ks.importKey(newKeyFromECDSA(priv), passphrase)
+	}
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	for _, derived := range wallet.Accounts {
+		if derived.Address == address {
+			return account, nil
+		}
+	}
+	wallet.Accounts = append(wallet.Accounts, hdAccountJSON{Address: address, Path: path.String()})
+
+	content, err := json.Marshal(wallet)
+	if err != nil {
+		return accounts.Account{}, err
+	}
+	if err := writeKeyFile(file, content); err != nil {
+		return accounts.Account{}, err
+	}
+	return account, nil
+}
+
+// findHDWallet loads the HD wallet an account was derived from.
+func (ks *KeyStore) findHDWallet(addr common.Address) (string, *hdWalletJSON, error) {
+	dir := ks.storage.JoinPath(hdDirName)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, ErrNoHDWallet
+		}
+		return "", nil, err
+	}
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasSuffix(fi.Name(), ".json") {
+			continue
+		}
+		file := filepath.Join(dir, fi.Name())
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", nil, err
+		}
+		wallet := new(hdWalletJSON)
+		if err := json.Unmarshal(content, wallet); err != nil {
+			return "", nil, err
+		}
+		for _, derived := range wallet.Accounts {
+			if derived.Address == addr {
+				return file, wallet, nil
+			}
+		}
+	}
+	return "", nil, ErrNoHDWallet
+}
+
+// decryptMnemonic decrypts the mnemonic of an HD wallet.
+func decryptMnemonic(wallet *hdWalletJSON, passphrase string) (string, error) {
+	entropy, err := DecryptDataV3(wallet.Crypto, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// nextPath returns the path following the last account derived under the
+// default derivation path.
+func (wallet *hdWalletJSON) nextPath() accounts.DerivationPath {
+	base := accounts.DefaultBaseDerivationPath
+	next := uint32(0)
+	for _, derived := range wallet.Accounts {
+		path, err := accounts.ParseDerivationPath(derived.Path)
+		if err != nil || len(path) != len(base) {
+			continue
+		}
+		if hasPrefix(path, base[:len(base)-1]) && path[len(base)-1] >= next {
+			next = path[len(base)-1] + 1
+		}
+	}
+	path := make(accounts.DerivationPath, len(base))
+	copy(path, base)
+	path[len(path)-1] = next
+	return path
+}
+
+// hasPrefix reports whether a derivation path starts with the given prefix.
+func hasPrefix(path, prefix accounts.DerivationPath) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
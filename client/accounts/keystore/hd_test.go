@@ -0,0 +1,121 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+)
+
+// Tests the BIP-32 derivation against the first test vector of the spec.
+func TestDeriveKeyBIP32(t *testing.T) {
+	seed := common.FromHex("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for i, tt := range tests {
+		var path accounts.DerivationPath
+		if tt.path != "m" {
+			var err error
+			if path, err = accounts.ParseDerivationPath(tt.path); err != nil {
+				t.Fatalf("test %d: failed to parse path: %v", i, err)
+			}
+		}
+		key, err := deriveKey(seed, path)
+		if err != nil {
+			t.Fatalf("test %d: failed to derive key: %v", i, err)
+		}
+		if have := hex.EncodeToString(crypto.FromECDSA(key)); have != tt.key {
+			t.Errorf("test %d: key mismatch: have %s, want %s", i, have, tt.key)
+		}
+	}
+}
+
+// Tests the derivation from a mnemonic against a well known account.
+func TestDeriveKeyMnemonic(t *testing.T) {
+	mnemonic := strings.Repeat("abandon ", 11) + "about"
+	path, _ := accounts.ParseDerivationPath("m/44'/60'/0'/0/0")
+
+	key, err := DeriveKey(mnemonic, path)
+	if err != nil {
+		t.Fatalf("failed to derive key: %v", err)
+	}
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if have := crypto.PubkeyToAddress(key.PublicKey); have != want {
+		t.Errorf("address mismatch: have %x, want %x", have, want)
+	}
+	if _, err := DeriveKey(strings.Repeat("abandon ", 12), path); err != ErrInvalidMnemonic {
+		t.Errorf("invalid mnemonic: error mismatch: have %v, want %v", err, ErrInvalidMnemonic)
+	}
+}
+
+func TestHDWallet(t *testing.T) {
+	dir, ks := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir)
+
+	mnemonic, first, err := ks.NewHDWallet("foo")
+	if err != nil {
+		t.Fatalf("failed to create HD wallet: %v", err)
+	}
+	if words := len(strings.Fields(mnemonic)); words != 24 {
+		t.Errorf("mnemonic length mismatch: have %d words, want 24", words)
+	}
+	if !ks.HasAddress(first.Address) {
+		t.Errorf("first account %x not in keystore", first.Address)
+	}
+	// Derive the next account and make sure it's usable like any other one
+	second, err := ks.DeriveHD(first, nil, "foo")
+	if err != nil {
+		t.Fatalf("failed to derive account: %v", err)
+	}
+	path := append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...)
+	path[len(path)-1] = 1
+
+	key, _ := DeriveKey(mnemonic, path)
+	if want := crypto.PubkeyToAddress(key.PublicKey); second.Address != want {
+		t.Errorf("derived address mismatch: have %x, want %x", second.Address, want)
+	}
+	if err := ks.Unlock(second, "foo"); err != nil {
+		t.Errorf("failed to unlock derived account: %v", err)
+	}
+	if _, err := ks.DeriveHD(second, nil, "bar"); err != ErrDecrypt {
+		t.Errorf("wrong passphrase: error mismatch: have %v, want %v", err, ErrDecrypt)
+	}
+	if _, err := ks.DeriveHD(accounts.Account{Address: common.HexToAddress("0x01")}, nil, "foo"); err != ErrNoHDWallet {
+		t.Errorf("unknown wallet: error mismatch: have %v, want %v", err, ErrNoHDWallet)
+	}
+	// Export the mnemonic and recover the wallet in another keystore
+	exported, err := ks.ExportMnemonic(second, "foo")
+	if err != nil {
+		t.Fatalf("failed to export mnemonic: %v", err)
+	}
+	if exported != mnemonic {
+		t.Errorf("exported mnemonic mismatch: have %q, want %q", exported, mnemonic)
+	}
+	dir2, ks2 := tmpKeyStore(t, true)
+	defer os.RemoveAll(dir2)
+
+	recovered, err := ks2.ImportMnemonic(mnemonic, "baz")
+	if err != nil {
+		t.Fatalf("failed to import mnemonic: %v", err)
+	}
+	if recovered.Address != first.Address {
+		t.Errorf("recovered address mismatch: have %x, want %x", recovered.Address, first.Address)
+	}
+	if again, err := ks2.ImportMnemonic(mnemonic, "baz"); err != nil || again != recovered {
+		t.Errorf("reimported account mismatch: have %v (%v), want %v", again, err, recovered)
+	}
+	if next, err := ks2.DeriveHD(recovered, nil, "baz"); err != nil || next.Address != second.Address {
+		t.Errorf("recovered derived account mismatch: have %x (%v), want %x", next.Address, err, second.Address)
+	}
+}
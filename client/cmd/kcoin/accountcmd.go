@@ -32,6 +32,20 @@ import (
 )
 
 var (
+	accountMnemonicFlag = cli.BoolFlag{
+		Name:  "mnemonic",
+		Usage: "Create an HD wallet backed up by a BIP-39 mnemonic",
+	}
+	accountPathFlag = cli.StringFlag{
+		Name:  "path",
+		Usage: "Derivation path of the account (default: next account under the default path)",
+	}
+	accountCountFlag = cli.IntFlag{
+		Name:  "count",
+		Value: 1,
+		Usage: "Number of accounts to recover under the default path",
+	}
+
 	walletCommand = cli.Command{
 		Name:      "wallet",
 		Usage:     "Manage Ethereum presale wallets",
@@ -112,12 +126,18 @@ Print a short summary of all accounts`,
 					utils.KeyStoreDirFlag,
 					utils.PasswordFileFlag,
 					utils.LightKDFFlag,
+					accountMnemonicFlag,
 				},
 				Description: `
     kcoin account new
 
 Creates a new account and prints the address.
 
+With the --mnemonic flag, the account is the first one of a new HD wallet whose
+BIP-39 mnemonic is printed along with it. Write the mnemonic down and keep it
+safe: it recovers all the accounts of the wallet. Further accounts are derived
+from the wallet with the derive command.
+
 The account is saved in encrypted format, you are prompted for a passphrase.
 
 You must remember this passphrase to unlock your account in the future.
@@ -188,6 +208,52 @@ Note:
 As you can directly copy your encrypted accounts to another ethereum instance,
 this import mechanism is not needed when you transfer an account between
 nodes.
+`,
+			},
+			{
+				Name:      "derive",
+				Usage:     "Derive a new account from an HD wallet",
+				Action:    utils.MigrateFlags(accountDerive),
+				ArgsUsage: "<address>",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+					utils.LightKDFFlag,
+					accountPathFlag,
+				},
+				Description: `
+    kcoin account derive [options] <address>
+
+Derives a new account from the HD wallet of an existing account and prints its
+address.
+
+Unless a path is given with the --path flag, the account following the last one
+derived under the default Kowala path (m/44'/91927009'/0'/0/n) is.
+
+The account is saved in encrypted format with the passphrase of the wallet.
+`,
+			},
+			{
+				Name:   "recover",
+				Usage:  "Recover the accounts of an HD wallet from its mnemonic",
+				Action: utils.MigrateFlags(accountRecover),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+					utils.LightKDFFlag,
+					accountCountFlag,
+				},
+				Description: `
+    kcoin account recover [options]
+
+Prompts for the BIP-39 mnemonic of an HD wallet and recovers its first accounts
+under the default Kowala path. The number of accounts is given with the --count
+flag.
+
+The wallet and its accounts are saved in encrypted format, you are prompted for
+a passphrase.
 `,
 			},
 		},
@@ -312,6 +378,16 @@ func accountCreate(ctx *cli.Context) error {
 
 	password := getPassPhrase("Your new account is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
 
+	if ctx.Bool(accountMnemonicFlag.Name) {
+		mnemonic, account, err := keystore.NewKeyStore(keydir, scryptN, scryptP).NewHDWallet(password)
+		if err != nil {
+			utils.Fatalf("Failed to create HD wallet: %v", err)
+		}
+		fmt.Printf("Address: {%x}\n", account.Address)
+		fmt.Printf("Mnemonic: %s\n", mnemonic)
+		fmt.Println("Write down the mnemonic and keep it safe, it is the only backup of your wallet.")
+		return nil
+	}
 	address, err := keystore.StoreKey(keydir, password, scryptN, scryptP)
 
 	if err != nil {
@@ -382,3 +458,61 @@ func accountImport(ctx *cli.Context) error {
 	fmt.Printf("Address: {%x}\n", acct.Address)
 	return nil
 }
+
+// accountDerive derives a new account from the HD wallet of an existing one.
+func accountDerive(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		utils.Fatalf("No account specified to derive from")
+	}
+	var path accounts.DerivationPath
+	if ctx.IsSet(accountPathFlag.Name) {
+		var err error
+		if path, err = accounts.ParseDerivationPath(ctx.String(accountPathFlag.Name)); err != nil {
+			utils.Fatalf("Invalid derivation path: %v", err)
+		}
+	}
+	stack, _ := makeConfigNode(ctx)
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+
+	account, err := utils.MakeAddress(ks, ctx.Args().First())
+	if err != nil {
+		utils.Fatalf("Could not list accounts: %v", err)
+	}
+	passphrase := getPassPhrase("Please give the password of the wallet.", false, 0, utils.MakePasswordList(ctx))
+
+	derived, err := ks.DeriveHD(account, path, passphrase)
+	if err != nil {
+		utils.Fatalf("Could not derive the account: %v", err)
+	}
+	fmt.Printf("Address: {%x}\n", derived.Address)
+	return nil
+}
+
+// accountRecover recovers the accounts of an HD wallet from its mnemonic.
+func accountRecover(ctx *cli.Context) error {
+	mnemonic, err := console.Stdin.PromptPassword("Mnemonic: ")
+	if err != nil {
+		utils.Fatalf("Failed to read mnemonic: %v", err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	passphrase := getPassPhrase("Your recovered accounts are locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
+
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	account, err := ks.ImportMnemonic(mnemonic, passphrase)
+	if err != nil {
+		utils.Fatalf("Could not recover the wallet: %v", err)
+	}
+	fmt.Printf("Address: {%x}\n", account.Address)
+
+	for i := 1; i < ctx.Int(accountCountFlag.Name); i++ {
+		path := append(accounts.DerivationPath{}, accounts.DefaultBaseDerivationPath...)
+		path[len(path)-1] = uint32(i)
+
+		derived, err := ks.DeriveHD(account, path, passphrase)
+		if err != nil {
+			utils.Fatalf("Could not recover account %s: %v", path, err)
+		}
+		fmt.Printf("Address: {%x}\n", derived.Address)
+	}
+	return nil
+}
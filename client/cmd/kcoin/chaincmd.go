@@ -2,7 +2,9 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"sync/atomic"
@@ -12,13 +14,14 @@ import (
 	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/console"
 	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/rawdb"
 	"github.com/kowala-tech/kcoin/client/core/state"
 	"github.com/kowala-tech/kcoin/client/core/types"
 	"github.com/kowala-tech/kcoin/client/event"
-	"github.com/kowala-tech/kcoin/client/kcoindb"
 	"github.com/kowala-tech/kcoin/client/knode/downloader"
 	genesisgen "github.com/kowala-tech/kcoin/client/knode/genesis"
 	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/params"
 	"github.com/kowala-tech/kcoin/client/trie"
 	"github.com/syndtr/goleveldb/leveldb/util"
 	"gopkg.in/urfave/cli.v1"
@@ -32,6 +35,7 @@ var (
 		ArgsUsage: "<genesisPath>",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.LightModeFlag,
 			utils.TestnetFlag,
 			utils.DevModeFlag,
@@ -52,6 +56,7 @@ It expects the genesis file as argument.`,
 		ArgsUsage: "<filename> (<filename 2> ... <filename N>) ",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 			utils.GCModeFlag,
@@ -73,6 +78,7 @@ processing will proceed even if an individual RLP-file import failure occurs.`,
 		ArgsUsage: "<filename> [<blockNumFirst> <blockNumLast>]",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 		},
@@ -115,16 +121,19 @@ The export-preimages command export hash preimages to an RLP encoded stream`,
 		Action:    utils.MigrateFlags(copyDb),
 		Name:      "copydb",
 		Usage:     "Create a local chain from a target chaindata folder",
-		ArgsUsage: "<sourceChaindataDir>",
+		ArgsUsage: "<sourceChaindataDir> [<sourceAncientDir>]",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.SyncModeFlag,
 			utils.TestnetFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
-The first argument must be the directory containing the blockchain to download from`,
+The first argument must be the directory containing the blockchain to download from.
+The optional second argument is the directory of its ancient chain segments, which
+defaults to the ancient folder within the chaindata directory.`,
 	}
 	removedbCommand = cli.Command{
 		Action:    utils.MigrateFlags(removeDB),
@@ -133,6 +142,7 @@ The first argument must be the directory containing the blockchain to download f
 		ArgsUsage: " ",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.LightModeFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
@@ -146,6 +156,7 @@ Remove blockchain and state databases`,
 		ArgsUsage: "[<blockHash> | <blockNum>]...",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.CacheFlag,
 			utils.LightModeFlag,
 		},
@@ -154,6 +165,77 @@ Remove blockchain and state databases`,
 The arguments are interpreted as block numbers or hashes.
 Use "ethereum dump 0" to dump the genesis block.`,
 	}
+	pruneStateCommand = cli.Command{
+		Action:    utils.MigrateFlags(pruneState),
+		Name:      "prune-state",
+		Usage:     "Delete the historical state outside of the retention window",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.StateRetainFlag,
+			utils.BloomFilterSizeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The prune-state command deletes the state of all the blocks except the genesis
+block and the most recent ones set by --state.retain (at least 128). The state
+entries that are still reachable are marked in a bloom filter, whose size can
+be raised with --bloomfilter.size to keep more memory for fewer leftovers.
+
+The node must not be running while the state is pruned.`,
+	}
+	exportGenesisCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportGenesis),
+		Name:      "export-genesis",
+		Usage:     "Export the state at a block into a new genesis",
+		ArgsUsage: "[<filename>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.ExportBlockFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-genesis command writes the state at the block set by --block (the
+current head by default) as a genesis file, to relaunch the network from it
+with "kcoin init". The accounts keep their balances, nonces, code and storage,
+and the validators at the block become the genesis validators.
+
+The genesis is written to the given file, or to the standard output.`,
+	}
+	rollbackCommand = cli.Command{
+		Action:    utils.MigrateFlags(rollback),
+		Name:      "rollback",
+		Usage:     "Rewind the chain and state to a past block",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.AncientFlag,
+			utils.CacheFlag,
+			utils.CheckpointFlag,
+			utils.RollbackTargetFlag,
+			utils.RollbackForceFlag,
+			utils.RollbackYesFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The rollback command rewinds the chain to the block set by --to, to recover from
+a halted network or a bad block. The blocks above it are discarded along with
+their state, and so is the journal of local transactions of the transaction
+pool, as they may depend on the discarded blocks. The validator keeps its
+consensus state (locked block and round) in memory only, so it starts over from
+the new head when the node is restarted.
+
+The command refuses to roll back below the trusted checkpoint of the network,
+set by --checkpoint or built in, unless --force is given. It can't roll back
+to a block whose state was pruned. The summary of the discarded data is
+confirmed interactively, unless --yes is given.
+
+The node must not be running while the chain is rolled back.`,
+	}
 )
 
 // initGenesis will initialise the given JSON format genesis file and writes it as
@@ -174,7 +256,7 @@ func initGenesis(ctx *cli.Context) error {
 
 	// light chain data is used by the light client(not available)
 	for _, name := range []string{"chaindata" /*"lightchaindata"*/} {
-		chaindb, err := stack.OpenDatabase(name, 0, 0)
+		chaindb, err := stack.OpenDatabaseWithFreezer(name, 0, 0, ctx.GlobalString(utils.AncientFlag.Name), "")
 		if err != nil {
 			utils.Fatalf("Failed to open database: %v", err)
 		}
@@ -182,6 +264,7 @@ func initGenesis(ctx *cli.Context) error {
 		if err != nil {
 			utils.Fatalf("Failed to write genesis block: %v", err)
 		}
+		chaindb.Close()
 		log.Info("Successfully wrote genesis state", "database", name, "hash", hash)
 	}
 
@@ -246,7 +329,7 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*kcoindb.LDBDatabase)
+	db := rawdb.LevelDB(chainDb)
 
 	stats, err := db.LDB().GetProperty("leveldb.stats")
 	if err != nil {
@@ -337,7 +420,7 @@ func importPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*kcoindb.LDBDatabase)
+	diskdb := rawdb.LevelDB(utils.MakeChainDatabase(ctx, stack))
 
 	start := time.Now()
 	if err := utils.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -353,7 +436,7 @@ func exportPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*kcoindb.LDBDatabase)
+	diskdb := rawdb.LevelDB(utils.MakeChainDatabase(ctx, stack))
 
 	start := time.Now()
 	if err := utils.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -365,18 +448,22 @@ func exportPreimages(ctx *cli.Context) error {
 
 func copyDb(ctx *cli.Context) error {
 	// Ensure we have a source chain directory to copy
-	if len(ctx.Args()) != 1 {
+	if len(ctx.Args()) < 1 || len(ctx.Args()) > 2 {
 		utils.Fatalf("Source chaindata directory path argument missing")
 	}
+	ancient := ctx.Args().Get(1)
+	if ancient == "" {
+		ancient = filepath.Join(ctx.Args().First(), "ancient")
+	}
 	// Initialize a new chain for the running node to sync into
 	stack := makeFullNode(ctx)
 	chain, chainDb := utils.MakeChain(ctx, stack)
 
 	syncmode := *utils.GlobalTextMarshaler(ctx, utils.SyncModeFlag.Name).(*downloader.SyncMode)
-	dl := downloader.New(syncmode, chainDb, new(event.TypeMux), chain, nil, nil)
+	dl := downloader.New(syncmode, nil, chainDb, new(event.TypeMux), chain, nil, nil)
 
 	// Create a source peer to satisfy downloader requests from
-	db, err := kcoindb.NewLDBDatabase(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
+	db, err := rawdb.NewLevelDBDatabaseWithFreezer(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256, ancient, "")
 	if err != nil {
 		return err
 	}
@@ -403,7 +490,7 @@ func copyDb(ctx *cli.Context) error {
 	// Compact the entire database to remove any sync overhead
 	start = time.Now()
 	fmt.Println("Compacting entire database...")
-	if err = chainDb.(*kcoindb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
+	if err = rawdb.LevelDB(chainDb).LDB().CompactRange(util.Range{}); err != nil {
 		utils.Fatalf("Compaction failed: %v", err)
 	}
 	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
@@ -412,13 +499,25 @@ func copyDb(ctx *cli.Context) error {
 }
 
 func removeDB(ctx *cli.Context) error {
-	stack, _ := makeConfigNode(ctx)
+	stack, config := makeConfigNode(ctx)
 
-	for _, name := range []string{"chaindata", "lightchaindata"} {
+	// The ancient chain segments are removed along with the chain database,
+	// unless they are stored in a directory of their own.
+	dbdirs := map[string]string{
+		"chaindata":      stack.ResolvePath("chaindata"),
+		"lightchaindata": stack.ResolvePath("lightchaindata"),
+	}
+	if ancient := config.Kowala.DatabaseFreezer; ancient != "" {
+		dbdirs["ancient"] = stack.ResolvePath(ancient)
+	}
+	for _, name := range []string{"chaindata", "lightchaindata", "ancient"} {
+		dbdir, ok := dbdirs[name]
+		if !ok {
+			continue
+		}
 		// Ensure the database exists in the first place
 		logger := log.New("database", name)
 
-		dbdir := stack.ResolvePath(name)
 		if !common.FileExist(dbdir) {
 			logger.Info("Database doesn't exist, skipping", "path", dbdir)
 			continue
@@ -466,6 +565,184 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+// exportGenesis writes the state at a block as a genesis file.
+func exportGenesis(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most one argument.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+
+	block := chain.CurrentBlock()
+	if number := ctx.Int64(utils.ExportBlockFlag.Name); number >= 0 {
+		if block = chain.GetBlockByNumber(uint64(number)); block == nil {
+			utils.Fatalf("Block %d not found", number)
+		}
+	}
+	statedb, err := chain.StateAt(block.Root())
+	if err != nil {
+		utils.Fatalf("State of block %d not available: %v", block.NumberU64(), err)
+	}
+	out := os.Stdout
+	if ctx.Args().Present() {
+		fh, err := os.OpenFile(ctx.Args().First(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+		if err != nil {
+			utils.Fatalf("Failed to create genesis file: %v", err)
+		}
+		defer fh.Close()
+		out = fh
+	}
+	start := time.Now()
+	validators, err := genesisgen.Export(out, chain.Config(), block.Header(), statedb)
+	if err != nil {
+		utils.Fatalf("Genesis export failed: %v", err)
+	}
+	log.Info("Exported genesis", "number", block.NumberU64(), "hash", block.Hash(), "validators", len(validators), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// rollback rewinds the chain and state to a past block, after confirming the
+// blocks to discard.
+func rollback(ctx *cli.Context) error {
+	if !ctx.IsSet(utils.RollbackTargetFlag.Name) {
+		utils.Fatalf("The block to roll back to must be set with --%s", utils.RollbackTargetFlag.Name)
+	}
+	stack, cfg := makeConfigNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+	defer chain.Stop()
+
+	checkpoint := cfg.Kowala.Checkpoint
+	if checkpoint == nil {
+		checkpoint = params.TrustedCheckpoints[chain.Genesis().Hash()]
+	}
+	journal := ""
+	if cfg.Kowala.TxPool.Journal != "" {
+		journal = stack.ResolvePath(cfg.Kowala.TxPool.Journal)
+	}
+	plan, err := newRollbackPlan(chain, ctx.Uint64(utils.RollbackTargetFlag.Name), checkpoint, journal, ctx.Bool(utils.RollbackForceFlag.Name))
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	plan.print(os.Stdout)
+
+	if !ctx.Bool(utils.RollbackYesFlag.Name) {
+		confirm, err := console.Stdin.PromptConfirm("Roll back the chain?")
+		switch {
+		case err != nil:
+			utils.Fatalf("%v", err)
+		case !confirm:
+			log.Warn("Rollback aborted")
+			return nil
+		}
+	}
+	start := time.Now()
+	if err := plan.apply(chain); err != nil {
+		utils.Fatalf("Rollback failed: %v", err)
+	}
+	log.Info("Rolled back chain", "number", plan.target.NumberU64(), "hash", plan.target.Hash(), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// rollbackPlan is a checked rewind of the chain to a past block, along with
+// what it discards.
+type rollbackPlan struct {
+	head       *types.Block              // Head block before the rollback
+	target     *types.Block              // Block to roll the chain back to
+	checkpoint *params.TrustedCheckpoint // Trusted checkpoint of the network, if any
+	journal    string                    // Transaction journal to remove, if any
+	txs        int                       // Number of transactions discarded
+}
+
+// newRollbackPlan checks that the chain can be rolled back to the given block.
+// The block must be below the head, have its state available and not be below
+// the trusted checkpoint, unless forced. The journal is only removed if it
+// exists.
+func newRollbackPlan(chain *core.BlockChain, number uint64, checkpoint *params.TrustedCheckpoint, journal string, force bool) (*rollbackPlan, error) {
+	head := chain.CurrentBlock()
+	if number >= head.NumberU64() {
+		return nil, fmt.Errorf("block %d is not below the head block %d", number, head.NumberU64())
+	}
+	target := chain.GetBlockByNumber(number)
+	if target == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	if checkpoint != nil && number < checkpoint.Number && !force {
+		return nil, fmt.Errorf("block %d is below the trusted checkpoint %d, use --%s to roll back anyway", number, checkpoint.Number, utils.RollbackForceFlag.Name)
+	}
+	if !chain.HasState(target.Root()) {
+		return nil, fmt.Errorf("state of block %d not available, it was pruned or never synced", number)
+	}
+	if journal != "" {
+		if _, err := os.Stat(journal); err != nil {
+			journal = ""
+		}
+	}
+	plan := &rollbackPlan{
+		head:       head,
+		target:     target,
+		checkpoint: checkpoint,
+		journal:    journal,
+	}
+	for n := number + 1; n <= head.NumberU64(); n++ {
+		if block := chain.GetBlockByNumber(n); block != nil {
+			plan.txs += len(block.Transactions())
+		}
+	}
+	return plan, nil
+}
+
+// print writes the summary of what the rollback discards.
+func (p *rollbackPlan) print(w io.Writer) {
+	fmt.Fprintf(w, "Current head:      #%d [%x…]\n", p.head.NumberU64(), p.head.Hash().Bytes()[:4])
+	fmt.Fprintf(w, "Roll back to:      #%d [%x…]\n", p.target.NumberU64(), p.target.Hash().Bytes()[:4])
+	fmt.Fprintf(w, "Discarded blocks:  %d\n", p.head.NumberU64()-p.target.NumberU64())
+	fmt.Fprintf(w, "Discarded txs:     %d\n", p.txs)
+	if p.journal != "" {
+		fmt.Fprintf(w, "Discarded journal: %s\n", p.journal)
+	}
+	if p.checkpoint != nil && p.target.NumberU64() < p.checkpoint.Number {
+		fmt.Fprintf(w, "WARNING: rolling back below the trusted checkpoint %d\n", p.checkpoint.Number)
+	}
+}
+
+// apply rewinds the chain and state to the target block and removes the
+// transaction journal.
+func (p *rollbackPlan) apply(chain *core.BlockChain) error {
+	if err := chain.SetHead(p.target.NumberU64()); err != nil {
+		return err
+	}
+	if head := chain.CurrentBlock(); head.Hash() != p.target.Hash() {
+		return fmt.Errorf("rollback ended at block %d [%x…] instead of %d", head.NumberU64(), head.Hash().Bytes()[:4], p.target.NumberU64())
+	}
+	if p.journal != "" {
+		if err := os.Remove(p.journal); err != nil {
+			return fmt.Errorf("failed to remove the transaction journal: %v", err)
+		}
+	}
+	return nil
+}
+
+// pruneState deletes the historical state outside of the retention window.
+func pruneState(ctx *cli.Context) error {
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+	defer chain.Stop()
+
+	retain := ctx.GlobalUint64(utils.StateRetainFlag.Name)
+	if retain < core.MinStateRetention {
+		retain = core.MinStateRetention
+	}
+	start := time.Now()
+	if err := chain.PruneState(retain, ctx.GlobalUint64(utils.BloomFilterSizeFlag.Name)); err != nil {
+		utils.Fatalf("State pruning failed: %v", err)
+	}
+	fmt.Printf("State pruning done in %v, state retained from block %d\n", time.Since(start), chain.StateTail())
+	return nil
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// newRollbackChain creates a chain of the given length, with a transfer in
+// every block, along with a transaction journal.
+func newRollbackChain(t *testing.T, length int) (*core.BlockChain, []*types.Block, string) {
+	var (
+		db      = kcoindb.NewMemDatabase()
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.MakeSigner(params.TestChainConfig, nil)
+		genesis = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{sender: {Balance: big.NewInt(1000000000000)}},
+		}
+	)
+	parent := genesis.MustCommit(db)
+
+	blocks, _ := core.GenerateChain(params.TestChainConfig, parent, konsensus.NewFaker(), db, length, func(i int, b *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(b.TxNonce(sender), common.Address{1}, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign the transaction: %v", err)
+		}
+		b.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, &core.CacheConfig{Disabled: true}, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create the chain: %v", err)
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+
+	dir, err := ioutil.TempDir("", "kcoin-rollback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal := filepath.Join(dir, "transactions.rlp")
+	if err := ioutil.WriteFile(journal, []byte{0xc0}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return chain, blocks, journal
+}
+
+func TestRollbackBelowCheckpoint(t *testing.T) {
+	chain, blocks, journal := newRollbackChain(t, 8)
+	defer chain.Stop()
+	defer os.RemoveAll(filepath.Dir(journal))
+
+	checkpoint := &params.TrustedCheckpoint{Number: 5, Hash: blocks[4].Hash()}
+	if _, err := newRollbackPlan(chain, 3, checkpoint, journal, false); err == nil || !strings.Contains(err.Error(), "below the trusted checkpoint") {
+		t.Fatalf("error mismatch: have %v, want the checkpoint refusal", err)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[7].Hash() {
+		t.Errorf("head mismatch: have %d, want %d", head.NumberU64(), blocks[7].NumberU64())
+	}
+	if _, err := os.Stat(journal); err != nil {
+		t.Errorf("journal removed: %v", err)
+	}
+
+	// The rollback goes through when forced, warning about the checkpoint
+	plan, err := newRollbackPlan(chain, 3, checkpoint, journal, true)
+	if err != nil {
+		t.Fatalf("failed to plan the forced rollback: %v", err)
+	}
+	var summary bytes.Buffer
+	plan.print(&summary)
+	if !strings.Contains(summary.String(), "WARNING: rolling back below the trusted checkpoint 5") {
+		t.Errorf("missing checkpoint warning in summary:\n%s", summary.String())
+	}
+	if err := plan.apply(chain); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[2].Hash() {
+		t.Errorf("head mismatch: have %d, want 3", head.NumberU64())
+	}
+}
+
+func TestRollback(t *testing.T) {
+	chain, blocks, journal := newRollbackChain(t, 8)
+	defer chain.Stop()
+	defer os.RemoveAll(filepath.Dir(journal))
+
+	checkpoint := &params.TrustedCheckpoint{Number: 5, Hash: blocks[4].Hash()}
+	for _, number := range []uint64{8, 9} {
+		if _, err := newRollbackPlan(chain, number, checkpoint, journal, false); err == nil {
+			t.Errorf("block %d: rollback above the head accepted", number)
+		}
+	}
+	plan, err := newRollbackPlan(chain, 6, checkpoint, journal, false)
+	if err != nil {
+		t.Fatalf("failed to plan the rollback: %v", err)
+	}
+	if plan.txs != 2 {
+		t.Errorf("discarded txs mismatch: have %d, want 2", plan.txs)
+	}
+	var summary bytes.Buffer
+	plan.print(&summary)
+	for _, line := range []string{"Discarded blocks:  2", "Discarded txs:     2", "Discarded journal: " + journal} {
+		if !strings.Contains(summary.String(), line) {
+			t.Errorf("missing %q in summary:\n%s", line, summary.String())
+		}
+	}
+	if strings.Contains(summary.String(), "WARNING") {
+		t.Errorf("unexpected warning in summary:\n%s", summary.String())
+	}
+
+	if err := plan.apply(chain); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+	head := chain.CurrentBlock()
+	if head.Hash() != blocks[5].Hash() {
+		t.Fatalf("head mismatch: have %d, want 6", head.NumberU64())
+	}
+	if _, err := chain.StateAt(head.Root()); err != nil {
+		t.Errorf("state of the new head not available: %v", err)
+	}
+	if block := chain.GetBlockByNumber(7); block != nil {
+		t.Errorf("discarded block 7 still canonical")
+	}
+	if _, err := os.Stat(journal); !os.IsNotExist(err) {
+		t.Errorf("journal not removed: %v", err)
+	}
+
+	// The discarded blocks can be imported again
+	if n, err := chain.InsertChain(blocks[6:]); err != nil {
+		t.Fatalf("failed to reimport block %d: %v", n, err)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != blocks[7].Hash() {
+		t.Errorf("head mismatch after reimport: have %d, want 8", head.NumberU64())
+	}
+}
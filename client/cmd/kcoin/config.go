@@ -123,6 +123,11 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 		utils.RegisterKowalaStatsService(stack, statsURL)
 	}
 
+	// Add the GraphQL endpoint if requested.
+	if endpoint := cfg.Node.GraphQLEndpoint(); endpoint != "" {
+		utils.RegisterGraphQLService(stack, endpoint, cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts)
+	}
+
 	return stack
 }
 
@@ -44,6 +44,7 @@ var (
 		utils.BootnodesV4Flag,
 		utils.BootnodesV5Flag,
 		utils.DataDirFlag,
+		utils.AncientFlag,
 		utils.KeyStoreDirFlag,
 		utils.NoUSBFlag,
 		utils.TxPoolNoLocalsFlag,
@@ -59,7 +60,9 @@ var (
 		utils.FastSyncFlag,
 		utils.LightModeFlag,
 		utils.SyncModeFlag,
+		utils.CheckpointFlag,
 		utils.GCModeFlag,
+		utils.StateRetainFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
 		utils.LightKDFFlag,
@@ -72,10 +75,13 @@ var (
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
 		utils.MaxPendingPeersFlag,
+		utils.RelayPeersFlag,
 		utils.CoinbaseFlag,
 		utils.GasPriceFlag,
 		utils.ValidatorDepositFlag,
 		utils.ValidationEnabledFlag,
+		utils.ValidatorConsensusKeyFlag,
+		utils.ValidatorShadowFlag,
 		utils.TargetGasLimitFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
@@ -86,6 +92,7 @@ var (
 		utils.TestnetFlag,
 		utils.CurrencyFlag,
 		utils.VMEnableDebugFlag,
+		utils.VMParallelWorkersFlag,
 		utils.NetworkIdFlag,
 		utils.RPCCORSDomainFlag,
 		utils.RPCVirtualHostsFlag,
@@ -111,6 +118,11 @@ var (
 		utils.WSPortFlag,
 		utils.WSApiFlag,
 		utils.WSAllowedOriginsFlag,
+		utils.GraphQLEnabledFlag,
+		utils.GraphQLListenAddrFlag,
+		utils.GraphQLPortFlag,
+		utils.GraphQLCORSDomainFlag,
+		utils.GraphQLVirtualHostsFlag,
 		utils.IPCDisabledFlag,
 		utils.IPCPathFlag,
 	}
@@ -140,6 +152,9 @@ func init() {
 		copydbCommand,
 		removedbCommand,
 		dumpCommand,
+		pruneStateCommand,
+		exportGenesisCommand,
+		rollbackCommand,
 		// See monitorcmd.go:
 		monitorCommand,
 		// See accountcmd.go:
@@ -191,7 +206,7 @@ func init() {
 		utils.SetupMetrics(ctx)
 
 		// Start system runtime metrics collection
-		go metrics.CollectProcessMetrics(3*time.Second, ctx.GlobalString(utils.MetricsPrometheusAddressFlag.Name), ctx.GlobalString(utils.MetricsPrometheusSubsystemFlag.Name))
+		go metrics.CollectProcessMetrics(3 * time.Second)
 
 		go version.Checker(ctx.GlobalString(utils.VersionRepository.Name))
 
@@ -317,7 +332,7 @@ func startNode(ctx *cli.Context, stack *node.Node) {
 	}()
 
 	// Start auxiliary services if enabled
-	if ctx.GlobalBool(utils.ValidationEnabledFlag.Name) {
+	if ctx.GlobalBool(utils.ValidationEnabledFlag.Name) || ctx.GlobalBool(utils.ValidatorShadowFlag.Name) {
 		// Validation only makes sense if a full Kowala node is running
 		var kowala *knode.Kowala
 		if err := stack.Service(&kowala); err != nil {
@@ -52,13 +52,16 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			configFileFlag,
 			utils.DataDirFlag,
+			utils.AncientFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.NetworkIdFlag,
 			utils.TestnetFlag,
 			utils.DevModeFlag,
 			utils.SyncModeFlag,
+			utils.CheckpointFlag,
 			utils.GCModeFlag,
+			utils.StateRetainFlag,
 			utils.KowalaStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightServFlag,
@@ -110,6 +113,11 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.WSPortFlag,
 			utils.WSApiFlag,
 			utils.WSAllowedOriginsFlag,
+			utils.GraphQLEnabledFlag,
+			utils.GraphQLListenAddrFlag,
+			utils.GraphQLPortFlag,
+			utils.GraphQLCORSDomainFlag,
+			utils.GraphQLVirtualHostsFlag,
 			utils.IPCDisabledFlag,
 			utils.IPCPathFlag,
 			utils.RPCCORSDomainFlag,
@@ -128,6 +136,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.ListenPortFlag,
 			utils.MaxPeersFlag,
 			utils.MaxPendingPeersFlag,
+			utils.RelayPeersFlag,
 			utils.NATFlag,
 			utils.NoDiscoverFlag,
 			utils.NetrestrictFlag,
@@ -139,6 +148,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "VALIDATOR",
 		Flags: []cli.Flag{
 			utils.ValidationEnabledFlag,
+			utils.ValidatorConsensusKeyFlag,
+			utils.ValidatorShadowFlag,
 			utils.ValidatorDepositFlag,
 			utils.CoinbaseFlag,
 			utils.TargetGasLimitFlag,
@@ -157,6 +168,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "VIRTUAL MACHINE",
 		Flags: []cli.Flag{
 			utils.VMEnableDebugFlag,
+			utils.VMParallelWorkersFlag,
 		},
 	},
 	{
@@ -176,6 +188,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MetricsInfluxDBUsernameFlag,
 			utils.MetricsInfluxDBPasswordFlag,
 			utils.MetricsInfluxDBHostTagFlag,
+			utils.MetricsPrometheusAddressFlag,
 		},
 	},
 	{
@@ -183,6 +196,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.FastSyncFlag,
 			utils.LightModeFlag,
+			utils.MetricsPrometheusSubsystemFlag,
 		},
 	},
 	{
@@ -0,0 +1,115 @@
+// relayer relays the committed headers and the bridge transfers of a Kowala
+// network to the bridge contracts of another Kowala network.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/accounts/keystore"
+	"github.com/kowala-tech/kcoin/client/cmd/utils"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/kcoinclient"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/relayer"
+	"github.com/kowala-tech/kcoin/client/rpc"
+)
+
+func main() {
+	var (
+		sourceURL     = flag.String("source", "", "RPC endpoint of the source network (with the debug API)")
+		sourceChainID = flag.Uint64("sourcechainid", 0, "chain ID of the source network")
+		destURL       = flag.String("destination", "", "RPC endpoint of the destination network")
+		destChainID   = flag.Uint64("destchainid", 0, "chain ID of the destination network")
+		keyFile       = flag.String("keyfile", "", "keystore file of the account paying for the relayed transactions")
+		passwordFile  = flag.String("password", "", "file with the password of the keystore file")
+		from          = flag.Uint64("from", 1, "first block of the source network to relay")
+		interval      = flag.Duration("interval", 5*time.Second, "interval between the relays")
+		verbosity     = flag.Int("verbosity", int(log.LvlInfo), "log verbosity (0-9)")
+	)
+	flag.Parse()
+
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(*verbosity))
+	log.Root().SetHandler(glogger)
+
+	if *sourceURL == "" || *destURL == "" {
+		utils.Fatalf("Use -source and -destination to specify the networks")
+	}
+	if *sourceChainID == 0 || *destChainID == 0 {
+		utils.Fatalf("Use -sourcechainid and -destchainid to specify the chain IDs of the networks")
+	}
+
+	opts, err := transactOpts(*keyFile, *passwordFile, new(big.Int).SetUint64(*destChainID))
+	if err != nil {
+		utils.Fatalf("Failed to load the relayer account: %v", err)
+	}
+
+	sourceClient, err := rpc.Dial(*sourceURL)
+	if err != nil {
+		utils.Fatalf("Failed to connect to the source network: %v", err)
+	}
+	destination, err := kcoinclient.Dial(*destURL)
+	if err != nil {
+		utils.Fatalf("Failed to connect to the destination network: %v", err)
+	}
+
+	source := relayer.NewRPCSource(sourceClient, new(big.Int).SetUint64(*sourceChainID))
+	r, err := relayer.New(source, destination, opts, new(big.Int).SetUint64(*from))
+	if err != nil {
+		utils.Fatalf("Failed to create the relayer: %v", err)
+	}
+
+	log.Info("Relaying bridge transfers", "account", opts.From, "from", *from)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), *interval*10)
+		if err := r.Relay(ctx); err != nil {
+			log.Warn("Failed to relay", "err", err)
+		}
+		cancel()
+		time.Sleep(*interval)
+	}
+}
+
+// transactOpts returns the options of the transactions signed by the account
+// of the keystore file for the destination network.
+func transactOpts(keyFile, passwordFile string, chainID *big.Int) (*bind.TransactOpts, error) {
+	if keyFile == "" {
+		return nil, errors.New("use -keyfile to specify the account")
+	}
+	keyJSON, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	var password string
+	if passwordFile != "" {
+		data, err := ioutil.ReadFile(passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		password = strings.TrimRight(string(data), "\r\n")
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := types.NewAndromedaSigner(chainID)
+	return &bind.TransactOpts{
+		From: key.Address,
+		Signer: func(_ types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != key.Address {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			return types.SignTx(tx, signer, key.PrivateKey)
+		},
+	}, nil
+}
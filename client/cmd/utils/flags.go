@@ -20,8 +20,10 @@ import (
 	"github.com/kowala-tech/kcoin/client/common/fdlimit"
 	"github.com/kowala-tech/kcoin/client/core"
 	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/state/pruner"
 	"github.com/kowala-tech/kcoin/client/core/vm"
 	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/graphql"
 	"github.com/kowala-tech/kcoin/client/kcoindb"
 	"github.com/kowala-tech/kcoin/client/knode"
 	"github.com/kowala-tech/kcoin/client/knode/downloader"
@@ -99,6 +101,10 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: DirectoryString{node.DefaultDataDir()},
 	}
+	AncientFlag = DirectoryFlag{
+		Name:  "datadir.ancient",
+		Usage: "Data directory for ancient chain segments (default = inside chaindata)",
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -154,14 +160,44 @@ var (
 	defaultSyncMode = knode.DefaultConfig.SyncMode
 	SyncModeFlag    = TextMarshalerFlag{
 		Name:  "syncmode",
-		Usage: `Blockchain sync mode ("fast", "full", or "light")`,
+		Usage: `Blockchain sync mode ("fast", "full", "light" or "checkpoint")`,
 		Value: &defaultSyncMode,
 	}
+	CheckpointFlag = cli.StringFlag{
+		Name:  "checkpoint",
+		Usage: "Trusted checkpoint to sync from (<number>:<hash>[:<validators hash>])",
+	}
 	GCModeFlag = cli.StringFlag{
 		Name:  "gcmode",
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "full",
 	}
+	StateRetainFlag = cli.Uint64Flag{
+		Name:  "state.retain",
+		Usage: "Number of recent blocks whose state is retained, older state is pruned (0 = disabled)",
+	}
+	BloomFilterSizeFlag = cli.Uint64Flag{
+		Name:  "bloomfilter.size",
+		Usage: "Megabytes of memory allocated to the bloom filter of the live state when pruning",
+		Value: pruner.DefaultBloomSize,
+	}
+	ExportBlockFlag = cli.Int64Flag{
+		Name:  "block",
+		Usage: "Number of the block whose state is exported (-1 = current head)",
+		Value: -1,
+	}
+	RollbackTargetFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Number of the block to roll the chain back to",
+	}
+	RollbackForceFlag = cli.BoolFlag{
+		Name:  "force",
+		Usage: "Roll back below the trusted checkpoint",
+	}
+	RollbackYesFlag = cli.BoolFlag{
+		Name:  "yes",
+		Usage: "Roll back without asking for confirmation",
+	}
 	LightServFlag = cli.IntFlag{
 		Name:  "lightserv",
 		Usage: "Maximum percentage of time allowed for serving LES requests (0-90)",
@@ -258,6 +294,14 @@ var (
 		Usage: "Deposit at stake",
 		Value: big.NewInt(0),
 	}
+	ValidatorConsensusKeyFlag = cli.StringFlag{
+		Name:  "validator.consensuskey",
+		Usage: "Account signing the proposals and votes of the validator (default = coinbase)",
+	}
+	ValidatorShadowFlag = cli.BoolFlag{
+		Name:  "validator.shadow",
+		Usage: "Run the consensus validation without deposit nor broadcasting proposals and votes (dry run)",
+	}
 
 	TargetGasLimitFlag = cli.Uint64Flag{
 		Name:  "targetgaslimit",
@@ -295,6 +339,10 @@ var (
 		Name:  "vmdebug",
 		Usage: "Record information useful for VM and contract debugging",
 	}
+	VMParallelWorkersFlag = cli.IntFlag{
+		Name:  "vm.workers",
+		Usage: "Number of goroutines executing the transactions of a block speculatively (0 = sequential)",
+	}
 	// Logging and debug settings
 	ShipLogzioFlag = cli.StringFlag{
 		Name:  "logzioapi",
@@ -343,6 +391,30 @@ var (
 		Usage: "API's offered over the HTTP-RPC interface",
 		Value: "",
 	}
+	GraphQLEnabledFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable the GraphQL server",
+	}
+	GraphQLListenAddrFlag = cli.StringFlag{
+		Name:  "graphql.addr",
+		Usage: "GraphQL server listening interface",
+		Value: node.DefaultGraphQLHost,
+	}
+	GraphQLPortFlag = cli.IntFlag{
+		Name:  "graphql.port",
+		Usage: "GraphQL server listening port",
+		Value: node.DefaultGraphQLPort,
+	}
+	GraphQLCORSDomainFlag = cli.StringFlag{
+		Name:  "graphql.corsdomain",
+		Usage: "Comma separated list of domains from which to accept cross origin requests (browser enforced)",
+		Value: "",
+	}
+	GraphQLVirtualHostsFlag = cli.StringFlag{
+		Name:  "graphql.vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept requests (server enforced). Accepts '*' wildcard.",
+		Value: strings.Join(node.DefaultConfig.GraphQLVirtualHosts, ","),
+	}
 	IPCDisabledFlag = cli.BoolFlag{
 		Name:  "ipcdisable",
 		Usage: "Disable the IPC-RPC server",
@@ -395,6 +467,11 @@ var (
 		Usage: "Maximum number of pending connection attempts (defaults used if set to 0)",
 		Value: 0,
 	}
+	RelayPeersFlag = cli.IntFlag{
+		Name:  "relaypeers",
+		Usage: "Number of consensus-relaying peers preferred by the dialer",
+		Value: node.DefaultConfig.P2P.RelayPeers,
+	}
 	ListenPortFlag = cli.IntFlag{
 		Name:  "port",
 		Usage: "Network listening port",
@@ -496,13 +573,12 @@ var (
 
 	MetricsPrometheusAddressFlag = cli.StringFlag{
 		Name:  metrics.MetricsPrometheusAddressFlag,
-		Usage: "Set the address for Prometheus reporting",
+		Usage: "Address of the HTTP server serving the metrics to Prometheus",
 		Value: ":8080",
 	}
 	MetricsPrometheusSubsystemFlag = cli.StringFlag{
 		Name:  metrics.MetricsPrometheusSubsystemFlag,
-		Usage: "Set the subsystem name for Prometheus reporting",
-		Value: "node",
+		Usage: "Prometheus subsystem name (deprecated, the metrics are served under their own names)",
 	}
 )
 
@@ -701,6 +777,38 @@ func setWS(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setGraphQL creates the GraphQL listener interface string from the set
+// command line flags, returning empty if the GraphQL endpoint is disabled.
+func setGraphQL(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalBool(GraphQLEnabledFlag.Name) && cfg.GraphQLHost == "" {
+		cfg.GraphQLHost = "127.0.0.1"
+		if ctx.GlobalIsSet(GraphQLListenAddrFlag.Name) {
+			cfg.GraphQLHost = ctx.GlobalString(GraphQLListenAddrFlag.Name)
+		}
+	}
+
+	if ctx.GlobalIsSet(GraphQLPortFlag.Name) {
+		cfg.GraphQLPort = ctx.GlobalInt(GraphQLPortFlag.Name)
+	}
+	if ctx.GlobalIsSet(GraphQLCORSDomainFlag.Name) {
+		cfg.GraphQLCors = splitAndTrim(ctx.GlobalString(GraphQLCORSDomainFlag.Name))
+	}
+	if ctx.GlobalIsSet(GraphQLVirtualHostsFlag.Name) {
+		cfg.GraphQLVirtualHosts = splitAndTrim(ctx.GlobalString(GraphQLVirtualHostsFlag.Name))
+	}
+}
+
+// setMetrics sets the address of the Prometheus metrics endpoint if the metrics
+// collection is enabled.
+func setMetrics(ctx *cli.Context, cfg *node.Config) {
+	if ctx.GlobalIsSet(MetricsPrometheusSubsystemFlag.Name) {
+		log.Warn("The --" + MetricsPrometheusSubsystemFlag.Name + " flag is deprecated and ignored, the metrics are served under their own names")
+	}
+	if metrics.Enabled && cfg.MetricsAddr == "" {
+		cfg.MetricsAddr = ctx.GlobalString(MetricsPrometheusAddressFlag.Name)
+	}
+}
+
 // setIPC creates an IPC path configuration from the set command line flags,
 // returning an empty string if IPC was explicitly disabled, or the set path.
 func setIPC(ctx *cli.Context, cfg *node.Config) {
@@ -777,6 +885,18 @@ func setCoinbase(ctx *cli.Context, ks *keystore.KeyStore, cfg *knode.Config) {
 	}
 }
 
+// setConsensusKey retrieves the consensus key either from the directly specified
+// command line flags or from the keystore if CLI indexed.
+func setConsensusKey(ctx *cli.Context, ks *keystore.KeyStore, cfg *knode.Config) {
+	if ctx.GlobalIsSet(ValidatorConsensusKeyFlag.Name) {
+		account, err := MakeAddress(ks, ctx.GlobalString(ValidatorConsensusKeyFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", ValidatorConsensusKeyFlag.Name, err)
+		}
+		cfg.ConsensusKey = account.Address
+	}
+}
+
 func setDeposit(ctx *cli.Context, cfg *knode.Config) {
 	cfg.Deposit = GlobalBig(ctx, ValidatorDepositFlag.Name)
 }
@@ -813,6 +933,9 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 	if ctx.GlobalIsSet(MaxPendingPeersFlag.Name) {
 		cfg.MaxPendingPeers = ctx.GlobalInt(MaxPendingPeersFlag.Name)
 	}
+	if ctx.GlobalIsSet(RelayPeersFlag.Name) {
+		cfg.RelayPeers = ctx.GlobalInt(RelayPeersFlag.Name)
+	}
 	if ctx.GlobalIsSet(NoDiscoverFlag.Name) || ctx.GlobalBool(LightModeFlag.Name) {
 		cfg.NoDiscovery = true
 	}
@@ -837,6 +960,8 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config, kowalaCfg *knode.Config)
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setWS(ctx, cfg)
+	setGraphQL(ctx, cfg)
+	setMetrics(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 
 	switch {
@@ -945,7 +1070,11 @@ func SetKowalaConfig(ctx *cli.Context, stack *node.Node, cfg *knode.Config) {
 
 	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
 	setCoinbase(ctx, ks, cfg)
+	setConsensusKey(ctx, ks, cfg)
 	setDeposit(ctx, cfg)
+	if ctx.GlobalIsSet(ValidatorShadowFlag.Name) {
+		cfg.ValidatorShadow = ctx.GlobalBool(ValidatorShadowFlag.Name)
+	}
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 
@@ -957,6 +1086,13 @@ func SetKowalaConfig(ctx *cli.Context, stack *node.Node, cfg *knode.Config) {
 	case ctx.GlobalBool(LightModeFlag.Name):
 		cfg.SyncMode = downloader.LightSync
 	}
+	if ctx.GlobalIsSet(CheckpointFlag.Name) {
+		checkpoint, err := params.ParseCheckpoint(ctx.GlobalString(CheckpointFlag.Name))
+		if err != nil {
+			Fatalf("Option %q: %v", CheckpointFlag.Name, err)
+		}
+		cfg.Checkpoint = checkpoint
+	}
 	if ctx.GlobalIsSet(LightServFlag.Name) {
 		cfg.LightServ = ctx.GlobalInt(LightServFlag.Name)
 	}
@@ -971,12 +1107,25 @@ func SetKowalaConfig(ctx *cli.Context, stack *node.Node, cfg *knode.Config) {
 		cfg.DatabaseCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
 	}
 	cfg.DatabaseHandles = makeDatabaseHandles()
+	if ctx.GlobalIsSet(AncientFlag.Name) {
+		cfg.DatabaseFreezer = ctx.GlobalString(AncientFlag.Name)
+	}
 
 	if gcmode := ctx.GlobalString(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
 	cfg.NoPruning = ctx.GlobalString(GCModeFlag.Name) == "archive"
 
+	if ctx.GlobalIsSet(StateRetainFlag.Name) {
+		if cfg.NoPruning {
+			Fatalf("--%s can't be used with --%s=archive", StateRetainFlag.Name, GCModeFlag.Name)
+		}
+		cfg.StateRetention = ctx.GlobalUint64(StateRetainFlag.Name)
+		if cfg.StateRetention != 0 && cfg.StateRetention < core.MinStateRetention {
+			Fatalf("--%s must retain at least %d blocks", StateRetainFlag.Name, core.MinStateRetention)
+		}
+	}
+
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cfg.TrieCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
@@ -993,6 +1142,9 @@ func SetKowalaConfig(ctx *cli.Context, stack *node.Node, cfg *knode.Config) {
 		// TODO(fjl): force-enable this in --dev mode
 		cfg.EnablePreimageRecording = ctx.GlobalBool(VMEnableDebugFlag.Name)
 	}
+	if ctx.GlobalIsSet(VMParallelWorkersFlag.Name) {
+		cfg.ParallelWorkers = ctx.GlobalInt(VMParallelWorkersFlag.Name)
+	}
 
 	// Override any default configs for hard coded networks.
 	switch {
@@ -1036,6 +1188,19 @@ func RegisterKowalaStatsService(stack *node.Node, url string) {
 	}
 }
 
+// RegisterGraphQLService adds the GraphQL API to the node.
+func RegisterGraphQLService(stack *node.Node, endpoint string, cors, vhosts []string) {
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		var kowalaServ *knode.Kowala
+		if err := ctx.Service(&kowalaServ); err != nil {
+			return nil, err
+		}
+		return graphql.New(kowalaServ.APIBackend(), endpoint, cors, vhosts)
+	}); err != nil {
+		Fatalf("Failed to register the GraphQL service: %v", err)
+	}
+}
+
 // SetupNetwork configures the system for either the main net or some test network.
 func SetupNetwork(ctx *cli.Context) {
 	// TODO(fjl): move target gas limit into config
@@ -1069,11 +1234,15 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) kcoindb.Database {
 		cache   = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
 		handles = makeDatabaseHandles()
 	)
-	name := "chaindata"
+	var (
+		chainDb kcoindb.Database
+		err     error
+	)
 	if ctx.GlobalBool(LightModeFlag.Name) {
-		name = "lightchaindata"
+		chainDb, err = stack.OpenDatabase("lightchaindata", cache, handles)
+	} else {
+		chainDb, err = stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.GlobalString(AncientFlag.Name), "")
 	}
-	chainDb, err := stack.OpenDatabase(name, cache, handles)
 	if err != nil {
 		Fatalf("Could not open database: %v", err)
 	}
@@ -1111,7 +1280,10 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cache.TrieNodeLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
 	}
-	vmcfg := vm.Config{EnablePreimageRecording: ctx.GlobalBool(VMEnableDebugFlag.Name)}
+	vmcfg := vm.Config{
+		EnablePreimageRecording: ctx.GlobalBool(VMEnableDebugFlag.Name),
+		ParallelWorkers:         ctx.GlobalInt(VMParallelWorkersFlag.Name),
+	}
 	chain, err = core.NewBlockChain(chainDb, cache, config, engine, vmcfg)
 	if err != nil {
 		Fatalf("Can't create BlockChain: %v", err)
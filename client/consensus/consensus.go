@@ -63,6 +63,11 @@ type Engine interface {
 	// consensus rules that happen at finalization (e.g. block rewards).
 	Finalize(chain ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, commit *types.Commit, receipts []*types.Receipt) (*types.Block, error)
 
+	// VerifyBlockCommit checks the commit of the parent block carried by the
+	// block. The voters of the parent block election are loaded from the given
+	// state, the state the election started from.
+	VerifyBlockCommit(chain ChainReader, block *types.Block, state *state.StateDB) error
+
 	// Seal generates a new block for the given input block with the local miner's
 	// seal place on top.
 	Seal(chain ChainReader, block *types.Block, stop <-chan struct{}) (*types.Block, error)
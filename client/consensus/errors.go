@@ -18,4 +18,20 @@ var (
 	// ErrInvalidNumber is returned if a block's number doesn't equal it's parent's
 	// plus one.
 	ErrInvalidNumber = errors.New("invalid block number")
+
+	// ErrAggregateCommitNotActive is returned if a block carries an aggregated
+	// commit before the aggregated commit fork.
+	ErrAggregateCommitNotActive = errors.New("aggregated commits not yet active")
+
+	// ErrMissingCommit is returned if a block doesn't carry the commit of its
+	// parent block.
+	ErrMissingCommit = errors.New("missing commit of the parent block")
+
+	// ErrInsufficientCommit is returned if a commit isn't signed by more than two
+	// thirds of the voters.
+	ErrInsufficientCommit = errors.New("commit does not have a +2/3 majority")
+
+	// ErrInvalidCommitSignature is returned if the aggregate signature of a commit
+	// doesn't match its signers.
+	ErrInvalidCommitSignature = errors.New("invalid commit signature")
 )
@@ -0,0 +1,96 @@
+package konsensus
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/kns"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// stateCaller is a contract caller executing the calls against the state of
+// the block being finalised. The changes made by the calls are reverted.
+type stateCaller struct {
+	config  *params.ChainConfig
+	header  *types.Header
+	statedb *state.StateDB
+}
+
+// CodeAt returns the code of the given account in the state.
+func (c *stateCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.statedb.GetCode(contract), nil
+}
+
+// CallContract executes a read-only contract call against the state.
+func (c *stateCaller) CallContract(ctx context.Context, call kowala.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil {
+		return nil, errors.New("contract creation not supported")
+	}
+
+	context := vm.Context{
+		CanTransfer: canTransfer,
+		Transfer:    transfer,
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		Origin:      call.From,
+		GasPrice:    new(big.Int),
+		Coinbase:    c.header.Coinbase,
+		GasLimit:    math.MaxUint64,
+		BlockNumber: c.header.Number,
+		Time:        c.header.Time,
+		Difficulty:  new(big.Int),
+	}
+	evm := vm.NewEVM(context, c.statedb, c.config, vm.Config{})
+
+	snapshot := c.statedb.Snapshot()
+	defer c.statedb.RevertToSnapshot(snapshot)
+
+	ret, _, err := evm.StaticCall(vm.AccountRef(call.From), *call.To, call.Data, math.MaxUint64)
+	return ret, err
+}
+
+// stateVoters returns the voters registered in the validator manager of the
+// state. The calls are executed in the context of the given header.
+func stateVoters(config *params.ChainConfig, state *state.StateDB, header *types.Header) (types.Voters, error) {
+	caller := &stateCaller{config: config, header: header, statedb: state}
+
+	addr, err := kns.GetAddressFromDomain(params.KNSDomains[params.ValidatorMgrDomain].FullDomain(), caller)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := consensus.NewValidatorMgrCaller(addr, caller)
+	if err != nil {
+		return nil, err
+	}
+	uptime, err := consensus.NewValidatorUptimeCaller(vm.UptimeAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := consensus.NewAggregateKeysCaller(vm.AggregateKeysAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	consensusKeys, err := consensus.NewConsensusKeysCaller(vm.ConsensusKeysAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	return consensus.GetVoters(manager, uptime, keys, consensusKeys)
+}
+
+// canTransfer and transfer mirror the core transfer functions, which can't be
+// imported by the consensus engine.
+func canTransfer(db vm.StateDB, addr common.Address, amount *big.Int) bool {
+	return db.GetBalance(addr).Cmp(amount) >= 0
+}
+
+func transfer(db vm.StateDB, sender, recipient common.Address, amount *big.Int) {
+	db.SubBalance(sender, amount)
+	db.AddBalance(recipient, amount)
+}
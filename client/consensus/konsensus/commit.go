@@ -0,0 +1,153 @@
+package konsensus
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+)
+
+var (
+	errMissingFirstPreCommit = errors.New("commit without a first pre-commit")
+	errNotPreCommit          = errors.New("commit vote is not a pre-commit")
+)
+
+// VerifyBlockCommit checks that the block carries a commit of its parent block
+// signed by more than two thirds of the voters, from the aggregated commit
+// fork on. The voters of the parent block election are loaded from the given
+// state: the state of the grandparent block, which the election started from.
+// The first block carries the commit of the genesis block, which wasn't
+// elected.
+func (kss *Konsensus) VerifyBlockCommit(chain consensus.ChainReader, block *types.Block, state *state.StateDB) error {
+	config := chain.Config()
+	if !config.IsAggregateCommit(block.Number()) || block.NumberU64() <= 1 {
+		return nil
+	}
+	// the blocks without a commit carry an empty first pre-commit
+	commit := block.LastCommit()
+	if commit == nil || commit.First() == nil || commit.First().BlockNumber() == nil {
+		return consensus.ErrMissingCommit
+	}
+	first := commit.First()
+	parent := chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	if first.BlockHash() != parent.Hash() || first.BlockNumber().Cmp(parent.Number) != 0 {
+		return errCommitParentMismatch
+	}
+	if first.Type() != types.PreCommit {
+		return errNotPreCommit
+	}
+
+	voters, err := stateVoters(config, state, parent)
+	if err != nil {
+		return err
+	}
+	return kss.VerifyCommit(chain, block.Number(), voters, commit)
+}
+
+// VerifyCommit checks that the commit included in the block number was signed
+// by more than two thirds of the given voters. Both the list of individually
+// signed pre-commits and, once the fork is active, the aggregated format are
+// accepted.
+func (kss *Konsensus) VerifyCommit(chain consensus.ChainReader, number *big.Int, voters types.Voters, commit *types.Commit) error {
+	first := commit.First()
+	if first == nil {
+		return errMissingFirstPreCommit
+	}
+	signer := types.NewAndromedaSigner(chain.Config().ChainID)
+
+	agg := commit.Aggregate()
+	if agg == nil {
+		return verifyPreCommits(signer, voters, first, commit.Commits())
+	}
+	if !chain.Config().IsAggregateCommit(number) {
+		return consensus.ErrAggregateCommitNotActive
+	}
+	return verifyAggregate(signer, voters, first, agg)
+}
+
+// verifyPreCommits verifies the ECDSA signature of every pre-commit against
+// the consensus keys of the voters.
+func verifyPreCommits(signer types.Signer, voters types.Voters, first *types.Vote, precommits types.Votes) error {
+	seen, err := preCommitSigners(signer, voters, first, precommits)
+	if err != nil {
+		return err
+	}
+	if !hasMajority(len(seen), voters.Len()) {
+		return consensus.ErrInsufficientCommit
+	}
+	return nil
+}
+
+// preCommitSigners returns the addresses of the voters that signed the
+// pre-commits.
+func preCommitSigners(signer types.Signer, voters types.Voters, first *types.Vote, precommits types.Votes) (map[common.Address]struct{}, error) {
+	seen := make(map[common.Address]struct{}, len(precommits))
+	for _, vote := range precommits {
+		if vote.BlockHash() != first.BlockHash() || vote.Round() != first.Round() || vote.Type() != first.Type() ||
+			vote.BlockNumber() == nil || first.BlockNumber() == nil || vote.BlockNumber().Cmp(first.BlockNumber()) != 0 {
+			return nil, types.ErrMismatchingPreCommits
+		}
+		addr, err := types.VoteSender(signer, vote)
+		if err != nil {
+			return nil, err
+		}
+		voter := voters.GetByConsensusKey(addr)
+		if voter == nil {
+			return nil, types.ErrUnknownCommitVoter
+		}
+		if _, ok := seen[voter.Address()]; ok {
+			return nil, types.ErrDuplicateCommitVoter
+		}
+		seen[voter.Address()] = struct{}{}
+	}
+	return seen, nil
+}
+
+// verifyAggregate verifies the aggregate signature against the aggregate
+// key of the voters flagged in the signer bitmap.
+func verifyAggregate(signer types.Signer, voters types.Voters, first *types.Vote, agg *types.AggregateSignature) error {
+	if !agg.ValidBitmap(voters.Len()) {
+		return types.ErrInvalidSignerBitmap
+	}
+	if !hasMajority(agg.Count(), voters.Len()) {
+		return consensus.ErrInsufficientCommit
+	}
+
+	keys := make([]*bls.PublicKey, 0, agg.Count())
+	for i := 0; i < voters.Len(); i++ {
+		if !agg.Signed(i) {
+			continue
+		}
+		key := voters.At(i).AggregateKey()
+		if key == nil {
+			return types.ErrNoAggregateKey
+		}
+		keys = append(keys, key)
+	}
+	aggKey, err := bls.AggregatePublicKeys(keys)
+	if err != nil {
+		return err
+	}
+	sig, err := bls.UnmarshalSignature(agg.Signature)
+	if err != nil {
+		return err
+	}
+
+	h := signer.Hash(first)
+	if !aggKey.Verify(h[:], sig) {
+		return consensus.ErrInvalidCommitSignature
+	}
+	return nil
+}
+
+// hasMajority reports whether count is more than two thirds of total.
+func hasMajority(count, total int) bool {
+	return count*3 > total*2
+}
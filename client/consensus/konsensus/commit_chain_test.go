@@ -0,0 +1,143 @@
+package konsensus_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/knode/genesis"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// newCommitTestGenesis returns a test network genesis with a single validator
+// and the aggregated commits active from the genesis block on.
+func newCommitTestGenesis(t *testing.T, validator common.Address) *core.Genesis {
+	opts := genesis.Networks["kusd"][genesis.TestNetwork]
+
+	consensusOpts := *opts.Consensus
+	consensusOpts.Validators = []genesis.Validator{{Address: validator.Hex(), Deposit: consensusOpts.BaseDeposit}}
+	tokenOpts := *consensusOpts.MiningToken
+	tokenOpts.Holders = []genesis.TokenHolder{{Address: validator.Hex(), NumTokens: consensusOpts.BaseDeposit}}
+	consensusOpts.MiningToken = &tokenOpts
+	opts.Consensus = &consensusOpts
+	opts.PrefundedAccounts = []genesis.PrefundedAccount{{Address: validator.Hex(), Balance: 1000}}
+
+	gen, err := genesis.Generate(opts)
+	if err != nil {
+		t.Fatalf("failed to generate the genesis: %v", err)
+	}
+	gen.Config.AggregateCommitBlock = big.NewInt(0)
+	return gen
+}
+
+// signedPreCommit returns the pre-commit of the block signed by the key, and
+// by the aggregate key if any.
+func signedPreCommit(t *testing.T, signer types.Signer, block *types.Block, key *ecdsa.PrivateKey, aggKey *bls.PrivateKey) *types.Vote {
+	vote, err := types.SignVote(types.NewVote(block.Number(), block.Hash(), 0, types.PreCommit), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aggKey != nil {
+		vote = types.SignVoteAggregate(vote, signer, aggKey)
+	}
+	return vote
+}
+
+func TestInsertChainVerifiesCommits(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	aggKey, _ := bls.GenerateKey(nil)
+	var (
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		gen     = newCommitTestGenesis(t, addr)
+		db      = kcoindb.NewMemDatabase()
+		genesis = gen.MustCommit(db)
+		engine  = konsensus.New(&params.KonsensusConfig{})
+		signer  = types.NewAndromedaSigner(gen.Config.ChainID)
+	)
+
+	keysABI, err := abi.JSON(strings.NewReader(vm.AggregateKeysABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := keysABI.Pack("setAggregateKey", aggKey.PublicKey().Marshal(), aggKey.ProvePossession().Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	register, err := types.SignTx(types.NewTransaction(0, vm.AggregateKeysAddress, new(big.Int), params.AggregateKeySetGas+100000, new(big.Int), input), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The election of the second block starts from the state of the first
+	// block, which holds the aggregate key of the validator, so the third
+	// block carries an aggregated commit.
+	blocks, _ := core.GenerateChain(gen.Config, genesis, engine, db, 3, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(addr)
+		switch i {
+		case 0:
+			b.AddTx(register)
+		case 1:
+			first := signedPreCommit(t, signer, b.PrevBlock(-1), key, nil)
+			b.SetCommit(&types.Commit{PreCommits: types.Votes{first}, FirstPreCommit: first})
+		case 2:
+			voter := types.NewVoterWithAggregateKey(addr, big.NewInt(1), big.NewInt(0), aggKey.PublicKey())
+			voters, err := types.NewVoters([]*types.Voter{voter})
+			if err != nil {
+				t.Fatal(err)
+			}
+			first := signedPreCommit(t, signer, b.PrevBlock(-1), key, aggKey)
+			commit, err := types.NewAggregateCommit(voters, signer, first, types.Votes{first})
+			if err != nil {
+				t.Fatal(err)
+			}
+			b.SetCommit(commit)
+		}
+	})
+
+	// the aggregate signature of a forged commit is over another block
+	forged, _ := core.GenerateChain(gen.Config, blocks[1], engine, db, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(addr)
+		commit := blocks[2].LastCommit()
+		other := signedPreCommit(t, signer, blocks[0], key, aggKey)
+		agg := commit.Aggregate().Copy()
+		agg.Signature = other.AggregateSignature()
+		b.SetCommit(&types.Commit{PreCommits: types.Votes{}, FirstPreCommit: commit.First(), Aggregated: []*types.AggregateSignature{agg}})
+	})
+	// a block must carry the commit of its parent block
+	uncommitted, _ := core.GenerateChain(gen.Config, blocks[1], engine, db, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(addr)
+	})
+
+	chain, err := core.NewBlockChain(db, nil, gen.Config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks[:2]); err != nil {
+		t.Fatalf("failed to insert the legacy commit blocks: %v", err)
+	}
+	if _, err := chain.InsertChain(forged); err != consensus.ErrInvalidCommitSignature {
+		t.Fatalf("forged commit error mismatch: have %v, want %v", err, consensus.ErrInvalidCommitSignature)
+	}
+	if _, err := chain.InsertChain(uncommitted); err != consensus.ErrMissingCommit {
+		t.Fatalf("missing commit error mismatch: have %v, want %v", err, consensus.ErrMissingCommit)
+	}
+	if _, err := chain.InsertChain(blocks[2:]); err != nil {
+		t.Fatalf("failed to insert the aggregated commit block: %v", err)
+	}
+	if head := chain.CurrentBlock().Hash(); head != blocks[2].Hash() {
+		t.Fatalf("head mismatch: have %x, want %x", head, blocks[2].Hash())
+	}
+}
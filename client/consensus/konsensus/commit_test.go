@@ -0,0 +1,291 @@
+package konsensus
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/kowala-tech/kcoin/client/rlp"
+)
+
+type testChain struct {
+	config *params.ChainConfig
+}
+
+func (c *testChain) Config() *params.ChainConfig                             { return c.config }
+func (c *testChain) CurrentHeader() *types.Header                            { return nil }
+func (c *testChain) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
+func (c *testChain) GetHeaderByNumber(number uint64) *types.Header           { return nil }
+func (c *testChain) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
+func (c *testChain) GetBlock(hash common.Hash, number uint64) *types.Block   { return nil }
+
+type testValidator struct {
+	key    *ecdsa.PrivateKey
+	aggKey *bls.PrivateKey
+}
+
+// newTestCommit creates n voters and the pre-commits of the first signed of
+// them for the same block, with and without the aggregate signatures.
+func newTestCommit(t testing.TB, n, signed int) (types.Voters, types.Votes, types.Votes) {
+	signer := types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+
+	var (
+		voters     = make([]*types.Voter, n)
+		validators = make([]testValidator, n)
+	)
+	for i := range voters {
+		key, _ := crypto.GenerateKey()
+		aggKey, _ := bls.GenerateKey(nil)
+		validators[i] = testValidator{key: key, aggKey: aggKey}
+		voters[i] = types.NewVoterWithAggregateKey(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1), big.NewInt(0), aggKey.PublicKey())
+	}
+	set, err := types.NewVoters(voters)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		plain      = make(types.Votes, signed)
+		precommits = make(types.Votes, signed)
+	)
+	for i := range precommits {
+		vote := types.NewVote(big.NewInt(10), common.HexToHash("0x01"), 0, types.PreCommit)
+		vote, err := types.SignVote(vote, signer, validators[i].key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plain[i] = vote
+		precommits[i] = types.SignVoteAggregate(vote, signer, validators[i].aggKey)
+	}
+	return set, plain, precommits
+}
+
+func TestVerifyCommit(t *testing.T) {
+	var (
+		engine = New(new(params.KonsensusConfig))
+		chain  = &testChain{config: params.TestChainConfig}
+		signer = types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+		number = big.NewInt(11)
+	)
+	voters, plain, precommits := newTestCommit(t, 4, 3)
+	first := plain[0]
+
+	legacy := &types.Commit{PreCommits: plain, FirstPreCommit: first}
+	if err := engine.VerifyCommit(chain, number, voters, legacy); err != nil {
+		t.Fatalf("legacy commit: %v", err)
+	}
+
+	aggregated, err := types.NewAggregateCommit(voters, signer, first, precommits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.VerifyCommit(chain, number, voters, aggregated); err != nil {
+		t.Fatalf("aggregated commit: %v", err)
+	}
+
+	// aggregated commits survive the block encoding
+	enc, err := rlp.EncodeToBytes(aggregated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(types.Commit)
+	if err := rlp.DecodeBytes(enc, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.VerifyCommit(chain, number, voters, decoded); err != nil {
+		t.Fatalf("decoded aggregated commit: %v", err)
+	}
+
+	// not enough signers
+	short := &types.Commit{PreCommits: plain[:2], FirstPreCommit: first}
+	if err := engine.VerifyCommit(chain, number, voters, short); err != consensus.ErrInsufficientCommit {
+		t.Fatalf("error mismatch: have %v, want %v", err, consensus.ErrInsufficientCommit)
+	}
+	shortAgg, err := types.NewAggregateCommit(voters, signer, first, precommits[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.VerifyCommit(chain, number, voters, shortAgg); err != consensus.ErrInsufficientCommit {
+		t.Fatalf("error mismatch: have %v, want %v", err, consensus.ErrInsufficientCommit)
+	}
+
+	// claiming a signer that didn't sign
+	forged := types.CopyCommit(aggregated)
+	forged.Aggregated[0].Signers[0] |= 1 << 3
+	if err := engine.VerifyCommit(chain, number, voters, forged); err != consensus.ErrInvalidCommitSignature {
+		t.Fatalf("error mismatch: have %v, want %v", err, consensus.ErrInvalidCommitSignature)
+	}
+
+	// duplicate pre-commits
+	dup := &types.Commit{PreCommits: append(plain[:2:2], plain[0]), FirstPreCommit: first}
+	if err := engine.VerifyCommit(chain, number, voters, dup); err != types.ErrDuplicateCommitVoter {
+		t.Fatalf("error mismatch: have %v, want %v", err, types.ErrDuplicateCommitVoter)
+	}
+
+	// aggregated commits before the fork
+	config := *params.TestChainConfig
+	config.AggregateCommitBlock = big.NewInt(100)
+	if err := engine.VerifyCommit(&testChain{config: &config}, number, voters, aggregated); err != consensus.ErrAggregateCommitNotActive {
+		t.Fatalf("error mismatch: have %v, want %v", err, consensus.ErrAggregateCommitNotActive)
+	}
+}
+
+func TestVerifyCommitConsensusKey(t *testing.T) {
+	var (
+		engine = New(new(params.KonsensusConfig))
+		chain  = &testChain{config: params.TestChainConfig}
+		signer = types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+		number = big.NewInt(11)
+	)
+	stakingKey, _ := crypto.GenerateKey()
+	consensusKey, _ := crypto.GenerateKey()
+	voter := types.NewVoterWithConsensusKey(crypto.PubkeyToAddress(stakingKey.PublicKey), crypto.PubkeyToAddress(consensusKey.PublicKey), big.NewInt(1), big.NewInt(0))
+	voters, err := types.NewVoters([]*types.Voter{voter})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(key *ecdsa.PrivateKey) *types.Commit {
+		vote, err := types.SignVote(types.NewVote(big.NewInt(10), common.HexToHash("0x01"), 0, types.PreCommit), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &types.Commit{PreCommits: types.Votes{vote}, FirstPreCommit: vote}
+	}
+
+	if err := engine.VerifyCommit(chain, number, voters, sign(consensusKey)); err != nil {
+		t.Fatalf("commit signed with the consensus key: %v", err)
+	}
+	// the staking account doesn't sign on behalf of the validator
+	if err := engine.VerifyCommit(chain, number, voters, sign(stakingKey)); err != types.ErrUnknownCommitVoter {
+		t.Fatalf("error mismatch: have %v, want %v", err, types.ErrUnknownCommitVoter)
+	}
+
+	// the aggregated pre-commits are signed with the consensus key too
+	aggKey, _ := bls.GenerateKey(nil)
+	voter.SetAggregateKey(aggKey.PublicKey())
+	precommit := types.SignVoteAggregate(sign(consensusKey).First(), signer, aggKey)
+	aggregated, err := types.NewAggregateCommit(voters, signer, precommit, types.Votes{precommit})
+	if err != nil {
+		t.Fatalf("failed to aggregate the pre-commits of the consensus key: %v", err)
+	}
+	if err := engine.VerifyCommit(chain, number, voters, aggregated); err != nil {
+		t.Fatalf("aggregated commit signed with the consensus key: %v", err)
+	}
+}
+
+func TestAggregateKeys(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+		validator  = common.HexToAddress("0x01")
+	)
+	key, _ := bls.GenerateKey(nil)
+	other, _ := bls.GenerateKey(nil)
+
+	keysABI, err := abi.JSON(strings.NewReader(vm.AggregateKeysABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setKey := func(pub *bls.PublicKey, proof *bls.Signature) error {
+		input, err := keysABI.Pack("setAggregateKey", pub.Marshal(), proof.Marshal())
+		if err != nil {
+			t.Fatal(err)
+		}
+		context := vm.Context{
+			CanTransfer: canTransfer,
+			Transfer:    transfer,
+			BlockNumber: big.NewInt(1),
+			Time:        new(big.Int),
+			Difficulty:  new(big.Int),
+			GasPrice:    new(big.Int),
+		}
+		evm := vm.NewEVM(context, statedb, params.TestChainConfig, vm.Config{})
+		_, _, err = evm.Call(vm.AccountRef(validator), vm.AggregateKeysAddress, input, params.AggregateKeySetGas, new(big.Int))
+		return err
+	}
+
+	// the proof of possession must be signed by the registered key
+	if err := setKey(key.PublicKey(), other.ProvePossession()); err == nil {
+		t.Fatal("registered a key without a proof of possession")
+	}
+	if registered := vm.AggregateKey(statedb, validator); len(registered) != 0 {
+		t.Fatalf("registered key mismatch: have %x, want none", registered)
+	}
+
+	if err := setKey(key.PublicKey(), key.ProvePossession()); err != nil {
+		t.Fatalf("failed to register the key: %v", err)
+	}
+	if registered := vm.AggregateKey(statedb, validator); !bytes.Equal(registered, key.PublicKey().Marshal()) {
+		t.Fatalf("registered key mismatch: have %x, want %x", registered, key.PublicKey().Marshal())
+	}
+	checksum := vm.AggregateKeysChecksum(statedb)
+	if checksum == (common.Hash{}) {
+		t.Fatal("aggregate keys checksum not updated")
+	}
+
+	// the key can be replaced
+	if err := setKey(other.PublicKey(), other.ProvePossession()); err != nil {
+		t.Fatalf("failed to replace the key: %v", err)
+	}
+	if registered := vm.AggregateKey(statedb, validator); !bytes.Equal(registered, other.PublicKey().Marshal()) {
+		t.Fatalf("replaced key mismatch: have %x, want %x", registered, other.PublicKey().Marshal())
+	}
+	if vm.AggregateKeysChecksum(statedb) == checksum {
+		t.Fatal("aggregate keys checksum not updated")
+	}
+}
+
+func BenchmarkCommit(b *testing.B) {
+	for _, n := range []int{4, 16, 64} {
+		voters, plain, precommits := newTestCommit(b, n, n)
+		legacy := &types.Commit{PreCommits: plain, FirstPreCommit: plain[0]}
+		aggregated, err := types.NewAggregateCommit(voters, types.NewAndromedaSigner(params.TestChainConfig.ChainID), plain[0], precommits)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, bench := range []struct {
+			name   string
+			commit *types.Commit
+		}{
+			{"legacy", legacy},
+			{"aggregated", aggregated},
+		} {
+			commit := bench.commit
+			b.Run(fmt.Sprintf("%s/%d", bench.name, n), func(b *testing.B) {
+				var (
+					engine = New(new(params.KonsensusConfig))
+					chain  = &testChain{config: params.TestChainConfig}
+					number = big.NewInt(11)
+				)
+				enc, _ := rlp.EncodeToBytes(commit)
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					// drop the cached senders so that every round recovers them
+					var fresh types.Commit
+					if err := rlp.DecodeBytes(enc, &fresh); err != nil {
+						b.Fatal(err)
+					}
+					if err := engine.VerifyCommit(chain, number, voters, &fresh); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.ReportMetric(float64(len(enc)), "bytes/commit")
+			})
+		}
+	}
+}
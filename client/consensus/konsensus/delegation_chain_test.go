@@ -0,0 +1,195 @@
+package konsensus_test
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/kns"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/knode/genesis"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// blockCaller executes read-only contract calls against the state of a block.
+type blockCaller struct {
+	config  *params.ChainConfig
+	block   *types.Block
+	statedb *state.StateDB
+}
+
+func newBlockCaller(t *testing.T, config *params.ChainConfig, db kcoindb.Database, block *types.Block) *blockCaller {
+	statedb, err := state.New(block.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &blockCaller{config: config, block: block, statedb: statedb}
+}
+
+func (c *blockCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.statedb.GetCode(contract), nil
+}
+
+func (c *blockCaller) CallContract(ctx context.Context, call kowala.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	context := vm.Context{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		Origin:      call.From,
+		GasPrice:    new(big.Int),
+		BlockNumber: c.block.Number(),
+		Time:        c.block.Time(),
+		GasLimit:    math.MaxUint64,
+		Difficulty:  new(big.Int),
+	}
+	evm := vm.NewEVM(context, c.statedb.Copy(), c.config, vm.Config{})
+	ret, _, err := evm.StaticCall(vm.AccountRef(call.From), *call.To, call.Data, math.MaxUint64)
+	return ret, err
+}
+
+// resolve returns the address of the given domain in the KNS.
+func (c *blockCaller) resolve(t *testing.T, domain int) common.Address {
+	addr, err := kns.GetAddressFromDomain(params.KNSDomains[domain].FullDomain(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+// tokenBalance returns the mining token balance of the account.
+func (c *blockCaller) tokenBalance(t *testing.T, account common.Address) *big.Int {
+	token, err := consensus.NewMiningTokenCaller(c.resolve(t, params.MiningTokenDomain), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := token.BalanceOf(&bind.CallOpts{}, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return balance
+}
+
+func TestDelegatedStakingChain(t *testing.T) {
+	validatorKey, _ := crypto.GenerateKey()
+	delegatorKey, _ := crypto.GenerateKey()
+	var (
+		validator = crypto.PubkeyToAddress(validatorKey.PublicKey)
+		delegator = crypto.PubkeyToAddress(delegatorKey.PublicKey)
+		amount    = new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Kcoin))
+	)
+	// the delegator holds tokens as well
+	opts := genesis.Networks["kusd"][genesis.TestNetwork]
+	consensusOpts := *opts.Consensus
+	consensusOpts.Validators = []genesis.Validator{{Address: validator.Hex(), Deposit: consensusOpts.BaseDeposit}}
+	tokenOpts := *consensusOpts.MiningToken
+	tokenOpts.Holders = []genesis.TokenHolder{
+		{Address: validator.Hex(), NumTokens: consensusOpts.BaseDeposit},
+		{Address: delegator.Hex(), NumTokens: 100},
+	}
+	consensusOpts.MiningToken = &tokenOpts
+	opts.Consensus = &consensusOpts
+	opts.PrefundedAccounts = []genesis.PrefundedAccount{{Address: delegator.Hex(), Balance: 1000}}
+	gen, err := genesis.Generate(opts)
+	if err != nil {
+		t.Fatalf("failed to generate the genesis: %v", err)
+	}
+	gen.Config.DelegatedStakingBlock = big.NewInt(0)
+
+	var (
+		db      = kcoindb.NewMemDatabase()
+		parent  = gen.MustCommit(db)
+		engine  = konsensus.New(&params.KonsensusConfig{})
+		signer  = types.NewAndromedaSigner(gen.Config.ChainID)
+		root    = newBlockCaller(t, gen.Config, db, parent)
+		token   = root.resolve(t, params.MiningTokenDomain)
+		tokens  = root.tokenBalance(t, delegator)
+		balance = root.statedb.GetBalance(delegator)
+	)
+	manager, err := consensus.NewValidatorMgrCaller(root.resolve(t, params.ValidatorMgrDomain), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	freezePeriod, err := manager.FreezePeriod(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sign signs a call of the delegator to the contract
+	tokenABI, _ := abi.JSON(strings.NewReader(consensus.MiningTokenABI))
+	delegationABI, _ := abi.JSON(strings.NewReader(consensus.DelegationABI))
+	sign := func(b *core.BlockGen, to common.Address, definition abi.ABI, method string, args ...interface{}) *types.Transaction {
+		input, err := definition.Pack(method, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx, err := types.SignTx(types.NewTransaction(b.TxNonce(delegator), to, new(big.Int), 500000, new(big.Int), input), signer, delegatorKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	// the delegation contract is installed at the end of the first block
+	blocks, receipts := core.GenerateChain(gen.Config, parent, engine, db, 4, func(i int, b *core.BlockGen) {
+		switch i {
+		case 1:
+			b.SetCoinbase(validator)
+			data := common.LeftPadBytes(validator.Bytes(), common.HashLength)
+			b.AddTx(sign(b, token, tokenABI, "transfer", vm.DelegationAddress, amount, data, consensus.DelegationHandler))
+		case 2:
+			b.SetCoinbase(validator)
+			b.AddTx(sign(b, vm.DelegationAddress, delegationABI, "undelegate", validator, amount))
+		case 3:
+			b.OffsetTime(freezePeriod.Int64())
+			b.SetCoinbase(validator)
+			b.AddTx(sign(b, vm.DelegationAddress, delegationABI, "releaseDelegations", validator))
+		default:
+			b.SetCoinbase(validator)
+		}
+	})
+	for i, block := range receipts {
+		for _, receipt := range block {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				t.Fatalf("block %d: transaction failed", i+1)
+			}
+		}
+	}
+
+	first := newBlockCaller(t, gen.Config, db, blocks[0])
+	if !vm.DelegationInstalled(first.statedb) {
+		t.Fatal("delegation contract not installed")
+	}
+
+	// the delegator shares the reward of the block in which it delegated
+	delegated := newBlockCaller(t, gen.Config, db, blocks[1])
+	deposit := new(big.Int).Mul(new(big.Int).SetUint64(consensusOpts.BaseDeposit), big.NewInt(params.Kcoin))
+	share := new(big.Int).Mul(konsensus.AndromedaBlockReward, amount)
+	share.Div(share, new(big.Int).Add(deposit, amount))
+	if have, want := delegated.statedb.GetBalance(delegator), new(big.Int).Add(balance, share); have.Cmp(want) != 0 {
+		t.Fatalf("delegator balance mismatch: have %v, want %v", have, want)
+	}
+	if have, want := delegated.tokenBalance(t, delegator), new(big.Int).Sub(tokens, amount); have.Cmp(want) != 0 {
+		t.Fatalf("delegator tokens mismatch: have %v, want %v", have, want)
+	}
+
+	// the undelegated tokens are released past the freeze period
+	released := newBlockCaller(t, gen.Config, db, blocks[3])
+	if have := released.tokenBalance(t, delegator); have.Cmp(tokens) != 0 {
+		t.Fatalf("delegator tokens mismatch: have %v, want %v", have, tokens)
+	}
+	if have, want := released.statedb.GetBalance(delegator), new(big.Int).Add(balance, share); have.Cmp(want) != 0 {
+		t.Fatalf("delegator balance mismatch: have %v, want %v", have, want)
+	}
+}
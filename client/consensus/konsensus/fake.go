@@ -45,6 +45,10 @@ func (fk *FakeKonsensus) Finalize(chain consensus.ChainReader, header *types.Hea
 	return types.NewBlock(header, txs, receipts, commit), nil
 }
 
+func (fk *FakeKonsensus) VerifyBlockCommit(chain consensus.ChainReader, block *types.Block, state *state.StateDB) error {
+	return nil
+}
+
 func (fk *FakeKonsensus) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan struct{}) (*types.Block, error) {
 	return nil, nil
 }
@@ -48,7 +48,11 @@ func (kss *Konsensus) Prepare(chain consensus.ChainReader, header *types.Header)
 }
 
 func (kss *Konsensus) Finalize(chain consensus.ChainReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, commit *types.Commit, receipts []*types.Receipt) (*types.Block, error) {
-	if err := AccumulateRewards(state, header); err != nil {
+	InstallDelegation(chain.Config(), state, header)
+	if err := AccumulateRewards(chain.Config(), state, header); err != nil {
+		return nil, err
+	}
+	if err := RecordUptime(chain, state, header, commit); err != nil {
 		return nil, err
 	}
 
@@ -59,16 +63,6 @@ func (kss *Konsensus) Finalize(chain consensus.ChainReader, header *types.Header
 	return types.NewBlock(header, txs, receipts, commit), nil
 }
 
-func AccumulateRewards(state *state.StateDB, header *types.Header) error {
-	blockReward := AndromedaBlockReward
-
-	// accumulate the rewards for the validator
-	reward := new(big.Int).Set(blockReward)
-	state.AddBalance(header.Coinbase, reward)
-
-	return nil
-}
-
 func (kss *Konsensus) Seal(chain consensus.ChainReader, block *types.Block, stop <-chan struct{}) (*types.Block, error) {
 	return nil, nil
 }
@@ -0,0 +1,126 @@
+package konsensus
+
+import (
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/kns"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// rewardShare is the part of a block reward credited to an account.
+type rewardShare struct {
+	account common.Address
+	amount  *big.Int
+}
+
+// AccumulateRewards credits the block reward to the coinbase, the account of
+// the proposer that holds its deposit. The consensus key that signed the
+// proposal and votes isn't rewarded.
+//
+// Since the delegated staking fork, the reward is shared with the delegators
+// of the proposer in proportion to their stake, minus the commission kept by
+// the proposer.
+func AccumulateRewards(config *params.ChainConfig, state *state.StateDB, header *types.Header) error {
+	reward := new(big.Int).Set(AndromedaBlockReward)
+
+	if config == nil || !config.IsDelegatedStaking(header.Number) {
+		state.AddBalance(header.Coinbase, reward)
+		return nil
+	}
+
+	stake, err := proposerStake(config, state, header)
+	if err != nil {
+		log.Warn("Failed to retrieve the proposer stake", "proposer", header.Coinbase, "err", err)
+		state.AddBalance(header.Coinbase, reward)
+		return nil
+	}
+	for _, share := range splitReward(header.Coinbase, reward, stake) {
+		state.AddBalance(share.account, share.amount)
+	}
+
+	return nil
+}
+
+// proposerStake returns the stake of the block proposer registered in the
+// validator manager and in the delegation contract.
+func proposerStake(config *params.ChainConfig, state *state.StateDB, header *types.Header) (*consensus.Stake, error) {
+	caller := &stateCaller{config: config, header: header, statedb: state}
+
+	addr, err := kns.GetAddressFromDomain(params.KNSDomains[params.ValidatorMgrDomain].FullDomain(), caller)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := consensus.NewValidatorMgrCaller(addr, caller)
+	if err != nil {
+		return nil, err
+	}
+	delegation, err := consensus.NewDelegationCaller(vm.DelegationAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	return consensus.GetStake(manager, delegation, header.Coinbase)
+}
+
+// InstallDelegation installs the delegation contract of the validator manager
+// and of the mining token registered in the KNS, from the delegated staking
+// fork on. The contract is installed at the end of the first block of the fork.
+func InstallDelegation(config *params.ChainConfig, state *state.StateDB, header *types.Header) {
+	if config == nil || !config.IsDelegatedStaking(header.Number) || vm.DelegationInstalled(state) {
+		return
+	}
+	caller := &stateCaller{config: config, header: header, statedb: state}
+
+	manager, err := kns.GetAddressFromDomain(params.KNSDomains[params.ValidatorMgrDomain].FullDomain(), caller)
+	if err != nil || manager == (common.Address{}) {
+		log.Warn("Failed to resolve the validator manager of the delegations", "number", header.Number, "err", err)
+		return
+	}
+	token, err := kns.GetAddressFromDomain(params.KNSDomains[params.MiningTokenDomain].FullDomain(), caller)
+	if err != nil || token == (common.Address{}) {
+		log.Warn("Failed to resolve the mining token of the delegations", "number", header.Number, "err", err)
+		return
+	}
+	vm.InstallDelegation(state, manager, token)
+}
+
+// splitReward splits the reward of the proposer between itself and its
+// delegators. The delegators share is proportional to the tokens delegated
+// to the proposer and is split between them pro rata, after the commission of
+// the proposer. The rounding remainder goes to the proposer.
+func splitReward(proposer common.Address, reward *big.Int, stake *consensus.Stake) []rewardShare {
+	total := new(big.Int).Add(stake.Deposit, stake.Delegated)
+	if stake.Delegated.Sign() <= 0 || total.Sign() <= 0 {
+		return []rewardShare{{account: proposer, amount: reward}}
+	}
+
+	rate := stake.CommissionRate
+	if rate.Cmp(big.NewInt(consensus.MaxCommissionRate)) > 0 {
+		rate = big.NewInt(consensus.MaxCommissionRate)
+	}
+
+	delegatorsShare := new(big.Int).Mul(reward, stake.Delegated)
+	delegatorsShare.Div(delegatorsShare, total)
+	commission := new(big.Int).Mul(delegatorsShare, rate)
+	commission.Div(commission, big.NewInt(consensus.MaxCommissionRate))
+	distributed := new(big.Int).Sub(delegatorsShare, commission)
+
+	shares := make([]rewardShare, 0, len(stake.Delegators)+1)
+	paid := new(big.Int)
+	for _, delegator := range stake.Delegators {
+		amount := new(big.Int).Mul(distributed, delegator.Amount)
+		amount.Div(amount, stake.Delegated)
+		if amount.Sign() == 0 {
+			continue
+		}
+		shares = append(shares, rewardShare{account: delegator.Address, amount: amount})
+		paid.Add(paid, amount)
+	}
+
+	return append([]rewardShare{{account: proposer, amount: new(big.Int).Sub(reward, paid)}}, shares...)
+}
@@ -0,0 +1,119 @@
+package konsensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+func TestSplitReward(t *testing.T) {
+	var (
+		proposer = common.HexToAddress("0x01")
+		alice    = common.HexToAddress("0x02")
+		bob      = common.HexToAddress("0x03")
+	)
+
+	tests := []struct {
+		name   string
+		reward int64
+		stake  *consensus.Stake
+		want   map[common.Address]int64
+	}{
+		{
+			name:   "no delegators",
+			reward: 1000,
+			stake:  &consensus.Stake{Deposit: big.NewInt(100), Delegated: big.NewInt(0), CommissionRate: big.NewInt(0)},
+			want:   map[common.Address]int64{proposer: 1000},
+		},
+		{
+			name:   "pro rata",
+			reward: 1000,
+			stake: &consensus.Stake{
+				Deposit:        big.NewInt(200),
+				Delegated:      big.NewInt(200),
+				CommissionRate: big.NewInt(0),
+				Delegators: []*consensus.Delegator{
+					{Address: alice, Amount: big.NewInt(150)},
+					{Address: bob, Amount: big.NewInt(50)},
+				},
+			},
+			want: map[common.Address]int64{proposer: 500, alice: 375, bob: 125},
+		},
+		{
+			name:   "commission",
+			reward: 1000,
+			stake: &consensus.Stake{
+				Deposit:        big.NewInt(100),
+				Delegated:      big.NewInt(300),
+				CommissionRate: big.NewInt(1000),
+				Delegators: []*consensus.Delegator{
+					{Address: alice, Amount: big.NewInt(300)},
+				},
+			},
+			want: map[common.Address]int64{proposer: 325, alice: 675},
+		},
+		{
+			name:   "rounding remainder",
+			reward: 10,
+			stake: &consensus.Stake{
+				Deposit:        big.NewInt(0),
+				Delegated:      big.NewInt(3),
+				CommissionRate: big.NewInt(0),
+				Delegators: []*consensus.Delegator{
+					{Address: alice, Amount: big.NewInt(1)},
+					{Address: bob, Amount: big.NewInt(2)},
+				},
+			},
+			want: map[common.Address]int64{proposer: 1, alice: 3, bob: 6},
+		},
+	}
+
+	for _, tt := range tests {
+		shares := splitReward(proposer, big.NewInt(tt.reward), tt.stake)
+
+		total := new(big.Int)
+		have := make(map[common.Address]int64)
+		for _, share := range shares {
+			have[share.account] = share.amount.Int64()
+			total.Add(total, share.amount)
+		}
+		if total.Int64() != tt.reward {
+			t.Errorf("%s: total mismatch: have %v, want %v", tt.name, total, tt.reward)
+		}
+		for account, amount := range tt.want {
+			if have[account] != amount {
+				t.Errorf("%s: share mismatch for %x: have %v, want %v", tt.name, account, have[account], amount)
+			}
+		}
+	}
+}
+
+func TestAccumulateRewardsBeforeDelegatedStaking(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+	header := &types.Header{Number: big.NewInt(10), Coinbase: common.HexToAddress("0x01")}
+
+	config := *params.TestChainConfig
+	config.DelegatedStakingBlock = big.NewInt(100)
+	if err := AccumulateRewards(&config, statedb, header); err != nil {
+		t.Fatal(err)
+	}
+	if balance := statedb.GetBalance(header.Coinbase); balance.Cmp(AndromedaBlockReward) != 0 {
+		t.Fatalf("coinbase balance mismatch: have %v, want %v", balance, AndromedaBlockReward)
+	}
+
+	// without a validator manager the proposer keeps the whole reward
+	config.DelegatedStakingBlock = big.NewInt(0)
+	if err := AccumulateRewards(&config, statedb, header); err != nil {
+		t.Fatal(err)
+	}
+	want := new(big.Int).Mul(AndromedaBlockReward, big.NewInt(2))
+	if balance := statedb.GetBalance(header.Coinbase); balance.Cmp(want) != 0 {
+		t.Fatalf("coinbase balance mismatch: have %v, want %v", balance, want)
+	}
+}
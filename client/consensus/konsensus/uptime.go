@@ -0,0 +1,136 @@
+package konsensus
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+var errCommitParentMismatch = errors.New("commit doesn't commit the parent block")
+
+// RecordUptime records which voters signed the commit of the parent block
+// included in the header, from the uptime fork on, and jails the voters that
+// missed too many blocks of the uptime window. The voters of the parent block
+// are kept in the uptime contract by the finalisation of its own parent, so
+// the block can be processed from the state of its parent alone, as after a
+// fast sync or a pruning. The uptime of the first commits after the fork isn't
+// recorded, as their voters weren't kept.
+//
+// Only the commits signed by more than two thirds of the voters are recorded,
+// so that a block without a valid commit doesn't count as missed by every
+// voter.
+func RecordUptime(chain consensus.ChainReader, state *state.StateDB, header *types.Header, commit *types.Commit) error {
+	config := chain.Config()
+	if config == nil || !config.IsUptime(header.Number) {
+		return nil
+	}
+
+	if commit != nil {
+		if err := recordCommit(config, state, header, commit); err != nil {
+			log.Debug("Skipping the uptime of an invalid commit", "number", header.Number, "err", err)
+		}
+	}
+
+	// the voters of the next block are elected from the state of this block,
+	// including the validators jailed above
+	voters, err := stateVoters(config, state, header)
+	if err != nil {
+		return err
+	}
+	addrs := make([]common.Address, voters.Len())
+	keys := make([]common.Address, voters.Len())
+	for i := range addrs {
+		addrs[i], keys[i] = voters.At(i).Address(), voters.At(i).ConsensusKey()
+	}
+	vm.SetUptimeVoters(state, new(big.Int).Add(header.Number, common.Big1), addrs, keys)
+	return nil
+}
+
+// recordCommit records the signers of the commit of the parent block of the
+// header among its voters.
+func recordCommit(config *params.ChainConfig, state *state.StateDB, header *types.Header, commit *types.Commit) error {
+	voters, err := parentVoters(state, header)
+	if err != nil {
+		return err
+	}
+	if voters == nil {
+		return nil
+	}
+	signed, err := commitSigners(types.NewAndromedaSigner(config.ChainID), voters, header, commit)
+	if err != nil {
+		return err
+	}
+
+	addrs := make([]common.Address, voters.Len())
+	count := 0
+	for i := range addrs {
+		addrs[i] = voters.At(i).Address()
+		if signed[i] {
+			count++
+		}
+	}
+	if !hasMajority(count, voters.Len()) {
+		log.Debug("Skipping the uptime of a commit without majority", "number", header.Number, "signers", count, "voters", voters.Len())
+		return nil
+	}
+
+	for _, jailed := range vm.RecordUptime(state, config.Konsensus, header.Number, addrs, signed) {
+		log.Info("Jailed validator for missing blocks", "number", header.Number, "validator", jailed)
+	}
+	return nil
+}
+
+// parentVoters returns the voters of the parent block of the header kept in
+// the uptime contract, nil if they weren't kept.
+func parentVoters(state *state.StateDB, header *types.Header) (types.Voters, error) {
+	addrs, keys := vm.UptimeVoters(state, new(big.Int).Sub(header.Number, common.Big1))
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	list := make([]*types.Voter, len(addrs))
+	for i := range addrs {
+		list[i] = types.NewVoterWithConsensusKey(addrs[i], keys[i], new(big.Int), new(big.Int))
+	}
+	return types.NewVoters(list)
+}
+
+// commitSigners returns whether each voter signed the commit of the parent
+// block of the header.
+func commitSigners(signer types.Signer, voters types.Voters, header *types.Header, commit *types.Commit) ([]bool, error) {
+	first := commit.First()
+	if first == nil {
+		return nil, errMissingFirstPreCommit
+	}
+	if first.BlockHash() != header.ParentHash {
+		return nil, errCommitParentMismatch
+	}
+
+	signed := make([]bool, voters.Len())
+	// the aggregate signature was verified with the block commit, against
+	// the aggregate keys of the voters
+	if agg := commit.Aggregate(); agg != nil {
+		if !agg.ValidBitmap(voters.Len()) {
+			return nil, types.ErrInvalidSignerBitmap
+		}
+		for i := range signed {
+			signed[i] = agg.Signed(i)
+		}
+		return signed, nil
+	}
+
+	seen, err := preCommitSigners(signer, voters, first, commit.Commits())
+	if err != nil {
+		return nil, err
+	}
+	for i := range signed {
+		_, signed[i] = seen[voters.At(i).Address()]
+	}
+	return signed, nil
+}
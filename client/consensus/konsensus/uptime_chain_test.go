@@ -0,0 +1,191 @@
+package konsensus_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/knode/genesis"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+func TestRecordUptimeChain(t *testing.T) {
+	validatorKey, _ := crypto.GenerateKey()
+	candidateKey, _ := crypto.GenerateKey()
+	var (
+		validator = crypto.PubkeyToAddress(validatorKey.PublicKey)
+		candidate = crypto.PubkeyToAddress(candidateKey.PublicKey)
+	)
+	// the candidate holds enough tokens to register as a validator
+	opts := genesis.Networks["kusd"][genesis.TestNetwork]
+	consensusOpts := *opts.Consensus
+	consensusOpts.Validators = []genesis.Validator{{Address: validator.Hex(), Deposit: consensusOpts.BaseDeposit}}
+	tokenOpts := *consensusOpts.MiningToken
+	tokenOpts.Holders = []genesis.TokenHolder{
+		{Address: validator.Hex(), NumTokens: consensusOpts.BaseDeposit},
+		{Address: candidate.Hex(), NumTokens: consensusOpts.BaseDeposit},
+	}
+	consensusOpts.MiningToken = &tokenOpts
+	opts.Consensus = &consensusOpts
+	gen, err := genesis.Generate(opts)
+	if err != nil {
+		t.Fatalf("failed to generate the genesis: %v", err)
+	}
+	gen.Config.UptimeBlock = big.NewInt(0)
+
+	var (
+		db      = kcoindb.NewMemDatabase()
+		parent  = gen.MustCommit(db)
+		engine  = konsensus.New(&params.KonsensusConfig{})
+		signer  = types.NewAndromedaSigner(gen.Config.ChainID)
+		root    = newBlockCaller(t, gen.Config, db, parent)
+		token   = root.resolve(t, params.MiningTokenDomain)
+		manager = root.resolve(t, params.ValidatorMgrDomain)
+		deposit = new(big.Int).Mul(new(big.Int).SetUint64(consensusOpts.BaseDeposit), big.NewInt(params.Kcoin))
+	)
+	tokenABI, _ := abi.JSON(strings.NewReader(consensus.MiningTokenABI))
+	input, err := tokenABI.Pack("transfer", manager, deposit, consensus.DefaultData, consensus.RegistrationHandler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	register, err := types.SignTx(types.NewTransaction(0, token, new(big.Int), 500000, new(big.Int), input), signer, candidateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the candidate registers in the block carrying the commit of the first
+	// block, which it didn't vote on. The voters of the first block weren't
+	// kept, so the first recorded commit is the commit of the second block.
+	blocks, receipts := core.GenerateChain(gen.Config, parent, engine, db, 3, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(validator)
+		if i == 0 {
+			return
+		}
+		first := signedPreCommit(t, signer, b.PrevBlock(-1), validatorKey, nil)
+		b.SetCommit(&types.Commit{PreCommits: types.Votes{first}, FirstPreCommit: first})
+		if i == 1 {
+			b.AddTx(register)
+		}
+	})
+	if len(receipts[1]) != 1 || receipts[1][0].Status != types.ReceiptStatusSuccessful {
+		t.Fatal("registration failed")
+	}
+
+	for i, want := range []int64{0, 0, 1} {
+		caller := newBlockCaller(t, gen.Config, db, blocks[i])
+		managerCaller, err := consensus.NewValidatorMgrCaller(manager, caller)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if registered, err := managerCaller.IsValidator(&bind.CallOpts{}, candidate); err != nil || registered != (i > 0) {
+			t.Fatalf("block %d: candidate registration mismatch: have %v, %v", i+1, registered, err)
+		}
+
+		uptime, err := consensus.NewValidatorUptimeCaller(vm.UptimeAddress, caller)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record, err := uptime.GetUptime(&bind.CallOpts{}, validator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.Blocks.Int64() != want || record.Missed.Sign() != 0 {
+			t.Fatalf("block %d: uptime mismatch: have %v blocks (%v missed), want %d blocks", i+1, record.Blocks, record.Missed, want)
+		}
+	}
+}
+
+// Tests that the block following the pivot block of a fast sync, whose parent
+// block was elected from a state the node doesn't hold, records its uptime.
+func TestRecordUptimeAfterSyncPivot(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	var (
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		gen     = newCommitTestGenesis(t, addr)
+		db      = kcoindb.NewMemDatabase()
+		genesis = gen.MustCommit(db)
+		engine  = konsensus.New(&params.KonsensusConfig{})
+		signer  = types.NewAndromedaSigner(gen.Config.ChainID)
+	)
+	gen.Config.UptimeBlock = big.NewInt(0)
+
+	blocks, receipts := core.GenerateChain(gen.Config, genesis, engine, db, 5, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(addr)
+		if i > 0 {
+			first := signedPreCommit(t, signer, b.PrevBlock(-1), key, nil)
+			b.SetCommit(&types.Commit{PreCommits: types.Votes{first}, FirstPreCommit: first})
+		}
+	})
+	pivot := blocks[2]
+
+	// the synced node only holds the state of the pivot block
+	syncdb := kcoindb.NewMemDatabase()
+	gen.MustCommit(syncdb)
+	pivotState, err := state.New(pivot.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for it := state.NewNodeIterator(pivotState); it.Next(); {
+		if it.Hash == (common.Hash{}) {
+			continue
+		}
+		node, err := db.Get(it.Hash.Bytes())
+		if err != nil {
+			t.Fatalf("failed to retrieve state node %x: %v", it.Hash, err)
+		}
+		syncdb.Put(it.Hash.Bytes(), node)
+	}
+
+	chain, err := core.NewBlockChain(syncdb, nil, gen.Config, engine, vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+
+	headers := make([]*types.Header, 3)
+	for i, block := range blocks[:3] {
+		headers[i] = block.Header()
+	}
+	if _, err := chain.InsertHeaderChain(headers, 1); err != nil {
+		t.Fatalf("failed to insert the headers: %v", err)
+	}
+	if _, err := chain.InsertReceiptChain(blocks[:3], receipts[:3]); err != nil {
+		t.Fatalf("failed to insert the receipts: %v", err)
+	}
+	if err := chain.FastSyncCommitHead(pivot.Hash()); err != nil {
+		t.Fatalf("failed to commit the pivot block: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks[3:]); err != nil {
+		t.Fatalf("failed to import the blocks following the pivot: %v", err)
+	}
+
+	if head := chain.CurrentBlock().Hash(); head != blocks[4].Hash() {
+		t.Fatalf("head mismatch: have %x, want %x", head, blocks[4].Hash())
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uptime, err := consensus.NewValidatorUptimeCaller(vm.UptimeAddress, &blockCaller{config: gen.Config, block: blocks[4], statedb: statedb})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := uptime.GetUptime(&bind.CallOpts{}, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Blocks.Int64() != 3 || record.Missed.Sign() != 0 {
+		t.Fatalf("uptime mismatch: have %v blocks (%v missed), want 3 blocks", record.Blocks, record.Missed)
+	}
+}
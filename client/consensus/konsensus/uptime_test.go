@@ -0,0 +1,165 @@
+package konsensus
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+func TestCommitSigners(t *testing.T) {
+	signer := types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+	voters, plain, precommits := newTestCommit(t, 4, 3)
+	header := &types.Header{Number: big.NewInt(11), ParentHash: common.HexToHash("0x01")}
+	want := []bool{true, true, true, false}
+
+	legacy := &types.Commit{PreCommits: plain, FirstPreCommit: plain[0]}
+	signed, err := commitSigners(signer, voters, header, legacy)
+	if err != nil {
+		t.Fatalf("legacy commit: %v", err)
+	}
+	for i := range want {
+		if signed[i] != want[i] {
+			t.Fatalf("legacy signer %d mismatch: have %v, want %v", i, signed[i], want[i])
+		}
+	}
+
+	aggregated, err := types.NewAggregateCommit(voters, signer, plain[0], precommits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed, err = commitSigners(signer, voters, header, aggregated); err != nil {
+		t.Fatalf("aggregated commit: %v", err)
+	}
+	for i := range want {
+		if signed[i] != want[i] {
+			t.Fatalf("aggregated signer %d mismatch: have %v, want %v", i, signed[i], want[i])
+		}
+	}
+
+	// the commit must be the commit of the parent block
+	other := &types.Header{Number: big.NewInt(11), ParentHash: common.HexToHash("0x02")}
+	if _, err := commitSigners(signer, voters, other, legacy); err != errCommitParentMismatch {
+		t.Fatalf("error mismatch: have %v, want %v", err, errCommitParentMismatch)
+	}
+}
+
+func TestRecordUptime(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+		config     = &params.KonsensusConfig{UptimeWindow: 10, MaxMissedRate: 3000, JailDuration: 5}
+		alice      = common.HexToAddress("0x01")
+		bob        = common.HexToAddress("0x02")
+		voters     = []common.Address{alice, bob}
+		number     = int64(0)
+	)
+	record := func(signed ...bool) []common.Address {
+		number++
+		return vm.RecordUptime(statedb, config, big.NewInt(number), voters, signed)
+	}
+
+	// the missed blocks leave the window as new blocks are signed
+	for i := 0; i < 3; i++ {
+		record(true, false)
+	}
+	for i := 0; i < 7; i++ {
+		record(true, true)
+	}
+	for i := 0; i < 3; i++ {
+		if jailed := record(true, false); len(jailed) != 0 {
+			t.Fatalf("jailed validators within the missed blocks limit: %v", jailed)
+		}
+	}
+
+	// a fourth missed block within the window jails the validator
+	jailed := record(true, false)
+	if len(jailed) != 1 || jailed[0] != bob {
+		t.Fatalf("jailed validators mismatch: have %v, want %v", jailed, []common.Address{bob})
+	}
+	if !vm.IsJailed(statedb, bob) || vm.IsJailed(statedb, alice) {
+		t.Fatal("only bob should be jailed")
+	}
+	if vm.JailChecksum(statedb) == (common.Hash{}) {
+		t.Fatal("jail checksum not updated")
+	}
+
+	// the last active voter is never jailed
+	voters = []common.Address{alice}
+	for i := 0; i < 10; i++ {
+		if jailed := record(false); len(jailed) != 0 {
+			t.Fatalf("jailed the last voter: %v", jailed)
+		}
+	}
+
+	// the uptime account survives the removal of the empty accounts
+	statedb.IntermediateRoot(true)
+	if !vm.IsJailed(statedb, bob) {
+		t.Fatal("jail record removed with the empty accounts")
+	}
+}
+
+func TestUnjail(t *testing.T) {
+	var (
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+		config     = *params.TestChainConfig
+		bob        = common.HexToAddress("0x02")
+		alice      = common.HexToAddress("0x01")
+	)
+	config.Konsensus = &params.KonsensusConfig{UptimeWindow: 10, MaxMissedRate: 1000, JailDuration: 5}
+
+	// bob misses two blocks out of a window of 10 and is jailed until block 7
+	vm.RecordUptime(statedb, config.Konsensus, big.NewInt(1), []common.Address{alice, bob}, []bool{true, false})
+	vm.RecordUptime(statedb, config.Konsensus, big.NewInt(2), []common.Address{alice, bob}, []bool{true, false})
+	if !vm.IsJailed(statedb, bob) {
+		t.Fatal("bob should be jailed")
+	}
+	checksum := vm.JailChecksum(statedb)
+
+	uptimeABI, err := abi.JSON(strings.NewReader(vm.ValidatorUptimeABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := uptimeABI.Pack("unjail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unjail := func(caller common.Address, number int64) error {
+		context := vm.Context{
+			CanTransfer: canTransfer,
+			Transfer:    transfer,
+			BlockNumber: big.NewInt(number),
+			Time:        new(big.Int),
+			Difficulty:  new(big.Int),
+			GasPrice:    new(big.Int),
+		}
+		evm := vm.NewEVM(context, statedb, &config, vm.Config{})
+		_, _, err := evm.Call(vm.AccountRef(caller), vm.UptimeAddress, input, params.UptimeUnjailGas, new(big.Int))
+		return err
+	}
+
+	if err := unjail(bob, 6); err == nil {
+		t.Fatal("unjailed before the end of the jail duration")
+	}
+	if err := unjail(alice, 7); err == nil {
+		t.Fatal("unjailed a validator that isn't jailed")
+	}
+	if err := unjail(bob, 7); err != nil {
+		t.Fatalf("failed to unjail: %v", err)
+	}
+	if vm.IsJailed(statedb, bob) {
+		t.Fatal("bob should be unjailed")
+	}
+	if vm.JailChecksum(statedb) == checksum {
+		t.Fatal("jail checksum not updated")
+	}
+	if logs := statedb.Logs(); len(logs) != 1 || logs[0].Topics[1] != bob.Hash() {
+		t.Fatalf("unjailed log mismatch: %v", logs)
+	}
+}
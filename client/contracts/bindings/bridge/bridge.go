@@ -0,0 +1,32 @@
+package bridge
+
+import (
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+)
+
+// The bridge contracts are native contracts of the client, so the bindings
+// are generated from the ABI of the contracts only.
+//go:generate ../../../build/bin/abigen -abi build/LightClient.abi -pkg bridge -type LightClient -out ./gen_lightclient.go
+//go:generate ../../../build/bin/abigen -abi build/BridgeToken.abi -pkg bridge -type BridgeToken -out ./gen_token.go
+
+// Bridge is a gateway to the contracts of the bridge to a remote network
+type Bridge struct {
+	LightClient *LightClient
+	Token       *BridgeToken
+}
+
+// Bind returns a binding to the bridge contracts
+func Bind(contractBackend bind.ContractBackend) (*Bridge, error) {
+	lightClient, err := NewLightClient(vm.BridgeLightClientAddress, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := NewBridgeToken(vm.BridgeTokenAddress, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bridge{LightClient: lightClient, Token: token}, nil
+}
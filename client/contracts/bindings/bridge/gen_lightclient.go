@@ -0,0 +1,420 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bridge
+
+import (
+	"math/big"
+	"strings"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/event"
+)
+
+// LightClientABI is the input ABI used to generate the binding from.
+const LightClientABI = "[{\"constant\":false,\"inputs\":[{\"name\":\"proof\",\"type\":\"bytes\"}],\"name\":\"submitHeader\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"latestNumber\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"trustedValidatorsHash\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"number\",\"type\":\"uint256\"}],\"name\":\"receiptsRoot\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"number\",\"type\":\"uint256\"}],\"name\":\"blockHash\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"number\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"hash\",\"type\":\"bytes32\"},{\"indexed\":false,\"name\":\"validatorsHash\",\"type\":\"bytes32\"}],\"name\":\"HeaderSubmitted\",\"type\":\"event\"}]"
+
+// LightClient is an auto generated Go binding around a Kowala contract.
+type LightClient struct {
+	LightClientCaller     // Read-only binding to the contract
+	LightClientTransactor // Write-only binding to the contract
+	LightClientFilterer   // Log filterer for contract events
+}
+
+// LightClientCaller is an auto generated read-only Go binding around a Kowala contract.
+type LightClientCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LightClientTransactor is an auto generated write-only Go binding around a Kowala contract.
+type LightClientTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LightClientFilterer is an auto generated log filtering Go binding around a Kowala contract events.
+type LightClientFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// LightClientSession is an auto generated Go binding around a Kowala contract,
+// with pre-set call and transact options.
+type LightClientSession struct {
+	Contract     *LightClient      // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// LightClientCallerSession is an auto generated read-only Go binding around a Kowala contract,
+// with pre-set call options.
+type LightClientCallerSession struct {
+	Contract *LightClientCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts      // Call options to use throughout this session
+}
+
+// LightClientTransactorSession is an auto generated write-only Go binding around a Kowala contract,
+// with pre-set transact options.
+type LightClientTransactorSession struct {
+	Contract     *LightClientTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts      // Transaction auth options to use throughout this session
+}
+
+// LightClientRaw is an auto generated low-level Go binding around a Kowala contract.
+type LightClientRaw struct {
+	Contract *LightClient // Generic contract binding to access the raw methods on
+}
+
+// LightClientCallerRaw is an auto generated low-level read-only Go binding around a Kowala contract.
+type LightClientCallerRaw struct {
+	Contract *LightClientCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// LightClientTransactorRaw is an auto generated low-level write-only Go binding around a Kowala contract.
+type LightClientTransactorRaw struct {
+	Contract *LightClientTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewLightClient creates a new instance of LightClient, bound to a specific deployed contract.
+func NewLightClient(address common.Address, backend bind.ContractBackend) (*LightClient, error) {
+	contract, err := bindLightClient(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &LightClient{LightClientCaller: LightClientCaller{contract: contract}, LightClientTransactor: LightClientTransactor{contract: contract}, LightClientFilterer: LightClientFilterer{contract: contract}}, nil
+}
+
+// NewLightClientCaller creates a new read-only instance of LightClient, bound to a specific deployed contract.
+func NewLightClientCaller(address common.Address, caller bind.ContractCaller) (*LightClientCaller, error) {
+	contract, err := bindLightClient(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LightClientCaller{contract: contract}, nil
+}
+
+// NewLightClientTransactor creates a new write-only instance of LightClient, bound to a specific deployed contract.
+func NewLightClientTransactor(address common.Address, transactor bind.ContractTransactor) (*LightClientTransactor, error) {
+	contract, err := bindLightClient(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LightClientTransactor{contract: contract}, nil
+}
+
+// NewLightClientFilterer creates a new log filterer instance of LightClient, bound to a specific deployed contract.
+func NewLightClientFilterer(address common.Address, filterer bind.ContractFilterer) (*LightClientFilterer, error) {
+	contract, err := bindLightClient(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &LightClientFilterer{contract: contract}, nil
+}
+
+// bindLightClient binds a generic wrapper to an already deployed contract.
+func bindLightClient(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(LightClientABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LightClient *LightClientRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _LightClient.Contract.LightClientCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LightClient *LightClientRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LightClient.Contract.LightClientTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LightClient *LightClientRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LightClient.Contract.LightClientTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_LightClient *LightClientCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _LightClient.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_LightClient *LightClientTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _LightClient.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_LightClient *LightClientTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _LightClient.Contract.contract.Transact(opts, method, params...)
+}
+
+// BlockHash is a free data retrieval call binding the contract method 0x85df51fd.
+//
+// Solidity: function blockHash(number uint256) constant returns(bytes32)
+func (_LightClient *LightClientCaller) BlockHash(opts *bind.CallOpts, number *big.Int) ([32]byte, error) {
+	var (
+		ret0 = new([32]byte)
+	)
+	out := ret0
+	err := _LightClient.contract.Call(opts, out, "blockHash", number)
+	return *ret0, err
+}
+
+// BlockHash is a free data retrieval call binding the contract method 0x85df51fd.
+//
+// Solidity: function blockHash(number uint256) constant returns(bytes32)
+func (_LightClient *LightClientSession) BlockHash(number *big.Int) ([32]byte, error) {
+	return _LightClient.Contract.BlockHash(&_LightClient.CallOpts, number)
+}
+
+// BlockHash is a free data retrieval call binding the contract method 0x85df51fd.
+//
+// Solidity: function blockHash(number uint256) constant returns(bytes32)
+func (_LightClient *LightClientCallerSession) BlockHash(number *big.Int) ([32]byte, error) {
+	return _LightClient.Contract.BlockHash(&_LightClient.CallOpts, number)
+}
+
+// LatestNumber is a free data retrieval call binding the contract method 0xdc34b0aa.
+//
+// Solidity: function latestNumber() constant returns(uint256)
+func (_LightClient *LightClientCaller) LatestNumber(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _LightClient.contract.Call(opts, out, "latestNumber")
+	return *ret0, err
+}
+
+// LatestNumber is a free data retrieval call binding the contract method 0xdc34b0aa.
+//
+// Solidity: function latestNumber() constant returns(uint256)
+func (_LightClient *LightClientSession) LatestNumber() (*big.Int, error) {
+	return _LightClient.Contract.LatestNumber(&_LightClient.CallOpts)
+}
+
+// LatestNumber is a free data retrieval call binding the contract method 0xdc34b0aa.
+//
+// Solidity: function latestNumber() constant returns(uint256)
+func (_LightClient *LightClientCallerSession) LatestNumber() (*big.Int, error) {
+	return _LightClient.Contract.LatestNumber(&_LightClient.CallOpts)
+}
+
+// ReceiptsRoot is a free data retrieval call binding the contract method 0x83c464c7.
+//
+// Solidity: function receiptsRoot(number uint256) constant returns(bytes32)
+func (_LightClient *LightClientCaller) ReceiptsRoot(opts *bind.CallOpts, number *big.Int) ([32]byte, error) {
+	var (
+		ret0 = new([32]byte)
+	)
+	out := ret0
+	err := _LightClient.contract.Call(opts, out, "receiptsRoot", number)
+	return *ret0, err
+}
+
+// ReceiptsRoot is a free data retrieval call binding the contract method 0x83c464c7.
+//
+// Solidity: function receiptsRoot(number uint256) constant returns(bytes32)
+func (_LightClient *LightClientSession) ReceiptsRoot(number *big.Int) ([32]byte, error) {
+	return _LightClient.Contract.ReceiptsRoot(&_LightClient.CallOpts, number)
+}
+
+// ReceiptsRoot is a free data retrieval call binding the contract method 0x83c464c7.
+//
+// Solidity: function receiptsRoot(number uint256) constant returns(bytes32)
+func (_LightClient *LightClientCallerSession) ReceiptsRoot(number *big.Int) ([32]byte, error) {
+	return _LightClient.Contract.ReceiptsRoot(&_LightClient.CallOpts, number)
+}
+
+// TrustedValidatorsHash is a free data retrieval call binding the contract method 0xc82f2531.
+//
+// Solidity: function trustedValidatorsHash() constant returns(bytes32)
+func (_LightClient *LightClientCaller) TrustedValidatorsHash(opts *bind.CallOpts) ([32]byte, error) {
+	var (
+		ret0 = new([32]byte)
+	)
+	out := ret0
+	err := _LightClient.contract.Call(opts, out, "trustedValidatorsHash")
+	return *ret0, err
+}
+
+// TrustedValidatorsHash is a free data retrieval call binding the contract method 0xc82f2531.
+//
+// Solidity: function trustedValidatorsHash() constant returns(bytes32)
+func (_LightClient *LightClientSession) TrustedValidatorsHash() ([32]byte, error) {
+	return _LightClient.Contract.TrustedValidatorsHash(&_LightClient.CallOpts)
+}
+
+// TrustedValidatorsHash is a free data retrieval call binding the contract method 0xc82f2531.
+//
+// Solidity: function trustedValidatorsHash() constant returns(bytes32)
+func (_LightClient *LightClientCallerSession) TrustedValidatorsHash() ([32]byte, error) {
+	return _LightClient.Contract.TrustedValidatorsHash(&_LightClient.CallOpts)
+}
+
+// SubmitHeader is a paid mutator transaction binding the contract method 0xc565ba10.
+//
+// Solidity: function submitHeader(proof bytes) returns()
+func (_LightClient *LightClientTransactor) SubmitHeader(opts *bind.TransactOpts, proof []byte) (*types.Transaction, error) {
+	return _LightClient.contract.Transact(opts, "submitHeader", proof)
+}
+
+// SubmitHeader is a paid mutator transaction binding the contract method 0xc565ba10.
+//
+// Solidity: function submitHeader(proof bytes) returns()
+func (_LightClient *LightClientSession) SubmitHeader(proof []byte) (*types.Transaction, error) {
+	return _LightClient.Contract.SubmitHeader(&_LightClient.TransactOpts, proof)
+}
+
+// SubmitHeader is a paid mutator transaction binding the contract method 0xc565ba10.
+//
+// Solidity: function submitHeader(proof bytes) returns()
+func (_LightClient *LightClientTransactorSession) SubmitHeader(proof []byte) (*types.Transaction, error) {
+	return _LightClient.Contract.SubmitHeader(&_LightClient.TransactOpts, proof)
+}
+
+// LightClientHeaderSubmittedIterator is returned from FilterHeaderSubmitted and is used to iterate over the raw logs and unpacked data for HeaderSubmitted events raised by the LightClient contract.
+type LightClientHeaderSubmittedIterator struct {
+	Event *LightClientHeaderSubmitted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *LightClientHeaderSubmittedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(LightClientHeaderSubmitted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(LightClientHeaderSubmitted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *LightClientHeaderSubmittedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *LightClientHeaderSubmittedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// LightClientHeaderSubmitted represents a HeaderSubmitted event raised by the LightClient contract.
+type LightClientHeaderSubmitted struct {
+	Number         *big.Int
+	Hash           [32]byte
+	ValidatorsHash [32]byte
+	Raw            types.Log // Blockchain specific contextual infos
+}
+
+// FilterHeaderSubmitted is a free log retrieval operation binding the contract event 0x8c1e434df408b1b8b302f5560d6ac9a84b3b4646140b2db2dbd22ce203508911.
+//
+// Solidity: e HeaderSubmitted(number indexed uint256, hash bytes32, validatorsHash bytes32)
+func (_LightClient *LightClientFilterer) FilterHeaderSubmitted(opts *bind.FilterOpts, number []*big.Int) (*LightClientHeaderSubmittedIterator, error) {
+
+	var numberRule []interface{}
+	for _, numberItem := range number {
+		numberRule = append(numberRule, numberItem)
+	}
+
+	logs, sub, err := _LightClient.contract.FilterLogs(opts, "HeaderSubmitted", numberRule)
+	if err != nil {
+		return nil, err
+	}
+	return &LightClientHeaderSubmittedIterator{contract: _LightClient.contract, event: "HeaderSubmitted", logs: logs, sub: sub}, nil
+}
+
+// WatchHeaderSubmitted is a free log subscription operation binding the contract event 0x8c1e434df408b1b8b302f5560d6ac9a84b3b4646140b2db2dbd22ce203508911.
+//
+// Solidity: e HeaderSubmitted(number indexed uint256, hash bytes32, validatorsHash bytes32)
+func (_LightClient *LightClientFilterer) WatchHeaderSubmitted(opts *bind.WatchOpts, sink chan<- *LightClientHeaderSubmitted, number []*big.Int) (event.Subscription, error) {
+
+	var numberRule []interface{}
+	for _, numberItem := range number {
+		numberRule = append(numberRule, numberItem)
+	}
+
+	logs, sub, err := _LightClient.contract.WatchLogs(opts, "HeaderSubmitted", numberRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(LightClientHeaderSubmitted)
+				if err := _LightClient.contract.UnpackLog(event, "HeaderSubmitted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
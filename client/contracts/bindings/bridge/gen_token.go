@@ -0,0 +1,891 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bridge
+
+import (
+	"math/big"
+	"strings"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/event"
+)
+
+// BridgeTokenABI is the input ABI used to generate the binding from.
+const BridgeTokenABI = "[{\"constant\":false,\"inputs\":[{\"name\":\"recipient\",\"type\":\"address\"}],\"name\":\"lock\",\"outputs\":[],\"payable\":true,\"stateMutability\":\"payable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"recipient\",\"type\":\"address\"},{\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"burn\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"proof\",\"type\":\"bytes\"}],\"name\":\"claim\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"to\",\"type\":\"address\"},{\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"totalSupply\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"number\",\"type\":\"uint256\"},{\"name\":\"txIndex\",\"type\":\"uint256\"},{\"name\":\"logIndex\",\"type\":\"uint256\"}],\"name\":\"claimed\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"recipient\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Locked\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"sender\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"recipient\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Burned\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"number\",\"type\":\"uint256\"},{\"indexed\":true,\"name\":\"recipient\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Claimed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"}]"
+
+// BridgeToken is an auto generated Go binding around a Kowala contract.
+type BridgeToken struct {
+	BridgeTokenCaller     // Read-only binding to the contract
+	BridgeTokenTransactor // Write-only binding to the contract
+	BridgeTokenFilterer   // Log filterer for contract events
+}
+
+// BridgeTokenCaller is an auto generated read-only Go binding around a Kowala contract.
+type BridgeTokenCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BridgeTokenTransactor is an auto generated write-only Go binding around a Kowala contract.
+type BridgeTokenTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BridgeTokenFilterer is an auto generated log filtering Go binding around a Kowala contract events.
+type BridgeTokenFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BridgeTokenSession is an auto generated Go binding around a Kowala contract,
+// with pre-set call and transact options.
+type BridgeTokenSession struct {
+	Contract     *BridgeToken      // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BridgeTokenCallerSession is an auto generated read-only Go binding around a Kowala contract,
+// with pre-set call options.
+type BridgeTokenCallerSession struct {
+	Contract *BridgeTokenCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts      // Call options to use throughout this session
+}
+
+// BridgeTokenTransactorSession is an auto generated write-only Go binding around a Kowala contract,
+// with pre-set transact options.
+type BridgeTokenTransactorSession struct {
+	Contract     *BridgeTokenTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts      // Transaction auth options to use throughout this session
+}
+
+// BridgeTokenRaw is an auto generated low-level Go binding around a Kowala contract.
+type BridgeTokenRaw struct {
+	Contract *BridgeToken // Generic contract binding to access the raw methods on
+}
+
+// BridgeTokenCallerRaw is an auto generated low-level read-only Go binding around a Kowala contract.
+type BridgeTokenCallerRaw struct {
+	Contract *BridgeTokenCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// BridgeTokenTransactorRaw is an auto generated low-level write-only Go binding around a Kowala contract.
+type BridgeTokenTransactorRaw struct {
+	Contract *BridgeTokenTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewBridgeToken creates a new instance of BridgeToken, bound to a specific deployed contract.
+func NewBridgeToken(address common.Address, backend bind.ContractBackend) (*BridgeToken, error) {
+	contract, err := bindBridgeToken(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeToken{BridgeTokenCaller: BridgeTokenCaller{contract: contract}, BridgeTokenTransactor: BridgeTokenTransactor{contract: contract}, BridgeTokenFilterer: BridgeTokenFilterer{contract: contract}}, nil
+}
+
+// NewBridgeTokenCaller creates a new read-only instance of BridgeToken, bound to a specific deployed contract.
+func NewBridgeTokenCaller(address common.Address, caller bind.ContractCaller) (*BridgeTokenCaller, error) {
+	contract, err := bindBridgeToken(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeTokenCaller{contract: contract}, nil
+}
+
+// NewBridgeTokenTransactor creates a new write-only instance of BridgeToken, bound to a specific deployed contract.
+func NewBridgeTokenTransactor(address common.Address, transactor bind.ContractTransactor) (*BridgeTokenTransactor, error) {
+	contract, err := bindBridgeToken(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeTokenTransactor{contract: contract}, nil
+}
+
+// NewBridgeTokenFilterer creates a new log filterer instance of BridgeToken, bound to a specific deployed contract.
+func NewBridgeTokenFilterer(address common.Address, filterer bind.ContractFilterer) (*BridgeTokenFilterer, error) {
+	contract, err := bindBridgeToken(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeTokenFilterer{contract: contract}, nil
+}
+
+// bindBridgeToken binds a generic wrapper to an already deployed contract.
+func bindBridgeToken(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(BridgeTokenABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_BridgeToken *BridgeTokenRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _BridgeToken.Contract.BridgeTokenCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_BridgeToken *BridgeTokenRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _BridgeToken.Contract.BridgeTokenTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_BridgeToken *BridgeTokenRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _BridgeToken.Contract.BridgeTokenTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_BridgeToken *BridgeTokenCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _BridgeToken.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_BridgeToken *BridgeTokenTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _BridgeToken.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_BridgeToken *BridgeTokenTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _BridgeToken.Contract.contract.Transact(opts, method, params...)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(owner address) constant returns(uint256)
+func (_BridgeToken *BridgeTokenCaller) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _BridgeToken.contract.Call(opts, out, "balanceOf", owner)
+	return *ret0, err
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(owner address) constant returns(uint256)
+func (_BridgeToken *BridgeTokenSession) BalanceOf(owner common.Address) (*big.Int, error) {
+	return _BridgeToken.Contract.BalanceOf(&_BridgeToken.CallOpts, owner)
+}
+
+// BalanceOf is a free data retrieval call binding the contract method 0x70a08231.
+//
+// Solidity: function balanceOf(owner address) constant returns(uint256)
+func (_BridgeToken *BridgeTokenCallerSession) BalanceOf(owner common.Address) (*big.Int, error) {
+	return _BridgeToken.Contract.BalanceOf(&_BridgeToken.CallOpts, owner)
+}
+
+// Claimed is a free data retrieval call binding the contract method 0xb480833b.
+//
+// Solidity: function claimed(number uint256, txIndex uint256, logIndex uint256) constant returns(bool)
+func (_BridgeToken *BridgeTokenCaller) Claimed(opts *bind.CallOpts, number *big.Int, txIndex *big.Int, logIndex *big.Int) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _BridgeToken.contract.Call(opts, out, "claimed", number, txIndex, logIndex)
+	return *ret0, err
+}
+
+// Claimed is a free data retrieval call binding the contract method 0xb480833b.
+//
+// Solidity: function claimed(number uint256, txIndex uint256, logIndex uint256) constant returns(bool)
+func (_BridgeToken *BridgeTokenSession) Claimed(number *big.Int, txIndex *big.Int, logIndex *big.Int) (bool, error) {
+	return _BridgeToken.Contract.Claimed(&_BridgeToken.CallOpts, number, txIndex, logIndex)
+}
+
+// Claimed is a free data retrieval call binding the contract method 0xb480833b.
+//
+// Solidity: function claimed(number uint256, txIndex uint256, logIndex uint256) constant returns(bool)
+func (_BridgeToken *BridgeTokenCallerSession) Claimed(number *big.Int, txIndex *big.Int, logIndex *big.Int) (bool, error) {
+	return _BridgeToken.Contract.Claimed(&_BridgeToken.CallOpts, number, txIndex, logIndex)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() constant returns(uint256)
+func (_BridgeToken *BridgeTokenCaller) TotalSupply(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _BridgeToken.contract.Call(opts, out, "totalSupply")
+	return *ret0, err
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() constant returns(uint256)
+func (_BridgeToken *BridgeTokenSession) TotalSupply() (*big.Int, error) {
+	return _BridgeToken.Contract.TotalSupply(&_BridgeToken.CallOpts)
+}
+
+// TotalSupply is a free data retrieval call binding the contract method 0x18160ddd.
+//
+// Solidity: function totalSupply() constant returns(uint256)
+func (_BridgeToken *BridgeTokenCallerSession) TotalSupply() (*big.Int, error) {
+	return _BridgeToken.Contract.TotalSupply(&_BridgeToken.CallOpts)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0x9dc29fac.
+//
+// Solidity: function burn(recipient address, amount uint256) returns()
+func (_BridgeToken *BridgeTokenTransactor) Burn(opts *bind.TransactOpts, recipient common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _BridgeToken.contract.Transact(opts, "burn", recipient, amount)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0x9dc29fac.
+//
+// Solidity: function burn(recipient address, amount uint256) returns()
+func (_BridgeToken *BridgeTokenSession) Burn(recipient common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Burn(&_BridgeToken.TransactOpts, recipient, amount)
+}
+
+// Burn is a paid mutator transaction binding the contract method 0x9dc29fac.
+//
+// Solidity: function burn(recipient address, amount uint256) returns()
+func (_BridgeToken *BridgeTokenTransactorSession) Burn(recipient common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Burn(&_BridgeToken.TransactOpts, recipient, amount)
+}
+
+// Claim is a paid mutator transaction binding the contract method 0xc63ff8dd.
+//
+// Solidity: function claim(proof bytes) returns()
+func (_BridgeToken *BridgeTokenTransactor) Claim(opts *bind.TransactOpts, proof []byte) (*types.Transaction, error) {
+	return _BridgeToken.contract.Transact(opts, "claim", proof)
+}
+
+// Claim is a paid mutator transaction binding the contract method 0xc63ff8dd.
+//
+// Solidity: function claim(proof bytes) returns()
+func (_BridgeToken *BridgeTokenSession) Claim(proof []byte) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Claim(&_BridgeToken.TransactOpts, proof)
+}
+
+// Claim is a paid mutator transaction binding the contract method 0xc63ff8dd.
+//
+// Solidity: function claim(proof bytes) returns()
+func (_BridgeToken *BridgeTokenTransactorSession) Claim(proof []byte) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Claim(&_BridgeToken.TransactOpts, proof)
+}
+
+// Lock is a paid mutator transaction binding the contract method 0xf435f5a7.
+//
+// Solidity: function lock(recipient address) returns()
+func (_BridgeToken *BridgeTokenTransactor) Lock(opts *bind.TransactOpts, recipient common.Address) (*types.Transaction, error) {
+	return _BridgeToken.contract.Transact(opts, "lock", recipient)
+}
+
+// Lock is a paid mutator transaction binding the contract method 0xf435f5a7.
+//
+// Solidity: function lock(recipient address) returns()
+func (_BridgeToken *BridgeTokenSession) Lock(recipient common.Address) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Lock(&_BridgeToken.TransactOpts, recipient)
+}
+
+// Lock is a paid mutator transaction binding the contract method 0xf435f5a7.
+//
+// Solidity: function lock(recipient address) returns()
+func (_BridgeToken *BridgeTokenTransactorSession) Lock(recipient common.Address) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Lock(&_BridgeToken.TransactOpts, recipient)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(to address, value uint256) returns(bool)
+func (_BridgeToken *BridgeTokenTransactor) Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _BridgeToken.contract.Transact(opts, "transfer", to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(to address, value uint256) returns(bool)
+func (_BridgeToken *BridgeTokenSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Transfer(&_BridgeToken.TransactOpts, to, value)
+}
+
+// Transfer is a paid mutator transaction binding the contract method 0xa9059cbb.
+//
+// Solidity: function transfer(to address, value uint256) returns(bool)
+func (_BridgeToken *BridgeTokenTransactorSession) Transfer(to common.Address, value *big.Int) (*types.Transaction, error) {
+	return _BridgeToken.Contract.Transfer(&_BridgeToken.TransactOpts, to, value)
+}
+
+// BridgeTokenBurnedIterator is returned from FilterBurned and is used to iterate over the raw logs and unpacked data for Burned events raised by the BridgeToken contract.
+type BridgeTokenBurnedIterator struct {
+	Event *BridgeTokenBurned // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *BridgeTokenBurnedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(BridgeTokenBurned)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(BridgeTokenBurned)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *BridgeTokenBurnedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *BridgeTokenBurnedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// BridgeTokenBurned represents a Burned event raised by the BridgeToken contract.
+type BridgeTokenBurned struct {
+	Sender    common.Address
+	Recipient common.Address
+	Amount    *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterBurned is a free log retrieval operation binding the contract event 0x6ab368f832c266c8eb942b84fbcaa20aedc24a699d2a05fae2568028733b1d09.
+//
+// Solidity: e Burned(sender indexed address, recipient indexed address, amount uint256)
+func (_BridgeToken *BridgeTokenFilterer) FilterBurned(opts *bind.FilterOpts, sender []common.Address, recipient []common.Address) (*BridgeTokenBurnedIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.FilterLogs(opts, "Burned", senderRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeTokenBurnedIterator{contract: _BridgeToken.contract, event: "Burned", logs: logs, sub: sub}, nil
+}
+
+// WatchBurned is a free log subscription operation binding the contract event 0x6ab368f832c266c8eb942b84fbcaa20aedc24a699d2a05fae2568028733b1d09.
+//
+// Solidity: e Burned(sender indexed address, recipient indexed address, amount uint256)
+func (_BridgeToken *BridgeTokenFilterer) WatchBurned(opts *bind.WatchOpts, sink chan<- *BridgeTokenBurned, sender []common.Address, recipient []common.Address) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.WatchLogs(opts, "Burned", senderRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(BridgeTokenBurned)
+				if err := _BridgeToken.contract.UnpackLog(event, "Burned", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// BridgeTokenClaimedIterator is returned from FilterClaimed and is used to iterate over the raw logs and unpacked data for Claimed events raised by the BridgeToken contract.
+type BridgeTokenClaimedIterator struct {
+	Event *BridgeTokenClaimed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *BridgeTokenClaimedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(BridgeTokenClaimed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(BridgeTokenClaimed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *BridgeTokenClaimedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *BridgeTokenClaimedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// BridgeTokenClaimed represents a Claimed event raised by the BridgeToken contract.
+type BridgeTokenClaimed struct {
+	Number    *big.Int
+	Recipient common.Address
+	Amount    *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterClaimed is a free log retrieval operation binding the contract event 0x4ec90e965519d92681267467f775ada5bd214aa92c0dc93d90a5e880ce9ed026.
+//
+// Solidity: e Claimed(number indexed uint256, recipient indexed address, amount uint256)
+func (_BridgeToken *BridgeTokenFilterer) FilterClaimed(opts *bind.FilterOpts, number []*big.Int, recipient []common.Address) (*BridgeTokenClaimedIterator, error) {
+
+	var numberRule []interface{}
+	for _, numberItem := range number {
+		numberRule = append(numberRule, numberItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.FilterLogs(opts, "Claimed", numberRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeTokenClaimedIterator{contract: _BridgeToken.contract, event: "Claimed", logs: logs, sub: sub}, nil
+}
+
+// WatchClaimed is a free log subscription operation binding the contract event 0x4ec90e965519d92681267467f775ada5bd214aa92c0dc93d90a5e880ce9ed026.
+//
+// Solidity: e Claimed(number indexed uint256, recipient indexed address, amount uint256)
+func (_BridgeToken *BridgeTokenFilterer) WatchClaimed(opts *bind.WatchOpts, sink chan<- *BridgeTokenClaimed, number []*big.Int, recipient []common.Address) (event.Subscription, error) {
+
+	var numberRule []interface{}
+	for _, numberItem := range number {
+		numberRule = append(numberRule, numberItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.WatchLogs(opts, "Claimed", numberRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(BridgeTokenClaimed)
+				if err := _BridgeToken.contract.UnpackLog(event, "Claimed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// BridgeTokenLockedIterator is returned from FilterLocked and is used to iterate over the raw logs and unpacked data for Locked events raised by the BridgeToken contract.
+type BridgeTokenLockedIterator struct {
+	Event *BridgeTokenLocked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *BridgeTokenLockedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(BridgeTokenLocked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(BridgeTokenLocked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *BridgeTokenLockedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *BridgeTokenLockedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// BridgeTokenLocked represents a Locked event raised by the BridgeToken contract.
+type BridgeTokenLocked struct {
+	Sender    common.Address
+	Recipient common.Address
+	Amount    *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterLocked is a free log retrieval operation binding the contract event 0x989eaa915cbb416ea3d6f9a63b1a3de51770c7674b11fe21ecdf76b4e1d13910.
+//
+// Solidity: e Locked(sender indexed address, recipient indexed address, amount uint256)
+func (_BridgeToken *BridgeTokenFilterer) FilterLocked(opts *bind.FilterOpts, sender []common.Address, recipient []common.Address) (*BridgeTokenLockedIterator, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.FilterLogs(opts, "Locked", senderRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeTokenLockedIterator{contract: _BridgeToken.contract, event: "Locked", logs: logs, sub: sub}, nil
+}
+
+// WatchLocked is a free log subscription operation binding the contract event 0x989eaa915cbb416ea3d6f9a63b1a3de51770c7674b11fe21ecdf76b4e1d13910.
+//
+// Solidity: e Locked(sender indexed address, recipient indexed address, amount uint256)
+func (_BridgeToken *BridgeTokenFilterer) WatchLocked(opts *bind.WatchOpts, sink chan<- *BridgeTokenLocked, sender []common.Address, recipient []common.Address) (event.Subscription, error) {
+
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+	var recipientRule []interface{}
+	for _, recipientItem := range recipient {
+		recipientRule = append(recipientRule, recipientItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.WatchLogs(opts, "Locked", senderRule, recipientRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(BridgeTokenLocked)
+				if err := _BridgeToken.contract.UnpackLog(event, "Locked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// BridgeTokenTransferIterator is returned from FilterTransfer and is used to iterate over the raw logs and unpacked data for Transfer events raised by the BridgeToken contract.
+type BridgeTokenTransferIterator struct {
+	Event *BridgeTokenTransfer // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *BridgeTokenTransferIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(BridgeTokenTransfer)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(BridgeTokenTransfer)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *BridgeTokenTransferIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *BridgeTokenTransferIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// BridgeTokenTransfer represents a Transfer event raised by the BridgeToken contract.
+type BridgeTokenTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	Raw   types.Log // Blockchain specific contextual infos
+}
+
+// FilterTransfer is a free log retrieval operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: e Transfer(from indexed address, to indexed address, value uint256)
+func (_BridgeToken *BridgeTokenFilterer) FilterTransfer(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*BridgeTokenTransferIterator, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.FilterLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return &BridgeTokenTransferIterator{contract: _BridgeToken.contract, event: "Transfer", logs: logs, sub: sub}, nil
+}
+
+// WatchTransfer is a free log subscription operation binding the contract event 0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef.
+//
+// Solidity: e Transfer(from indexed address, to indexed address, value uint256)
+func (_BridgeToken *BridgeTokenFilterer) WatchTransfer(opts *bind.WatchOpts, sink chan<- *BridgeTokenTransfer, from []common.Address, to []common.Address) (event.Subscription, error) {
+
+	var fromRule []interface{}
+	for _, fromItem := range from {
+		fromRule = append(fromRule, fromItem)
+	}
+	var toRule []interface{}
+	for _, toItem := range to {
+		toRule = append(toRule, toItem)
+	}
+
+	logs, sub, err := _BridgeToken.contract.WatchLogs(opts, "Transfer", fromRule, toRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(BridgeTokenTransfer)
+				if err := _BridgeToken.contract.UnpackLog(event, "Transfer", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
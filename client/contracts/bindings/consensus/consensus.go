@@ -1,12 +1,14 @@
 package consensus
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 	"sync"
 
+	kowala "github.com/kowala-tech/kcoin/client"
 	"github.com/kowala-tech/kcoin/client/accounts"
 	"github.com/kowala-tech/kcoin/client/accounts/abi"
 	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
@@ -17,6 +19,9 @@ import (
 	"github.com/kowala-tech/kcoin/client/contracts/bindings/ownership"
 	"github.com/kowala-tech/kcoin/client/contracts/bindings/token"
 	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
 	"github.com/kowala-tech/kcoin/client/log"
 	"github.com/kowala-tech/kcoin/client/params"
 )
@@ -26,10 +31,32 @@ import (
 //go:generate solc --allow-paths ., --abi --bin --overwrite -o build zos-lib/=../../truffle/node_modules/zos-lib/ github.com/kowala-tech/kcoin/client/contracts/=../../truffle/contracts openzeppelin-solidity/=../../truffle/node_modules/openzeppelin-solidity/ ../../truffle/contracts/consensus/token/MiningToken.sol
 //go:generate ../../../build/bin/abigen -abi build/MiningToken.abi -bin build/MiningToken.bin -pkg consensus -type MiningToken -out ./gen_mtoken.go
 
-const RegistrationHandler = "registerValidator(address,uint256)"
+// The uptime, aggregate keys, consensus keys and delegation contracts are native
+// contracts of the client, so their bindings are generated from the ABI of the
+// contracts only.
+//go:generate ../../../build/bin/abigen -abi build/ValidatorUptime.abi -pkg consensus -type ValidatorUptime -out ./gen_uptime.go
+//go:generate ../../../build/bin/abigen -abi build/AggregateKeys.abi -pkg consensus -type AggregateKeys -out ./gen_aggregate.go
+//go:generate ../../../build/bin/abigen -abi build/ConsensusKeys.abi -pkg consensus -type ConsensusKeys -out ./gen_keys.go
+//go:generate ../../../build/bin/abigen -abi build/Delegation.abi -pkg consensus -type Delegation -out ./gen_delegation.go
+
+const (
+	RegistrationHandler = "registerValidator(address,uint256)"
+
+	// DelegationHandler delegates tokens to a validator. The transfer data
+	// holds the validator address left padded to 32 bytes.
+	DelegationHandler = "delegate(address,uint256,address)"
+
+	// MaxCommissionRate is the commission rate of a validator keeping the
+	// whole reward of its delegators. The rates are set in basis points.
+	MaxCommissionRate = 10000
+)
 
 var DefaultData = []byte("not_zero")
 
+// ErrDelegationUnavailable is returned by Delegate until the delegation
+// contract is installed.
+var ErrDelegationUnavailable = errors.New("the delegation contract isn't installed yet")
+
 type mUSD struct {
 	*MiningToken
 	chainID *big.Int
@@ -106,6 +133,10 @@ func (tkn *mUSD) MintingFinished() (bool, error) {
 type Consensus struct {
 	manager         *ValidatorMgr
 	managerAddr     common.Address
+	uptime          *ValidatorUptime
+	aggregateKeys   *AggregateKeys
+	consensusKeys   *ConsensusKeys
+	delegation      *Delegation
 	mtoken          token.Token
 	chainID         *big.Int
 	contractBackend bind.ContractBackend
@@ -139,6 +170,26 @@ func Bind(contractBackend bind.ContractBackend, chainID *big.Int) (bindings.Bind
 		return nil, err
 	}
 
+	uptime, err := NewValidatorUptime(vm.UptimeAddress, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregateKeys, err := NewAggregateKeys(vm.AggregateKeysAddress, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	consensusKeys, err := NewConsensusKeys(vm.ConsensusKeysAddress, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation, err := NewDelegation(vm.DelegationAddress, contractBackend)
+	if err != nil {
+		return nil, err
+	}
+
 	mUSD, err := NewMUSD(contractBackend, chainID)
 	if err != nil {
 		return nil, err
@@ -147,6 +198,10 @@ func Bind(contractBackend bind.ContractBackend, chainID *big.Int) (bindings.Bind
 	return &Consensus{
 		manager:         manager,
 		managerAddr:     addr,
+		uptime:          uptime,
+		aggregateKeys:   aggregateKeys,
+		consensusKeys:   consensusKeys,
+		delegation:      delegation,
 		mtoken:          mUSD,
 		chainID:         chainID,
 		contractBackend: contractBackend,
@@ -157,7 +212,7 @@ func Bind(contractBackend bind.ContractBackend, chainID *big.Int) (bindings.Bind
 func (css *Consensus) Join(walletAccount accounts.WalletAccount, deposit *big.Int) (common.Hash, error) {
 	log.Warn(fmt.Sprintf("Joining the network %v with a deposit %v. Account %q",
 		css.chainID.String(), deposit.String(), walletAccount.Account().Address.String()))
-	hash, err := css.mtoken.Transfer(walletAccount, css.managerAddr, deposit, []byte("not_zero"), RegistrationHandler)
+	hash, err := css.mtoken.Transfer(walletAccount, css.managerAddr, deposit, DefaultData, RegistrationHandler)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to transact the deposit: %s", err)
 	}
@@ -187,30 +242,198 @@ func (css *Consensus) RedeemDeposits(walletAccount accounts.WalletAccount) (comm
 	return tx.Hash(), nil
 }
 
+// ValidatorsChecksum returns a checksum changing whenever the validators set
+// changes, including the validators being jailed or unjailed and registering
+// aggregate or consensus keys.
 func (css *Consensus) ValidatorsChecksum() (types.VotersChecksum, error) {
-	return css.manager.ValidatorsChecksum(&bind.CallOpts{})
+	checksum, err := css.manager.ValidatorsChecksum(&bind.CallOpts{})
+	if err != nil {
+		return checksum, err
+	}
+	// the native contracts aren't active before their forks
+	if jails, err := css.uptime.JailChecksum(&bind.CallOpts{}); err == nil && jails != ([32]byte{}) {
+		checksum = crypto.Keccak256Hash(checksum[:], jails[:])
+	}
+	if keys, err := css.aggregateKeys.AggregateKeysChecksum(&bind.CallOpts{}); err == nil && keys != ([32]byte{}) {
+		checksum = crypto.Keccak256Hash(checksum[:], keys[:])
+	}
+	if keys, err := css.consensusKeys.ConsensusKeysChecksum(&bind.CallOpts{}); err == nil && keys != ([32]byte{}) {
+		checksum = crypto.Keccak256Hash(checksum[:], keys[:])
+	}
+	return types.VotersChecksum(checksum), nil
 }
 
+// Validators returns the voters set: the registered validators that are not
+// jailed.
 func (css *Consensus) Validators() (types.Voters, error) {
-	count, err := css.manager.GetValidatorCount(&bind.CallOpts{})
+	return GetVoters(&css.manager.ValidatorMgrCaller, &css.uptime.ValidatorUptimeCaller, &css.aggregateKeys.AggregateKeysCaller, &css.consensusKeys.ConsensusKeysCaller)
+}
+
+// GetVoters returns the validators registered in the given manager, except
+// the validators jailed by the uptime contract, along with their aggregate
+// and consensus keys. A consensus key that is the account of another voter
+// is ignored, so that the voters can't be confused with each other.
+func GetVoters(manager *ValidatorMgrCaller, uptime *ValidatorUptimeCaller, keys *AggregateKeysCaller, consensusKeys *ConsensusKeysCaller) (types.Voters, error) {
+	count, err := manager.GetValidatorCount(&bind.CallOpts{})
 	if err != nil {
 		return nil, err
 	}
 
-	voters := make([]*types.Voter, count.Uint64())
+	validators := make([]struct {
+		Code    common.Address
+		Deposit *big.Int
+	}, 0, count.Uint64())
+	codes := make(map[common.Address]bool)
 	for i := int64(0); i < count.Int64(); i++ {
-		validator, err := css.manager.GetValidatorAtIndex(&bind.CallOpts{}, big.NewInt(i))
+		validator, err := manager.GetValidatorAtIndex(&bind.CallOpts{}, big.NewInt(i))
 		if err != nil {
 			return nil, err
 		}
+		if isJailed(uptime, validator.Code) {
+			continue
+		}
+		validators = append(validators, validator)
+		codes[validator.Code] = true
+	}
+
+	voters := make([]*types.Voter, 0, len(validators))
+	for _, validator := range validators {
+		signer, err := consensusKey(consensusKeys, validator.Code)
+		if err != nil {
+			return nil, err
+		}
+		if signer != validator.Code && codes[signer] {
+			signer = validator.Code
+		}
 
 		weight := big.NewInt(0)
-		voters[i] = types.NewVoter(validator.Code, validator.Deposit, weight)
+		voter := types.NewVoterWithConsensusKey(validator.Code, signer, validator.Deposit, weight)
+		key, err := aggregateKey(keys, validator.Code)
+		if err != nil {
+			return nil, err
+		}
+		if key != nil {
+			voter.SetAggregateKey(key)
+		}
+		voters = append(voters, voter)
 	}
 
 	return types.NewVoters(voters)
 }
 
+// ConsensusKey returns the consensus key registered by the validator, the
+// validator account if there isn't any.
+func (css *Consensus) ConsensusKey(code common.Address) (common.Address, error) {
+	return consensusKey(&css.consensusKeys.ConsensusKeysCaller, code)
+}
+
+// consensusKey returns the key registered by the validator. The validators
+// sign with their account until they register a key, and there aren't any
+// keys before the consensus key fork.
+func consensusKey(keys *ConsensusKeysCaller, code common.Address) (common.Address, error) {
+	key, err := keys.GetConsensusKey(&bind.CallOpts{}, code)
+	if err == bind.ErrNoCode {
+		return code, nil
+	}
+	if err != nil {
+		return common.Address{}, err
+	}
+	if key == (common.Address{}) {
+		return code, nil
+	}
+	return key, nil
+}
+
+// SetConsensusKey registers the consensus key of the wallet account, which is
+// used from the next election on. The consensus keys contract doesn't have
+// any code, so the gas limit isn't estimated by the binding.
+func (css *Consensus) SetConsensusKey(walletAccount accounts.WalletAccount, key common.Address) (common.Hash, error) {
+	log.Warn(fmt.Sprintf("Registering the consensus key on the network %v. Account %q, consensus key %q",
+		css.chainID.String(), walletAccount.Account().Address.String(), key.String()))
+
+	keysABI, err := abi.JSON(strings.NewReader(ConsensusKeysABI))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	input, err := keysABI.Pack("setConsensusKey", key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	opts := transactOpts(walletAccount, css.chainID)
+	opts.GasLimit, err = css.contractBackend.EstimateGas(context.Background(), kowala.CallMsg{
+		From: opts.From,
+		To:   &vm.ConsensusKeysAddress,
+		Data: input,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx, err := css.consensusKeys.SetConsensusKey(opts, key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
+// AggregateKey returns the aggregate signature key registered by the
+// validator, nil if there isn't any.
+func (css *Consensus) AggregateKey(code common.Address) (*bls.PublicKey, error) {
+	return aggregateKey(&css.aggregateKeys.AggregateKeysCaller, code)
+}
+
+// aggregateKey returns the key registered by the validator. The contract
+// checked the proof of possession of the key at its registration. There
+// aren't any keys before the aggregated commit fork.
+func aggregateKey(keys *AggregateKeysCaller, code common.Address) (*bls.PublicKey, error) {
+	registered, err := keys.GetAggregateKey(&bind.CallOpts{}, code)
+	if err == bind.ErrNoCode {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(registered) == 0 {
+		return nil, nil
+	}
+	return bls.UnmarshalPublicKey(registered)
+}
+
+// SetAggregateKey registers the aggregate signature key of the wallet account
+// along with its proof of possession. The aggregate keys contract doesn't have
+// any code, so the gas limit isn't estimated by the binding.
+func (css *Consensus) SetAggregateKey(walletAccount accounts.WalletAccount, key *bls.PrivateKey) (common.Hash, error) {
+	log.Warn(fmt.Sprintf("Registering the aggregate key on the network %v. Account %q",
+		css.chainID.String(), walletAccount.Account().Address.String()))
+
+	keysABI, err := abi.JSON(strings.NewReader(AggregateKeysABI))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	pub, proof := key.PublicKey().Marshal(), key.ProvePossession().Marshal()
+	input, err := keysABI.Pack("setAggregateKey", pub, proof)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	opts := transactOpts(walletAccount, css.chainID)
+	opts.GasLimit, err = css.contractBackend.EstimateGas(context.Background(), kowala.CallMsg{
+		From: opts.From,
+		To:   &vm.AggregateKeysAddress,
+		Data: input,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx, err := css.aggregateKeys.SetAggregateKey(opts, pub, proof)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
 func (css *Consensus) Deposits(addr common.Address) ([]*types.Deposit, error) {
 	count, err := css.manager.GetDepositCount(&bind.CallOpts{From: addr})
 	if err != nil {
@@ -229,6 +452,160 @@ func (css *Consensus) Deposits(addr common.Address) ([]*types.Deposit, error) {
 	return deposits, nil
 }
 
+// Stake is the stake of a validator: its deposit along with the tokens
+// delegated to it.
+type Stake struct {
+	Deposit        *big.Int
+	Delegated      *big.Int
+	CommissionRate *big.Int // share of the delegators rewards kept by the validator, in basis points
+	Delegators     []*Delegator
+}
+
+// Delegator is a token holder staking its tokens through a validator.
+type Delegator struct {
+	Address common.Address
+	Amount  *big.Int
+}
+
+// GetStake returns the stake of a validator registered in the given manager,
+// along with the tokens delegated to it in the delegation contract.
+func GetStake(manager *ValidatorMgrCaller, delegation *DelegationCaller, code common.Address) (*Stake, error) {
+	deposit := new(big.Int)
+	isValidator, err := manager.IsValidator(&bind.CallOpts{}, code)
+	if err != nil {
+		return nil, err
+	}
+	if isValidator {
+		// the last deposit of a validator is the deposit of the current election
+		count, err := manager.GetDepositCount(&bind.CallOpts{From: code})
+		if err != nil {
+			return nil, err
+		}
+		if count.Sign() > 0 {
+			current, err := manager.GetDepositAtIndex(&bind.CallOpts{From: code}, new(big.Int).Sub(count, common.Big1))
+			if err != nil {
+				return nil, err
+			}
+			deposit = current.Amount
+		}
+	}
+
+	delegated, err := delegation.GetDelegated(&bind.CallOpts{}, code)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := delegation.GetCommissionRate(&bind.CallOpts{}, code)
+	if err != nil {
+		return nil, err
+	}
+	count, err := delegation.GetDelegatorCount(&bind.CallOpts{}, code)
+	if err != nil {
+		return nil, err
+	}
+
+	delegators := make([]*Delegator, count.Uint64())
+	for i := int64(0); i < count.Int64(); i++ {
+		delegator, err := delegation.GetDelegatorAtIndex(&bind.CallOpts{}, code, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		delegators[i] = &Delegator{Address: delegator.Delegator, Amount: delegator.Amount}
+	}
+
+	return &Stake{
+		Deposit:        deposit,
+		Delegated:      delegated,
+		CommissionRate: rate,
+		Delegators:     delegators,
+	}, nil
+}
+
+// Stake returns the stake of the validator.
+func (css *Consensus) Stake(code common.Address) (*Stake, error) {
+	return GetStake(&css.manager.ValidatorMgrCaller, &css.delegation.DelegationCaller, code)
+}
+
+// Delegate stakes the given amount of tokens of the wallet account through the
+// validator. The tokens are transferred to the delegation contract, which is
+// installed at the end of the first block of the delegated staking fork: the
+// mining token wouldn't call the delegation handler of an account without
+// code and the tokens would be lost.
+func (css *Consensus) Delegate(walletAccount accounts.WalletAccount, validator common.Address, amount *big.Int) (common.Hash, error) {
+	code, err := css.contractBackend.PendingCodeAt(context.Background(), vm.DelegationAddress)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(code) == 0 {
+		return common.Hash{}, ErrDelegationUnavailable
+	}
+
+	log.Warn(fmt.Sprintf("Delegating %v tokens on the network %v. Account %q, validator %q",
+		amount.String(), css.chainID.String(), walletAccount.Account().Address.String(), validator.String()))
+
+	data := common.LeftPadBytes(validator.Bytes(), common.HashLength)
+	hash, err := css.mtoken.Transfer(walletAccount, vm.DelegationAddress, amount, data, DelegationHandler)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to transact the delegation: %s", err)
+	}
+
+	return hash, nil
+}
+
+// Undelegate withdraws the given amount of tokens delegated to the validator.
+// The tokens can be redeemed with ReleaseDelegations after the freeze period.
+func (css *Consensus) Undelegate(walletAccount accounts.WalletAccount, validator common.Address, amount *big.Int) (common.Hash, error) {
+	log.Warn(fmt.Sprintf("Undelegating %v tokens on the network %v. Account %q, validator %q",
+		amount.String(), css.chainID.String(), walletAccount.Account().Address.String(), validator.String()))
+	tx, err := css.delegation.Undelegate(transactOpts(walletAccount, css.chainID), validator, amount)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
+// ReleaseDelegations transfers the tokens undelegated from the validator that
+// are past the freeze period back to the wallet account.
+func (css *Consensus) ReleaseDelegations(walletAccount accounts.WalletAccount, validator common.Address) (common.Hash, error) {
+	tx, err := css.delegation.ReleaseDelegations(transactOpts(walletAccount, css.chainID), validator)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
+// Undelegations returns the tokens undelegated by the address from the
+// validator that haven't been released yet.
+func (css *Consensus) Undelegations(addr common.Address, validator common.Address) ([]*types.Deposit, error) {
+	count, err := css.delegation.GetUndelegationCount(&bind.CallOpts{}, addr, validator)
+	if err != nil {
+		return nil, err
+	}
+
+	deposits := make([]*types.Deposit, count.Uint64())
+	for i := int64(0); i < count.Int64(); i++ {
+		deposit, err := css.delegation.GetUndelegationAtIndex(&bind.CallOpts{}, addr, validator, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		deposits[i] = types.NewDeposit(deposit.Amount, deposit.AvailableAt.Int64())
+	}
+
+	return deposits, nil
+}
+
+// SetCommissionRate sets the share of the delegators rewards, in basis
+// points, kept by the validator.
+func (css *Consensus) SetCommissionRate(walletAccount accounts.WalletAccount, rate *big.Int) (common.Hash, error) {
+	tx, err := css.delegation.SetCommissionRate(transactOpts(walletAccount, css.chainID), rate)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
 func (css *Consensus) IsGenesisValidator(address common.Address) (bool, error) {
 	return css.manager.IsGenesisValidator(&bind.CallOpts{}, address)
 }
@@ -333,6 +710,67 @@ func (css *Consensus) Confirm(opts *accounts.TransactOpts, transactionID *big.In
 	return tx.Hash(), err
 }
 
+// Uptime is the signing statistics of a validator over the uptime window.
+type Uptime struct {
+	Blocks      *big.Int // blocks of the window tracked for the validator
+	Missed      *big.Int // blocks of the window not signed by the validator
+	JailedUntil *big.Int // block from which a jailed validator can unjail itself, zero if not jailed
+}
+
+// Uptime returns the signing statistics of the validator.
+func (css *Consensus) Uptime(code common.Address) (*Uptime, error) {
+	uptime, err := css.uptime.GetUptime(&bind.CallOpts{}, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Uptime{
+		Blocks:      uptime.Blocks,
+		Missed:      uptime.Missed,
+		JailedUntil: uptime.JailedUntil,
+	}, nil
+}
+
+// Unjail returns the jailed validator to the voters set once the jail
+// duration has elapsed. The uptime contract doesn't have any code, so the gas
+// limit isn't estimated by the binding.
+func (css *Consensus) Unjail(walletAccount accounts.WalletAccount) (common.Hash, error) {
+	log.Warn(fmt.Sprintf("Unjailing the validator on the network %v. Account %q",
+		css.chainID.String(), walletAccount.Account().Address.String()))
+
+	uptimeABI, err := abi.JSON(strings.NewReader(ValidatorUptimeABI))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	input, err := uptimeABI.Pack("unjail")
+	if err != nil {
+		return common.Hash{}, err
+	}
+	opts := transactOpts(walletAccount, css.chainID)
+	opts.GasLimit, err = css.contractBackend.EstimateGas(context.Background(), kowala.CallMsg{
+		From: opts.From,
+		To:   &vm.UptimeAddress,
+		Data: input,
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx, err := css.uptime.Unjail(opts)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), nil
+}
+
+// isJailed reports whether the validator is jailed. The calls fail before the
+// uptime fork, when no validator is jailed.
+func isJailed(uptime *ValidatorUptimeCaller, code common.Address) bool {
+	jailed, err := uptime.IsJailed(&bind.CallOpts{}, code)
+	return err == nil && jailed
+}
+
 // @TODO(rgeraldes) - temporary method
 func (css *Consensus) Domain() string {
 	return ""
@@ -0,0 +1,56 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/common"
+)
+
+// testCaller is a contract caller returning the same result to all the calls.
+type testCaller struct {
+	output []byte
+	err    error
+}
+
+func (c *testCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, c.err
+}
+
+func (c *testCaller) CallContract(ctx context.Context, call kowala.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.output, c.err
+}
+
+func TestConsensusKey(t *testing.T) {
+	var (
+		code = common.HexToAddress("0x01")
+		key  = common.HexToAddress("0x02")
+		fail = errors.New("call failed")
+	)
+	tests := []struct {
+		caller *testCaller
+		key    common.Address
+		err    error
+	}{
+		// the validators sign with their account before the fork
+		{&testCaller{}, code, nil},
+		// and until they register a key
+		{&testCaller{output: make([]byte, common.HashLength)}, code, nil},
+		{&testCaller{output: key.Hash().Bytes()}, key, nil},
+		// a failing call doesn't fall back to the validator account
+		{&testCaller{err: fail}, common.Address{}, fail},
+	}
+	for i, tt := range tests {
+		keys, err := NewConsensusKeysCaller(common.Address{}, tt.caller)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, err := consensusKey(keys, code)
+		if have != tt.key || err != tt.err {
+			t.Errorf("test %d: key mismatch: have %x (%v), want %x (%v)", i, have, err, tt.key, tt.err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package consensus_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind/backends"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/kns"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/knode/genesis"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// newDelegationBackend returns a simulated backend running the contracts of
+// the test network, with the given validator and delegator, along with the
+// installed delegation contract.
+func newDelegationBackend(t *testing.T, validator, delegator common.Address) (*backends.SimulatedBackend, *consensus.ValidatorMgr, *consensus.MiningToken) {
+	opts := genesis.Networks["kusd"][genesis.TestNetwork]
+
+	consensusOpts := *opts.Consensus
+	consensusOpts.Validators = []genesis.Validator{{Address: validator.Hex(), Deposit: consensusOpts.BaseDeposit}}
+	tokenOpts := *consensusOpts.MiningToken
+	tokenOpts.Holders = []genesis.TokenHolder{
+		{Address: validator.Hex(), NumTokens: consensusOpts.BaseDeposit},
+		{Address: delegator.Hex(), NumTokens: 100},
+	}
+	consensusOpts.MiningToken = &tokenOpts
+	opts.Consensus = &consensusOpts
+	opts.PrefundedAccounts = []genesis.PrefundedAccount{
+		{Address: validator.Hex(), Balance: 1000},
+		{Address: delegator.Hex(), Balance: 1000},
+	}
+
+	gen, err := genesis.Generate(opts)
+	if err != nil {
+		t.Fatalf("failed to generate the genesis: %v", err)
+	}
+	gen.Config.DelegatedStakingBlock = big.NewInt(0)
+
+	// the consensus engine installs the delegation contract, the simulated
+	// backend doesn't run it
+	contracts := backends.NewSimulatedBackendWithConfig(gen.Config, gen.Alloc)
+	managerAddr, err := kns.GetAddressFromDomain(params.KNSDomains[params.ValidatorMgrDomain].FullDomain(), contracts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenAddr, err := kns.GetAddressFromDomain(params.KNSDomains[params.MiningTokenDomain].FullDomain(), contracts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, storage := vm.DelegationAccount(managerAddr, tokenAddr)
+	gen.Alloc[vm.DelegationAddress] = core.GenesisAccount{Code: code, Storage: storage, Nonce: 1, Balance: new(big.Int)}
+
+	backend := backends.NewSimulatedBackendWithConfig(gen.Config, gen.Alloc)
+	manager, err := consensus.NewValidatorMgr(managerAddr, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := consensus.NewMiningToken(tokenAddr, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return backend, manager, token
+}
+
+// committer returns a function mining the sent transaction and returning its
+// receipt.
+func committer(t *testing.T, backend *backends.SimulatedBackend) func(*types.Transaction, error) *types.Receipt {
+	return func(tx *types.Transaction, err error) *types.Receipt {
+		if err != nil {
+			t.Fatalf("failed to send the transaction: %v", err)
+		}
+		backend.Commit()
+		receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return receipt
+	}
+}
+
+// delegateOpts returns the options of a transaction that isn't estimated, to
+// check the failures of the delegation contract.
+func delegateOpts(key *ecdsa.PrivateKey) *bind.TransactOpts {
+	opts := bind.NewKeyedTransactor(key)
+	opts.GasLimit = 500000
+	return opts
+}
+
+func TestDelegation(t *testing.T) {
+	validatorKey, _ := crypto.GenerateKey()
+	delegatorKey, _ := crypto.GenerateKey()
+	var (
+		validator = crypto.PubkeyToAddress(validatorKey.PublicKey)
+		delegator = crypto.PubkeyToAddress(delegatorKey.PublicKey)
+		amount    = new(big.Int).Mul(big.NewInt(3), big.NewInt(params.Kcoin))
+		data      = common.LeftPadBytes(validator.Bytes(), common.HashLength)
+	)
+	backend, manager, token := newDelegationBackend(t, validator, delegator)
+	delegation, err := consensus.NewDelegation(vm.DelegationAddress, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := committer(t, backend)
+	balance, err := token.BalanceOf(&bind.CallOpts{}, delegator)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the tokens are delegated through the token fallback
+	receipt := commit(token.Transfer(delegateOpts(delegatorKey), vm.DelegationAddress, amount, data, consensus.DelegationHandler))
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to delegate the tokens")
+	}
+	var delegated *types.Log
+	for _, log := range receipt.Logs {
+		if log.Address == vm.DelegationAddress {
+			delegated = log
+		}
+	}
+	if delegated == nil || delegated.Topics[1] != delegator.Hash() || delegated.Topics[2] != validator.Hash() {
+		t.Fatalf("delegation log mismatch: %v", receipt.Logs)
+	}
+	if have, _ := token.BalanceOf(&bind.CallOpts{}, delegator); have.Cmp(new(big.Int).Sub(balance, amount)) != 0 {
+		t.Fatalf("delegator balance mismatch: have %v, want %v", have, new(big.Int).Sub(balance, amount))
+	}
+	stake, err := consensus.GetStake(&manager.ValidatorMgrCaller, &delegation.DelegationCaller, validator)
+	if err != nil {
+		t.Fatalf("failed to retrieve the stake: %v", err)
+	}
+	deposit := new(big.Int).Mul(new(big.Int).SetUint64(genesis.Networks["kusd"][genesis.TestNetwork].Consensus.BaseDeposit), big.NewInt(params.Kcoin))
+	if stake.Deposit.Cmp(deposit) != 0 || stake.Delegated.Cmp(amount) != 0 {
+		t.Fatalf("stake mismatch: have deposit %v delegated %v, want %v and %v", stake.Deposit, stake.Delegated, deposit, amount)
+	}
+	if len(stake.Delegators) != 1 || stake.Delegators[0].Address != delegator || stake.Delegators[0].Amount.Cmp(amount) != 0 {
+		t.Fatalf("delegators mismatch: %v", stake.Delegators)
+	}
+
+	// only validators can be delegated to, and only by the mining token
+	other := common.LeftPadBytes(delegator.Bytes(), common.HashLength)
+	if receipt := commit(token.Transfer(delegateOpts(validatorKey), vm.DelegationAddress, big.NewInt(1), other, consensus.DelegationHandler)); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("delegated to an account that isn't a validator")
+	}
+	if receipt := commit(delegation.Delegate(delegateOpts(delegatorKey), delegator, amount, validator)); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("delegated without transferring tokens")
+	}
+
+	// the undelegated tokens are locked for the freeze period
+	undelegated := big.NewInt(params.Kcoin)
+	if receipt := commit(delegation.Undelegate(bind.NewKeyedTransactor(delegatorKey), validator, undelegated)); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to undelegate the tokens")
+	}
+	if receipt := commit(delegation.Undelegate(delegateOpts(delegatorKey), validator, amount)); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("undelegated more tokens than delegated")
+	}
+	if have, _ := delegation.GetDelegated(&bind.CallOpts{}, validator); have.Cmp(new(big.Int).Sub(amount, undelegated)) != 0 {
+		t.Fatalf("delegated tokens mismatch: have %v, want %v", have, new(big.Int).Sub(amount, undelegated))
+	}
+	if count, _ := delegation.GetUndelegationCount(&bind.CallOpts{}, delegator, validator); count.Cmp(common.Big1) != 0 {
+		t.Fatalf("undelegation count mismatch: have %v, want 1", count)
+	}
+	undelegation, err := delegation.GetUndelegationAtIndex(&bind.CallOpts{}, delegator, validator, common.Big0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	freezePeriod, err := manager.FreezePeriod(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if undelegation.Amount.Cmp(undelegated) != 0 || undelegation.AvailableAt.Cmp(freezePeriod) <= 0 {
+		t.Fatalf("undelegation mismatch: have %v available at %v", undelegation.Amount, undelegation.AvailableAt)
+	}
+
+	// the tokens stay locked until the end of the freeze period
+	if receipt := commit(delegation.ReleaseDelegations(bind.NewKeyedTransactor(delegatorKey), validator)); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to release the delegations")
+	}
+	if count, _ := delegation.GetUndelegationCount(&bind.CallOpts{}, delegator, validator); count.Cmp(common.Big1) != 0 {
+		t.Fatalf("released locked tokens: %v undelegations left", count)
+	}
+
+	// undelegating all the tokens removes the delegator
+	if receipt := commit(delegation.Undelegate(bind.NewKeyedTransactor(delegatorKey), validator, new(big.Int).Sub(amount, undelegated))); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to undelegate the tokens")
+	}
+	if count, _ := delegation.GetDelegatorCount(&bind.CallOpts{}, validator); count.Sign() != 0 {
+		t.Fatalf("delegator count mismatch: have %v, want 0", count)
+	}
+}
+
+func TestSetCommissionRate(t *testing.T) {
+	validatorKey, _ := crypto.GenerateKey()
+	delegatorKey, _ := crypto.GenerateKey()
+	var (
+		validator = crypto.PubkeyToAddress(validatorKey.PublicKey)
+		delegator = crypto.PubkeyToAddress(delegatorKey.PublicKey)
+	)
+	backend, _, _ := newDelegationBackend(t, validator, delegator)
+	delegation, err := consensus.NewDelegation(vm.DelegationAddress, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := committer(t, backend)
+
+	if receipt := commit(delegation.SetCommissionRate(bind.NewKeyedTransactor(validatorKey), big.NewInt(1000))); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to set the commission rate")
+	} else if len(receipt.Logs) != 1 || receipt.Logs[0].Topics[1] != validator.Hash() {
+		t.Fatalf("commission rate log mismatch: %v", receipt.Logs)
+	}
+	if rate, err := delegation.GetCommissionRate(&bind.CallOpts{}, validator); err != nil || rate.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("commission rate mismatch: have %v (%v), want 1000", rate, err)
+	}
+
+	if receipt := commit(delegation.SetCommissionRate(delegateOpts(validatorKey), big.NewInt(consensus.MaxCommissionRate+1))); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("set a commission rate above the maximum")
+	}
+	if receipt := commit(delegation.SetCommissionRate(delegateOpts(delegatorKey), big.NewInt(1000))); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("set the commission rate of an account that isn't a validator")
+	}
+}
+
+func TestDelegationBeforeFork(t *testing.T) {
+	config := *params.AllKonsensusProtocolChanges
+	config.DelegatedStakingBlock = nil
+
+	key, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	backend := backends.NewSimulatedBackendWithConfig(&config, core.GenesisAlloc{account: {Balance: big.NewInt(params.Kcoin)}})
+	delegation, err := consensus.NewDelegationCaller(vm.DelegationAddress, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// there isn't any delegation contract before the fork
+	if _, err := delegation.GetDelegated(&bind.CallOpts{}, account); err != bind.ErrNoCode {
+		t.Fatalf("error mismatch: have %v, want %v", err, bind.ErrNoCode)
+	}
+}
@@ -0,0 +1,365 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package consensus
+
+import (
+	"strings"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/event"
+)
+
+// AggregateKeysABI is the input ABI used to generate the binding from.
+const AggregateKeysABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getAggregateKey\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"key\",\"type\":\"bytes\"},{\"name\":\"proof\",\"type\":\"bytes\"}],\"name\":\"setAggregateKey\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"aggregateKeysChecksum\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"AggregateKeySet\",\"type\":\"event\"}]"
+
+// AggregateKeys is an auto generated Go binding around a Kowala contract.
+type AggregateKeys struct {
+	AggregateKeysCaller     // Read-only binding to the contract
+	AggregateKeysTransactor // Write-only binding to the contract
+	AggregateKeysFilterer   // Log filterer for contract events
+}
+
+// AggregateKeysCaller is an auto generated read-only Go binding around a Kowala contract.
+type AggregateKeysCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AggregateKeysTransactor is an auto generated write-only Go binding around a Kowala contract.
+type AggregateKeysTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AggregateKeysFilterer is an auto generated log filtering Go binding around a Kowala contract events.
+type AggregateKeysFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// AggregateKeysSession is an auto generated Go binding around a Kowala contract,
+// with pre-set call and transact options.
+type AggregateKeysSession struct {
+	Contract     *AggregateKeys    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// AggregateKeysCallerSession is an auto generated read-only Go binding around a Kowala contract,
+// with pre-set call options.
+type AggregateKeysCallerSession struct {
+	Contract *AggregateKeysCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// AggregateKeysTransactorSession is an auto generated write-only Go binding around a Kowala contract,
+// with pre-set transact options.
+type AggregateKeysTransactorSession struct {
+	Contract     *AggregateKeysTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// AggregateKeysRaw is an auto generated low-level Go binding around a Kowala contract.
+type AggregateKeysRaw struct {
+	Contract *AggregateKeys // Generic contract binding to access the raw methods on
+}
+
+// AggregateKeysCallerRaw is an auto generated low-level read-only Go binding around a Kowala contract.
+type AggregateKeysCallerRaw struct {
+	Contract *AggregateKeysCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// AggregateKeysTransactorRaw is an auto generated low-level write-only Go binding around a Kowala contract.
+type AggregateKeysTransactorRaw struct {
+	Contract *AggregateKeysTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewAggregateKeys creates a new instance of AggregateKeys, bound to a specific deployed contract.
+func NewAggregateKeys(address common.Address, backend bind.ContractBackend) (*AggregateKeys, error) {
+	contract, err := bindAggregateKeys(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateKeys{AggregateKeysCaller: AggregateKeysCaller{contract: contract}, AggregateKeysTransactor: AggregateKeysTransactor{contract: contract}, AggregateKeysFilterer: AggregateKeysFilterer{contract: contract}}, nil
+}
+
+// NewAggregateKeysCaller creates a new read-only instance of AggregateKeys, bound to a specific deployed contract.
+func NewAggregateKeysCaller(address common.Address, caller bind.ContractCaller) (*AggregateKeysCaller, error) {
+	contract, err := bindAggregateKeys(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateKeysCaller{contract: contract}, nil
+}
+
+// NewAggregateKeysTransactor creates a new write-only instance of AggregateKeys, bound to a specific deployed contract.
+func NewAggregateKeysTransactor(address common.Address, transactor bind.ContractTransactor) (*AggregateKeysTransactor, error) {
+	contract, err := bindAggregateKeys(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateKeysTransactor{contract: contract}, nil
+}
+
+// NewAggregateKeysFilterer creates a new log filterer instance of AggregateKeys, bound to a specific deployed contract.
+func NewAggregateKeysFilterer(address common.Address, filterer bind.ContractFilterer) (*AggregateKeysFilterer, error) {
+	contract, err := bindAggregateKeys(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateKeysFilterer{contract: contract}, nil
+}
+
+// bindAggregateKeys binds a generic wrapper to an already deployed contract.
+func bindAggregateKeys(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(AggregateKeysABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AggregateKeys *AggregateKeysRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _AggregateKeys.Contract.AggregateKeysCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AggregateKeys *AggregateKeysRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AggregateKeys.Contract.AggregateKeysTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AggregateKeys *AggregateKeysRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AggregateKeys.Contract.AggregateKeysTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_AggregateKeys *AggregateKeysCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _AggregateKeys.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_AggregateKeys *AggregateKeysTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _AggregateKeys.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_AggregateKeys *AggregateKeysTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _AggregateKeys.Contract.contract.Transact(opts, method, params...)
+}
+
+// AggregateKeysChecksum is a free data retrieval call binding the contract method 0x27273daa.
+//
+// Solidity: function aggregateKeysChecksum() constant returns(bytes32)
+func (_AggregateKeys *AggregateKeysCaller) AggregateKeysChecksum(opts *bind.CallOpts) ([32]byte, error) {
+	var (
+		ret0 = new([32]byte)
+	)
+	out := ret0
+	err := _AggregateKeys.contract.Call(opts, out, "aggregateKeysChecksum")
+	return *ret0, err
+}
+
+// AggregateKeysChecksum is a free data retrieval call binding the contract method 0x27273daa.
+//
+// Solidity: function aggregateKeysChecksum() constant returns(bytes32)
+func (_AggregateKeys *AggregateKeysSession) AggregateKeysChecksum() ([32]byte, error) {
+	return _AggregateKeys.Contract.AggregateKeysChecksum(&_AggregateKeys.CallOpts)
+}
+
+// AggregateKeysChecksum is a free data retrieval call binding the contract method 0x27273daa.
+//
+// Solidity: function aggregateKeysChecksum() constant returns(bytes32)
+func (_AggregateKeys *AggregateKeysCallerSession) AggregateKeysChecksum() ([32]byte, error) {
+	return _AggregateKeys.Contract.AggregateKeysChecksum(&_AggregateKeys.CallOpts)
+}
+
+// GetAggregateKey is a free data retrieval call binding the contract method 0xed2db924.
+//
+// Solidity: function getAggregateKey(validator address) constant returns(bytes)
+func (_AggregateKeys *AggregateKeysCaller) GetAggregateKey(opts *bind.CallOpts, validator common.Address) ([]byte, error) {
+	var (
+		ret0 = new([]byte)
+	)
+	out := ret0
+	err := _AggregateKeys.contract.Call(opts, out, "getAggregateKey", validator)
+	return *ret0, err
+}
+
+// GetAggregateKey is a free data retrieval call binding the contract method 0xed2db924.
+//
+// Solidity: function getAggregateKey(validator address) constant returns(bytes)
+func (_AggregateKeys *AggregateKeysSession) GetAggregateKey(validator common.Address) ([]byte, error) {
+	return _AggregateKeys.Contract.GetAggregateKey(&_AggregateKeys.CallOpts, validator)
+}
+
+// GetAggregateKey is a free data retrieval call binding the contract method 0xed2db924.
+//
+// Solidity: function getAggregateKey(validator address) constant returns(bytes)
+func (_AggregateKeys *AggregateKeysCallerSession) GetAggregateKey(validator common.Address) ([]byte, error) {
+	return _AggregateKeys.Contract.GetAggregateKey(&_AggregateKeys.CallOpts, validator)
+}
+
+// SetAggregateKey is a paid mutator transaction binding the contract method 0x59b8558d.
+//
+// Solidity: function setAggregateKey(key bytes, proof bytes) returns()
+func (_AggregateKeys *AggregateKeysTransactor) SetAggregateKey(opts *bind.TransactOpts, key []byte, proof []byte) (*types.Transaction, error) {
+	return _AggregateKeys.contract.Transact(opts, "setAggregateKey", key, proof)
+}
+
+// SetAggregateKey is a paid mutator transaction binding the contract method 0x59b8558d.
+//
+// Solidity: function setAggregateKey(key bytes, proof bytes) returns()
+func (_AggregateKeys *AggregateKeysSession) SetAggregateKey(key []byte, proof []byte) (*types.Transaction, error) {
+	return _AggregateKeys.Contract.SetAggregateKey(&_AggregateKeys.TransactOpts, key, proof)
+}
+
+// SetAggregateKey is a paid mutator transaction binding the contract method 0x59b8558d.
+//
+// Solidity: function setAggregateKey(key bytes, proof bytes) returns()
+func (_AggregateKeys *AggregateKeysTransactorSession) SetAggregateKey(key []byte, proof []byte) (*types.Transaction, error) {
+	return _AggregateKeys.Contract.SetAggregateKey(&_AggregateKeys.TransactOpts, key, proof)
+}
+
+// AggregateKeysAggregateKeySetIterator is returned from FilterAggregateKeySet and is used to iterate over the raw logs and unpacked data for AggregateKeySet events raised by the AggregateKeys contract.
+type AggregateKeysAggregateKeySetIterator struct {
+	Event *AggregateKeysAggregateKeySet // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *AggregateKeysAggregateKeySetIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(AggregateKeysAggregateKeySet)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(AggregateKeysAggregateKeySet)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *AggregateKeysAggregateKeySetIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *AggregateKeysAggregateKeySetIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// AggregateKeysAggregateKeySet represents a AggregateKeySet event raised by the AggregateKeys contract.
+type AggregateKeysAggregateKeySet struct {
+	Validator common.Address
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterAggregateKeySet is a free log retrieval operation binding the contract event 0xd21893adba8613fb5b523565899bd8736466a91cf863864e64f4a5d4617b82d1.
+//
+// Solidity: e AggregateKeySet(validator indexed address)
+func (_AggregateKeys *AggregateKeysFilterer) FilterAggregateKeySet(opts *bind.FilterOpts, validator []common.Address) (*AggregateKeysAggregateKeySetIterator, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _AggregateKeys.contract.FilterLogs(opts, "AggregateKeySet", validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &AggregateKeysAggregateKeySetIterator{contract: _AggregateKeys.contract, event: "AggregateKeySet", logs: logs, sub: sub}, nil
+}
+
+// WatchAggregateKeySet is a free log subscription operation binding the contract event 0xd21893adba8613fb5b523565899bd8736466a91cf863864e64f4a5d4617b82d1.
+//
+// Solidity: e AggregateKeySet(validator indexed address)
+func (_AggregateKeys *AggregateKeysFilterer) WatchAggregateKeySet(opts *bind.WatchOpts, sink chan<- *AggregateKeysAggregateKeySet, validator []common.Address) (event.Subscription, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _AggregateKeys.contract.WatchLogs(opts, "AggregateKeySet", validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(AggregateKeysAggregateKeySet)
+				if err := _AggregateKeys.contract.UnpackLog(event, "AggregateKeySet", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
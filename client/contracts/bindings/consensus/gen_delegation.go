@@ -0,0 +1,1007 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package consensus
+
+import (
+	"math/big"
+	"strings"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/event"
+)
+
+// DelegationABI is the input ABI used to generate the binding from.
+const DelegationABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getDelegated\",\"outputs\":[{\"name\":\"amount\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getCommissionRate\",\"outputs\":[{\"name\":\"rate\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getDelegatorCount\",\"outputs\":[{\"name\":\"count\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"},{\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"getDelegatorAtIndex\",\"outputs\":[{\"name\":\"delegator\",\"type\":\"address\"},{\"name\":\"amount\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\"},{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getDelegation\",\"outputs\":[{\"name\":\"amount\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\"},{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getUndelegationCount\",\"outputs\":[{\"name\":\"count\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"delegator\",\"type\":\"address\"},{\"name\":\"validator\",\"type\":\"address\"},{\"name\":\"index\",\"type\":\"uint256\"}],\"name\":\"getUndelegationAtIndex\",\"outputs\":[{\"name\":\"amount\",\"type\":\"uint256\"},{\"name\":\"availableAt\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_from\",\"type\":\"address\"},{\"name\":\"_value\",\"type\":\"uint256\"},{\"name\":\"_validator\",\"type\":\"address\"}],\"name\":\"delegate\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"},{\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"undelegate\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"releaseDelegations\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"rate\",\"type\":\"uint256\"}],\"name\":\"setCommissionRate\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"delegator\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"validator\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"Delegated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"delegator\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"validator\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"},{\"indexed\":false,\"name\":\"availableAt\",\"type\":\"uint256\"}],\"name\":\"Undelegated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"delegator\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"validator\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"DelegationsReleased\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"validator\",\"type\":\"address\"},{\"indexed\":false,\"name\":\"rate\",\"type\":\"uint256\"}],\"name\":\"CommissionRateSet\",\"type\":\"event\"}]"
+
+// Delegation is an auto generated Go binding around a Kowala contract.
+type Delegation struct {
+	DelegationCaller     // Read-only binding to the contract
+	DelegationTransactor // Write-only binding to the contract
+	DelegationFilterer   // Log filterer for contract events
+}
+
+// DelegationCaller is an auto generated read-only Go binding around a Kowala contract.
+type DelegationCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DelegationTransactor is an auto generated write-only Go binding around a Kowala contract.
+type DelegationTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DelegationFilterer is an auto generated log filtering Go binding around a Kowala contract events.
+type DelegationFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// DelegationSession is an auto generated Go binding around a Kowala contract,
+// with pre-set call and transact options.
+type DelegationSession struct {
+	Contract     *Delegation       // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// DelegationCallerSession is an auto generated read-only Go binding around a Kowala contract,
+// with pre-set call options.
+type DelegationCallerSession struct {
+	Contract *DelegationCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts     // Call options to use throughout this session
+}
+
+// DelegationTransactorSession is an auto generated write-only Go binding around a Kowala contract,
+// with pre-set transact options.
+type DelegationTransactorSession struct {
+	Contract     *DelegationTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts     // Transaction auth options to use throughout this session
+}
+
+// DelegationRaw is an auto generated low-level Go binding around a Kowala contract.
+type DelegationRaw struct {
+	Contract *Delegation // Generic contract binding to access the raw methods on
+}
+
+// DelegationCallerRaw is an auto generated low-level read-only Go binding around a Kowala contract.
+type DelegationCallerRaw struct {
+	Contract *DelegationCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// DelegationTransactorRaw is an auto generated low-level write-only Go binding around a Kowala contract.
+type DelegationTransactorRaw struct {
+	Contract *DelegationTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewDelegation creates a new instance of Delegation, bound to a specific deployed contract.
+func NewDelegation(address common.Address, backend bind.ContractBackend) (*Delegation, error) {
+	contract, err := bindDelegation(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Delegation{DelegationCaller: DelegationCaller{contract: contract}, DelegationTransactor: DelegationTransactor{contract: contract}, DelegationFilterer: DelegationFilterer{contract: contract}}, nil
+}
+
+// NewDelegationCaller creates a new read-only instance of Delegation, bound to a specific deployed contract.
+func NewDelegationCaller(address common.Address, caller bind.ContractCaller) (*DelegationCaller, error) {
+	contract, err := bindDelegation(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationCaller{contract: contract}, nil
+}
+
+// NewDelegationTransactor creates a new write-only instance of Delegation, bound to a specific deployed contract.
+func NewDelegationTransactor(address common.Address, transactor bind.ContractTransactor) (*DelegationTransactor, error) {
+	contract, err := bindDelegation(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationTransactor{contract: contract}, nil
+}
+
+// NewDelegationFilterer creates a new log filterer instance of Delegation, bound to a specific deployed contract.
+func NewDelegationFilterer(address common.Address, filterer bind.ContractFilterer) (*DelegationFilterer, error) {
+	contract, err := bindDelegation(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationFilterer{contract: contract}, nil
+}
+
+// bindDelegation binds a generic wrapper to an already deployed contract.
+func bindDelegation(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(DelegationABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Delegation *DelegationRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _Delegation.Contract.DelegationCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Delegation *DelegationRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Delegation.Contract.DelegationTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Delegation *DelegationRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Delegation.Contract.DelegationTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Delegation *DelegationCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _Delegation.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Delegation *DelegationTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Delegation.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Delegation *DelegationTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Delegation.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetCommissionRate is a free data retrieval call binding the contract method 0xe0cc26a2.
+//
+// Solidity: function getCommissionRate(validator address) constant returns(rate uint256)
+func (_Delegation *DelegationCaller) GetCommissionRate(opts *bind.CallOpts, validator common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Delegation.contract.Call(opts, out, "getCommissionRate", validator)
+	return *ret0, err
+}
+
+// GetCommissionRate is a free data retrieval call binding the contract method 0xe0cc26a2.
+//
+// Solidity: function getCommissionRate(validator address) constant returns(rate uint256)
+func (_Delegation *DelegationSession) GetCommissionRate(validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetCommissionRate(&_Delegation.CallOpts, validator)
+}
+
+// GetCommissionRate is a free data retrieval call binding the contract method 0xe0cc26a2.
+//
+// Solidity: function getCommissionRate(validator address) constant returns(rate uint256)
+func (_Delegation *DelegationCallerSession) GetCommissionRate(validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetCommissionRate(&_Delegation.CallOpts, validator)
+}
+
+// GetDelegated is a free data retrieval call binding the contract method 0x56f0b57e.
+//
+// Solidity: function getDelegated(validator address) constant returns(amount uint256)
+func (_Delegation *DelegationCaller) GetDelegated(opts *bind.CallOpts, validator common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Delegation.contract.Call(opts, out, "getDelegated", validator)
+	return *ret0, err
+}
+
+// GetDelegated is a free data retrieval call binding the contract method 0x56f0b57e.
+//
+// Solidity: function getDelegated(validator address) constant returns(amount uint256)
+func (_Delegation *DelegationSession) GetDelegated(validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetDelegated(&_Delegation.CallOpts, validator)
+}
+
+// GetDelegated is a free data retrieval call binding the contract method 0x56f0b57e.
+//
+// Solidity: function getDelegated(validator address) constant returns(amount uint256)
+func (_Delegation *DelegationCallerSession) GetDelegated(validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetDelegated(&_Delegation.CallOpts, validator)
+}
+
+// GetDelegation is a free data retrieval call binding the contract method 0x15049a5a.
+//
+// Solidity: function getDelegation(delegator address, validator address) constant returns(amount uint256)
+func (_Delegation *DelegationCaller) GetDelegation(opts *bind.CallOpts, delegator common.Address, validator common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Delegation.contract.Call(opts, out, "getDelegation", delegator, validator)
+	return *ret0, err
+}
+
+// GetDelegation is a free data retrieval call binding the contract method 0x15049a5a.
+//
+// Solidity: function getDelegation(delegator address, validator address) constant returns(amount uint256)
+func (_Delegation *DelegationSession) GetDelegation(delegator common.Address, validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetDelegation(&_Delegation.CallOpts, delegator, validator)
+}
+
+// GetDelegation is a free data retrieval call binding the contract method 0x15049a5a.
+//
+// Solidity: function getDelegation(delegator address, validator address) constant returns(amount uint256)
+func (_Delegation *DelegationCallerSession) GetDelegation(delegator common.Address, validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetDelegation(&_Delegation.CallOpts, delegator, validator)
+}
+
+// GetDelegatorAtIndex is a free data retrieval call binding the contract method 0x6b9fb5d4.
+//
+// Solidity: function getDelegatorAtIndex(validator address, index uint256) constant returns(delegator address, amount uint256)
+func (_Delegation *DelegationCaller) GetDelegatorAtIndex(opts *bind.CallOpts, validator common.Address, index *big.Int) (struct {
+	Delegator common.Address
+	Amount    *big.Int
+}, error) {
+	ret := new(struct {
+		Delegator common.Address
+		Amount    *big.Int
+	})
+	out := ret
+	err := _Delegation.contract.Call(opts, out, "getDelegatorAtIndex", validator, index)
+	return *ret, err
+}
+
+// GetDelegatorAtIndex is a free data retrieval call binding the contract method 0x6b9fb5d4.
+//
+// Solidity: function getDelegatorAtIndex(validator address, index uint256) constant returns(delegator address, amount uint256)
+func (_Delegation *DelegationSession) GetDelegatorAtIndex(validator common.Address, index *big.Int) (struct {
+	Delegator common.Address
+	Amount    *big.Int
+}, error) {
+	return _Delegation.Contract.GetDelegatorAtIndex(&_Delegation.CallOpts, validator, index)
+}
+
+// GetDelegatorAtIndex is a free data retrieval call binding the contract method 0x6b9fb5d4.
+//
+// Solidity: function getDelegatorAtIndex(validator address, index uint256) constant returns(delegator address, amount uint256)
+func (_Delegation *DelegationCallerSession) GetDelegatorAtIndex(validator common.Address, index *big.Int) (struct {
+	Delegator common.Address
+	Amount    *big.Int
+}, error) {
+	return _Delegation.Contract.GetDelegatorAtIndex(&_Delegation.CallOpts, validator, index)
+}
+
+// GetDelegatorCount is a free data retrieval call binding the contract method 0x7ab6bf66.
+//
+// Solidity: function getDelegatorCount(validator address) constant returns(count uint256)
+func (_Delegation *DelegationCaller) GetDelegatorCount(opts *bind.CallOpts, validator common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Delegation.contract.Call(opts, out, "getDelegatorCount", validator)
+	return *ret0, err
+}
+
+// GetDelegatorCount is a free data retrieval call binding the contract method 0x7ab6bf66.
+//
+// Solidity: function getDelegatorCount(validator address) constant returns(count uint256)
+func (_Delegation *DelegationSession) GetDelegatorCount(validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetDelegatorCount(&_Delegation.CallOpts, validator)
+}
+
+// GetDelegatorCount is a free data retrieval call binding the contract method 0x7ab6bf66.
+//
+// Solidity: function getDelegatorCount(validator address) constant returns(count uint256)
+func (_Delegation *DelegationCallerSession) GetDelegatorCount(validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetDelegatorCount(&_Delegation.CallOpts, validator)
+}
+
+// GetUndelegationAtIndex is a free data retrieval call binding the contract method 0xbd3a0abc.
+//
+// Solidity: function getUndelegationAtIndex(delegator address, validator address, index uint256) constant returns(amount uint256, availableAt uint256)
+func (_Delegation *DelegationCaller) GetUndelegationAtIndex(opts *bind.CallOpts, delegator common.Address, validator common.Address, index *big.Int) (struct {
+	Amount      *big.Int
+	AvailableAt *big.Int
+}, error) {
+	ret := new(struct {
+		Amount      *big.Int
+		AvailableAt *big.Int
+	})
+	out := ret
+	err := _Delegation.contract.Call(opts, out, "getUndelegationAtIndex", delegator, validator, index)
+	return *ret, err
+}
+
+// GetUndelegationAtIndex is a free data retrieval call binding the contract method 0xbd3a0abc.
+//
+// Solidity: function getUndelegationAtIndex(delegator address, validator address, index uint256) constant returns(amount uint256, availableAt uint256)
+func (_Delegation *DelegationSession) GetUndelegationAtIndex(delegator common.Address, validator common.Address, index *big.Int) (struct {
+	Amount      *big.Int
+	AvailableAt *big.Int
+}, error) {
+	return _Delegation.Contract.GetUndelegationAtIndex(&_Delegation.CallOpts, delegator, validator, index)
+}
+
+// GetUndelegationAtIndex is a free data retrieval call binding the contract method 0xbd3a0abc.
+//
+// Solidity: function getUndelegationAtIndex(delegator address, validator address, index uint256) constant returns(amount uint256, availableAt uint256)
+func (_Delegation *DelegationCallerSession) GetUndelegationAtIndex(delegator common.Address, validator common.Address, index *big.Int) (struct {
+	Amount      *big.Int
+	AvailableAt *big.Int
+}, error) {
+	return _Delegation.Contract.GetUndelegationAtIndex(&_Delegation.CallOpts, delegator, validator, index)
+}
+
+// GetUndelegationCount is a free data retrieval call binding the contract method 0x12db7ca7.
+//
+// Solidity: function getUndelegationCount(delegator address, validator address) constant returns(count uint256)
+func (_Delegation *DelegationCaller) GetUndelegationCount(opts *bind.CallOpts, delegator common.Address, validator common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _Delegation.contract.Call(opts, out, "getUndelegationCount", delegator, validator)
+	return *ret0, err
+}
+
+// GetUndelegationCount is a free data retrieval call binding the contract method 0x12db7ca7.
+//
+// Solidity: function getUndelegationCount(delegator address, validator address) constant returns(count uint256)
+func (_Delegation *DelegationSession) GetUndelegationCount(delegator common.Address, validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetUndelegationCount(&_Delegation.CallOpts, delegator, validator)
+}
+
+// GetUndelegationCount is a free data retrieval call binding the contract method 0x12db7ca7.
+//
+// Solidity: function getUndelegationCount(delegator address, validator address) constant returns(count uint256)
+func (_Delegation *DelegationCallerSession) GetUndelegationCount(delegator common.Address, validator common.Address) (*big.Int, error) {
+	return _Delegation.Contract.GetUndelegationCount(&_Delegation.CallOpts, delegator, validator)
+}
+
+// Delegate is a paid mutator transaction binding the contract method 0x75e2b5b9.
+//
+// Solidity: function delegate(_from address, _value uint256, _validator address) returns()
+func (_Delegation *DelegationTransactor) Delegate(opts *bind.TransactOpts, _from common.Address, _value *big.Int, _validator common.Address) (*types.Transaction, error) {
+	return _Delegation.contract.Transact(opts, "delegate", _from, _value, _validator)
+}
+
+// Delegate is a paid mutator transaction binding the contract method 0x75e2b5b9.
+//
+// Solidity: function delegate(_from address, _value uint256, _validator address) returns()
+func (_Delegation *DelegationSession) Delegate(_from common.Address, _value *big.Int, _validator common.Address) (*types.Transaction, error) {
+	return _Delegation.Contract.Delegate(&_Delegation.TransactOpts, _from, _value, _validator)
+}
+
+// Delegate is a paid mutator transaction binding the contract method 0x75e2b5b9.
+//
+// Solidity: function delegate(_from address, _value uint256, _validator address) returns()
+func (_Delegation *DelegationTransactorSession) Delegate(_from common.Address, _value *big.Int, _validator common.Address) (*types.Transaction, error) {
+	return _Delegation.Contract.Delegate(&_Delegation.TransactOpts, _from, _value, _validator)
+}
+
+// ReleaseDelegations is a paid mutator transaction binding the contract method 0x9194eed4.
+//
+// Solidity: function releaseDelegations(validator address) returns()
+func (_Delegation *DelegationTransactor) ReleaseDelegations(opts *bind.TransactOpts, validator common.Address) (*types.Transaction, error) {
+	return _Delegation.contract.Transact(opts, "releaseDelegations", validator)
+}
+
+// ReleaseDelegations is a paid mutator transaction binding the contract method 0x9194eed4.
+//
+// Solidity: function releaseDelegations(validator address) returns()
+func (_Delegation *DelegationSession) ReleaseDelegations(validator common.Address) (*types.Transaction, error) {
+	return _Delegation.Contract.ReleaseDelegations(&_Delegation.TransactOpts, validator)
+}
+
+// ReleaseDelegations is a paid mutator transaction binding the contract method 0x9194eed4.
+//
+// Solidity: function releaseDelegations(validator address) returns()
+func (_Delegation *DelegationTransactorSession) ReleaseDelegations(validator common.Address) (*types.Transaction, error) {
+	return _Delegation.Contract.ReleaseDelegations(&_Delegation.TransactOpts, validator)
+}
+
+// SetCommissionRate is a paid mutator transaction binding the contract method 0x19fac8fd.
+//
+// Solidity: function setCommissionRate(rate uint256) returns()
+func (_Delegation *DelegationTransactor) SetCommissionRate(opts *bind.TransactOpts, rate *big.Int) (*types.Transaction, error) {
+	return _Delegation.contract.Transact(opts, "setCommissionRate", rate)
+}
+
+// SetCommissionRate is a paid mutator transaction binding the contract method 0x19fac8fd.
+//
+// Solidity: function setCommissionRate(rate uint256) returns()
+func (_Delegation *DelegationSession) SetCommissionRate(rate *big.Int) (*types.Transaction, error) {
+	return _Delegation.Contract.SetCommissionRate(&_Delegation.TransactOpts, rate)
+}
+
+// SetCommissionRate is a paid mutator transaction binding the contract method 0x19fac8fd.
+//
+// Solidity: function setCommissionRate(rate uint256) returns()
+func (_Delegation *DelegationTransactorSession) SetCommissionRate(rate *big.Int) (*types.Transaction, error) {
+	return _Delegation.Contract.SetCommissionRate(&_Delegation.TransactOpts, rate)
+}
+
+// Undelegate is a paid mutator transaction binding the contract method 0x4d99dd16.
+//
+// Solidity: function undelegate(validator address, amount uint256) returns()
+func (_Delegation *DelegationTransactor) Undelegate(opts *bind.TransactOpts, validator common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _Delegation.contract.Transact(opts, "undelegate", validator, amount)
+}
+
+// Undelegate is a paid mutator transaction binding the contract method 0x4d99dd16.
+//
+// Solidity: function undelegate(validator address, amount uint256) returns()
+func (_Delegation *DelegationSession) Undelegate(validator common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _Delegation.Contract.Undelegate(&_Delegation.TransactOpts, validator, amount)
+}
+
+// Undelegate is a paid mutator transaction binding the contract method 0x4d99dd16.
+//
+// Solidity: function undelegate(validator address, amount uint256) returns()
+func (_Delegation *DelegationTransactorSession) Undelegate(validator common.Address, amount *big.Int) (*types.Transaction, error) {
+	return _Delegation.Contract.Undelegate(&_Delegation.TransactOpts, validator, amount)
+}
+
+// DelegationCommissionRateSetIterator is returned from FilterCommissionRateSet and is used to iterate over the raw logs and unpacked data for CommissionRateSet events raised by the Delegation contract.
+type DelegationCommissionRateSetIterator struct {
+	Event *DelegationCommissionRateSet // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DelegationCommissionRateSetIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DelegationCommissionRateSet)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DelegationCommissionRateSet)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DelegationCommissionRateSetIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DelegationCommissionRateSetIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DelegationCommissionRateSet represents a CommissionRateSet event raised by the Delegation contract.
+type DelegationCommissionRateSet struct {
+	Validator common.Address
+	Rate      *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterCommissionRateSet is a free log retrieval operation binding the contract event 0x4f67b1beecb3cf376558d9bb47e4d7f98f84c012acc2a0d1113c4a270e408ef9.
+//
+// Solidity: e CommissionRateSet(validator indexed address, rate uint256)
+func (_Delegation *DelegationFilterer) FilterCommissionRateSet(opts *bind.FilterOpts, validator []common.Address) (*DelegationCommissionRateSetIterator, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.FilterLogs(opts, "CommissionRateSet", validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationCommissionRateSetIterator{contract: _Delegation.contract, event: "CommissionRateSet", logs: logs, sub: sub}, nil
+}
+
+// WatchCommissionRateSet is a free log subscription operation binding the contract event 0x4f67b1beecb3cf376558d9bb47e4d7f98f84c012acc2a0d1113c4a270e408ef9.
+//
+// Solidity: e CommissionRateSet(validator indexed address, rate uint256)
+func (_Delegation *DelegationFilterer) WatchCommissionRateSet(opts *bind.WatchOpts, sink chan<- *DelegationCommissionRateSet, validator []common.Address) (event.Subscription, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.WatchLogs(opts, "CommissionRateSet", validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DelegationCommissionRateSet)
+				if err := _Delegation.contract.UnpackLog(event, "CommissionRateSet", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// DelegationDelegatedIterator is returned from FilterDelegated and is used to iterate over the raw logs and unpacked data for Delegated events raised by the Delegation contract.
+type DelegationDelegatedIterator struct {
+	Event *DelegationDelegated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DelegationDelegatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DelegationDelegated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DelegationDelegated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DelegationDelegatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DelegationDelegatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DelegationDelegated represents a Delegated event raised by the Delegation contract.
+type DelegationDelegated struct {
+	Delegator common.Address
+	Validator common.Address
+	Amount    *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterDelegated is a free log retrieval operation binding the contract event 0xe5541a6b6103d4fa7e021ed54fad39c66f27a76bd13d374cf6240ae6bd0bb72b.
+//
+// Solidity: e Delegated(delegator indexed address, validator indexed address, amount uint256)
+func (_Delegation *DelegationFilterer) FilterDelegated(opts *bind.FilterOpts, delegator []common.Address, validator []common.Address) (*DelegationDelegatedIterator, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.FilterLogs(opts, "Delegated", delegatorRule, validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationDelegatedIterator{contract: _Delegation.contract, event: "Delegated", logs: logs, sub: sub}, nil
+}
+
+// WatchDelegated is a free log subscription operation binding the contract event 0xe5541a6b6103d4fa7e021ed54fad39c66f27a76bd13d374cf6240ae6bd0bb72b.
+//
+// Solidity: e Delegated(delegator indexed address, validator indexed address, amount uint256)
+func (_Delegation *DelegationFilterer) WatchDelegated(opts *bind.WatchOpts, sink chan<- *DelegationDelegated, delegator []common.Address, validator []common.Address) (event.Subscription, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.WatchLogs(opts, "Delegated", delegatorRule, validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DelegationDelegated)
+				if err := _Delegation.contract.UnpackLog(event, "Delegated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// DelegationDelegationsReleasedIterator is returned from FilterDelegationsReleased and is used to iterate over the raw logs and unpacked data for DelegationsReleased events raised by the Delegation contract.
+type DelegationDelegationsReleasedIterator struct {
+	Event *DelegationDelegationsReleased // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DelegationDelegationsReleasedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DelegationDelegationsReleased)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DelegationDelegationsReleased)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DelegationDelegationsReleasedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DelegationDelegationsReleasedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DelegationDelegationsReleased represents a DelegationsReleased event raised by the Delegation contract.
+type DelegationDelegationsReleased struct {
+	Delegator common.Address
+	Validator common.Address
+	Amount    *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterDelegationsReleased is a free log retrieval operation binding the contract event 0x0581efc9221f467d4ae10e7767792b4ee743ee32792b249fcba2d97133d2763a.
+//
+// Solidity: e DelegationsReleased(delegator indexed address, validator indexed address, amount uint256)
+func (_Delegation *DelegationFilterer) FilterDelegationsReleased(opts *bind.FilterOpts, delegator []common.Address, validator []common.Address) (*DelegationDelegationsReleasedIterator, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.FilterLogs(opts, "DelegationsReleased", delegatorRule, validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationDelegationsReleasedIterator{contract: _Delegation.contract, event: "DelegationsReleased", logs: logs, sub: sub}, nil
+}
+
+// WatchDelegationsReleased is a free log subscription operation binding the contract event 0x0581efc9221f467d4ae10e7767792b4ee743ee32792b249fcba2d97133d2763a.
+//
+// Solidity: e DelegationsReleased(delegator indexed address, validator indexed address, amount uint256)
+func (_Delegation *DelegationFilterer) WatchDelegationsReleased(opts *bind.WatchOpts, sink chan<- *DelegationDelegationsReleased, delegator []common.Address, validator []common.Address) (event.Subscription, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.WatchLogs(opts, "DelegationsReleased", delegatorRule, validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DelegationDelegationsReleased)
+				if err := _Delegation.contract.UnpackLog(event, "DelegationsReleased", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// DelegationUndelegatedIterator is returned from FilterUndelegated and is used to iterate over the raw logs and unpacked data for Undelegated events raised by the Delegation contract.
+type DelegationUndelegatedIterator struct {
+	Event *DelegationUndelegated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *DelegationUndelegatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(DelegationUndelegated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(DelegationUndelegated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *DelegationUndelegatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *DelegationUndelegatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// DelegationUndelegated represents a Undelegated event raised by the Delegation contract.
+type DelegationUndelegated struct {
+	Delegator   common.Address
+	Validator   common.Address
+	Amount      *big.Int
+	AvailableAt *big.Int
+	Raw         types.Log // Blockchain specific contextual infos
+}
+
+// FilterUndelegated is a free log retrieval operation binding the contract event 0x3aace7340547de7b9156593a7652dc07ee900cea3fd8f82cb6c9d38b40829802.
+//
+// Solidity: e Undelegated(delegator indexed address, validator indexed address, amount uint256, availableAt uint256)
+func (_Delegation *DelegationFilterer) FilterUndelegated(opts *bind.FilterOpts, delegator []common.Address, validator []common.Address) (*DelegationUndelegatedIterator, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.FilterLogs(opts, "Undelegated", delegatorRule, validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationUndelegatedIterator{contract: _Delegation.contract, event: "Undelegated", logs: logs, sub: sub}, nil
+}
+
+// WatchUndelegated is a free log subscription operation binding the contract event 0x3aace7340547de7b9156593a7652dc07ee900cea3fd8f82cb6c9d38b40829802.
+//
+// Solidity: e Undelegated(delegator indexed address, validator indexed address, amount uint256, availableAt uint256)
+func (_Delegation *DelegationFilterer) WatchUndelegated(opts *bind.WatchOpts, sink chan<- *DelegationUndelegated, delegator []common.Address, validator []common.Address) (event.Subscription, error) {
+
+	var delegatorRule []interface{}
+	for _, delegatorItem := range delegator {
+		delegatorRule = append(delegatorRule, delegatorItem)
+	}
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _Delegation.contract.WatchLogs(opts, "Undelegated", delegatorRule, validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(DelegationUndelegated)
+				if err := _Delegation.contract.UnpackLog(event, "Undelegated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
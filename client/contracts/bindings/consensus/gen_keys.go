@@ -0,0 +1,374 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package consensus
+
+import (
+	"strings"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/event"
+)
+
+// ConsensusKeysABI is the input ABI used to generate the binding from.
+const ConsensusKeysABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getConsensusKey\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"key\",\"type\":\"address\"}],\"name\":\"setConsensusKey\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"consensusKeysChecksum\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"validator\",\"type\":\"address\"},{\"indexed\":true,\"name\":\"key\",\"type\":\"address\"}],\"name\":\"ConsensusKeySet\",\"type\":\"event\"}]"
+
+// ConsensusKeys is an auto generated Go binding around a Kowala contract.
+type ConsensusKeys struct {
+	ConsensusKeysCaller     // Read-only binding to the contract
+	ConsensusKeysTransactor // Write-only binding to the contract
+	ConsensusKeysFilterer   // Log filterer for contract events
+}
+
+// ConsensusKeysCaller is an auto generated read-only Go binding around a Kowala contract.
+type ConsensusKeysCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ConsensusKeysTransactor is an auto generated write-only Go binding around a Kowala contract.
+type ConsensusKeysTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ConsensusKeysFilterer is an auto generated log filtering Go binding around a Kowala contract events.
+type ConsensusKeysFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ConsensusKeysSession is an auto generated Go binding around a Kowala contract,
+// with pre-set call and transact options.
+type ConsensusKeysSession struct {
+	Contract     *ConsensusKeys    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// ConsensusKeysCallerSession is an auto generated read-only Go binding around a Kowala contract,
+// with pre-set call options.
+type ConsensusKeysCallerSession struct {
+	Contract *ConsensusKeysCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// ConsensusKeysTransactorSession is an auto generated write-only Go binding around a Kowala contract,
+// with pre-set transact options.
+type ConsensusKeysTransactorSession struct {
+	Contract     *ConsensusKeysTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// ConsensusKeysRaw is an auto generated low-level Go binding around a Kowala contract.
+type ConsensusKeysRaw struct {
+	Contract *ConsensusKeys // Generic contract binding to access the raw methods on
+}
+
+// ConsensusKeysCallerRaw is an auto generated low-level read-only Go binding around a Kowala contract.
+type ConsensusKeysCallerRaw struct {
+	Contract *ConsensusKeysCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ConsensusKeysTransactorRaw is an auto generated low-level write-only Go binding around a Kowala contract.
+type ConsensusKeysTransactorRaw struct {
+	Contract *ConsensusKeysTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewConsensusKeys creates a new instance of ConsensusKeys, bound to a specific deployed contract.
+func NewConsensusKeys(address common.Address, backend bind.ContractBackend) (*ConsensusKeys, error) {
+	contract, err := bindConsensusKeys(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsensusKeys{ConsensusKeysCaller: ConsensusKeysCaller{contract: contract}, ConsensusKeysTransactor: ConsensusKeysTransactor{contract: contract}, ConsensusKeysFilterer: ConsensusKeysFilterer{contract: contract}}, nil
+}
+
+// NewConsensusKeysCaller creates a new read-only instance of ConsensusKeys, bound to a specific deployed contract.
+func NewConsensusKeysCaller(address common.Address, caller bind.ContractCaller) (*ConsensusKeysCaller, error) {
+	contract, err := bindConsensusKeys(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsensusKeysCaller{contract: contract}, nil
+}
+
+// NewConsensusKeysTransactor creates a new write-only instance of ConsensusKeys, bound to a specific deployed contract.
+func NewConsensusKeysTransactor(address common.Address, transactor bind.ContractTransactor) (*ConsensusKeysTransactor, error) {
+	contract, err := bindConsensusKeys(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsensusKeysTransactor{contract: contract}, nil
+}
+
+// NewConsensusKeysFilterer creates a new log filterer instance of ConsensusKeys, bound to a specific deployed contract.
+func NewConsensusKeysFilterer(address common.Address, filterer bind.ContractFilterer) (*ConsensusKeysFilterer, error) {
+	contract, err := bindConsensusKeys(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsensusKeysFilterer{contract: contract}, nil
+}
+
+// bindConsensusKeys binds a generic wrapper to an already deployed contract.
+func bindConsensusKeys(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(ConsensusKeysABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ConsensusKeys *ConsensusKeysRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _ConsensusKeys.Contract.ConsensusKeysCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ConsensusKeys *ConsensusKeysRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ConsensusKeys.Contract.ConsensusKeysTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ConsensusKeys *ConsensusKeysRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ConsensusKeys.Contract.ConsensusKeysTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ConsensusKeys *ConsensusKeysCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _ConsensusKeys.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ConsensusKeys *ConsensusKeysTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ConsensusKeys.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ConsensusKeys *ConsensusKeysTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ConsensusKeys.Contract.contract.Transact(opts, method, params...)
+}
+
+// ConsensusKeysChecksum is a free data retrieval call binding the contract method 0x3cdf8855.
+//
+// Solidity: function consensusKeysChecksum() constant returns(bytes32)
+func (_ConsensusKeys *ConsensusKeysCaller) ConsensusKeysChecksum(opts *bind.CallOpts) ([32]byte, error) {
+	var (
+		ret0 = new([32]byte)
+	)
+	out := ret0
+	err := _ConsensusKeys.contract.Call(opts, out, "consensusKeysChecksum")
+	return *ret0, err
+}
+
+// ConsensusKeysChecksum is a free data retrieval call binding the contract method 0x3cdf8855.
+//
+// Solidity: function consensusKeysChecksum() constant returns(bytes32)
+func (_ConsensusKeys *ConsensusKeysSession) ConsensusKeysChecksum() ([32]byte, error) {
+	return _ConsensusKeys.Contract.ConsensusKeysChecksum(&_ConsensusKeys.CallOpts)
+}
+
+// ConsensusKeysChecksum is a free data retrieval call binding the contract method 0x3cdf8855.
+//
+// Solidity: function consensusKeysChecksum() constant returns(bytes32)
+func (_ConsensusKeys *ConsensusKeysCallerSession) ConsensusKeysChecksum() ([32]byte, error) {
+	return _ConsensusKeys.Contract.ConsensusKeysChecksum(&_ConsensusKeys.CallOpts)
+}
+
+// GetConsensusKey is a free data retrieval call binding the contract method 0xf356b476.
+//
+// Solidity: function getConsensusKey(validator address) constant returns(address)
+func (_ConsensusKeys *ConsensusKeysCaller) GetConsensusKey(opts *bind.CallOpts, validator common.Address) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _ConsensusKeys.contract.Call(opts, out, "getConsensusKey", validator)
+	return *ret0, err
+}
+
+// GetConsensusKey is a free data retrieval call binding the contract method 0xf356b476.
+//
+// Solidity: function getConsensusKey(validator address) constant returns(address)
+func (_ConsensusKeys *ConsensusKeysSession) GetConsensusKey(validator common.Address) (common.Address, error) {
+	return _ConsensusKeys.Contract.GetConsensusKey(&_ConsensusKeys.CallOpts, validator)
+}
+
+// GetConsensusKey is a free data retrieval call binding the contract method 0xf356b476.
+//
+// Solidity: function getConsensusKey(validator address) constant returns(address)
+func (_ConsensusKeys *ConsensusKeysCallerSession) GetConsensusKey(validator common.Address) (common.Address, error) {
+	return _ConsensusKeys.Contract.GetConsensusKey(&_ConsensusKeys.CallOpts, validator)
+}
+
+// SetConsensusKey is a paid mutator transaction binding the contract method 0x73341495.
+//
+// Solidity: function setConsensusKey(key address) returns()
+func (_ConsensusKeys *ConsensusKeysTransactor) SetConsensusKey(opts *bind.TransactOpts, key common.Address) (*types.Transaction, error) {
+	return _ConsensusKeys.contract.Transact(opts, "setConsensusKey", key)
+}
+
+// SetConsensusKey is a paid mutator transaction binding the contract method 0x73341495.
+//
+// Solidity: function setConsensusKey(key address) returns()
+func (_ConsensusKeys *ConsensusKeysSession) SetConsensusKey(key common.Address) (*types.Transaction, error) {
+	return _ConsensusKeys.Contract.SetConsensusKey(&_ConsensusKeys.TransactOpts, key)
+}
+
+// SetConsensusKey is a paid mutator transaction binding the contract method 0x73341495.
+//
+// Solidity: function setConsensusKey(key address) returns()
+func (_ConsensusKeys *ConsensusKeysTransactorSession) SetConsensusKey(key common.Address) (*types.Transaction, error) {
+	return _ConsensusKeys.Contract.SetConsensusKey(&_ConsensusKeys.TransactOpts, key)
+}
+
+// ConsensusKeysConsensusKeySetIterator is returned from FilterConsensusKeySet and is used to iterate over the raw logs and unpacked data for ConsensusKeySet events raised by the ConsensusKeys contract.
+type ConsensusKeysConsensusKeySetIterator struct {
+	Event *ConsensusKeysConsensusKeySet // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ConsensusKeysConsensusKeySetIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ConsensusKeysConsensusKeySet)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ConsensusKeysConsensusKeySet)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ConsensusKeysConsensusKeySetIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ConsensusKeysConsensusKeySetIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ConsensusKeysConsensusKeySet represents a ConsensusKeySet event raised by the ConsensusKeys contract.
+type ConsensusKeysConsensusKeySet struct {
+	Validator common.Address
+	Key       common.Address
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterConsensusKeySet is a free log retrieval operation binding the contract event 0xf3fc195677741428ebf32171fa6aece28d80881e5ba99a5dfdc19090a4146fc2.
+//
+// Solidity: e ConsensusKeySet(validator indexed address, key indexed address)
+func (_ConsensusKeys *ConsensusKeysFilterer) FilterConsensusKeySet(opts *bind.FilterOpts, validator []common.Address, key []common.Address) (*ConsensusKeysConsensusKeySetIterator, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+	var keyRule []interface{}
+	for _, keyItem := range key {
+		keyRule = append(keyRule, keyItem)
+	}
+
+	logs, sub, err := _ConsensusKeys.contract.FilterLogs(opts, "ConsensusKeySet", validatorRule, keyRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ConsensusKeysConsensusKeySetIterator{contract: _ConsensusKeys.contract, event: "ConsensusKeySet", logs: logs, sub: sub}, nil
+}
+
+// WatchConsensusKeySet is a free log subscription operation binding the contract event 0xf3fc195677741428ebf32171fa6aece28d80881e5ba99a5dfdc19090a4146fc2.
+//
+// Solidity: e ConsensusKeySet(validator indexed address, key indexed address)
+func (_ConsensusKeys *ConsensusKeysFilterer) WatchConsensusKeySet(opts *bind.WatchOpts, sink chan<- *ConsensusKeysConsensusKeySet, validator []common.Address, key []common.Address) (event.Subscription, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+	var keyRule []interface{}
+	for _, keyItem := range key {
+		keyRule = append(keyRule, keyItem)
+	}
+
+	logs, sub, err := _ConsensusKeys.contract.WatchLogs(opts, "ConsensusKeySet", validatorRule, keyRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ConsensusKeysConsensusKeySet)
+				if err := _ConsensusKeys.contract.UnpackLog(event, "ConsensusKeySet", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
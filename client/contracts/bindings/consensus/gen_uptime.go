@@ -0,0 +1,406 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package consensus
+
+import (
+	"math/big"
+	"strings"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/event"
+)
+
+// ValidatorUptimeABI is the input ABI used to generate the binding from.
+const ValidatorUptimeABI = "[{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"getUptime\",\"outputs\":[{\"name\":\"blocks\",\"type\":\"uint256\"},{\"name\":\"missed\",\"type\":\"uint256\"},{\"name\":\"jailedUntil\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"isJailed\",\"outputs\":[{\"name\":\"\",\"type\":\"bool\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"jailChecksum\",\"outputs\":[{\"name\":\"\",\"type\":\"bytes32\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[],\"name\":\"unjail\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"validator\",\"type\":\"address\"}],\"name\":\"Unjailed\",\"type\":\"event\"}]"
+
+// ValidatorUptime is an auto generated Go binding around a Kowala contract.
+type ValidatorUptime struct {
+	ValidatorUptimeCaller     // Read-only binding to the contract
+	ValidatorUptimeTransactor // Write-only binding to the contract
+	ValidatorUptimeFilterer   // Log filterer for contract events
+}
+
+// ValidatorUptimeCaller is an auto generated read-only Go binding around a Kowala contract.
+type ValidatorUptimeCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ValidatorUptimeTransactor is an auto generated write-only Go binding around a Kowala contract.
+type ValidatorUptimeTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ValidatorUptimeFilterer is an auto generated log filtering Go binding around a Kowala contract events.
+type ValidatorUptimeFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ValidatorUptimeSession is an auto generated Go binding around a Kowala contract,
+// with pre-set call and transact options.
+type ValidatorUptimeSession struct {
+	Contract     *ValidatorUptime  // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// ValidatorUptimeCallerSession is an auto generated read-only Go binding around a Kowala contract,
+// with pre-set call options.
+type ValidatorUptimeCallerSession struct {
+	Contract *ValidatorUptimeCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts          // Call options to use throughout this session
+}
+
+// ValidatorUptimeTransactorSession is an auto generated write-only Go binding around a Kowala contract,
+// with pre-set transact options.
+type ValidatorUptimeTransactorSession struct {
+	Contract     *ValidatorUptimeTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts          // Transaction auth options to use throughout this session
+}
+
+// ValidatorUptimeRaw is an auto generated low-level Go binding around a Kowala contract.
+type ValidatorUptimeRaw struct {
+	Contract *ValidatorUptime // Generic contract binding to access the raw methods on
+}
+
+// ValidatorUptimeCallerRaw is an auto generated low-level read-only Go binding around a Kowala contract.
+type ValidatorUptimeCallerRaw struct {
+	Contract *ValidatorUptimeCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ValidatorUptimeTransactorRaw is an auto generated low-level write-only Go binding around a Kowala contract.
+type ValidatorUptimeTransactorRaw struct {
+	Contract *ValidatorUptimeTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewValidatorUptime creates a new instance of ValidatorUptime, bound to a specific deployed contract.
+func NewValidatorUptime(address common.Address, backend bind.ContractBackend) (*ValidatorUptime, error) {
+	contract, err := bindValidatorUptime(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorUptime{ValidatorUptimeCaller: ValidatorUptimeCaller{contract: contract}, ValidatorUptimeTransactor: ValidatorUptimeTransactor{contract: contract}, ValidatorUptimeFilterer: ValidatorUptimeFilterer{contract: contract}}, nil
+}
+
+// NewValidatorUptimeCaller creates a new read-only instance of ValidatorUptime, bound to a specific deployed contract.
+func NewValidatorUptimeCaller(address common.Address, caller bind.ContractCaller) (*ValidatorUptimeCaller, error) {
+	contract, err := bindValidatorUptime(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorUptimeCaller{contract: contract}, nil
+}
+
+// NewValidatorUptimeTransactor creates a new write-only instance of ValidatorUptime, bound to a specific deployed contract.
+func NewValidatorUptimeTransactor(address common.Address, transactor bind.ContractTransactor) (*ValidatorUptimeTransactor, error) {
+	contract, err := bindValidatorUptime(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorUptimeTransactor{contract: contract}, nil
+}
+
+// NewValidatorUptimeFilterer creates a new log filterer instance of ValidatorUptime, bound to a specific deployed contract.
+func NewValidatorUptimeFilterer(address common.Address, filterer bind.ContractFilterer) (*ValidatorUptimeFilterer, error) {
+	contract, err := bindValidatorUptime(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorUptimeFilterer{contract: contract}, nil
+}
+
+// bindValidatorUptime binds a generic wrapper to an already deployed contract.
+func bindValidatorUptime(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(ValidatorUptimeABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ValidatorUptime *ValidatorUptimeRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _ValidatorUptime.Contract.ValidatorUptimeCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ValidatorUptime *ValidatorUptimeRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ValidatorUptime.Contract.ValidatorUptimeTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ValidatorUptime *ValidatorUptimeRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ValidatorUptime.Contract.ValidatorUptimeTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ValidatorUptime *ValidatorUptimeCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _ValidatorUptime.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ValidatorUptime *ValidatorUptimeTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ValidatorUptime.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ValidatorUptime *ValidatorUptimeTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ValidatorUptime.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetUptime is a free data retrieval call binding the contract method 0x9da2defd.
+//
+// Solidity: function getUptime(validator address) constant returns(blocks uint256, missed uint256, jailedUntil uint256)
+func (_ValidatorUptime *ValidatorUptimeCaller) GetUptime(opts *bind.CallOpts, validator common.Address) (struct {
+	Blocks      *big.Int
+	Missed      *big.Int
+	JailedUntil *big.Int
+}, error) {
+	ret := new(struct {
+		Blocks      *big.Int
+		Missed      *big.Int
+		JailedUntil *big.Int
+	})
+	out := ret
+	err := _ValidatorUptime.contract.Call(opts, out, "getUptime", validator)
+	return *ret, err
+}
+
+// GetUptime is a free data retrieval call binding the contract method 0x9da2defd.
+//
+// Solidity: function getUptime(validator address) constant returns(blocks uint256, missed uint256, jailedUntil uint256)
+func (_ValidatorUptime *ValidatorUptimeSession) GetUptime(validator common.Address) (struct {
+	Blocks      *big.Int
+	Missed      *big.Int
+	JailedUntil *big.Int
+}, error) {
+	return _ValidatorUptime.Contract.GetUptime(&_ValidatorUptime.CallOpts, validator)
+}
+
+// GetUptime is a free data retrieval call binding the contract method 0x9da2defd.
+//
+// Solidity: function getUptime(validator address) constant returns(blocks uint256, missed uint256, jailedUntil uint256)
+func (_ValidatorUptime *ValidatorUptimeCallerSession) GetUptime(validator common.Address) (struct {
+	Blocks      *big.Int
+	Missed      *big.Int
+	JailedUntil *big.Int
+}, error) {
+	return _ValidatorUptime.Contract.GetUptime(&_ValidatorUptime.CallOpts, validator)
+}
+
+// IsJailed is a free data retrieval call binding the contract method 0x14bfb527.
+//
+// Solidity: function isJailed(validator address) constant returns(bool)
+func (_ValidatorUptime *ValidatorUptimeCaller) IsJailed(opts *bind.CallOpts, validator common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _ValidatorUptime.contract.Call(opts, out, "isJailed", validator)
+	return *ret0, err
+}
+
+// IsJailed is a free data retrieval call binding the contract method 0x14bfb527.
+//
+// Solidity: function isJailed(validator address) constant returns(bool)
+func (_ValidatorUptime *ValidatorUptimeSession) IsJailed(validator common.Address) (bool, error) {
+	return _ValidatorUptime.Contract.IsJailed(&_ValidatorUptime.CallOpts, validator)
+}
+
+// IsJailed is a free data retrieval call binding the contract method 0x14bfb527.
+//
+// Solidity: function isJailed(validator address) constant returns(bool)
+func (_ValidatorUptime *ValidatorUptimeCallerSession) IsJailed(validator common.Address) (bool, error) {
+	return _ValidatorUptime.Contract.IsJailed(&_ValidatorUptime.CallOpts, validator)
+}
+
+// JailChecksum is a free data retrieval call binding the contract method 0xaf266abb.
+//
+// Solidity: function jailChecksum() constant returns(bytes32)
+func (_ValidatorUptime *ValidatorUptimeCaller) JailChecksum(opts *bind.CallOpts) ([32]byte, error) {
+	var (
+		ret0 = new([32]byte)
+	)
+	out := ret0
+	err := _ValidatorUptime.contract.Call(opts, out, "jailChecksum")
+	return *ret0, err
+}
+
+// JailChecksum is a free data retrieval call binding the contract method 0xaf266abb.
+//
+// Solidity: function jailChecksum() constant returns(bytes32)
+func (_ValidatorUptime *ValidatorUptimeSession) JailChecksum() ([32]byte, error) {
+	return _ValidatorUptime.Contract.JailChecksum(&_ValidatorUptime.CallOpts)
+}
+
+// JailChecksum is a free data retrieval call binding the contract method 0xaf266abb.
+//
+// Solidity: function jailChecksum() constant returns(bytes32)
+func (_ValidatorUptime *ValidatorUptimeCallerSession) JailChecksum() ([32]byte, error) {
+	return _ValidatorUptime.Contract.JailChecksum(&_ValidatorUptime.CallOpts)
+}
+
+// Unjail is a paid mutator transaction binding the contract method 0xf679d305.
+//
+// Solidity: function unjail() returns()
+func (_ValidatorUptime *ValidatorUptimeTransactor) Unjail(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ValidatorUptime.contract.Transact(opts, "unjail")
+}
+
+// Unjail is a paid mutator transaction binding the contract method 0xf679d305.
+//
+// Solidity: function unjail() returns()
+func (_ValidatorUptime *ValidatorUptimeSession) Unjail() (*types.Transaction, error) {
+	return _ValidatorUptime.Contract.Unjail(&_ValidatorUptime.TransactOpts)
+}
+
+// Unjail is a paid mutator transaction binding the contract method 0xf679d305.
+//
+// Solidity: function unjail() returns()
+func (_ValidatorUptime *ValidatorUptimeTransactorSession) Unjail() (*types.Transaction, error) {
+	return _ValidatorUptime.Contract.Unjail(&_ValidatorUptime.TransactOpts)
+}
+
+// ValidatorUptimeUnjailedIterator is returned from FilterUnjailed and is used to iterate over the raw logs and unpacked data for Unjailed events raised by the ValidatorUptime contract.
+type ValidatorUptimeUnjailedIterator struct {
+	Event *ValidatorUptimeUnjailed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log      // Log channel receiving the found contract events
+	sub  kowala.Subscription // Subscription for errors, completion and termination
+	done bool                // Whether the subscription completed delivering logs
+	fail error               // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ValidatorUptimeUnjailedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ValidatorUptimeUnjailed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ValidatorUptimeUnjailed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ValidatorUptimeUnjailedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ValidatorUptimeUnjailedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ValidatorUptimeUnjailed represents a Unjailed event raised by the ValidatorUptime contract.
+type ValidatorUptimeUnjailed struct {
+	Validator common.Address
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterUnjailed is a free log retrieval operation binding the contract event 0xfa5039497ad9ba11f0eb5239b2614e925541bbcc0cf3476dd68e1927c86d33ff.
+//
+// Solidity: e Unjailed(validator indexed address)
+func (_ValidatorUptime *ValidatorUptimeFilterer) FilterUnjailed(opts *bind.FilterOpts, validator []common.Address) (*ValidatorUptimeUnjailedIterator, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _ValidatorUptime.contract.FilterLogs(opts, "Unjailed", validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorUptimeUnjailedIterator{contract: _ValidatorUptime.contract, event: "Unjailed", logs: logs, sub: sub}, nil
+}
+
+// WatchUnjailed is a free log subscription operation binding the contract event 0xfa5039497ad9ba11f0eb5239b2614e925541bbcc0cf3476dd68e1927c86d33ff.
+//
+// Solidity: e Unjailed(validator indexed address)
+func (_ValidatorUptime *ValidatorUptimeFilterer) WatchUnjailed(opts *bind.WatchOpts, sink chan<- *ValidatorUptimeUnjailed, validator []common.Address) (event.Subscription, error) {
+
+	var validatorRule []interface{}
+	for _, validatorItem := range validator {
+		validatorRule = append(validatorRule, validatorItem)
+	}
+
+	logs, sub, err := _ValidatorUptime.contract.WatchLogs(opts, "Unjailed", validatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ValidatorUptimeUnjailed)
+				if err := _ValidatorUptime.contract.UnpackLog(event, "Unjailed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
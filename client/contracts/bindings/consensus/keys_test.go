@@ -0,0 +1,107 @@
+package consensus_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind/backends"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+func TestSetConsensusKey(t *testing.T) {
+	alice, _ := crypto.GenerateKey()
+	bob, _ := crypto.GenerateKey()
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		crypto.PubkeyToAddress(alice.PublicKey): {Balance: big.NewInt(params.Kcoin)},
+		crypto.PubkeyToAddress(bob.PublicKey):   {Balance: big.NewInt(params.Kcoin)},
+	})
+	keys, err := consensus.NewConsensusKeys(vm.ConsensusKeysAddress, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// setKey registers the key of the account and returns the receipt
+	setKey := func(account *bind.TransactOpts, key common.Address) *types.Receipt {
+		opts := *account
+		opts.GasLimit = params.TxGas + params.ConsensusKeySetGas + 10000
+		tx, err := keys.SetConsensusKey(&opts, key)
+		if err != nil {
+			t.Fatalf("failed to send the key registration: %v", err)
+		}
+		backend.Commit()
+		receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return receipt
+	}
+	var (
+		aliceOpts, bobOpts = bind.NewKeyedTransactor(alice), bind.NewKeyedTransactor(bob)
+		aliceAddr, bobAddr = aliceOpts.From, bobOpts.From
+		key, rotated       = common.HexToAddress("0x0a"), common.HexToAddress("0x0b")
+	)
+
+	if registered, err := keys.GetConsensusKey(&bind.CallOpts{}, aliceAddr); err != nil || registered != (common.Address{}) {
+		t.Fatalf("unregistered key mismatch: have %x (%v), want none", registered, err)
+	}
+	if receipt := setKey(aliceOpts, key); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to register the key")
+	} else if len(receipt.Logs) != 1 || receipt.Logs[0].Topics[1] != aliceAddr.Hash() || receipt.Logs[0].Topics[2] != key.Hash() {
+		t.Fatalf("key log mismatch: %v", receipt.Logs)
+	}
+	if registered, err := keys.GetConsensusKey(&bind.CallOpts{}, aliceAddr); err != nil || registered != key {
+		t.Fatalf("registered key mismatch: have %x (%v), want %x", registered, err, key)
+	}
+	checksum, err := keys.ConsensusKeysChecksum(&bind.CallOpts{})
+	if err != nil || checksum == ([32]byte{}) {
+		t.Fatalf("consensus keys checksum not updated: %v", err)
+	}
+
+	// a key signs on behalf of a single account
+	if receipt := setKey(bobOpts, key); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("registered the key of another account")
+	}
+	if receipt := setKey(bobOpts, common.Address{}); receipt.Status != types.ReceiptStatusFailed {
+		t.Fatal("registered an empty key")
+	}
+
+	// the rotation releases the previous key
+	if receipt := setKey(aliceOpts, rotated); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to rotate the key")
+	}
+	if receipt := setKey(bobOpts, key); receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("failed to register the released key")
+	}
+	if registered, err := keys.GetConsensusKey(&bind.CallOpts{}, bobAddr); err != nil || registered != key {
+		t.Fatalf("registered key mismatch: have %x (%v), want %x", registered, err, key)
+	}
+	if rotatedChecksum, _ := keys.ConsensusKeysChecksum(&bind.CallOpts{}); rotatedChecksum == checksum {
+		t.Fatal("consensus keys checksum not updated")
+	}
+}
+
+func TestConsensusKeyBeforeFork(t *testing.T) {
+	config := *params.AllKonsensusProtocolChanges
+	config.ConsensusKeyBlock = nil
+
+	alice, _ := crypto.GenerateKey()
+	account := crypto.PubkeyToAddress(alice.PublicKey)
+	backend := backends.NewSimulatedBackendWithConfig(&config, core.GenesisAlloc{account: {Balance: big.NewInt(params.Kcoin)}})
+	keys, err := consensus.NewConsensusKeysCaller(vm.ConsensusKeysAddress, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// there isn't any consensus keys contract before the fork
+	if _, err := keys.GetConsensusKey(&bind.CallOpts{}, account); err != bind.ErrNoCode {
+		t.Fatalf("error mismatch: have %v, want %v", err, bind.ErrNoCode)
+	}
+}
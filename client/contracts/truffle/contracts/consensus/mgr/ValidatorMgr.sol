@@ -348,4 +348,5 @@ contract ValidatorMgr is Pausable, Initializable{
         tkn = TKN(_from, _value/*, _data, bytes4(u)*/);
         _registerValidator();
     }
+
 }
\ No newline at end of file
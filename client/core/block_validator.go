@@ -22,6 +22,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/consensus"
 	"github.com/kowala-tech/kcoin/client/core/state"
 	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/log"
 	"github.com/kowala-tech/kcoin/client/params"
 )
 
@@ -62,7 +63,14 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	// Header validity is known at this point, check transactions
 	header := block.Header()
 
-	// @TODO (rgeraldes) - add commit verification + hash
+	if commit := block.LastCommit(); commit != nil && commit.Aggregate() != nil && !v.config.IsAggregateCommit(block.Number()) {
+		return consensus.ErrAggregateCommitNotActive
+	}
+	if v.config.IsAggregateCommit(block.Number()) && block.NumberU64() > 1 {
+		if err := v.validateCommit(block); err != nil {
+			return err
+		}
+	}
 
 	if hash := types.DeriveSha(block.Transactions()); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
@@ -70,6 +78,38 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	return nil
 }
 
+// validateCommit verifies the commit of the parent block carried by the block
+// against the voters of the parent block election, which started from the
+// state of the grandparent block.
+func (v *BlockValidator) validateCommit(block *types.Block) error {
+	parent := v.bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	// The election of the block following the pivot block of a fast sync or
+	// a checkpoint started from a state that was never downloaded, and whose
+	// header may not be backfilled yet. Its commit was verified by the peers
+	// the pivot block was synced from.
+	pivot := v.bc.SyncPivot()
+	grandparent := v.bc.GetHeader(parent.ParentHash, parent.Number.Uint64()-1)
+	if grandparent == nil {
+		if pivot > 0 && block.NumberU64() <= pivot+1 {
+			log.Warn("Skipping the commit verification, missing election header", "number", block.Number(), "hash", block.Hash())
+			return nil
+		}
+		return consensus.ErrUnknownAncestor
+	}
+	statedb, err := v.bc.StateAt(grandparent.Root)
+	if err != nil {
+		if pivot > 0 && block.NumberU64() <= pivot+1 {
+			log.Warn("Skipping the commit verification, missing election state", "number", block.Number(), "hash", block.Hash(), "err", err)
+			return nil
+		}
+		return consensus.ErrUnknownAncestor
+	}
+	return v.engine.VerifyBlockCommit(v.bc, block, statedb)
+}
+
 // ValidateState validates the various changes that happen after a state
 // transition, such as amount of used gas, the receipt roots and the state root
 // itself. ValidateState returns a database batch if the validation was a success
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/consensus"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core/rawdb"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// Tests that the commit verification is only skipped for the missing election
+// state of the block following the sync pivot.
+func TestValidateCommitMissingElectionState(t *testing.T) {
+	_, genesis, blocks, _ := generateParallelChain(t, 4, 5)
+
+	db := kcoindb.NewMemDatabase()
+	genesis.MustCommit(db)
+	chain, err := NewBlockChain(db, &CacheConfig{Disabled: true}, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	chain.Stop()
+
+	// the election of the third block started from the state of the first one
+	if err := db.Delete(blocks[0].Root().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	chain, err = NewBlockChain(db, &CacheConfig{Disabled: true}, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+	validator := chain.Validator().(*BlockValidator)
+
+	if err := validator.validateCommit(blocks[2]); err != consensus.ErrUnknownAncestor {
+		t.Fatalf("error mismatch: have %v, want %v", err, consensus.ErrUnknownAncestor)
+	}
+	rawdb.WriteSyncPivot(db, blocks[1].NumberU64())
+	if err := validator.validateCommit(blocks[2]); err != nil {
+		t.Fatalf("failed to skip the commit of the block following the pivot: %v", err)
+	}
+	if err := validator.validateCommit(blocks[3]); err != nil {
+		t.Fatalf("failed to verify the commit of the block: %v", err)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/consensus"
 	"github.com/kowala-tech/kcoin/client/core/rawdb"
 	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/state/pruner"
 	"github.com/kowala-tech/kcoin/client/core/types"
 	"github.com/kowala-tech/kcoin/client/core/vm"
 	"github.com/kowala-tech/kcoin/client/crypto"
@@ -35,6 +36,18 @@ var (
 	blockInsertTimer = metrics.NewRegisteredTimer("chain/inserts", nil)
 
 	ErrNoGenesis = errors.New("Genesis not found in chain")
+
+	// ErrNoCheckpointGap is returned when backfilling headers below a checkpoint
+	// if all of them are already known.
+	ErrNoCheckpointGap = errors.New("no headers missing below the checkpoint")
+
+	// ErrStatePruned is returned if the state of a block was requested that was
+	// deleted by the state pruning.
+	ErrStatePruned = errors.New("state pruned")
+
+	// ErrPruningInProgress is returned if a state pruning is requested while
+	// another one is still running.
+	ErrPruningInProgress = errors.New("state pruning already in progress")
 )
 
 const (
@@ -45,6 +58,10 @@ const (
 	badBlockLimit       = 10
 	triesInMemory       = 128
 
+	// MinStateRetention is the minimum number of recent blocks whose state is
+	// retained by the state pruning, the tries kept in memory are never pruned.
+	MinStateRetention = triesInMemory
+
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	BlockChainVersion = 3
 )
@@ -55,6 +72,8 @@ type CacheConfig struct {
 	Disabled      bool          // Whether to disable trie write caching (archive node)
 	TrieNodeLimit int           // Memory limit (MB) at which to flush the current in-memory trie to disk
 	TrieTimeLimit time.Duration // Time limit after which to flush the current in-memory trie to disk
+
+	StateRetention uint64 // Number of recent blocks whose state survives the online pruning (0 = disabled)
 }
 
 // BlockChain represents the canonical chain given a database with a genesis
@@ -107,6 +126,7 @@ type BlockChain struct {
 	// procInterrupt must be atomically called
 	procInterrupt int32          // interrupt signaler for block processing
 	wg            sync.WaitGroup // chain processing wait group for shutting down
+	pruning       int32          // pruning must be called atomically, set while the state is pruned
 
 	engine    consensus.Engine
 	processor Processor // block processor interface
@@ -256,6 +276,14 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	bc.hc.SetHead(head, delFn)
 	currentHeader := bc.hc.CurrentHeader()
 
+	// Frozen blocks can't be deleted one by one, drop the ancient store items
+	// above the new head altogether
+	if ancients, ok := bc.db.(kcoindb.AncientStore); ok {
+		if err := ancients.TruncateAncients(currentHeader.Number.Uint64() + 1); err != nil {
+			return err
+		}
+	}
+
 	// Clear out any stale content from the caches
 	bc.bodyCache.Purge()
 	bc.bodyRLPCache.Purge()
@@ -304,6 +332,7 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 		return err
 	}
 	// If all checks out, manually set the head block
+	rawdb.WriteSyncPivot(bc.db, block.NumberU64())
 	bc.mu.Lock()
 	bc.currentBlock.Store(block)
 	bc.mu.Unlock()
@@ -312,6 +341,187 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	return nil
 }
 
+// InsertCheckpoint sets the head of the chain to a trusted checkpoint block
+// whose state has already been downloaded. The ancestors of the checkpoint
+// don't need to be known; their headers can be backfilled later on through
+// InsertCheckpointHeaders.
+func (bc *BlockChain) InsertCheckpoint(block *types.Block, receipts types.Receipts) error {
+	if _, err := trie.NewSecure(block.Root(), bc.stateCache.TrieDB(), 0); err != nil {
+		return err
+	}
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	hash, number := block.Hash(), block.NumberU64()
+
+	batch := bc.db.NewBatch()
+	rawdb.WriteBlock(batch, block)
+	rawdb.WriteReceipts(batch, hash, number, receipts)
+	rawdb.WriteTxLookupEntries(batch, block)
+	rawdb.WriteCanonicalHash(batch, hash, number)
+	rawdb.WriteHeadBlockHash(batch, hash)
+	rawdb.WriteHeadFastBlockHash(batch, hash)
+	if rawdb.ReadCheckpointTail(bc.db) == 0 {
+		rawdb.WriteCheckpointTail(batch, number)
+	}
+	rawdb.WriteSyncPivot(batch, number)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	bc.mu.Lock()
+	bc.hc.SetCurrentHeader(block.Header())
+	bc.currentFastBlock.Store(block)
+	bc.currentBlock.Store(block)
+	bc.mu.Unlock()
+
+	log.Info("Committed checkpoint as new head block", "number", number, "hash", hash)
+	return nil
+}
+
+// CheckpointTail returns the number of the lowest block whose header is known
+// after a checkpoint sync, or zero if the header chain is complete.
+func (bc *BlockChain) CheckpointTail() uint64 {
+	return rawdb.ReadCheckpointTail(bc.db)
+}
+
+// SyncPivot returns the number of the block whose state was downloaded by the
+// last fast or checkpoint sync, or zero if the chain was fully processed.
+func (bc *BlockChain) SyncPivot() uint64 {
+	return rawdb.ReadSyncPivot(bc.db)
+}
+
+// StateTail returns the number of the oldest block whose state survived the
+// last state pruning, or zero if the state was never pruned.
+func (bc *BlockChain) StateTail() uint64 {
+	return rawdb.ReadStateTail(bc.db)
+}
+
+// StatePruned reports whether the state of the given block was deleted by the
+// state pruning. The genesis state is never pruned.
+func (bc *BlockChain) StatePruned(number uint64) bool {
+	return number > 0 && number < rawdb.ReadStateTail(bc.db)
+}
+
+// PruneState deletes the state of all the blocks except the genesis block and
+// the retain most recent ones. The bloom size of the live state entries is
+// given in megabytes, zero picks the default size.
+func (bc *BlockChain) PruneState(retain uint64, bloomSize uint64) error {
+	if !atomic.CompareAndSwapInt32(&bc.pruning, 0, 1) {
+		return ErrPruningInProgress
+	}
+	return bc.pruneState(retain, bloomSize)
+}
+
+// pruneState deletes the historical state outside of the retention window. The
+// pruning flag must be set by the caller, it's cleared once done.
+func (bc *BlockChain) pruneState(retain uint64, bloomSize uint64) error {
+	defer atomic.StoreInt32(&bc.pruning, 0)
+
+	diskdb := rawdb.LevelDB(bc.db)
+	if diskdb == nil {
+		return errors.New("state pruning requires a leveldb database")
+	}
+	if bc.cacheConfig.Disabled {
+		return errors.New("state pruning is not available on archive nodes")
+	}
+	if retain < MinStateRetention {
+		retain = MinStateRetention
+	}
+	// The imports carry on while pruning: the sweep only visits the entries on
+	// disk when it starts, and no trie node is flushed until the pruning flag
+	// is cleared, so the nodes of the blocks imported in the mean time can't
+	// be deleted. The flushes in progress are done once the block lock is
+	// held, the roots collected below include the ones they flushed.
+	bc.mu.Lock()
+	var (
+		head   = bc.CurrentBlock().NumberU64()
+		tail   = uint64(1)
+		triedb = bc.stateCache.TrieDB()
+		roots  = []common.Hash{bc.genesisBlock.Root()}
+		seen   = map[common.Hash]bool{bc.genesisBlock.Root(): true}
+	)
+	if head >= retain {
+		tail = head - retain + 1
+	}
+	if tail <= rawdb.ReadStateTail(bc.db) {
+		bc.mu.Unlock()
+		return nil
+	}
+	for number := tail; number <= head; number++ {
+		header := bc.GetHeaderByNumber(number)
+		if header == nil || seen[header.Root] {
+			continue
+		}
+		// Only a few of the roots are flushed to disk, skip the ones that were
+		// already garbage collected from memory
+		if _, err := triedb.Node(header.Root); err != nil {
+			continue
+		}
+		seen[header.Root] = true
+		roots = append(roots, header.Root)
+	}
+	bc.mu.Unlock()
+
+	log.Info("Pruning historical state", "tail", tail, "head", head, "roots", len(roots))
+	if err := pruner.NewPruner(diskdb, bc.stateCache, bloomSize).Prune(roots, bc.quit); err != nil {
+		return err
+	}
+	rawdb.WriteStateTail(bc.db, tail)
+	return nil
+}
+
+// InsertCheckpointHeaders backfills the headers below the checkpoint tail. The
+// headers must be ordered from the highest to the lowest and link to the tail
+// by their hashes.
+func (bc *BlockChain) InsertCheckpointHeaders(headers []*types.Header) (int, error) {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	tail := rawdb.ReadCheckpointTail(bc.db)
+	if tail == 0 {
+		return 0, ErrNoCheckpointGap
+	}
+	child := bc.GetHeaderByNumber(tail)
+	if child == nil {
+		return 0, fmt.Errorf("missing checkpoint tail header #%d", tail)
+	}
+
+	batch := bc.db.NewBatch()
+	for i, header := range headers {
+		number := header.Number.Uint64()
+		if number+1 != child.Number.Uint64() || header.Hash() != child.ParentHash {
+			return i, fmt.Errorf("non contiguous backfill: item %d is #%d [%x…], child is #%d (parent [%x…])", i, number,
+				header.Hash().Bytes()[:4], child.Number, child.ParentHash[:4])
+		}
+		if number == 0 {
+			// the chain is complete once we get to the genesis block
+			if header.Hash() != bc.genesisBlock.Hash() {
+				return i, ErrNoGenesis
+			}
+			child = header
+			break
+		}
+		rawdb.WriteHeader(batch, header)
+		rawdb.WriteCanonicalHash(batch, header.Hash(), number)
+		child = header
+	}
+
+	tail = child.Number.Uint64()
+	if tail == 1 && child.ParentHash == bc.genesisBlock.Hash() {
+		tail = 0
+	}
+	if tail == 0 {
+		rawdb.DeleteCheckpointTail(batch)
+	} else {
+		rawdb.WriteCheckpointTail(batch, tail)
+	}
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	return len(headers), nil
+}
+
 // GasLimit returns the gas limit of the current HEAD block.
 func (bc *BlockChain) GasLimit() uint64 {
 	return bc.CurrentBlock().GasLimit()
@@ -857,7 +1067,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
 		bc.triegc.Push(root, -float32(block.NumberU64()))
 
-		if current := block.NumberU64(); current > triesInMemory {
+		// Nothing may be flushed or dereferenced while the state is pruned, the
+		// collection catches up once the pruning is done
+		if current := block.NumberU64(); current > triesInMemory && atomic.LoadInt32(&bc.pruning) == 0 {
 			// If we exceeded our memory allowance, flush matured singleton nodes to disk
 			var (
 				nodes, imgs = triedb.Size()
@@ -891,6 +1103,19 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 				}
 				triedb.Dereference(root.(common.Hash))
 			}
+			// Prune the historical state once the retention window moved past
+			// the previous tail by a full window
+			if retain := bc.cacheConfig.StateRetention; retain > 0 && current >= rawdb.ReadStateTail(bc.db)+2*retain {
+				if atomic.CompareAndSwapInt32(&bc.pruning, 0, 1) {
+					bc.wg.Add(1)
+					go func() {
+						defer bc.wg.Done()
+						if err := bc.pruneState(retain, 0); err != nil {
+							log.Error("Failed to prune state", "err", err)
+						}
+					}()
+				}
+			}
 		}
 	}
 
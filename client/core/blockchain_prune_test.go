@@ -0,0 +1,184 @@
+package core
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core/rawdb"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/kowala-tech/kcoin/client/rlp"
+)
+
+// Tests that the state pruned while blocks are imported keeps the state of the
+// retained blocks intact.
+func TestPruneStateWhileImporting(t *testing.T) {
+	_, genesis, blocks, _ := generateParallelChain(t, 2*MinStateRetention+150, 5)
+
+	dir, err := ioutil.TempDir("", "prune")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := kcoindb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	genesis.MustCommit(db)
+
+	// Flush the state of every block to disk, so that the pruning runs while
+	// the imports write state entries
+	cacheConfig := &CacheConfig{
+		TrieNodeLimit:  0,
+		TrieTimeLimit:  time.Nanosecond,
+		StateRetention: MinStateRetention,
+	}
+	chain, err := NewBlockChain(db, cacheConfig, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	done := make(chan error)
+	go func() {
+		for _, block := range blocks {
+			if _, err := chain.InsertChain([]*types.Block{block}); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	for importing := true; importing; {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("failed to import block: %v", err)
+			}
+			importing = false
+		default:
+			if err := chain.PruneState(MinStateRetention, 1); err != nil && err != ErrPruningInProgress {
+				t.Fatalf("failed to prune state: %v", err)
+			}
+		}
+	}
+	if err := chain.PruneState(MinStateRetention, 1); err != nil {
+		t.Fatalf("failed to prune state: %v", err)
+	}
+
+	head := chain.CurrentBlock()
+	if head.Hash() != blocks[len(blocks)-1].Hash() {
+		t.Fatalf("head mismatch: have %x, want %x", head.Hash(), blocks[len(blocks)-1].Hash())
+	}
+	tail := chain.StateTail()
+	if tail == 0 {
+		t.Fatal("state not pruned")
+	}
+	if err := checkStateComplete(chain.stateCache, head.Root()); err != nil {
+		t.Fatalf("head state incomplete: %v", err)
+	}
+	// Every retained state flushed to disk must be complete on disk
+	diskdb := state.NewDatabase(db)
+	for number := tail; number <= head.NumberU64(); number++ {
+		root := chain.GetHeaderByNumber(number).Root
+		if ok, _ := db.Has(root[:]); !ok {
+			continue
+		}
+		if err := checkStateComplete(diskdb, root); err != nil {
+			t.Fatalf("state of block %d incomplete: %v", number, err)
+		}
+	}
+	if root := chain.GetHeaderByNumber(tail - 1).Root; !chain.StatePruned(tail - 1) {
+		t.Errorf("state of block %d (%x) not reported as pruned", tail-1, root)
+	}
+	if rawdb.ReadStateTail(db) != tail {
+		t.Errorf("state tail mismatch")
+	}
+}
+
+// Tests that the pruning doesn't hold the import lock, so that the blocks keep
+// being imported while the historical state is swept.
+func TestPruneStateDoesntBlockImports(t *testing.T) {
+	_, genesis, blocks, _ := generateParallelChain(t, MinStateRetention+10, 5)
+
+	dir, err := ioutil.TempDir("", "prune")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := kcoindb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	genesis.MustCommit(db)
+
+	chain, err := NewBlockChain(db, nil, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+
+	chain.chainmu.Lock()
+	defer chain.chainmu.Unlock()
+
+	done := make(chan error, 1)
+	go func() { done <- chain.PruneState(MinStateRetention, 1) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("failed to prune state: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("pruning blocked by the import lock")
+	}
+}
+
+// checkStateComplete walks the whole state of the root, including the storage
+// tries and the contract codes.
+func checkStateComplete(db state.Database, root common.Hash) error {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if !it.Leaf() {
+			continue
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+			return err
+		}
+		addrHash := common.BytesToHash(it.LeafKey())
+		if !bytes.Equal(account.CodeHash, crypto.Keccak256(nil)) {
+			if _, err := db.ContractCode(addrHash, common.BytesToHash(account.CodeHash)); err != nil {
+				return err
+			}
+		}
+		storage, err := db.OpenStorageTrie(addrHash, account.Root)
+		if err != nil {
+			return err
+		}
+		sit := storage.NodeIterator(nil)
+		for sit.Next(true) {
+		}
+		if err := sit.Error(); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
@@ -50,6 +50,21 @@ func (b *BlockGen) SetExtra(data []byte) {
 	b.header.Extra = data
 }
 
+// OffsetTime moves the time of the generated block forward by the given
+// number of seconds. It must be called before adding transactions.
+func (b *BlockGen) OffsetTime(seconds int64) {
+	if len(b.txs) > 0 {
+		panic("time must be set before adding transactions")
+	}
+	b.header.Time.Add(b.header.Time, big.NewInt(seconds))
+}
+
+// SetCommit sets the commit of the parent block carried by the generated
+// block.
+func (b *BlockGen) SetCommit(commit *types.Commit) {
+	b.lastCommit = commit
+}
+
 // AddTx adds a transaction to the generated block. If no coinbase has
 // been set, the block's coinbase is set to the zero address.
 //
@@ -32,4 +32,8 @@ var (
 	// ErrNonceTooHigh is returned if the nonce of a transaction is higher than the
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
+
+	// ErrSponsoredTxNotActive is returned if a sponsored transaction is included
+	// before the sponsored transactions fork.
+	ErrSponsoredTxNotActive = errors.New("sponsored transactions not yet active")
 )
@@ -0,0 +1,200 @@
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/metrics"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+var (
+	speculativeHitCounter      = metrics.NewRegisteredCounter("chain/parallel/hits", nil)      // Transactions committed from their speculative execution
+	speculativeConflictCounter = metrics.NewRegisteredCounter("chain/parallel/conflicts", nil) // Transactions re-executed after a conflict
+)
+
+// Speculation states, tracking which of the workers and the committer claimed
+// a transaction first.
+const (
+	speculationPending int32 = iota // Not picked up by a worker yet
+	speculationRunning              // Executed by a worker
+	speculationSkipped              // Claimed by the committer before any worker
+)
+
+// speculation is the result of executing a transaction against the state
+// snapshot taken before the first transaction was committed.
+type speculation struct {
+	tx     *types.Transaction
+	status int32
+	done   chan struct{}
+
+	receipt *types.Receipt
+	gas     uint64
+	access  *state.AccessSet
+	err     error
+}
+
+// ParallelExecutor executes a list of transactions optimistically on multiple
+// goroutines, each against its own copy of the state, while the transactions
+// are committed in order to the state by ApplyTransaction.
+//
+// The speculative execution of a transaction records the state it reads and
+// writes. If none of the transactions committed before it wrote any of the
+// state it read, its writes are replayed onto the state. Otherwise it's
+// executed again against the state, so that the results are always identical
+// to those of the sequential execution.
+type ParallelExecutor struct {
+	config *params.ChainConfig
+	bc     ChainContext
+	author *common.Address
+	header *types.Header
+	cfg    vm.Config
+
+	statedb *state.StateDB  // State the transactions are committed to
+	base    *state.StateDB  // Snapshot of the state the speculations run against
+	written *state.WriteSet // State written by the transactions committed so far
+
+	specs  []*speculation
+	lookup map[common.Hash]*speculation
+	next   int32 // Index of the next speculation to pick up
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewParallelExecutor starts executing the given transactions speculatively on
+// the number of goroutines set by the config, against a snapshot of the current
+// state. The transactions are listed in the order they are expected to be
+// committed in, but they don't need to be committed all, nor only them.
+//
+// If the config sets less than two workers, nothing is executed speculatively
+// and the transactions are applied sequentially as they are committed.
+func NewParallelExecutor(config *params.ChainConfig, bc ChainContext, author *common.Address, statedb *state.StateDB, header *types.Header, txs []*types.Transaction, cfg vm.Config) *ParallelExecutor {
+	e := &ParallelExecutor{
+		config:  config,
+		bc:      bc,
+		author:  author,
+		header:  header,
+		cfg:     cfg,
+		statedb: statedb,
+		quit:    make(chan struct{}),
+	}
+	if cfg.ParallelWorkers < 2 {
+		return e
+	}
+	e.base = statedb.Copy()
+	e.written = state.NewWriteSet()
+	e.specs = make([]*speculation, 0, len(txs))
+	e.lookup = make(map[common.Hash]*speculation, len(txs))
+
+	for _, tx := range txs {
+		if _, ok := e.lookup[tx.Hash()]; ok {
+			continue
+		}
+		spec := &speculation{tx: tx, done: make(chan struct{})}
+		e.specs = append(e.specs, spec)
+		e.lookup[tx.Hash()] = spec
+	}
+	for i := 0; i < cfg.ParallelWorkers; i++ {
+		e.wg.Add(1)
+		go e.loop()
+	}
+	return e
+}
+
+// Close stops the speculative executions, waiting for the running ones to
+// terminate.
+func (e *ParallelExecutor) Close() {
+	close(e.quit)
+	e.wg.Wait()
+}
+
+// loop executes the pending speculations in order, until all have been picked
+// up or the executor is closed.
+func (e *ParallelExecutor) loop() {
+	defer e.wg.Done()
+
+	for {
+		select {
+		case <-e.quit:
+			return
+		default:
+		}
+		index := int(atomic.AddInt32(&e.next, 1)) - 1
+		if index >= len(e.specs) {
+			return
+		}
+		spec := e.specs[index]
+		if atomic.CompareAndSwapInt32(&spec.status, speculationPending, speculationRunning) {
+			e.speculate(spec)
+		}
+	}
+}
+
+// speculate executes a transaction against a copy of the state snapshot.
+func (e *ParallelExecutor) speculate(spec *speculation) {
+	defer close(spec.done)
+
+	statedb := e.base.Copy()
+	statedb.Prepare(spec.tx.Hash(), common.Hash{}, 0)
+	statedb.StartAccessRecording()
+
+	var (
+		gp      = new(GasPool).AddGas(e.header.GasLimit)
+		usedGas uint64
+	)
+	spec.receipt, spec.gas, spec.err = ApplyTransaction(e.config, e.bc, e.author, gp, statedb, e.header, spec.tx, &usedGas, e.cfg)
+	if spec.err == nil {
+		spec.err = statedb.Error()
+	}
+	spec.access = statedb.StopAccessRecording()
+}
+
+// ApplyTransaction commits a transaction to the state, with the same semantics
+// as the package level ApplyTransaction. The result of its speculative
+// execution is used if it's still valid, otherwise the transaction is executed
+// against the state.
+func (e *ParallelExecutor) ApplyTransaction(gp *GasPool, tx *types.Transaction, usedGas *uint64) (*types.Receipt, uint64, error) {
+	if e.written == nil {
+		return ApplyTransaction(e.config, e.bc, e.author, gp, e.statedb, e.header, tx, usedGas, e.cfg)
+	}
+	if spec := e.lookup[tx.Hash()]; spec != nil {
+		delete(e.lookup, tx.Hash())
+
+		if !atomic.CompareAndSwapInt32(&spec.status, speculationPending, speculationSkipped) {
+			<-spec.done
+			if receipt, gas, ok := e.commit(spec, gp, usedGas); ok {
+				speculativeHitCounter.Inc(1)
+				return receipt, gas, nil
+			}
+			speculativeConflictCounter.Inc(1)
+		}
+	}
+	e.statedb.StartAccessRecording()
+	receipt, gas, err := ApplyTransaction(e.config, e.bc, e.author, gp, e.statedb, e.header, tx, usedGas, e.cfg)
+	e.written.Add(e.statedb.StopAccessRecording())
+
+	return receipt, gas, err
+}
+
+// commit replays the effects of a speculative execution onto the state, unless
+// it failed or read any state written since the snapshot. Failed executions are
+// always run again, so that the error is the one of the sequential execution.
+func (e *ParallelExecutor) commit(spec *speculation, gp *GasPool, usedGas *uint64) (*types.Receipt, uint64, bool) {
+	if spec.err != nil || spec.access.Conflicts(e.written) || gp.Gas() < spec.tx.Gas() {
+		return nil, 0, false
+	}
+	e.statedb.ApplyAccessSet(spec.access)
+	e.statedb.Finalise(true)
+	e.written.Add(spec.access)
+
+	gp.SubGas(spec.gas)
+	*usedGas += spec.gas
+	spec.receipt.CumulativeGasUsed = *usedGas
+
+	return spec.receipt, spec.gas, true
+}
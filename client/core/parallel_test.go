@@ -0,0 +1,204 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+var (
+	// counterCode increments the first storage slot and logs the new value.
+	counterCode = common.FromHex("6000546001018060005560005260206000a000")
+
+	// counterInitCode deploys counterCode.
+	counterInitCode = append(common.FromHex("6013600c60003960136000f3"), counterCode...)
+
+	// registryCode stores the current block number in the slot of the caller.
+	registryCode = common.FromHex("43335500")
+
+	// suicideCode self destructs, sending its balance to the caller.
+	suicideCode = common.FromHex("33ff")
+
+	counterAddr  = common.HexToAddress("0x0c")
+	registryAddr = common.HexToAddress("0x0d")
+	suicideAddr  = common.HexToAddress("0x0e")
+	coinbaseAddr = common.HexToAddress("0xc0")
+)
+
+// generateParallelChain generates a chain of blocks mixing independent and
+// conflicting transactions: transfers to new and existing accounts, calls to
+// contracts sharing or partitioning their storage, contract creations and self
+// destructs.
+func generateParallelChain(t *testing.T, blocks, txsPerBlock int) (kcoindb.Database, *Genesis, []*types.Block, []types.Receipts) {
+	var (
+		db      = kcoindb.NewMemDatabase()
+		keys    = make([]*ecdsa.PrivateKey, 10)
+		addrs   = make([]common.Address, len(keys))
+		genesis = &Genesis{
+			Config:   params.TestChainConfig,
+			GasLimit: 100000000,
+			Alloc: GenesisAlloc{
+				counterAddr:  {Code: counterCode, Balance: new(big.Int)},
+				registryAddr: {Code: registryCode, Balance: new(big.Int)},
+				suicideAddr:  {Code: suicideCode, Balance: big.NewInt(1000)},
+			},
+		}
+		signer = types.MakeSigner(params.TestChainConfig, nil)
+		random = rand.New(rand.NewSource(1))
+	)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+		genesis.Alloc[addrs[i]] = GenesisAccount{Balance: big.NewInt(1000000000000)}
+	}
+	parent := genesis.MustCommit(db)
+
+	chain, receipts := GenerateChain(params.TestChainConfig, parent, konsensus.NewFaker(), db, blocks, func(i int, b *BlockGen) {
+		b.SetCoinbase(coinbaseAddr)
+
+		for j := 0; j < txsPerBlock; j++ {
+			from := random.Intn(len(keys))
+			nonce := b.TxNonce(addrs[from])
+			price := big.NewInt(int64(1 + random.Intn(3)))
+
+			var tx *types.Transaction
+			switch random.Intn(7) {
+			case 0: // transfer to a new account
+				to := common.BigToAddress(big.NewInt(random.Int63()))
+				tx = types.NewTransaction(nonce, to, big.NewInt(int64(1+random.Intn(1000))), params.TxGas, price, nil)
+			case 1: // transfer to another sender
+				tx = types.NewTransaction(nonce, addrs[random.Intn(len(addrs))], big.NewInt(int64(random.Intn(1000))), params.TxGas, price, nil)
+			case 2: // touch a new account
+				to := common.BigToAddress(big.NewInt(random.Int63()))
+				tx = types.NewTransaction(nonce, to, new(big.Int), params.TxGas, price, nil)
+			case 3: // shared storage slot
+				tx = types.NewTransaction(nonce, counterAddr, new(big.Int), 100000, price, nil)
+			case 4: // storage slot of the sender
+				tx = types.NewTransaction(nonce, registryAddr, big.NewInt(1), 100000, price, nil)
+			case 5: // self destruct, a plain transfer once destroyed
+				tx = types.NewTransaction(nonce, suicideAddr, big.NewInt(10), 100000, price, nil)
+			case 6: // contract creation
+				tx = types.NewContractCreation(nonce, big.NewInt(5), 200000, price, counterInitCode)
+			}
+			tx, err := types.SignTx(tx, signer, keys[from])
+			if err != nil {
+				t.Fatalf("failed to sign transaction: %v", err)
+			}
+			b.AddTx(tx)
+		}
+	})
+	return db, genesis, chain, receipts
+}
+
+// Tests that processing blocks with parallel execution yields the same state
+// and receipts as the sequential execution they were generated with.
+func TestParallelProcessing(t *testing.T) {
+	db, _, chain, want := generateParallelChain(t, 8, 60)
+
+	blockchain, err := NewBlockChain(db, nil, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	processor := NewStateProcessor(params.TestChainConfig, blockchain, konsensus.NewFaker())
+	for _, workers := range []int{0, 1, 2, 8} {
+
+		parent := blockchain.Genesis()
+		for i, block := range chain {
+			statedb, err := state.New(parent.Root(), state.NewDatabase(db))
+			if err != nil {
+				t.Fatalf("workers %d, block %d: failed to open state: %v", workers, i, err)
+			}
+			receipts, _, usedGas, err := processor.Process(block, statedb, vm.Config{ParallelWorkers: workers})
+			if err != nil {
+				t.Fatalf("workers %d, block %d: failed to process: %v", workers, i, err)
+			}
+			if usedGas != block.GasUsed() {
+				t.Errorf("workers %d, block %d: gas used mismatch: have %d, want %d", workers, i, usedGas, block.GasUsed())
+			}
+			if root := statedb.IntermediateRoot(true); root != block.Root() {
+				t.Errorf("workers %d, block %d: state root mismatch: have %x, want %x", workers, i, root, block.Root())
+			}
+			checkReceipts(t, workers, i, receipts, want[i])
+			parent = block
+		}
+	}
+}
+
+func checkReceipts(t *testing.T, workers, block int, have, want types.Receipts) {
+	if len(have) != len(want) {
+		t.Fatalf("workers %d, block %d: receipt count mismatch: have %d, want %d", workers, block, len(have), len(want))
+	}
+	for i := range have {
+		h, w := have[i], want[i]
+		if h.Status != w.Status || h.GasUsed != w.GasUsed || h.CumulativeGasUsed != w.CumulativeGasUsed || h.ContractAddress != w.ContractAddress || h.Bloom != w.Bloom {
+			t.Errorf("workers %d, block %d, receipt %d: mismatch: have %+v, want %+v", workers, block, i, h, w)
+			continue
+		}
+		if len(h.Logs) != len(w.Logs) {
+			t.Errorf("workers %d, block %d, receipt %d: log count mismatch: have %d, want %d", workers, block, i, len(h.Logs), len(w.Logs))
+			continue
+		}
+		for j := range h.Logs {
+			hl, wl := h.Logs[j], w.Logs[j]
+			if hl.Index != wl.Index || hl.TxIndex != wl.TxIndex || hl.Address != wl.Address || common.Bytes2Hex(hl.Data) != common.Bytes2Hex(wl.Data) {
+				t.Errorf("workers %d, block %d, receipt %d, log %d: mismatch: have %+v, want %+v", workers, block, i, j, hl, wl)
+			}
+		}
+	}
+}
+
+// Tests that transactions not speculated, speculated with errors and committed
+// out of the speculation order are executed like sequentially.
+func TestParallelExecutorFallback(t *testing.T) {
+	db, _, chain, want := generateParallelChain(t, 1, 40)
+
+	blockchain, err := NewBlockChain(db, nil, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	block := chain[0]
+	statedb, _ := state.New(blockchain.Genesis().Root(), state.NewDatabase(db))
+
+	// Speculate the transactions in reverse order, skipping every third one
+	var txs []*types.Transaction
+	for i := len(block.Transactions()) - 1; i >= 0; i-- {
+		if i%3 != 0 {
+			txs = append(txs, block.Transactions()[i])
+		}
+	}
+	header := block.Header()
+	executor := NewParallelExecutor(params.TestChainConfig, blockchain, &header.Coinbase, statedb, header, txs, vm.Config{ParallelWorkers: 4})
+	defer executor.Close()
+
+	var (
+		gp       = new(GasPool).AddGas(block.GasLimit())
+		usedGas  uint64
+		receipts types.Receipts
+	)
+	for i, tx := range block.Transactions() {
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		receipt, _, err := executor.ApplyTransaction(gp, tx, &usedGas)
+		if err != nil {
+			t.Fatalf("transaction %d: failed to apply: %v", i, err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	if root := statedb.IntermediateRoot(true); root != block.Root() {
+		t.Errorf("state root mismatch: have %x, want %x", root, block.Root())
+	}
+	checkReceipts(t, 4, 0, receipts, want[0])
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
 	"github.com/kowala-tech/kcoin/client/log"
 	"github.com/kowala-tech/kcoin/client/rlp"
 )
@@ -30,6 +31,11 @@ import (
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
 	data, _ := db.Get(headerHashKey(number))
+	if len(data) == 0 {
+		if ancients, ok := db.(kcoindb.AncientReader); ok {
+			data, _ = ancients.Ancient(freezerHashTable, number)
+		}
+	}
 	if len(data) == 0 {
 		return common.Hash{}
 	}
@@ -126,16 +132,83 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 	}
 }
 
+// ReadCheckpointTail retrieves the number of the lowest block whose header is
+// known after a checkpoint sync. Zero means that there is no gap in the headers.
+func ReadCheckpointTail(db DatabaseReader) uint64 {
+	data, _ := db.Get(checkpointTailKey)
+	if len(data) == 0 {
+		return 0
+	}
+	return new(big.Int).SetBytes(data).Uint64()
+}
+
+// WriteCheckpointTail stores the number of the lowest block whose header is known
+// after a checkpoint sync.
+func WriteCheckpointTail(db DatabaseWriter, number uint64) {
+	if err := db.Put(checkpointTailKey, new(big.Int).SetUint64(number).Bytes()); err != nil {
+		log.Crit("Failed to store checkpoint tail", "err", err)
+	}
+}
+
+// DeleteCheckpointTail removes the checkpoint tail once the header chain is complete.
+func DeleteCheckpointTail(db DatabaseDeleter) {
+	if err := db.Delete(checkpointTailKey); err != nil {
+		log.Crit("Failed to delete checkpoint tail", "err", err)
+	}
+}
+
+// ReadStateTail retrieves the number of the oldest block whose state survived
+// the last state pruning. The state of the blocks below it, except the genesis
+// block, was deleted.
+func ReadStateTail(db DatabaseReader) uint64 {
+	data, _ := db.Get(stateTailKey)
+	if len(data) == 0 {
+		return 0
+	}
+	return new(big.Int).SetBytes(data).Uint64()
+}
+
+// WriteStateTail stores the number of the oldest block whose state survived the
+// last state pruning.
+func WriteStateTail(db DatabaseWriter, number uint64) {
+	if err := db.Put(stateTailKey, new(big.Int).SetUint64(number).Bytes()); err != nil {
+		log.Crit("Failed to store state tail", "err", err)
+	}
+}
+
+// ReadSyncPivot retrieves the number of the block whose state was downloaded
+// by the last fast or checkpoint sync. The states of the blocks below it, except
+// the genesis block, were never written. Zero means that the chain was fully
+// processed.
+func ReadSyncPivot(db DatabaseReader) uint64 {
+	data, _ := db.Get(syncPivotKey)
+	if len(data) == 0 {
+		return 0
+	}
+	return new(big.Int).SetBytes(data).Uint64()
+}
+
+// WriteSyncPivot stores the number of the block whose state was downloaded by
+// the last fast or checkpoint sync.
+func WriteSyncPivot(db DatabaseWriter, number uint64) {
+	if err := db.Put(syncPivotKey, new(big.Int).SetUint64(number).Bytes()); err != nil {
+		log.Crit("Failed to store sync pivot", "err", err)
+	}
+}
+
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(headerKey(number, hash))
+	if len(data) == 0 {
+		data = readAncient(db, freezerHeaderTable, hash, number)
+	}
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
-		return false
+		return len(readAncient(db, freezerHeaderTable, hash, number)) > 0
 	}
 	return true
 }
@@ -191,6 +264,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 // ReadBodyRLP retrieves the block body (transactions and commits) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
 	data, _ := db.Get(blockBodyKey(number, hash))
+	if len(data) == 0 {
+		data = readAncient(db, freezerBodiesTable, hash, number)
+	}
 	return data
 }
 
@@ -204,7 +280,7 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
-		return false
+		return len(readAncient(db, freezerBodiesTable, hash, number)) > 0
 	}
 	return true
 }
@@ -239,10 +315,19 @@ func DeleteBody(db DatabaseDeleter, hash common.Hash, number uint64) {
 	}
 }
 
+// ReadReceiptsRLP retrieves all the transaction receipts belonging to a block in RLP encoding.
+func ReadReceiptsRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(blockReceiptsKey(number, hash))
+	if len(data) == 0 {
+		data = readAncient(db, freezerReceiptTable, hash, number)
+	}
+	return data
+}
+
 // ReadReceipts retrieves all the transaction receipts belonging to a block.
 func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Receipts {
 	// Retrieve the flattened receipt slice
-	data, _ := db.Get(blockReceiptsKey(number, hash))
+	data := ReadReceiptsRLP(db, hash, number)
 	if len(data) == 0 {
 		return nil
 	}
@@ -314,6 +399,32 @@ func DeleteBlock(db DatabaseDeleter, hash common.Hash, number uint64) {
 	DeleteBody(db, hash, number)
 }
 
+// deleteBlockWithoutNumber removes all block data associated with a hash, except
+// the hash to number mapping, which is still needed to look up frozen blocks.
+func deleteBlockWithoutNumber(db DatabaseDeleter, hash common.Hash, number uint64) {
+	DeleteReceipts(db, hash, number)
+	if err := db.Delete(headerKey(number, hash)); err != nil {
+		log.Crit("Failed to delete header", "err", err)
+	}
+	DeleteBody(db, hash, number)
+}
+
+// readAncient retrieves an item of the given kind of the canonical block with
+// the given hash and number from the ancient store of the database, if it has
+// one. Items missing from the store, such as the bodies below a sync checkpoint,
+// are returned empty.
+func readAncient(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	ancients, ok := db.(kcoindb.AncientReader)
+	if !ok {
+		return nil
+	}
+	if data, _ := ancients.Ancient(freezerHashTable, number); common.BytesToHash(data) != hash {
+		return nil
+	}
+	data, _ := ancients.Ancient(kind, number)
+	return data
+}
+
 // FindCommonAncestor returns the last common ancestor of two block headers
 func FindCommonAncestor(db DatabaseReader, a, b *types.Header) *types.Header {
 	for bn := b.Number.Uint64(); a.Number.Uint64() > bn; {
@@ -0,0 +1,84 @@
+package rawdb
+
+import (
+	"fmt"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/log"
+)
+
+// freezerdb is a database wrapper that enables freezer data retrievals.
+type freezerdb struct {
+	*kcoindb.LDBDatabase
+	*freezer
+}
+
+// Close implements kcoindb.Database, closing both the fast key-value store as
+// well as the slow ancient tables.
+func (frdb *freezerdb) Close() {
+	if err := frdb.freezer.Close(); err != nil {
+		log.Error("Failed to close ancient database", "err", err)
+	}
+	frdb.LDBDatabase.Close()
+}
+
+// NewDatabaseWithFreezer creates a high level database on top of a given
+// key-value data store with a freezer moving immutable chain segments into
+// cold storage.
+func NewDatabaseWithFreezer(db *kcoindb.LDBDatabase, freezer string, namespace string) (kcoindb.Database, error) {
+	// Create the idle freezer instance
+	frdb, err := newFreezer(freezer, namespace)
+	if err != nil {
+		return nil, err
+	}
+	// The key-value store and the freezer have to belong to the same chain,
+	// a mismatch means that one of them was replaced by hand.
+	if frozen, _ := frdb.Ancients(); frozen > 0 {
+		kvgenesis := ReadCanonicalHash(db, 0)
+		if kvgenesis != (common.Hash{}) {
+			if frgenesis, _ := frdb.Ancient(freezerHashTable, 0); kvgenesis != common.BytesToHash(frgenesis) {
+				frdb.Close()
+				return nil, fmt.Errorf("genesis mismatch: %#x (leveldb) != %#x (ancients)", kvgenesis, frgenesis)
+			}
+		}
+	}
+	// Freezer is consistent with the key-value database, permit combining the two
+	frdb.wg.Add(1)
+	go frdb.freeze(db)
+
+	return &freezerdb{
+		LDBDatabase: db,
+		freezer:     frdb,
+	}, nil
+}
+
+// NewLevelDBDatabaseWithFreezer creates a persistent key-value database with a
+// freezer moving immutable chain segments into cold storage.
+func NewLevelDBDatabaseWithFreezer(file string, cache int, handles int, freezer string, namespace string) (kcoindb.Database, error) {
+	kvdb, err := kcoindb.NewLDBDatabase(file, cache, handles)
+	if err != nil {
+		return nil, err
+	}
+	if namespace != "" {
+		kvdb.Meter(namespace)
+	}
+	frdb, err := NewDatabaseWithFreezer(kvdb, freezer, namespace)
+	if err != nil {
+		kvdb.Close()
+		return nil, err
+	}
+	return frdb, nil
+}
+
+// LevelDB returns the LevelDB store backing a database opened with or without
+// a freezer, or nil if the database isn't persisted in LevelDB.
+func LevelDB(db kcoindb.Database) *kcoindb.LDBDatabase {
+	switch db := db.(type) {
+	case *kcoindb.LDBDatabase:
+		return db
+	case *freezerdb:
+		return db.LDBDatabase
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/metrics"
+)
+
+// getChunk returns a chunk of data of the given size, filled with b.
+func getChunk(size int, b int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(b)
+	}
+	return data
+}
+
+func newTestTable(t *testing.T, dir string, maxFileSize uint32) *freezerTable {
+	table, err := newCustomTable(dir, "test", metrics.NilMeter{}, metrics.NilMeter{}, metrics.NilGauge{}, maxFileSize, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+// Tests that items can be appended and retrieved across data files.
+func TestFreezerTableAppendRetrieve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table := newTestTable(t, dir, 50)
+	for i := 0; i < 255; i++ {
+		if err := table.Append(uint64(i), getChunk(15, i)); err != nil {
+			t.Fatalf("item %d: failed to append: %v", i, err)
+		}
+	}
+	if err := table.Append(300, getChunk(15, 0)); err == nil {
+		t.Fatal("out of order append succeeded")
+	}
+	table.Close()
+
+	// Reopen the table and check all the items
+	table = newTestTable(t, dir, 50)
+	defer table.Close()
+
+	for i := 0; i < 255; i++ {
+		got, err := table.Retrieve(uint64(i))
+		if err != nil {
+			t.Fatalf("item %d: failed to retrieve: %v", i, err)
+		}
+		if !bytes.Equal(got, getChunk(15, i)) {
+			t.Fatalf("item %d: data mismatch: have %x", i, got)
+		}
+	}
+	if _, err := table.Retrieve(255); err != errOutOfBounds {
+		t.Fatalf("error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+}
+
+// Tests that a table recovers from a data file cut short by a crash.
+func TestFreezerTableRepair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table := newTestTable(t, dir, 50)
+	for i := 0; i < 9; i++ {
+		if err := table.Append(uint64(i), getChunk(15, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	table.Close()
+
+	// Items are stored three per file, drop half of the last item
+	head := filepath.Join(dir, fmt.Sprintf("test.%04d.rdat", 2))
+	if err := os.Truncate(head, 37); err != nil {
+		t.Fatal(err)
+	}
+	table = newTestTable(t, dir, 50)
+	defer table.Close()
+
+	if table.items != 8 {
+		t.Fatalf("item count mismatch: have %d, want %d", table.items, 8)
+	}
+	if _, err := table.Retrieve(8); err != errOutOfBounds {
+		t.Fatalf("error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+	if got, _ := table.Retrieve(7); !bytes.Equal(got, getChunk(15, 7)) {
+		t.Fatalf("data mismatch: have %x", got)
+	}
+	// The repaired table must accept appends again
+	if err := table.Append(8, getChunk(15, 8)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests that a table can be truncated back into earlier data files.
+func TestFreezerTableTruncate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table := newTestTable(t, dir, 50)
+	defer table.Close()
+
+	for i := 0; i < 30; i++ {
+		if err := table.Append(uint64(i), getChunk(15, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := table.truncate(4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.Retrieve(4); err != errOutOfBounds {
+		t.Fatalf("error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+	for i := 4; i < 8; i++ {
+		if err := table.Append(uint64(i), getChunk(15, 0xff)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, _ := table.Retrieve(3); !bytes.Equal(got, getChunk(15, 3)) {
+		t.Fatalf("data mismatch: have %x", got)
+	}
+	if got, _ := table.Retrieve(7); !bytes.Equal(got, getChunk(15, 0xff)) {
+		t.Fatalf("data mismatch: have %x", got)
+	}
+}
+
+// Tests that the chain accessors transparently read frozen blocks.
+func TestFreezerAccessors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := NewLevelDBDatabaseWithFreezer(filepath.Join(dir, "chaindata"), 0, 0, filepath.Join(dir, "ancient"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	block := types.NewBlock(&types.Header{Number: big.NewInt(0), Extra: []byte("frozen block")}, nil, nil, nil)
+	receipts := types.Receipts{types.NewReceipt(nil, false, 1)}
+	WriteBlock(db, block)
+	WriteReceipts(db, block.Hash(), 0, receipts)
+	WriteCanonicalHash(db, block.Hash(), 0)
+
+	// Move the block into the ancient store by hand
+	var (
+		hash   = block.Hash()
+		header = ReadHeaderRLP(db, hash, 0)
+		body   = ReadBodyRLP(db, hash, 0)
+		rcpts  = ReadReceiptsRLP(db, hash, 0)
+	)
+	if err := db.(kcoindb.AncientStore).AppendAncient(0, hash[:], header, body, rcpts); err != nil {
+		t.Fatal(err)
+	}
+	deleteBlockWithoutNumber(db, hash, 0)
+	DeleteCanonicalHash(db, 0)
+
+	if have := ReadCanonicalHash(db, 0); have != hash {
+		t.Fatalf("canonical hash mismatch: have %x, want %x", have, hash)
+	}
+	if !HasHeader(db, hash, 0) || !HasBody(db, hash, 0) {
+		t.Fatal("frozen block not found")
+	}
+	if frozen := ReadBlock(db, hash, 0); frozen == nil || frozen.Hash() != hash {
+		t.Fatalf("frozen block mismatch: have %v", frozen)
+	}
+	if frozen := ReadReceipts(db, hash, 0); len(frozen) != 1 || frozen[0].CumulativeGasUsed != 1 {
+		t.Fatalf("frozen receipts mismatch: have %v", frozen)
+	}
+	// Other blocks at the same height are not served from the ancient store
+	other := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Extra: []byte("other block")})
+	if HasHeader(db, other.Hash(), 0) || ReadHeader(db, other.Hash(), 0) != nil {
+		t.Fatal("non canonical block served from the ancient store")
+	}
+}
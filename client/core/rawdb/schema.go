@@ -41,6 +41,15 @@ var (
 	// fastTrieProgressKey tracks the number of trie entries imported during fast sync.
 	fastTrieProgressKey = []byte("TrieSync")
 
+	// checkpointTailKey tracks the lowest block with a known header after a checkpoint sync.
+	checkpointTailKey = []byte("CheckpointTail")
+
+	// stateTailKey tracks the oldest block whose state survived the last state pruning.
+	stateTailKey = []byte("StateTail")
+
+	// syncPivotKey tracks the block whose state was downloaded by the last fast or checkpoint sync.
+	syncPivotKey = []byte("SyncPivot")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -63,6 +72,28 @@ var (
 	preimageHitCounter = metrics.NewRegisteredCounter("db/preimage/hits", nil)
 )
 
+const (
+	// freezerHashTable indicates the name of the freezer canonical hash table.
+	freezerHashTable = "hashes"
+
+	// freezerHeaderTable indicates the name of the freezer header table.
+	freezerHeaderTable = "headers"
+
+	// freezerBodiesTable indicates the name of the freezer block body table.
+	freezerBodiesTable = "bodies"
+
+	// freezerReceiptTable indicates the name of the freezer receipts table.
+	freezerReceiptTable = "receipts"
+)
+
+// freezerNoSnappy configures whether compression is disabled for the ancient tables.
+var freezerNoSnappy = map[string]bool{
+	freezerHashTable:    true,
+	freezerHeaderTable:  false,
+	freezerBodiesTable:  false,
+	freezerReceiptTable: false,
+}
+
 // TxLookupEntry is a positional metadata to help looking up the data content of
 // a transaction or receipt given only its hash.
 type TxLookupEntry struct {
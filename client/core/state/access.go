@@ -0,0 +1,260 @@
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+)
+
+// accessKind identifies the part of an account accessed by a transaction.
+type accessKind uint8
+
+const (
+	accessExistence accessKind = iota // Whether the account exists
+	accessBalance                     // Balance of the account
+	accessNonce                       // Nonce of the account
+	accessCode                        // Code of the account
+	accessStorage                     // A storage slot of the account
+)
+
+// accessKey identifies a piece of state accessed by a transaction.
+type accessKey struct {
+	addr common.Address
+	kind accessKind
+	slot common.Hash // Storage slot, only set for accessStorage
+}
+
+// accountOrigin is the state of an account before a transaction accessed it.
+type accountOrigin struct {
+	exists   bool
+	balance  *big.Int
+	nonce    uint64
+	codeHash []byte
+	storage  map[common.Hash]common.Hash // Original values of the accessed slots
+	reset    bool                        // Whether the account was recreated by the transaction
+}
+
+// accessRecorder tracks the state read by a transaction along with the original
+// values of the accounts it accessed.
+type accessRecorder struct {
+	reads   map[accessKey]struct{}
+	origins map[common.Address]*accountOrigin
+}
+
+func newAccessRecorder() *accessRecorder {
+	return &accessRecorder{
+		reads:   make(map[accessKey]struct{}),
+		origins: make(map[common.Address]*accountOrigin),
+	}
+}
+
+// read records the given parts of an account as read.
+func (r *accessRecorder) read(addr common.Address, kinds ...accessKind) {
+	for _, kind := range kinds {
+		r.reads[accessKey{addr: addr, kind: kind}] = struct{}{}
+	}
+}
+
+// readSlot records a storage slot as read. Slots depend on the existence of
+// their account, as recreating or deleting it wipes the storage.
+func (r *accessRecorder) readSlot(addr common.Address, slot common.Hash) {
+	r.reads[accessKey{addr: addr, kind: accessExistence}] = struct{}{}
+	r.reads[accessKey{addr: addr, kind: accessStorage, slot: slot}] = struct{}{}
+}
+
+// loadAccount records the original state of an account the first time it's
+// accessed. A nil object stands for a non-existent account.
+func (r *accessRecorder) loadAccount(addr common.Address, obj *stateObject) {
+	if _, ok := r.origins[addr]; ok {
+		return
+	}
+	origin := &accountOrigin{balance: new(big.Int), codeHash: emptyCodeHash, storage: make(map[common.Hash]common.Hash)}
+	if obj != nil {
+		origin.exists = true
+		origin.balance.Set(obj.Balance())
+		origin.nonce = obj.Nonce()
+		origin.codeHash = obj.CodeHash()
+	}
+	r.origins[addr] = origin
+}
+
+// loadSlot records the original value of a storage slot the first time it's
+// accessed.
+func (r *accessRecorder) loadSlot(addr common.Address, slot, value common.Hash) {
+	origin, ok := r.origins[addr]
+	if !ok {
+		return
+	}
+	if _, ok := origin.storage[slot]; !ok {
+		origin.storage[slot] = value
+	}
+}
+
+// accountChange is the change made by a transaction to an account.
+type accountChange struct {
+	addr     common.Address
+	deleted  bool                        // Whether the account was deleted
+	reset    bool                        // Whether the account was recreated, wiping its storage
+	balance  *big.Int                    // Balance delta, nil if unchanged
+	nonce    *uint64                     // New nonce, nil if unchanged
+	code     []byte                      // New code, if codeHash is set
+	codeHash *common.Hash                // New code hash, nil if unchanged
+	storage  map[common.Hash]common.Hash // New values of the written slots
+}
+
+// AccessSet is the state read and written by a transaction executed against a
+// snapshot of the state, along with the changes needed to replay its effects
+// onto another state in which none of the state it read was modified.
+//
+// Balance changes are replayed as deltas, so that transactions crediting the
+// same account, e.g. the coinbase, don't conflict unless one of them reads its
+// balance.
+type AccessSet struct {
+	reads     map[accessKey]struct{}
+	writes    map[accessKey]struct{}
+	changes   []*accountChange
+	logs      []*types.Log
+	preimages map[common.Hash][]byte
+}
+
+// Conflicts reports whether the transaction read any of the given writes.
+func (set *AccessSet) Conflicts(writes *WriteSet) bool {
+	small, large := set.reads, writes.keys
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	for key := range small {
+		if _, ok := large[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteSet accumulates the state written by a sequence of transactions.
+type WriteSet struct {
+	keys map[accessKey]struct{}
+}
+
+// NewWriteSet creates an empty write set.
+func NewWriteSet() *WriteSet {
+	return &WriteSet{keys: make(map[accessKey]struct{})}
+}
+
+// Add merges the writes of a transaction into the set.
+func (w *WriteSet) Add(set *AccessSet) {
+	for key := range set.writes {
+		w.keys[key] = struct{}{}
+	}
+}
+
+// StartAccessRecording starts recording the state read and written by the
+// operations on the state, until StopAccessRecording is called.
+func (self *StateDB) StartAccessRecording() {
+	self.recorder = newAccessRecorder()
+}
+
+// StopAccessRecording stops recording the state accesses, returning the state
+// read and written since StartAccessRecording was called. The state must have
+// been finalised beforehand.
+func (self *StateDB) StopAccessRecording() *AccessSet {
+	recorder := self.recorder
+	self.recorder = nil
+
+	set := &AccessSet{
+		reads:     recorder.reads,
+		writes:    make(map[accessKey]struct{}),
+		logs:      self.logs[self.thash],
+		preimages: self.preimages,
+	}
+	write := func(addr common.Address, kinds ...accessKind) {
+		for _, kind := range kinds {
+			set.writes[accessKey{addr: addr, kind: kind}] = struct{}{}
+		}
+	}
+	for addr, origin := range recorder.origins {
+		obj := self.stateObjects[addr]
+		exists := obj != nil && !obj.deleted && !obj.suicided
+
+		switch {
+		case !exists && !origin.exists:
+			continue
+
+		case !exists:
+			// The deletion depends on the whole account being empty or
+			// suicided, so it's a read of the account as well.
+			recorder.read(addr, accessExistence, accessBalance, accessNonce, accessCode)
+			write(addr, accessExistence, accessBalance, accessNonce, accessCode)
+			set.changes = append(set.changes, &accountChange{addr: addr, deleted: true})
+			continue
+		}
+		change := &accountChange{addr: addr, reset: origin.reset, storage: make(map[common.Hash]common.Hash)}
+		if !origin.exists || origin.reset {
+			write(addr, accessExistence)
+		}
+		if delta := new(big.Int).Sub(obj.Balance(), origin.balance); delta.Sign() != 0 {
+			change.balance = delta
+			write(addr, accessBalance)
+		}
+		if nonce := obj.Nonce(); nonce != origin.nonce {
+			change.nonce = &nonce
+			write(addr, accessNonce)
+		}
+		if !bytes.Equal(obj.CodeHash(), origin.codeHash) {
+			hash := common.BytesToHash(obj.CodeHash())
+			change.code, change.codeHash = obj.Code(self.db), &hash
+			write(addr, accessCode)
+		}
+		for slot, value := range origin.storage {
+			// Recreated accounts start with an empty storage, so all the
+			// slots are replayed
+			if final := obj.GetState(self.db, slot); final != value || origin.reset {
+				change.storage[slot] = final
+				set.writes[accessKey{addr: addr, kind: accessStorage, slot: slot}] = struct{}{}
+			}
+		}
+		set.changes = append(set.changes, change)
+	}
+	sort.Slice(set.changes, func(i, j int) bool {
+		return bytes.Compare(set.changes[i].addr[:], set.changes[j].addr[:]) < 0
+	})
+	return set
+}
+
+// ApplyAccessSet replays the changes of a transaction recorded against another
+// state, along with its logs and preimages. The caller is responsible for
+// ensuring that the state it read wasn't modified in between, and for
+// finalising the state afterwards.
+func (self *StateDB) ApplyAccessSet(set *AccessSet) {
+	for _, change := range set.changes {
+		if change.deleted {
+			self.Suicide(change.addr)
+			continue
+		}
+		if change.reset {
+			self.CreateAccount(change.addr)
+		}
+		obj := self.GetOrNewStateObject(change.addr)
+		if change.balance != nil {
+			obj.SetBalance(new(big.Int).Add(obj.Balance(), change.balance))
+		}
+		if change.nonce != nil {
+			obj.SetNonce(*change.nonce)
+		}
+		if change.codeHash != nil {
+			obj.SetCode(*change.codeHash, change.code)
+		}
+		for slot, value := range change.storage {
+			obj.SetState(self.db, slot, value)
+		}
+	}
+	for _, log := range set.logs {
+		self.AddLog(log)
+	}
+	for hash, preimage := range set.preimages {
+		self.AddPreimage(hash, preimage)
+	}
+}
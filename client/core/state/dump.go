@@ -71,6 +71,49 @@ func (self *StateDB) RawDump() Dump {
 	return dump
 }
 
+// IterativeDump calls fn with each account of the state, along with its code
+// and storage, without loading the whole state into memory. The preimages of
+// the account addresses and storage slots must be available.
+func (self *StateDB) IterativeDump(fn func(addr common.Address, data Account, code []byte, storage map[common.Hash]common.Hash) error) error {
+	it := trie.NewIterator(self.trie.NodeIterator(nil))
+	for it.Next() {
+		addr := self.trie.GetKey(it.Key)
+		if addr == nil {
+			return fmt.Errorf("missing preimage of account hash %x", it.Key)
+		}
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return err
+		}
+		obj := newObject(nil, common.BytesToAddress(addr), data)
+		code := obj.Code(self.db)
+
+		storage := make(map[common.Hash]common.Hash)
+		storageIt := trie.NewIterator(obj.getTrie(self.db).NodeIterator(nil))
+		for storageIt.Next() {
+			slot := self.trie.GetKey(storageIt.Key)
+			if slot == nil {
+				return fmt.Errorf("missing preimage of storage hash %x of account %x", storageIt.Key, addr)
+			}
+			_, content, _, err := rlp.Split(storageIt.Value)
+			if err != nil {
+				return err
+			}
+			storage[common.BytesToHash(slot)] = common.BytesToHash(content)
+		}
+		if storageIt.Err != nil {
+			return storageIt.Err
+		}
+		if obj.dbErr != nil {
+			return obj.dbErr
+		}
+		if err := fn(common.BytesToAddress(addr), data, code, storage); err != nil {
+			return err
+		}
+	}
+	return it.Err
+}
+
 func (self *StateDB) Dump() []byte {
 	json, err := json.MarshalIndent(self.RawDump(), "", "    ")
 	if err != nil {
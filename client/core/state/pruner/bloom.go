@@ -0,0 +1,57 @@
+package pruner
+
+import (
+	"encoding/binary"
+
+	"github.com/kowala-tech/kcoin/client/common"
+)
+
+// bloomHashes is the number of bit indexes set per key.
+const bloomHashes = 4
+
+// stateBloom is a bloom filter of the trie nodes and contract codes that are
+// reachable from the retained state roots. The keys are hashes already, so the
+// bit indexes are taken straight from the key itself.
+//
+// A false positive only means that an unreachable entry survives the pruning,
+// a reachable entry is never reported missing.
+type stateBloom struct {
+	bits []uint64
+}
+
+// newStateBloom creates a bloom filter of the given size in bytes.
+func newStateBloom(size uint64) *stateBloom {
+	if size < 8 {
+		size = 8
+	}
+	return &stateBloom{bits: make([]uint64, size/8)}
+}
+
+// add inserts a hash into the filter.
+func (b *stateBloom) add(key []byte) {
+	for i := 0; i < bloomHashes; i++ {
+		idx := b.index(key, i)
+		b.bits[idx/64] |= 1 << (idx % 64)
+	}
+}
+
+// contains reports whether the hash might have been inserted into the filter.
+func (b *stateBloom) contains(key []byte) bool {
+	for i := 0; i < bloomHashes; i++ {
+		idx := b.index(key, i)
+		if b.bits[idx/64]&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// index returns the i-th bit index of the hash.
+func (b *stateBloom) index(key []byte, i int) uint64 {
+	return binary.BigEndian.Uint64(key[i*8:i*8+8]) % uint64(len(b.bits)*64)
+}
+
+// size returns the size of the filter in bytes.
+func (b *stateBloom) size() common.StorageSize {
+	return common.StorageSize(len(b.bits) * 8)
+}
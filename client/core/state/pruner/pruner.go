@@ -0,0 +1,199 @@
+// Package pruner implements the deletion of the historical state that is no
+// longer reachable from the retained state roots.
+package pruner
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/rlp"
+)
+
+const (
+	// DefaultBloomSize is the default size in megabytes of the bloom filter of
+	// the live state entries.
+	DefaultBloomSize = 256
+
+	// visitedCacheSize is the number of the most recently visited account trie
+	// nodes and storage roots remembered while marking.
+	visitedCacheSize = 1024 * 1024
+)
+
+var (
+	// ErrAborted is returned if the pruning was interrupted. The entries that
+	// were deleted until then were unreachable, the database stays consistent.
+	ErrAborted = errors.New("state pruning aborted")
+
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCode = crypto.Keccak256(nil)
+)
+
+// Pruner deletes the trie nodes and the contract codes that aren't reachable
+// from a set of retained state roots. The live entries are marked in a bloom
+// filter, after which every state entry of the database that isn't part of it
+// is swept.
+type Pruner struct {
+	diskdb    *kcoindb.LDBDatabase // Key-value store holding the state entries
+	statedb   state.Database       // State database to resolve the retained tries from
+	bloomSize uint64               // Size of the bloom filter in bytes
+}
+
+// NewPruner creates a pruner sweeping the given database. The retained tries
+// are resolved through statedb, so that tries only partially flushed to disk
+// are marked as well. The bloom size is given in megabytes.
+func NewPruner(diskdb *kcoindb.LDBDatabase, statedb state.Database, bloomSize uint64) *Pruner {
+	if bloomSize == 0 {
+		bloomSize = DefaultBloomSize
+	}
+	return &Pruner{
+		diskdb:    diskdb,
+		statedb:   statedb,
+		bloomSize: bloomSize * 1024 * 1024,
+	}
+}
+
+// Prune deletes all the state entries that aren't reachable from the given
+// roots. The database must not be flushed any new state entries while pruning,
+// entries written in the mean time are only safe if they're reachable from the
+// roots.
+func (p *Pruner) Prune(roots []common.Hash, abort <-chan struct{}) error {
+	// Take the view of the database to sweep before marking, entries written
+	// afterwards are never touched
+	it := p.diskdb.NewIterator()
+	defer it.Release()
+
+	start := time.Now()
+	bloom := newStateBloom(p.bloomSize)
+	if err := p.mark(bloom, roots, abort); err != nil {
+		return err
+	}
+	log.Info("Marked live state entries", "roots", len(roots), "bloom", bloom.size(), "elapsed", common.PrettyDuration(time.Since(start)))
+
+	var (
+		batch   = p.diskdb.NewBatch()
+		swept   int
+		size    common.StorageSize
+		checked int
+		logged  = time.Now()
+	)
+	for it.Next() {
+		checked++
+		if checked%10000 == 0 {
+			select {
+			case <-abort:
+				batch.Write()
+				return ErrAborted
+			default:
+			}
+		}
+		// Trie nodes and contract codes are the only entries keyed by their hash
+		key := it.Key()
+		if len(key) != common.HashLength || bloom.contains(key) {
+			continue
+		}
+		batch.Delete(common.CopyBytes(key))
+		swept++
+		size += common.StorageSize(len(key) + len(it.Value()))
+
+		if batch.ValueSize() >= kcoindb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Pruning state data", "swept", swept, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Pruned state data", "swept", swept, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// mark inserts all the trie nodes and contract codes reachable from the roots
+// into the bloom filter. The roots of consecutive blocks share most of their
+// nodes, so the recently visited account trie nodes and storage tries are
+// skipped. The bloom filter can't tell the visited nodes apart, a false
+// positive would leave the children of a node unmarked.
+func (p *Pruner) mark(bloom *stateBloom, roots []common.Hash, abort <-chan struct{}) error {
+	nodes, _ := lru.New(visitedCacheSize)
+	storages, _ := lru.New(visitedCacheSize)
+
+	for _, root := range roots {
+		tr, err := p.statedb.OpenTrie(root)
+		if err != nil {
+			return err
+		}
+		it := tr.NodeIterator(nil)
+		for descend := true; it.Next(descend); {
+			descend = true
+
+			if hash := it.Hash(); hash != (common.Hash{}) {
+				if nodes.Contains(hash) {
+					descend = false
+					continue
+				}
+				nodes.Add(hash, nil)
+				bloom.add(hash[:])
+			}
+			if !it.Leaf() {
+				continue
+			}
+			var account state.Account
+			if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+				return err
+			}
+			if !bytes.Equal(account.CodeHash, emptyCode) {
+				bloom.add(account.CodeHash)
+			}
+			if account.Root == emptyRoot {
+				continue
+			}
+			if storages.Contains(account.Root) {
+				continue
+			}
+			storages.Add(account.Root, nil)
+
+			if err := p.markStorage(bloom, common.BytesToHash(it.LeafKey()), account.Root); err != nil {
+				return err
+			}
+		}
+		if err := it.Error(); err != nil {
+			return err
+		}
+		select {
+		case <-abort:
+			return ErrAborted
+		default:
+		}
+	}
+	return nil
+}
+
+// markStorage inserts all the nodes of a storage trie into the bloom filter.
+func (p *Pruner) markStorage(bloom *stateBloom, addrHash common.Hash, root common.Hash) error {
+	tr, err := p.statedb.OpenStorageTrie(addrHash, root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			bloom.add(hash[:])
+		}
+	}
+	return it.Error()
+}
@@ -0,0 +1,107 @@
+package pruner
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+)
+
+// Tests that pruning keeps the retained state intact and deletes the state of
+// the roots that weren't retained.
+func TestPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pruner")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	diskdb, err := kcoindb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer diskdb.Close()
+	db := state.NewDatabase(diskdb)
+
+	// Create an old state and a new one that modifies some of the accounts
+	commit := func(root common.Hash, modify func(*state.StateDB)) common.Hash {
+		statedb, _ := state.New(root, db)
+		modify(statedb)
+		root, err := statedb.Commit(false)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		if err := db.TrieDB().Commit(root, false); err != nil {
+			t.Fatalf("failed to flush state: %v", err)
+		}
+		return root
+	}
+	oldRoot := commit(common.Hash{}, func(statedb *state.StateDB) {
+		for i := byte(0); i < 64; i++ {
+			addr := common.BytesToAddress([]byte{i})
+			statedb.AddBalance(addr, big.NewInt(int64(i)+1))
+			statedb.SetState(addr, common.Hash{i}, common.Hash{i, i})
+			if i%4 == 0 {
+				statedb.SetCode(addr, []byte{i, i, i})
+			}
+		}
+	})
+	newRoot := commit(oldRoot, func(statedb *state.StateDB) {
+		for i := byte(0); i < 64; i += 2 {
+			addr := common.BytesToAddress([]byte{i})
+			statedb.AddBalance(addr, big.NewInt(1))
+			statedb.SetState(addr, common.Hash{i}, common.Hash{i, i, i})
+		}
+	})
+
+	pruner := NewPruner(diskdb, state.NewDatabase(diskdb), 1)
+	if err := pruner.Prune([]common.Hash{newRoot}, nil); err != nil {
+		t.Fatalf("failed to prune state: %v", err)
+	}
+	if ok, _ := diskdb.Has(oldRoot[:]); ok {
+		t.Errorf("old state root not pruned")
+	}
+	statedb, err := state.New(newRoot, state.NewDatabase(diskdb))
+	if err != nil {
+		t.Fatalf("retained state unavailable: %v", err)
+	}
+	for i := byte(0); i < 64; i++ {
+		addr := common.BytesToAddress([]byte{i})
+
+		balance, value := int64(i)+1, common.Hash{i, i}
+		if i%2 == 0 {
+			balance, value = balance+1, common.Hash{i, i, i}
+		}
+		if have := statedb.GetBalance(addr); have.Int64() != balance {
+			t.Errorf("account %d: balance mismatch: have %v, want %v", i, have, balance)
+		}
+		if have := statedb.GetState(addr, common.Hash{i}); have != value {
+			t.Errorf("account %d: storage mismatch: have %x, want %x", i, have, value)
+		}
+		if i%4 == 0 {
+			if code := statedb.GetCode(addr); len(code) != 3 {
+				t.Errorf("account %d: code missing", i)
+			}
+		}
+	}
+	if err := statedb.Error(); err != nil {
+		t.Fatalf("retained state incomplete: %v", err)
+	}
+}
+
+// Tests that the bloom filter never reports an inserted hash missing.
+func TestStateBloom(t *testing.T) {
+	bloom := newStateBloom(1024)
+	for i := 0; i < 512; i++ {
+		hash := crypto.Keccak256([]byte{byte(i), byte(i >> 8)})
+		bloom.add(hash)
+		if !bloom.contains(hash) {
+			t.Fatalf("hash %d: missing from bloom", i)
+		}
+	}
+}
@@ -163,6 +163,9 @@ func (c *stateObject) getTrie(db Database) Trie {
 func (self *stateObject) GetState(db Database, key common.Hash) common.Hash {
 	value, exists := self.cachedStorage[key]
 	if exists {
+		if self.db.recorder != nil {
+			self.db.recorder.loadSlot(self.address, key, value)
+		}
 		return value
 	}
 	// Load from DB in case it is missing.
@@ -179,6 +182,9 @@ func (self *stateObject) GetState(db Database, key common.Hash) common.Hash {
 		value.SetBytes(content)
 	}
 	self.cachedStorage[key] = value
+	if self.db.recorder != nil {
+		self.db.recorder.loadSlot(self.address, key, value)
+	}
 	return value
 }
 
@@ -192,6 +198,17 @@ func (self *stateObject) SetState(db Database, key, value common.Hash) {
 	self.setState(key, value)
 }
 
+// SetStorage replaces the entire account storage, discarding all the existing
+// entries. The change isn't journaled.
+func (self *stateObject) SetStorage(db Database, storage map[common.Hash]common.Hash) {
+	self.trie, _ = db.OpenStorageTrie(self.addrHash, common.Hash{})
+	self.cachedStorage = make(Storage)
+	self.dirtyStorage = make(Storage)
+	for key, value := range storage {
+		self.setState(key, value)
+	}
+}
+
 func (self *stateObject) setState(key, value common.Hash) {
 	self.cachedStorage[key] = value
 	self.dirtyStorage[key] = value
@@ -31,6 +31,14 @@ import (
 	"github.com/kowala-tech/kcoin/client/trie"
 )
 
+// proofList collects the nodes of a Merkle proof in path order.
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, value)
+	return nil
+}
+
 type revision struct {
 	id           int
 	journalIndex int
@@ -80,6 +88,10 @@ type StateDB struct {
 	validRevisions []revision
 	nextRevisionId int
 
+	// Recorder of the state accesses, only set while a transaction is executed
+	// speculatively.
+	recorder *accessRecorder
+
 	lock sync.Mutex
 }
 
@@ -177,18 +189,27 @@ func (self *StateDB) AddRefund(gas uint64) {
 // Exist reports whether the given account address exists in the state.
 // Notably this also returns true for suicided accounts.
 func (self *StateDB) Exist(addr common.Address) bool {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessExistence)
+	}
 	return self.getStateObject(addr) != nil
 }
 
 // Empty returns whether the state object is either non-existent
 // or empty according to the EIP161 specification (balance = nonce = code = 0)
 func (self *StateDB) Empty(addr common.Address) bool {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessExistence, accessBalance, accessNonce, accessCode)
+	}
 	so := self.getStateObject(addr)
 	return so == nil || so.empty()
 }
 
 // Retrieve the balance from the given address or 0 if object not found
 func (self *StateDB) GetBalance(addr common.Address) *big.Int {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessBalance)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Balance()
@@ -197,6 +218,9 @@ func (self *StateDB) GetBalance(addr common.Address) *big.Int {
 }
 
 func (self *StateDB) GetNonce(addr common.Address) uint64 {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessNonce)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Nonce()
@@ -206,6 +230,9 @@ func (self *StateDB) GetNonce(addr common.Address) uint64 {
 }
 
 func (self *StateDB) GetCode(addr common.Address) []byte {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessCode)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.Code(self.db)
@@ -214,6 +241,9 @@ func (self *StateDB) GetCode(addr common.Address) []byte {
 }
 
 func (self *StateDB) GetCodeSize(addr common.Address) int {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessCode)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject == nil {
 		return 0
@@ -229,6 +259,9 @@ func (self *StateDB) GetCodeSize(addr common.Address) int {
 }
 
 func (self *StateDB) GetCodeHash(addr common.Address) common.Hash {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessExistence, accessCode)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject == nil {
 		return common.Hash{}
@@ -236,7 +269,31 @@ func (self *StateDB) GetCodeHash(addr common.Address) common.Hash {
 	return common.BytesToHash(stateObject.CodeHash())
 }
 
+// GetProof returns the Merkle proof of the account at the given address in the
+// state trie, from the root down to the account leaf (or its absence).
+func (self *StateDB) GetProof(addr common.Address) ([][]byte, error) {
+	var proof proofList
+	err := self.trie.Prove(crypto.Keccak256(addr.Bytes()), 0, &proof)
+	return [][]byte(proof), err
+}
+
+// GetStorageProof returns the Merkle proof of the given storage slot in the
+// storage trie of the account at the given address. Non-existent accounts have
+// an empty storage trie, so their proofs are empty.
+func (self *StateDB) GetStorageProof(addr common.Address, key common.Hash) ([][]byte, error) {
+	var proof proofList
+	trie := self.StorageTrie(addr)
+	if trie == nil {
+		return [][]byte(proof), nil
+	}
+	err := trie.Prove(crypto.Keccak256(key.Bytes()), 0, &proof)
+	return [][]byte(proof), err
+}
+
 func (self *StateDB) GetState(addr common.Address, bhash common.Hash) common.Hash {
+	if self.recorder != nil {
+		self.recorder.readSlot(addr, bhash)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetState(self.db, bhash)
@@ -261,6 +318,9 @@ func (self *StateDB) StorageTrie(addr common.Address) Trie {
 }
 
 func (self *StateDB) HasSuicided(addr common.Address) bool {
+	if self.recorder != nil {
+		self.recorder.read(addr, accessExistence)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.suicided
@@ -316,6 +376,16 @@ func (self *StateDB) SetState(addr common.Address, key, value common.Hash) {
 	}
 }
 
+// SetStorage replaces the entire storage of the given account. It's meant to
+// simulate calls against an overridden state, the change can't be reverted.
+func (self *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common.Hash) {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.SetStorage(self.db, storage)
+		self.stateObjectsDirty[addr] = struct{}{}
+	}
+}
+
 // Suicide marks the given account as suicided.
 // This clears the account balance.
 //
@@ -360,6 +430,9 @@ func (self *StateDB) deleteStateObject(stateObject *stateObject) {
 
 // Retrieve a state object given by the address. Returns nil if not found.
 func (self *StateDB) getStateObject(addr common.Address) (stateObject *stateObject) {
+	if self.recorder != nil {
+		defer func() { self.recorder.loadAccount(addr, stateObject) }()
+	}
 	// Prefer 'live' objects.
 	if obj := self.stateObjects[addr]; obj != nil {
 		if obj.deleted {
@@ -408,6 +481,9 @@ func (self *StateDB) createObject(addr common.Address) (newobj, prev *stateObjec
 		self.journal.append(createObjectChange{account: &addr})
 	} else {
 		self.journal.append(resetObjectChange{prev: prev})
+		if self.recorder != nil {
+			self.recorder.origins[addr].reset = true
+		}
 	}
 	self.setStateObject(newobj)
 	return newobj, prev
@@ -23,7 +23,7 @@ import (
 // actually committing the state.
 func TestUpdateLeaks(t *testing.T) {
 	// Create an empty state database
-	db, _ := kcoindb.NewMemDatabase()
+	db := kcoindb.NewMemDatabase()
 	state, _ := New(common.Hash{}, NewDatabase(db))
 
 	// Update it with some accounts
@@ -50,8 +50,8 @@ func TestUpdateLeaks(t *testing.T) {
 // only the one right before the commit.
 func TestIntermediateLeaks(t *testing.T) {
 	// Create two state databases, one transitioning to the final state, the other final from the beginning
-	transDb, _ := kcoindb.NewMemDatabase()
-	finalDb, _ := kcoindb.NewMemDatabase()
+	transDb := kcoindb.NewMemDatabase()
+	finalDb := kcoindb.NewMemDatabase()
 	transState, _ := New(common.Hash{}, NewDatabase(transDb))
 	finalState, _ := New(common.Hash{}, NewDatabase(finalDb))
 
@@ -106,7 +106,7 @@ func TestIntermediateLeaks(t *testing.T) {
 // https://github.com/ethereum/go-ethereum/pull/15549.
 func TestCopy(t *testing.T) {
 	// Create a random state test to copy and modify "independently"
-	orig, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+	orig, _ := New(common.Hash{}, NewDatabase(kcoindb.NewMemDatabase()))
 
 	for i := byte(0); i < 255; i++ {
 		obj := orig.GetOrNewStateObject(common.BytesToAddress([]byte{i}))
@@ -408,7 +408,7 @@ func (s *StateSuite) TestTouchDelete(c *check.C) {
 // TestCopyOfCopy tests that modified objects are carried over to the copy, and the copy of the copy.
 // See https://github.com/ethereum/go-ethereum/pull/15225#issuecomment-380191512
 func TestCopyOfCopy(t *testing.T) {
-	sdb, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+	sdb, _ := New(common.Hash{}, NewDatabase(kcoindb.NewMemDatabase()))
 	addr := common.HexToAddress("aaaa")
 	sdb.SetBalance(addr, big.NewInt(42))
 
@@ -419,3 +419,27 @@ func TestCopyOfCopy(t *testing.T) {
 		t.Fatalf("2nd copy fail, expected 42, got %v", got)
 	}
 }
+
+// Tests that replacing the storage of an account discards all the previously
+// committed slots, while keeping the new ones across a commit.
+func TestSetStorage(t *testing.T) {
+	db := kcoindb.NewMemDatabase()
+	state, _ := New(common.Hash{}, NewDatabase(db))
+
+	addr := common.BytesToAddress([]byte{0x01})
+	state.SetState(addr, common.Hash{0x01}, common.Hash{0x11})
+	root, _ := state.Commit(false)
+	state, _ = New(root, state.Database())
+
+	state.SetStorage(addr, map[common.Hash]common.Hash{{0x02}: {0x22}})
+	for i := 0; i < 2; i++ {
+		if value := state.GetState(addr, common.Hash{0x01}); value != (common.Hash{}) {
+			t.Errorf("round %d: discarded slot value mismatch: have %x, want empty", i, value)
+		}
+		if value := state.GetState(addr, common.Hash{0x02}); value != (common.Hash{0x22}) {
+			t.Errorf("round %d: new slot value mismatch: have %x, want %x", i, value, common.Hash{0x22})
+		}
+		root, _ = state.Commit(false)
+		state, _ = New(root, state.Database())
+	}
+}
@@ -35,6 +35,10 @@ func NewStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consen
 // the transaction messages using the statedb and applying any rewards to both
 // the processor (coinbase) and any included uncles.
 //
+// The transactions are executed speculatively in parallel if the config sets
+// multiple workers, unless the block holds a single one or the transactions
+// are traced, and committed in order.
+//
 // Process returns the receipts and logs accumulated during the process and
 // returns the amount of gas that was used in the process. If any of the
 // transactions failed to execute due to insufficient gas it will return an error.
@@ -45,12 +49,26 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		header   = block.Header()
 		allLogs  []*types.Log
 		gp       = new(GasPool).AddGas(block.GasLimit())
+		txs      = block.Transactions()
+		executor *ParallelExecutor
 	)
-
+	if cfg.ParallelWorkers > 1 && len(txs) > 1 && !cfg.Debug {
+		executor = NewParallelExecutor(p.config, p.bc, nil, statedb, header, txs, cfg)
+		defer executor.Close()
+	}
 	// Iterate over and process the individual transactions
-	for i, tx := range block.Transactions() {
+	for i, tx := range txs {
 		statedb.Prepare(tx.Hash(), block.Hash(), i)
-		receipt, _, err := ApplyTransaction(p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg)
+
+		var (
+			receipt *types.Receipt
+			err     error
+		)
+		if executor != nil {
+			receipt, _, err = executor.ApplyTransaction(gp, tx, usedGas)
+		} else {
+			receipt, _, err = ApplyTransaction(p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg)
+		}
 		if err != nil {
 			log.Debug("failed StateProcessor.Process", "data", spew.Sdump(
 				header.Number,
@@ -77,6 +95,9 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 // for the transaction, gas used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, uint64, error) {
+	if tx.Sponsored() && !config.IsSponsoredTx(header.Number) {
+		return nil, 0, ErrSponsoredTxNotActive
+	}
 	msg, err := tx.AsMessage(types.MakeSigner(config, header.Number))
 	if err != nil {
 		return nil, 0, err
@@ -60,6 +60,14 @@ var (
 	// is higher than the balance of the user's account.
 	ErrInsufficientFunds = errors.New("insufficient funds for gas * price + value")
 
+	// ErrInvalidPayer is returned if a sponsored transaction doesn't carry a valid
+	// signature of its fee payer.
+	ErrInvalidPayer = errors.New("invalid fee payer")
+
+	// ErrInsufficientPayerFunds is returned if the fee payer of a sponsored
+	// transaction can't afford its gas.
+	ErrInsufficientPayerFunds = errors.New("insufficient fee payer funds for gas * price")
+
 	// ErrIntrinsicGas is returned if the transaction is specified to use less gas
 	// than required to start the invocation.
 	ErrIntrinsicGas = errors.New("intrinsic gas too low")
@@ -515,6 +523,28 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// ContentFrom retrieves the data content of the transaction pool for a single
+// account, returning its pending as well as queued transactions sorted by nonce.
+func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	var pending, queued types.Transactions
+	if list, ok := pool.pending[addr]; ok {
+		pending = list.Flatten()
+	}
+	if list, ok := pool.queue[addr]; ok {
+		queued = list.Flatten()
+	}
+	return pending, queued
+}
+
+// ReplacementPrice returns the minimum gas price a transaction with the same
+// sender and nonce as the given one must pay to replace it in the pool.
+func (pool *TxPool) ReplacementPrice(tx *types.Transaction) *big.Int {
+	return replacementPrice(tx.GasPrice(), pool.config.PriceBump)
+}
+
 // Pending retrieves all currently processable transactions, groupped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -575,9 +605,32 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if pool.currentState.GetNonce(from) > tx.Nonce() {
 		return ErrNonceTooLow
 	}
+	// Sponsored transactions are only valid once the fork is scheduled and must
+	// be signed by a fee payer able to cover the gas
+	if tx.Sponsored() {
+		next := new(big.Int).Add(pool.chain.CurrentBlock().Number(), common.Big1)
+		if !pool.chainconfig.IsSponsoredTx(next) {
+			return ErrSponsoredTxNotActive
+		}
+		payer, err := types.TxPayer(pool.signer, tx)
+		if err != nil {
+			return ErrInvalidPayer
+		}
+		// The payer must cover the fees of all its pooled transactions, apart
+		// from the one the transaction replaces
+		fees := pool.all.Sponsored(payer)
+		if old := pool.sameNonce(from, tx.Nonce()); old != nil {
+			if oldPayer := old.FeePayer(); oldPayer != nil && *oldPayer == payer {
+				fees.Sub(fees, old.Fee())
+			}
+		}
+		if pool.currentState.GetBalance(payer).Cmp(fees.Add(fees, tx.Fee())) < 0 {
+			return ErrInsufficientPayerFunds
+		}
+	}
 	// Transactor should have enough funds to cover the costs
-	// cost == V + GP * GL
-	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
+	// cost == V + GP * GL, or V alone if the gas is sponsored
+	if pool.currentState.GetBalance(from).Cmp(tx.SenderCost()) < 0 {
 		return ErrInsufficientFunds
 	}
 	intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, true)
@@ -590,6 +643,20 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// sameNonce returns the pooled transaction of an account with the given nonce,
+// if any.
+func (pool *TxPool) sameNonce(from common.Address, nonce uint64) *types.Transaction {
+	if list := pool.pending[from]; list != nil {
+		if tx := list.txs.Get(nonce); tx != nil {
+			return tx
+		}
+	}
+	if list := pool.queue[from]; list != nil {
+		return list.txs.Get(nonce)
+	}
+	return nil
+}
+
 // add validates a transaction and inserts it into the non-executable queue for
 // later pending promotion and execution. If the transaction is a replacement for
 // an already pending or queued one, it overwrites the previous and returns this
@@ -668,6 +735,38 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 	return replace, nil
 }
 
+// Check runs a transaction through the same checks as add without inserting it
+// into the pool, returning the reason it would be rejected, if any.
+func (pool *TxPool) Check(tx *types.Transaction, local bool) error {
+	// The priced heap is mutated while checking for underpriced transactions
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	hash := tx.Hash()
+	if pool.all.Get(hash) != nil {
+		return fmt.Errorf("known transaction: %x", hash)
+	}
+	if err := pool.validateTx(tx, local); err != nil {
+		return err
+	}
+	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+		if !local && pool.priced.Underpriced(tx, pool.locals) {
+			return ErrUnderpriced
+		}
+	}
+	from, _ := types.TxSender(pool.signer, tx) // already validated
+	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
+		if !list.Accepts(tx, pool.config.PriceBump) {
+			return ErrReplaceUnderpriced
+		}
+		return nil
+	}
+	if list := pool.queue[from]; list != nil && !list.Accepts(tx, pool.config.PriceBump) {
+		return ErrReplaceUnderpriced
+	}
+	return nil
+}
+
 // enqueueTx inserts a new transaction into the non-executable transaction queue.
 //
 // Note, this method assumes the pool lock is held!
@@ -1114,6 +1213,34 @@ func (pool *TxPool) demoteUnexecutables() {
 			delete(pool.beats, addr)
 		}
 	}
+	pool.dropUnpayableSponsored()
+}
+
+// dropUnpayableSponsored drops the cheapest sponsored transactions of the fee
+// payers whose balance no longer covers the fees of their pooled transactions.
+func (pool *TxPool) dropUnpayableSponsored() {
+	for payer, fees := range pool.all.Payers() {
+		balance := pool.currentState.GetBalance(payer)
+		if fees.Cmp(balance) <= 0 {
+			continue
+		}
+		var txs types.Transactions
+		pool.all.Range(func(hash common.Hash, tx *types.Transaction) bool {
+			if p := tx.FeePayer(); p != nil && *p == payer {
+				txs = append(txs, tx)
+			}
+			return true
+		})
+		sort.Sort(types.TxByPrice(txs))
+
+		for i := len(txs) - 1; i >= 0 && fees.Cmp(balance) > 0; i-- {
+			hash := txs[i].Hash()
+			log.Trace("Removed unpayable sponsored transaction", "hash", hash, "payer", payer)
+			fees.Sub(fees, txs[i].Fee())
+			pool.removeTx(hash, true)
+			pendingNofundsCounter.Inc(1)
+		}
+	}
 }
 
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
@@ -1175,16 +1302,41 @@ func (as *accountSet) add(addr common.Address) {
 // TxPool.mu mutex.
 type txLookup struct {
 	all  map[common.Hash]*types.Transaction
+	fees map[common.Address]*big.Int // fees of the sponsored transactions by payer
 	lock sync.RWMutex
 }
 
 // newTxLookup returns a new txLookup structure.
 func newTxLookup() *txLookup {
 	return &txLookup{
-		all: make(map[common.Hash]*types.Transaction),
+		all:  make(map[common.Hash]*types.Transaction),
+		fees: make(map[common.Address]*big.Int),
 	}
 }
 
+// Sponsored returns the fees of the transactions sponsored by the payer.
+func (t *txLookup) Sponsored(payer common.Address) *big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if fees, ok := t.fees[payer]; ok {
+		return new(big.Int).Set(fees)
+	}
+	return new(big.Int)
+}
+
+// Payers returns the fees of the sponsored transactions of every fee payer.
+func (t *txLookup) Payers() map[common.Address]*big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	payers := make(map[common.Address]*big.Int, len(t.fees))
+	for payer, fees := range t.fees {
+		payers[payer] = new(big.Int).Set(fees)
+	}
+	return payers
+}
+
 // Range calls f on each key and value present in the map.
 func (t *txLookup) Range(f func(hash common.Hash, tx *types.Transaction) bool) {
 	t.lock.RLock()
@@ -1218,7 +1370,20 @@ func (t *txLookup) Add(tx *types.Transaction) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	t.all[tx.Hash()] = tx
+	hash := tx.Hash()
+	if _, ok := t.all[hash]; ok {
+		return
+	}
+	t.all[hash] = tx
+
+	if payer := tx.FeePayer(); payer != nil {
+		fees, ok := t.fees[*payer]
+		if !ok {
+			fees = new(big.Int)
+			t.fees[*payer] = fees
+		}
+		fees.Add(fees, tx.Fee())
+	}
 }
 
 // Remove removes a transaction from the lookup.
@@ -1226,5 +1391,16 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	tx, ok := t.all[hash]
+	if !ok {
+		return
+	}
 	delete(t.all, hash)
+
+	if payer := tx.FeePayer(); payer != nil {
+		fees := t.fees[*payer]
+		if fees.Sub(fees, tx.Fee()).Sign() <= 0 {
+			delete(t.fees, *payer)
+		}
+	}
 }
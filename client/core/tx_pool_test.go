@@ -0,0 +1,276 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/event"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// testTxPoolConfig is a transaction pool configuration without stateful disk
+// sideeffects used during testing.
+var testTxPoolConfig TxPoolConfig
+
+func init() {
+	testTxPoolConfig = DefaultTxPoolConfig
+	testTxPoolConfig.Journal = ""
+}
+
+type testBlockChain struct {
+	statedb       *state.StateDB
+	gasLimit      uint64
+	chainHeadFeed *event.Feed
+}
+
+func (bc *testBlockChain) CurrentBlock() *types.Block {
+	return types.NewBlock(&types.Header{
+		GasLimit: bc.gasLimit,
+	}, nil, nil, nil)
+}
+
+func (bc *testBlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.CurrentBlock()
+}
+
+func (bc *testBlockChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return bc.statedb, nil
+}
+
+func (bc *testBlockChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription {
+	return bc.chainHeadFeed.Subscribe(ch)
+}
+
+func transaction(nonce uint64, gaslimit uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	return pricedTransaction(nonce, gaslimit, big.NewInt(1), key)
+}
+
+func pricedTransaction(nonce uint64, gaslimit uint64, gasprice *big.Int, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), gaslimit, gasprice, nil), types.NewAndromedaSigner(params.TestChainConfig.ChainID), key)
+	return tx
+}
+
+func sponsoredTransaction(nonce uint64, gasprice *big.Int, key, payerKey *ecdsa.PrivateKey) *types.Transaction {
+	signer := types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+	tx := types.NewTransaction(nonce, common.Address{}, big.NewInt(100), params.TxGas, gasprice, nil).WithFeePayer(crypto.PubkeyToAddress(payerKey.PublicKey))
+	tx, _ = types.SignTx(tx, signer, key)
+	tx, _ = types.SignPayer(tx, signer, payerKey)
+	return tx
+}
+
+func setupTxPool() (*TxPool, *ecdsa.PrivateKey) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	key, _ := crypto.GenerateKey()
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+
+	return pool, key
+}
+
+// testAddBalance adds a balance to an account in the state of the pool.
+func testAddBalance(pool *TxPool, addr common.Address, amount *big.Int) {
+	pool.mu.Lock()
+	pool.currentState.AddBalance(addr, amount)
+	pool.mu.Unlock()
+}
+
+// testSetNonce sets the nonce of an account in the state of the pool.
+func testSetNonce(pool *TxPool, addr common.Address, nonce uint64) {
+	pool.mu.Lock()
+	pool.currentState.SetNonce(addr, nonce)
+	pool.mu.Unlock()
+}
+
+func TestTxPoolContentFrom(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000))
+
+	for _, nonce := range []uint64{0, 1, 3} {
+		if err := pool.AddRemote(transaction(nonce, 100000, key)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+	}
+	pending, queued := pool.ContentFrom(from)
+	if len(pending) != 2 || pending[0].Nonce() != 0 || pending[1].Nonce() != 1 {
+		t.Fatalf("pending transactions mismatch: have %v", pending)
+	}
+	if len(queued) != 1 || queued[0].Nonce() != 3 {
+		t.Fatalf("queued transactions mismatch: have %v", queued)
+	}
+
+	other, _ := crypto.GenerateKey()
+	if pending, queued := pool.ContentFrom(crypto.PubkeyToAddress(other.PublicKey)); len(pending) != 0 || len(queued) != 0 {
+		t.Fatalf("unknown account content: have %d pending and %d queued, want none", len(pending), len(queued))
+	}
+}
+
+func TestTxPoolReplacementPrice(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	original := pricedTransaction(0, 100000, big.NewInt(100), key)
+	if err := pool.AddRemote(original); err != nil {
+		t.Fatalf("failed to add original transaction: %v", err)
+	}
+	price := pool.ReplacementPrice(original)
+	if price.Cmp(big.NewInt(110)) != 0 {
+		t.Fatalf("replacement price mismatch: have %v, want %v", price, 110)
+	}
+
+	// the replacement price is the lowest accepted one
+	if err := pool.Check(pricedTransaction(0, 100000, new(big.Int).Sub(price, common.Big1), key), false); err != ErrReplaceUnderpriced {
+		t.Fatalf("underpriced replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	replacement := pricedTransaction(0, 100000, price, key)
+	if err := pool.Check(replacement, false); err != nil {
+		t.Fatalf("replacement rejected: %v", err)
+	}
+	if err := pool.AddRemote(replacement); err != nil {
+		t.Fatalf("failed to replace transaction: %v", err)
+	}
+	if pool.Get(original.Hash()) != nil || pool.Get(replacement.Hash()) == nil {
+		t.Fatal("original transaction not replaced")
+	}
+}
+
+func TestTxPoolCheck(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000))
+	testSetNonce(pool, from, 1)
+	pool.lockedReset(nil, nil)
+
+	known := transaction(1, 100000, key)
+	if err := pool.AddRemote(known); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+
+	poor, _ := crypto.GenerateKey()
+	tests := []struct {
+		tx   *types.Transaction
+		want error
+	}{
+		{transaction(0, 100000, key), ErrNonceTooLow},
+		{transaction(2, 2000000, key), ErrGasLimit},
+		{transaction(2, 1000, key), ErrIntrinsicGas},
+		{transaction(0, 100000, poor), ErrInsufficientFunds},
+		{pricedTransaction(2, 100000, big.NewInt(0), key), ErrUnderpriced},
+	}
+	for i, test := range tests {
+		if err := pool.Check(test.tx, false); err != test.want {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, test.want)
+		}
+	}
+	if err := pool.Check(known, false); err == nil {
+		t.Error("known transaction accepted")
+	}
+
+	// valid transactions are checked without being added
+	valid := transaction(2, 100000, key)
+	if err := pool.Check(valid, false); err != nil {
+		t.Fatalf("valid transaction rejected: %v", err)
+	}
+	if pool.Get(valid.Hash()) != nil {
+		t.Fatal("checked transaction added to the pool")
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d pending and %d queued, want 1 and 0", pending, queued)
+	}
+}
+
+func TestTxPoolSponsoredFeesAreCumulative(t *testing.T) {
+	t.Parallel()
+
+	pool, payerKey := setupTxPool()
+	defer pool.Stop()
+
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000))
+		keys = append(keys, key)
+	}
+	// the payer covers two and a half fees
+	price := big.NewInt(100)
+	fee := new(big.Int).Mul(price, new(big.Int).SetUint64(params.TxGas))
+	testAddBalance(pool, crypto.PubkeyToAddress(payerKey.PublicKey), new(big.Int).Div(new(big.Int).Mul(fee, big.NewInt(5)), big.NewInt(2)))
+
+	if err := pool.AddRemote(sponsoredTransaction(0, price, keys[0], payerKey)); err != nil {
+		t.Fatalf("failed to add first sponsored transaction: %v", err)
+	}
+	if err := pool.AddRemote(sponsoredTransaction(0, price, keys[1], payerKey)); err != nil {
+		t.Fatalf("failed to add second sponsored transaction: %v", err)
+	}
+	if err := pool.AddRemote(sponsoredTransaction(0, price, keys[2], payerKey)); err != ErrInsufficientPayerFunds {
+		t.Fatalf("third sponsored transaction error mismatch: have %v, want %v", err, ErrInsufficientPayerFunds)
+	}
+
+	// a replacement only pays for the difference with the replaced fee
+	if err := pool.AddRemote(sponsoredTransaction(0, big.NewInt(110), keys[1], payerKey)); err != nil {
+		t.Fatalf("failed to replace sponsored transaction: %v", err)
+	}
+	if err := pool.AddRemote(sponsoredTransaction(0, big.NewInt(160), keys[1], payerKey)); err != ErrInsufficientPayerFunds {
+		t.Fatalf("unpayable replacement error mismatch: have %v, want %v", err, ErrInsufficientPayerFunds)
+	}
+}
+
+func TestTxPoolDropsUnpayableSponsored(t *testing.T) {
+	t.Parallel()
+
+	pool, payerKey := setupTxPool()
+	defer pool.Stop()
+
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	price := big.NewInt(100)
+	fee := new(big.Int).Mul(price, new(big.Int).SetUint64(params.TxGas))
+	testAddBalance(pool, payer, new(big.Int).Mul(fee, big.NewInt(3)))
+
+	var txs types.Transactions
+	for i := 0; i < 2; i++ {
+		key, _ := crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000))
+
+		tx := sponsoredTransaction(0, new(big.Int).Add(price, big.NewInt(int64(i))), key, payerKey)
+		if err := pool.AddRemote(tx); err != nil {
+			t.Fatalf("failed to add sponsored transaction %d: %v", i, err)
+		}
+		txs = append(txs, tx)
+	}
+
+	// the payer spends its funds elsewhere, only the best paying transaction
+	// can still be paid for
+	pool.mu.Lock()
+	pool.currentState.SubBalance(payer, new(big.Int).Add(fee, common.Big1))
+	pool.mu.Unlock()
+	pool.lockedReset(nil, nil)
+
+	if pool.Get(txs[0].Hash()) != nil {
+		t.Error("cheapest unpayable sponsored transaction not dropped")
+	}
+	if pool.Get(txs[1].Hash()) == nil {
+		t.Error("payable sponsored transaction dropped")
+	}
+	if fees := pool.all.Sponsored(payer); fees.Cmp(txs[1].Fee()) != 0 {
+		t.Errorf("sponsored fees mismatch: have %v, want %v", fees, txs[1].Fee())
+	}
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+)
+
+//go:generate gencodec -type AggregateSignature -field-override aggregateSignatureMarshalling -out gen_aggregate_json.go
+
+var (
+	ErrNoAggregateKey        = errors.New("voter has no aggregate key")
+	ErrNoAggregateSignature  = errors.New("vote has no aggregate signature")
+	ErrUnknownCommitVoter    = errors.New("pre-commit sender is not a voter")
+	ErrDuplicateCommitVoter  = errors.New("duplicate pre-commit sender")
+	ErrInvalidSignerBitmap   = errors.New("signer bitmap does not match the voters set")
+	ErrMismatchingPreCommits = errors.New("pre-commits do not match the first pre-commit")
+)
+
+// AggregateSignature is the compact form of a commit: a bitmap of the voters
+// (in voters set order) that signed the pre-commit and the aggregate of their
+// BLS signatures.
+type AggregateSignature struct {
+	Signers   []byte `json:"signers"    gencodec:"required"`
+	Signature []byte `json:"signature"  gencodec:"required"`
+}
+
+// aggregateSignatureMarshalling - field type overrides for gencodec
+type aggregateSignatureMarshalling struct {
+	Signers   hexutil.Bytes
+	Signature hexutil.Bytes
+}
+
+// Copy returns a deep copy of the aggregate signature.
+func (agg *AggregateSignature) Copy() *AggregateSignature {
+	return &AggregateSignature{
+		Signers:   common.CopyBytes(agg.Signers),
+		Signature: common.CopyBytes(agg.Signature),
+	}
+}
+
+// Signed reports whether the voter at index i is part of the signers.
+func (agg *AggregateSignature) Signed(i int) bool {
+	if i < 0 || i/8 >= len(agg.Signers) {
+		return false
+	}
+	return agg.Signers[i/8]&(1<<uint(i%8)) != 0
+}
+
+// Count returns the number of signers.
+func (agg *AggregateSignature) Count() int {
+	count := 0
+	for _, b := range agg.Signers {
+		for ; b != 0; b &= b - 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// ValidBitmap reports whether the signer bitmap covers exactly n voters.
+func (agg *AggregateSignature) ValidBitmap(n int) bool {
+	if len(agg.Signers) != (n+7)/8 {
+		return false
+	}
+	for i := n; i < len(agg.Signers)*8; i++ {
+		if agg.Signed(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// SignVoteAggregate adds the BLS signature of the vote, used to build
+// aggregated commits, to an (ECDSA) signed vote.
+func SignVoteAggregate(vote *Vote, signer Signer, key *bls.PrivateKey) *Vote {
+	h := signer.Hash(vote)
+	return vote.WithAggregateSignature(key.Sign(h[:]).Marshal())
+}
+
+// NewAggregateCommit builds an aggregated commit out of the pre-commits for
+// the same block. Every pre-commit must carry a BLS signature of a voter with
+// a registered aggregate key.
+func NewAggregateCommit(voters Voters, signer Signer, first *Vote, precommits Votes) (*Commit, error) {
+	// the pre-commits are signed with the consensus keys of the voters
+	index := make(map[common.Address]int, voters.Len())
+	for i := 0; i < voters.Len(); i++ {
+		index[voters.At(i).ConsensusKey()] = i
+	}
+
+	signers := make([]byte, (voters.Len()+7)/8)
+	sigs := make([]*bls.Signature, 0, len(precommits))
+	for _, vote := range precommits {
+		if vote.BlockHash() != first.BlockHash() || vote.Round() != first.Round() {
+			return nil, ErrMismatchingPreCommits
+		}
+		addr, err := VoteSender(signer, vote)
+		if err != nil {
+			return nil, err
+		}
+		i, ok := index[addr]
+		if !ok {
+			return nil, ErrUnknownCommitVoter
+		}
+		if signers[i/8]&(1<<uint(i%8)) != 0 {
+			return nil, ErrDuplicateCommitVoter
+		}
+		if voters.At(i).AggregateKey() == nil {
+			return nil, ErrNoAggregateKey
+		}
+		raw := vote.AggregateSignature()
+		if raw == nil {
+			return nil, ErrNoAggregateSignature
+		}
+		sig, err := bls.UnmarshalSignature(raw)
+		if err != nil {
+			return nil, err
+		}
+		signers[i/8] |= 1 << uint(i%8)
+		sigs = append(sigs, sig)
+	}
+
+	agg, err := bls.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Commit{
+		PreCommits:     Votes{},
+		FirstPreCommit: first,
+		Aggregated: []*AggregateSignature{{
+			Signers:   signers,
+			Signature: agg.Marshal(),
+		}},
+	}, nil
+}
@@ -0,0 +1,29 @@
+package types
+
+import "testing"
+
+func TestAggregateSignatureBitmap(t *testing.T) {
+	agg := &AggregateSignature{Signers: []byte{0x05, 0x01}}
+
+	if have, want := agg.Count(), 3; have != want {
+		t.Fatalf("count mismatch: have %d, want %d", have, want)
+	}
+	for i, want := range []bool{true, false, true, false, false, false, false, false, true, false} {
+		if have := agg.Signed(i); have != want {
+			t.Errorf("voter %d: signed mismatch: have %v, want %v", i, have, want)
+		}
+	}
+	if agg.Signed(-1) || agg.Signed(16) {
+		t.Error("out of range voter reported as signer")
+	}
+
+	if !agg.ValidBitmap(9) {
+		t.Error("bitmap rejected for 9 voters")
+	}
+	if agg.ValidBitmap(8) {
+		t.Error("bitmap accepted with a signer past the voters set")
+	}
+	if agg.ValidBitmap(17) {
+		t.Error("bitmap accepted with the wrong length")
+	}
+}
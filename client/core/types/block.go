@@ -93,6 +93,10 @@ type Commit struct {
 	// @NOTE (rgeraldes) - pre-commits are in order of address
 	PreCommits     Votes `json:"votes"    gencodec:"required"`
 	FirstPreCommit *Vote `json:"vote"     gencodec:"required"`
+
+	// @NOTE - after the aggregated commit fork the pre-commits are replaced
+	// by a signer bitmap and a single aggregate signature.
+	Aggregated []*AggregateSignature `json:"aggregated,omitempty" rlp:"tail"`
 }
 
 func (cmt *Commit) Commits() Votes {
@@ -107,8 +111,16 @@ func (cmt *Commit) Hash() common.Hash {
 	return rlpHash(cmt)
 }
 
+// Aggregate returns the aggregate signature of the commit, if any.
+func (cmt *Commit) Aggregate() *AggregateSignature {
+	if len(cmt.Aggregated) == 0 {
+		return nil
+	}
+	return cmt.Aggregated[0]
+}
+
 func (cmt *Commit) Round() uint64 {
-	if len(cmt.PreCommits) == 0 {
+	if len(cmt.PreCommits) == 0 && cmt.Aggregate() == nil {
 		return 0
 	}
 
@@ -222,6 +234,13 @@ func CopyCommit(commit *Commit) *Commit {
 		cpy.FirstPreCommit = &(*commit.FirstPreCommit)
 	}
 
+	if len(commit.Aggregated) > 0 {
+		cpy.Aggregated = make([]*AggregateSignature, len(commit.Aggregated))
+		for i, agg := range commit.Aggregated {
+			cpy.Aggregated[i] = agg.Copy()
+		}
+	}
+
 	return &cpy
 }
 
@@ -0,0 +1,156 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/rlp"
+	"github.com/kowala-tech/kcoin/client/trie"
+)
+
+var (
+	ErrReceiptNotFound = errors.New("receipt not found in the receipts trie")
+	ErrLogNotFound     = errors.New("log not found in the receipt")
+)
+
+// VoterKey identifies a voter of the validators set of a block: its account
+// along with the consensus key that signs its votes. The hash of the set only
+// depends on these keys.
+type VoterKey struct {
+	Address      common.Address
+	ConsensusKey common.Address
+}
+
+// VoterKeys is the validators set of a block of a remote chain.
+type VoterKeys []VoterKey
+
+// NewVoterKeys returns the keys of the given voters.
+func NewVoterKeys(voters Voters) VoterKeys {
+	keys := make(VoterKeys, voters.Len())
+	for i := range keys {
+		voter := voters.At(i)
+		keys[i] = VoterKey{Address: voter.Address(), ConsensusKey: voter.ConsensusKey()}
+	}
+	return keys
+}
+
+// Voters returns the voters set with the keys, which can be verified against
+// the validators hash of a header.
+func (keys VoterKeys) Voters() (Voters, error) {
+	list := make([]*Voter, len(keys))
+	for i, key := range keys {
+		list[i] = NewVoterWithConsensusKey(key.Address, key.ConsensusKey, new(big.Int), new(big.Int))
+	}
+	return NewVoters(list)
+}
+
+// HeaderProof proves to the light client of a bridge that a header of the
+// remote chain was committed by more than two thirds of its validators.
+//
+// The trusted voters are the validators set last trusted by the light
+// client. They must be given if the header changes the validators set, so
+// that the light client can check that enough of the validators it trusts
+// committed the header.
+type HeaderProof struct {
+	Header  *Header
+	Commit  *Commit
+	Voters  VoterKeys
+	Trusted VoterKeys
+}
+
+// ReceiptProof proves that a log was emitted in a block of the remote chain,
+// through the path of the receipt in the receipts trie of the block.
+type ReceiptProof struct {
+	Number   *big.Int
+	TxIndex  uint64
+	LogIndex uint64
+	Nodes    [][]byte
+}
+
+// NewReceiptProof returns the proof of the log at the given index of the
+// receipt at the given index in the receipts of a block.
+func NewReceiptProof(number *big.Int, receipts Receipts, txIndex, logIndex uint64) (*ReceiptProof, error) {
+	if txIndex >= uint64(len(receipts)) {
+		return nil, ErrReceiptNotFound
+	}
+	if logIndex >= uint64(len(receipts[txIndex].Logs)) {
+		return nil, ErrLogNotFound
+	}
+
+	// the receipts trie is built like DeriveSha
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(kcoindb.NewMemDatabase()))
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < receipts.Len(); i++ {
+		key, _ := rlp.EncodeToBytes(uint(i))
+		tr.Update(key, receipts.GetRlp(i))
+	}
+
+	key, _ := rlp.EncodeToBytes(uint(txIndex))
+	nodes := new(proofNodes)
+	if err := tr.Prove(key, 0, nodes); err != nil {
+		return nil, err
+	}
+
+	return &ReceiptProof{
+		Number:   new(big.Int).Set(number),
+		TxIndex:  txIndex,
+		LogIndex: logIndex,
+		Nodes:    nodes.list,
+	}, nil
+}
+
+// Log verifies the proof against the receipts root of the block and returns
+// the proven log.
+func (proof *ReceiptProof) Log(receiptsRoot common.Hash) (*Log, error) {
+	nodes := &proofNodes{index: make(map[common.Hash][]byte, len(proof.Nodes))}
+	for _, node := range proof.Nodes {
+		nodes.index[crypto.Keccak256Hash(node)] = node
+	}
+
+	key, _ := rlp.EncodeToBytes(uint(proof.TxIndex))
+	value, _, err := trie.VerifyProof(receiptsRoot, key, nodes)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, ErrReceiptNotFound
+	}
+
+	var receipt Receipt
+	if err := rlp.DecodeBytes(value, &receipt); err != nil {
+		return nil, err
+	}
+	if receipt.Status != ReceiptStatusSuccessful || proof.LogIndex >= uint64(len(receipt.Logs)) {
+		return nil, ErrLogNotFound
+	}
+	return receipt.Logs[proof.LogIndex], nil
+}
+
+// proofNodes collects the nodes of a trie proof and serves them back for the
+// verification of the proof.
+type proofNodes struct {
+	list  [][]byte
+	index map[common.Hash][]byte
+}
+
+func (n *proofNodes) Put(key []byte, value []byte) error {
+	n.list = append(n.list, common.CopyBytes(value))
+	return nil
+}
+
+func (n *proofNodes) Get(key []byte) ([]byte, error) {
+	if value, ok := n.index[common.BytesToHash(key)]; ok {
+		return value, nil
+	}
+	return nil, errors.New("missing proof node")
+}
+
+func (n *proofNodes) Has(key []byte) (bool, error) {
+	_, ok := n.index[common.BytesToHash(key)]
+	return ok, nil
+}
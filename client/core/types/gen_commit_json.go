@@ -10,20 +10,23 @@ import (
 // MarshalJSON marshals as JSON.
 func (c Commit) MarshalJSON() ([]byte, error) {
 	type Commit struct {
-		PreCommits     Votes `json:"votes"    gencodec:"required"`
-		FirstPreCommit *Vote `json:"vote"     gencodec:"required"`
+		PreCommits     Votes                 `json:"votes"    gencodec:"required"`
+		FirstPreCommit *Vote                 `json:"vote"     gencodec:"required"`
+		Aggregated     []*AggregateSignature `json:"aggregated,omitempty" rlp:"tail"`
 	}
 	var enc Commit
 	enc.PreCommits = c.PreCommits
 	enc.FirstPreCommit = c.FirstPreCommit
+	enc.Aggregated = c.Aggregated
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (c *Commit) UnmarshalJSON(input []byte) error {
 	type Commit struct {
-		PreCommits     *Votes `json:"votes"    gencodec:"required"`
-		FirstPreCommit *Vote  `json:"vote"     gencodec:"required"`
+		PreCommits     *Votes                `json:"votes"    gencodec:"required"`
+		FirstPreCommit *Vote                 `json:"vote"     gencodec:"required"`
+		Aggregated     []*AggregateSignature `json:"aggregated,omitempty" rlp:"tail"`
 	}
 	var dec Commit
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -37,5 +40,8 @@ func (c *Commit) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 'vote' for Commit")
 	}
 	c.FirstPreCommit = dec.FirstPreCommit
+	if dec.Aggregated != nil {
+		c.Aggregated = dec.Aggregated
+	}
 	return nil
 }
@@ -16,13 +16,14 @@ var _ = (*votedataMarshalling)(nil)
 // MarshalJSON marshals as JSON.
 func (v votedata) MarshalJSON() ([]byte, error) {
 	type votedata struct {
-		BlockHash   common.Hash    `json:"blockHash"    gencodec:"required"`
-		BlockNumber *hexutil.Big   `json:"blockNumber"  gencodec:"required"`
-		Round       hexutil.Uint64 `json:"round"        gencodec:"required"`
-		Type        VoteType       `json:"type"         gencodec:"required"`
-		V           *hexutil.Big   `json:"v"   gencodec:"required"`
-		R           *hexutil.Big   `json:"r"   gencodec:"required"`
-		S           *hexutil.Big   `json:"s"   gencodec:"required"`
+		BlockHash   common.Hash     `json:"blockHash"    gencodec:"required"`
+		BlockNumber *hexutil.Big    `json:"blockNumber"  gencodec:"required"`
+		Round       hexutil.Uint64  `json:"round"        gencodec:"required"`
+		Type        VoteType        `json:"type"         gencodec:"required"`
+		V           *hexutil.Big    `json:"v"   gencodec:"required"`
+		R           *hexutil.Big    `json:"r"   gencodec:"required"`
+		S           *hexutil.Big    `json:"s"   gencodec:"required"`
+		Aggregate   []hexutil.Bytes `json:"aggregate,omitempty" rlp:"tail"`
 	}
 	var enc votedata
 	enc.BlockHash = v.BlockHash
@@ -32,6 +33,12 @@ func (v votedata) MarshalJSON() ([]byte, error) {
 	enc.V = (*hexutil.Big)(v.V)
 	enc.R = (*hexutil.Big)(v.R)
 	enc.S = (*hexutil.Big)(v.S)
+	if v.Aggregate != nil {
+		enc.Aggregate = make([]hexutil.Bytes, len(v.Aggregate))
+		for k, a := range v.Aggregate {
+			enc.Aggregate[k] = a
+		}
+	}
 	return json.Marshal(&enc)
 }
 
@@ -45,6 +52,7 @@ func (v *votedata) UnmarshalJSON(input []byte) error {
 		V           *hexutil.Big    `json:"v"   gencodec:"required"`
 		R           *hexutil.Big    `json:"r"   gencodec:"required"`
 		S           *hexutil.Big    `json:"s"   gencodec:"required"`
+		Aggregate   []hexutil.Bytes `json:"aggregate,omitempty" rlp:"tail"`
 	}
 	var dec votedata
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -78,5 +86,11 @@ func (v *votedata) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 's' for votedata")
 	}
 	v.S = (*big.Int)(dec.S)
+	if dec.Aggregate != nil {
+		v.Aggregate = make([][]byte, len(dec.Aggregate))
+		for k, a := range dec.Aggregate {
+			v.Aggregate[k] = a
+		}
+	}
 	return nil
 }
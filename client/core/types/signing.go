@@ -14,6 +14,8 @@ import (
 var (
 	ErrInvalidSig     = errors.New("invalid v, r, s values")
 	ErrInvalidChainID = errors.New("invalid chain id for signer")
+	ErrInvalidPayer   = errors.New("fee payer signature doesn't match the fee payer")
+	ErrNotSponsored   = errors.New("transaction has no fee payer")
 )
 
 type Hasher interface {
@@ -54,6 +56,20 @@ func SignTx(tx *Transaction, signer Signer, prv *ecdsa.PrivateKey) (*Transaction
 	return tx.WithSignature(signer, sig)
 }
 
+// SignPayer signs a sponsored transaction as its fee payer using the given signer
+// and private key. The transaction must already be signed by its sender.
+func SignPayer(tx *Transaction, signer Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	if !tx.Sponsored() {
+		return nil, ErrNotSponsored
+	}
+	h := signer.PayerHash(tx)
+	sig, err := crypto.Sign(h.Bytes(), prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithPayerSignature(signer, sig)
+}
+
 // SignProposal signs the proposal using the given signer and private key
 func SignProposal(proposal *Proposal, signer Signer, prv *ecdsa.PrivateKey) (*Proposal, error) {
 	h := signer.Hash(proposal)
@@ -94,6 +110,30 @@ func TxSender(signer Signer, tx *Transaction) (common.Address, error) {
 	return addr, nil
 }
 
+// TxPayer returns the account paying for the gas of the transaction, which is
+// its sender unless the transaction is sponsored.
+func TxPayer(signer Signer, tx *Transaction) (common.Address, error) {
+	if !tx.Sponsored() {
+		return TxSender(signer, tx)
+	}
+	if sc := tx.payer.Load(); sc != nil {
+		sigCache := sc.(sigCache)
+		// If the signer used to derive the payer in a previous
+		// call is not the same as used current, invalidate
+		// the cache.
+		if sigCache.signer.Equal(signer) {
+			return sigCache.from, nil
+		}
+	}
+
+	addr, err := signer.Payer(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	tx.payer.Store(sigCache{signer: signer, from: addr})
+	return addr, nil
+}
+
 func ProposalSender(signer Signer, proposal *Proposal) (common.Address, error) {
 	if sc := proposal.from.Load(); sc != nil {
 		sigCache := sc.(sigCache)
@@ -137,11 +177,15 @@ func VoteSender(signer Signer, vote *Vote) (common.Address, error) {
 type Signer interface {
 	// Sender returns the sender address of the transaction.
 	Sender(s Sender) (common.Address, error)
+	// Payer returns the address paying for the gas of the transaction.
+	Payer(tx *Transaction) (common.Address, error)
 	// SignatureValues returns the raw R, S, V values corresponding to the
 	// given signature.
 	SignatureValues(sig []byte) (r, s, v *big.Int, err error)
 	// Hash returns the hash to be signed.
 	Hash(h Hasher) common.Hash
+	// PayerHash returns the hash to be signed by the fee payer.
+	PayerHash(tx *Transaction) common.Hash
 	// Equal returns true if the given signer is the same as the receiver.
 	Equal(Signer) bool
 }
@@ -183,6 +227,24 @@ func (s AndromedaSigner) Sender(sn Sender) (common.Address, error) {
 	return recoverPlain(s.Hash(sn), snR, snS, V, true)
 }
 
+// Payer returns the fee payer of a sponsored transaction after checking it
+// against the payer signature. Other transactions are paid for by their sender.
+func (s AndromedaSigner) Payer(tx *Transaction) (common.Address, error) {
+	if !tx.Sponsored() {
+		return s.Sender(tx)
+	}
+	V, R, S := tx.RawPayerSignatureValues()
+	if !isProtectedV(V) {
+		return UnprotectedSigner{}.Payer(tx)
+	}
+	if deriveChainID(V).Cmp(s.chainID) != 0 {
+		return common.Address{}, ErrInvalidChainID
+	}
+	V = new(big.Int).Sub(V, s.chainIDMul)
+	V.Sub(V, big8)
+	return checkPayer(tx, s.PayerHash(tx), R, S, V)
+}
+
 // SignatureValues returns a new signature. This signature
 // needs to be in the [R || S || V] format where V is 0 or 1.
 func (s AndromedaSigner) SignatureValues(sig []byte) (R, S, V *big.Int, err error) {
@@ -203,6 +265,11 @@ func (s AndromedaSigner) Hash(h Hasher) common.Hash {
 	return h.HashWithData(s.chainID, uint(0), uint(0))
 }
 
+// PayerHash returns the hash to be signed by the fee payer.
+func (s AndromedaSigner) PayerHash(tx *Transaction) common.Hash {
+	return tx.PayerHashWithData(s.chainID, uint(0), uint(0))
+}
+
 type UnprotectedSigner struct{}
 
 func (s UnprotectedSigner) Equal(s2 Signer) bool {
@@ -225,10 +292,35 @@ func (s UnprotectedSigner) Sender(sn Sender) (common.Address, error) {
 	return recoverPlain(s.Hash(sn), snR, snS, snV, true)
 }
 
+func (s UnprotectedSigner) Payer(tx *Transaction) (common.Address, error) {
+	if !tx.Sponsored() {
+		return s.Sender(tx)
+	}
+	V, R, S := tx.RawPayerSignatureValues()
+	return checkPayer(tx, s.PayerHash(tx), R, S, V)
+}
+
 func (s UnprotectedSigner) Hash(h Hasher) common.Hash {
 	return h.HashWithData()
 }
 
+func (s UnprotectedSigner) PayerHash(tx *Transaction) common.Hash {
+	return tx.PayerHashWithData()
+}
+
+// checkPayer recovers the fee payer signature of a sponsored transaction and
+// makes sure it was produced by the designated fee payer.
+func checkPayer(tx *Transaction, sighash common.Hash, R, S, V *big.Int) (common.Address, error) {
+	addr, err := recoverPlain(sighash, R, S, V, true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if addr != *tx.FeePayer() {
+		return common.Address{}, ErrInvalidPayer
+	}
+	return addr, nil
+}
+
 func recoverPlain(sighash common.Hash, R, S, Vb *big.Int, homestead bool) (common.Address, error) {
 	if Vb.BitLen() > 8 {
 		return common.Address{}, ErrInvalidSig
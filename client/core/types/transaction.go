@@ -15,6 +15,11 @@ import (
 )
 
 //go:generate gencodec -type txdata -field-override txdataMarshaling -out gen_tx_json.go
+//go:generate gencodec -type sponsorship -field-override sponsorshipMarshaling -out gen_sponsorship_json.go
+
+var (
+	errTooManySponsors = errors.New("transaction has more than one fee payer")
+)
 
 // deriveSigner makes a *best* guess about which signer to use.
 func deriveSigner(V *big.Int) Signer {
@@ -24,9 +29,10 @@ func deriveSigner(V *big.Int) Signer {
 type Transaction struct {
 	data txdata
 	// caches
-	hash atomic.Value
-	size atomic.Value
-	from atomic.Value
+	hash  atomic.Value
+	size  atomic.Value
+	from  atomic.Value
+	payer atomic.Value
 }
 
 type txdata struct {
@@ -44,6 +50,27 @@ type txdata struct {
 
 	// This is only used when marshaling to JSON.
 	Hash *common.Hash `json:"hash" rlp:"-"`
+
+	// Fee payer of a sponsored transaction. Regular transactions leave it empty,
+	// which keeps their encoding unchanged.
+	Sponsor []sponsorship `json:"sponsor,omitempty" rlp:"tail"`
+}
+
+// sponsorship holds the account paying for the gas of a sponsored transaction
+// along with its signature over the sender signed transaction.
+type sponsorship struct {
+	Payer common.Address `json:"payer" gencodec:"required"`
+
+	// Signature values
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+}
+
+type sponsorshipMarshaling struct {
+	V *hexutil.Big
+	R *hexutil.Big
+	S *hexutil.Big
 }
 
 type txdataMarshaling struct {
@@ -109,6 +136,9 @@ func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
 	_, size, _ := s.Kind()
 	err := s.Decode(&tx.data)
 	if err == nil {
+		if len(tx.data.Sponsor) > 1 {
+			return errTooManySponsors
+		}
 		tx.size.Store(common.StorageSize(rlp.ListSize(size)))
 	}
 
@@ -139,6 +169,9 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 	if !crypto.ValidateSignatureValues(V, dec.R, dec.S, false) {
 		return ErrInvalidSig
 	}
+	if len(dec.Sponsor) > 1 {
+		return errTooManySponsors
+	}
 	*tx = Transaction{data: dec}
 	return nil
 }
@@ -174,6 +207,31 @@ func (tx *Transaction) To() *common.Address {
 	return &to
 }
 
+// FeePayer returns the account paying for the gas of the transaction.
+// It returns nil if the transaction is paid for by its sender.
+func (tx *Transaction) FeePayer() *common.Address {
+	if !tx.Sponsored() {
+		return nil
+	}
+	payer := tx.data.Sponsor[0].Payer
+	return &payer
+}
+
+// Sponsored returns whether the gas of the transaction is paid for by an account
+// other than its sender.
+func (tx *Transaction) Sponsored() bool {
+	return len(tx.data.Sponsor) > 0
+}
+
+// WithFeePayer returns a new, unsigned transaction whose gas is paid for by the
+// given account. Both the sender and the fee payer must sign it afterwards.
+func (tx *Transaction) WithFeePayer(payer common.Address) *Transaction {
+	cpy := &Transaction{data: tx.data}
+	cpy.data.V, cpy.data.R, cpy.data.S = new(big.Int), new(big.Int), new(big.Int)
+	cpy.data.Sponsor = []sponsorship{{Payer: payer, V: new(big.Int), R: new(big.Int), S: new(big.Int)}}
+	return cpy
+}
+
 // Hash hashes the RLP encoding of tx.
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
@@ -195,6 +253,9 @@ func (tx *Transaction) UnprotectedHash() common.Hash {
 	return tx.HashWithData()
 }
 
+// HashWithData returns the hash of the transaction fields signed by the sender.
+// Sponsored transactions also commit to the fee payer, so a sponsorship can't be
+// stripped to make the sender pay for the gas.
 func (tx *Transaction) HashWithData(data ...interface{}) common.Hash {
 	txData := []interface{}{
 		tx.data.AccountNonce,
@@ -204,6 +265,27 @@ func (tx *Transaction) HashWithData(data ...interface{}) common.Hash {
 		tx.data.Amount,
 		tx.data.Payload,
 	}
+	if tx.Sponsored() {
+		txData = append(txData, tx.data.Sponsor[0].Payer)
+	}
+	return rlpHash(append(txData, data...))
+}
+
+// PayerHashWithData returns the hash signed by the fee payer of a sponsored
+// transaction. It covers the sender signature, binding the payer to the sender.
+func (tx *Transaction) PayerHashWithData(data ...interface{}) common.Hash {
+	txData := []interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		tx.FeePayer(),
+		tx.data.V,
+		tx.data.R,
+		tx.data.S,
+	}
 	return rlpHash(append(txData, data...))
 }
 
@@ -250,7 +332,10 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 	}
 
 	var err error
-	msg.from, err = TxSender(s, tx)
+	if msg.from, err = TxSender(s, tx); err != nil {
+		return msg, err
+	}
+	msg.payer, err = TxPayer(s, tx)
 	return msg, err
 }
 
@@ -266,17 +351,56 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	return cpy, nil
 }
 
+// WithPayerSignature returns a new transaction with the given fee payer signature.
+// This signature needs to be formatted as described in the yellow paper (v+27).
+func (tx *Transaction) WithPayerSignature(signer Signer, sig []byte) (*Transaction, error) {
+	if !tx.Sponsored() {
+		return nil, ErrNotSponsored
+	}
+	r, s, v, err := signer.SignatureValues(sig)
+	if err != nil {
+		return nil, err
+	}
+	cpy := &Transaction{data: tx.data}
+	cpy.data.Sponsor = []sponsorship{{Payer: tx.data.Sponsor[0].Payer, V: v, R: r, S: s}}
+	return cpy, nil
+}
+
 // Cost returns amount + gasprice * gaslimit.
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+	total := tx.Fee()
 	total.Add(total, tx.data.Amount)
 	return total
 }
 
+// Fee returns gasprice * gaslimit, the most the transaction can pay for gas.
+func (tx *Transaction) Fee() *big.Int {
+	return new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+}
+
+// SenderCost returns the part of the cost charged to the sender, which excludes
+// the fee of sponsored transactions.
+func (tx *Transaction) SenderCost() *big.Int {
+	if tx.Sponsored() {
+		return tx.Value()
+	}
+	return tx.Cost()
+}
+
 func (tx *Transaction) RawSignatureValues() (*big.Int, *big.Int, *big.Int) {
 	return tx.data.V, tx.data.R, tx.data.S
 }
 
+// RawPayerSignatureValues returns the fee payer signature values of a sponsored
+// transaction, or nils if the transaction isn't sponsored.
+func (tx *Transaction) RawPayerSignatureValues() (*big.Int, *big.Int, *big.Int) {
+	if !tx.Sponsored() {
+		return nil, nil, nil
+	}
+	sponsor := tx.data.Sponsor[0]
+	return sponsor.V, sponsor.R, sponsor.S
+}
+
 func (tx *Transaction) String() string {
 	var from, to string
 	if tx.data.V != nil {
@@ -459,6 +583,7 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 type Message struct {
 	to         *common.Address
 	from       common.Address
+	payer      common.Address
 	nonce      uint64
 	amount     *big.Int
 	gasLimit   uint64
@@ -470,6 +595,7 @@ type Message struct {
 func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool) Message {
 	return Message{
 		from:       from,
+		payer:      from,
 		to:         to,
 		nonce:      nonce,
 		amount:     amount,
@@ -480,11 +606,12 @@ func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *b
 	}
 }
 
-func (m Message) From() common.Address { return m.from }
-func (m Message) To() *common.Address  { return m.to }
-func (m Message) GasPrice() *big.Int   { return m.gasPrice }
-func (m Message) Value() *big.Int      { return m.amount }
-func (m Message) Gas() uint64          { return m.gasLimit }
-func (m Message) Nonce() uint64        { return m.nonce }
-func (m Message) Data() []byte         { return m.data }
-func (m Message) CheckNonce() bool     { return m.checkNonce }
+func (m Message) From() common.Address  { return m.from }
+func (m Message) Payer() common.Address { return m.payer }
+func (m Message) To() *common.Address   { return m.to }
+func (m Message) GasPrice() *big.Int    { return m.gasPrice }
+func (m Message) Value() *big.Int       { return m.amount }
+func (m Message) Gas() uint64           { return m.gasLimit }
+func (m Message) Nonce() uint64         { return m.nonce }
+func (m Message) Data() []byte          { return m.data }
+func (m Message) CheckNonce() bool      { return m.checkNonce }
@@ -9,6 +9,8 @@ import (
 	"github.com/kowala-tech/kcoin/client/accounts/keystore"
 	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/rlp"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,3 +41,76 @@ func TestFrom(t *testing.T) {
 
 	require.Equal(t, &account.Address, fromAddr)
 }
+
+func TestSponsoredTransaction(t *testing.T) {
+	senderKey, _ := crypto.GenerateKey()
+	payerKey, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	signer := types.NewAndromedaSigner(big.NewInt(1))
+
+	tx := types.NewTransaction(
+		0,
+		common.HexToAddress("0xecf8f87f810ecf450940c9f60066b4a7a501d6a7"),
+		big.NewInt(10),
+		21000,
+		big.NewInt(1),
+		nil,
+	).WithFeePayer(payer)
+
+	signedTx, err := types.SignTx(tx, signer, senderKey)
+	require.NoError(t, err)
+	signedTx, err = types.SignPayer(signedTx, signer, payerKey)
+	require.NoError(t, err)
+
+	enc, err := rlp.EncodeToBytes(signedTx)
+	require.NoError(t, err)
+	decoded := new(types.Transaction)
+	require.NoError(t, rlp.DecodeBytes(enc, decoded))
+	require.Equal(t, signedTx.Hash(), decoded.Hash())
+
+	from, err := types.TxSender(signer, decoded)
+	require.NoError(t, err)
+	require.Equal(t, sender, from)
+
+	feePayer, err := types.TxPayer(signer, decoded)
+	require.NoError(t, err)
+	require.Equal(t, payer, feePayer)
+	require.Equal(t, big.NewInt(10), decoded.SenderCost())
+}
+
+func TestSponsoredTransactionWrongPayer(t *testing.T) {
+	senderKey, _ := crypto.GenerateKey()
+	payerKey, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	signer := types.NewAndromedaSigner(big.NewInt(1))
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(10), 21000, big.NewInt(1), nil).
+		WithFeePayer(crypto.PubkeyToAddress(payerKey.PublicKey))
+
+	signedTx, err := types.SignTx(tx, signer, senderKey)
+	require.NoError(t, err)
+	signedTx, err = types.SignPayer(signedTx, signer, otherKey)
+	require.NoError(t, err)
+
+	_, err = types.TxPayer(signer, signedTx)
+	require.Equal(t, types.ErrInvalidPayer, err)
+}
+
+func TestRegularTransactionEncodingUnchanged(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := types.NewAndromedaSigner(big.NewInt(1))
+
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(10), 21000, big.NewInt(1), nil), signer, key)
+	require.NoError(t, err)
+
+	enc, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+
+	v, r, s := tx.RawSignatureValues()
+	legacy, err := rlp.EncodeToBytes([]interface{}{uint64(0), big.NewInt(1), uint64(21000), &common.Address{}, big.NewInt(10), []byte{}, v, r, s})
+	require.NoError(t, err)
+	require.Equal(t, legacy, enc)
+	require.False(t, tx.Sponsored())
+	require.Nil(t, tx.FeePayer())
+}
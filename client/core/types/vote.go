@@ -83,6 +83,9 @@ type votedata struct {
 	V *big.Int `json:"v"   gencodec:"required"`
 	R *big.Int `json:"r"   gencodec:"required"`
 	S *big.Int `json:"s"   gencodec:"required"`
+
+	// optional BLS signature of the vote, used to build aggregated commits
+	Aggregate [][]byte `json:"aggregate,omitempty" rlp:"tail"`
 }
 
 // votedataMarshalling - field type overrides for gencodec
@@ -92,6 +95,7 @@ type votedataMarshalling struct {
 	V           *hexutil.Big
 	R           *hexutil.Big
 	S           *hexutil.Big
+	Aggregate   []hexutil.Bytes
 }
 
 // NewVote returns a new consensus vote
@@ -182,6 +186,21 @@ func (vote *Vote) WithSignature(signer Signer, sig []byte) (*Vote, error) {
 	return cpy, nil
 }
 
+// WithAggregateSignature returns a new vote carrying the given BLS signature.
+func (vote *Vote) WithAggregateSignature(sig []byte) *Vote {
+	cpy := &Vote{data: vote.data}
+	cpy.data.Aggregate = [][]byte{common.CopyBytes(sig)}
+	return cpy
+}
+
+// AggregateSignature returns the BLS signature of the vote, if any.
+func (vote *Vote) AggregateSignature() []byte {
+	if len(vote.data.Aggregate) == 0 {
+		return nil
+	}
+	return vote.data.Aggregate[0]
+}
+
 func (vote *Vote) Protected() bool {
 	return true
 }
@@ -295,6 +314,20 @@ func (v *VotesSet) Leader() common.Hash {
 	return res
 }
 
+// ForBlock returns the votes for the given block.
+func (v *VotesSet) ForBlock(blockHash common.Hash) Votes {
+	v.l.RLock()
+	defer v.l.RUnlock()
+
+	votes := make(Votes, 0, v.counter[blockHash])
+	for _, vote := range v.m {
+		if vote.data.BlockHash == blockHash {
+			votes = append(votes, vote)
+		}
+	}
+	return votes
+}
+
 func (v *VotesSet) Get(h common.Hash) (*Vote, bool) {
 	v.l.RLock()
 	vote, ok := v.m[h]
@@ -6,33 +6,65 @@ import (
 	"math/big"
 
 	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
 	"github.com/kowala-tech/kcoin/client/rlp"
 )
 
 var ErrInvalidParams = errors.New("voters set needs at least one voter")
 
-// Voter represents a consensus Voter
+// Voter represents a consensus Voter. The address identifies the validator: it
+// holds the deposit and receives the block rewards. The consensus key is the
+// account that signs the proposals and votes of the validator.
 type Voter struct {
-	address common.Address
-	deposit *big.Int
-	weight  *big.Int
+	address      common.Address
+	consensusKey common.Address
+	deposit      *big.Int
+	weight       *big.Int
+	aggregateKey *bls.PublicKey
 }
 
 // NewVoter returns a new Voter instance
 func NewVoter(address common.Address, deposit *big.Int, weight *big.Int) *Voter {
 	return &Voter{
-		address: address,
-		deposit: deposit,
-		weight:  weight,
+		address:      address,
+		consensusKey: address,
+		deposit:      deposit,
+		weight:       weight,
 	}
 }
 
-func (val *Voter) Address() common.Address { return val.address }
-func (val *Voter) Deposit() *big.Int       { return val.deposit }
-func (val *Voter) Weight() *big.Int        { return val.weight }
+// NewVoterWithConsensusKey returns a new Voter instance that signs its
+// proposals and votes with a key distinct from its address
+func NewVoterWithConsensusKey(address common.Address, consensusKey common.Address, deposit *big.Int, weight *big.Int) *Voter {
+	voter := NewVoter(address, deposit, weight)
+	voter.consensusKey = consensusKey
+	return voter
+}
+
+// NewVoterWithAggregateKey returns a new Voter instance that is able to take part
+// in aggregated commits
+func NewVoterWithAggregateKey(address common.Address, deposit *big.Int, weight *big.Int, key *bls.PublicKey) *Voter {
+	voter := NewVoter(address, deposit, weight)
+	voter.aggregateKey = key
+	return voter
+}
+
+func (val *Voter) Address() common.Address      { return val.address }
+func (val *Voter) ConsensusKey() common.Address { return val.consensusKey }
+func (val *Voter) Deposit() *big.Int            { return val.deposit }
+func (val *Voter) Weight() *big.Int             { return val.weight }
+func (val *Voter) AggregateKey() *bls.PublicKey { return val.aggregateKey }
+
+// SetAggregateKey sets the key used by the voter to take part in aggregated
+// commits
+func (val *Voter) SetAggregateKey(key *bls.PublicKey) { val.aggregateKey = key }
 
 func (val *Voter) EncodeRLP(w io.Writer) error {
 	w.Write(val.address.Bytes())
+	// the consensus key only changes the hash of the set if it was rotated
+	if val.consensusKey != val.address {
+		w.Write(val.consensusKey.Bytes())
+	}
 	return nil
 }
 
@@ -43,6 +75,7 @@ type Voters interface {
 	NextProposer() *Voter
 	At(i int) *Voter
 	Get(addr common.Address) *Voter
+	GetByConsensusKey(key common.Address) *Voter
 	Len() int
 	Contains(addr common.Address) bool
 	Hash() common.Hash
@@ -98,6 +131,16 @@ func (voters voters) Get(addr common.Address) *Voter {
 	return nil
 }
 
+// GetByConsensusKey returns the Voter that signs with the given key, nil if not found
+func (voters voters) GetByConsensusKey(key common.Address) *Voter {
+	for _, voter := range voters {
+		if voter.ConsensusKey() == key {
+			return voter
+		}
+	}
+	return nil
+}
+
 // Len returns the amount of voters in this set
 // needed for hash thru interface DerivableList interface
 func (voters voters) Len() int {
@@ -26,10 +26,28 @@ func TestVoter_Properties(t *testing.T) {
 	voter := NewVoter(address, deposit, weight)
 
 	assert.Equal(t, address, voter.Address())
+	assert.Equal(t, address, voter.ConsensusKey())
 	assert.Equal(t, deposit, voter.Deposit())
 	assert.Equal(t, weight, voter.Weight())
 }
 
+func TestVoter_ConsensusKey(t *testing.T) {
+	address := common.HexToAddress("0x1000000000000000000000000000000000000000")
+	key := common.HexToAddress("0x5000000000000000000000000000000000000000")
+	voter := NewVoterWithConsensusKey(address, key, big.NewInt(100), big.NewInt(0))
+
+	assert.Equal(t, address, voter.Address())
+	assert.Equal(t, key, voter.ConsensusKey())
+
+	voters, err := NewVoters([]*Voter{voterSet[1], voter})
+	require.NoError(t, err)
+
+	assert.Equal(t, voter, voters.Get(address))
+	assert.Equal(t, voter, voters.GetByConsensusKey(key))
+	assert.Nil(t, voters.GetByConsensusKey(address))
+	assert.False(t, voters.Contains(key))
+}
+
 func TestVoters_EmptyReturnsError(t *testing.T) {
 	voters, err := NewVoters(nil)
 
@@ -118,6 +136,23 @@ func TestVoters_IsHashable(t *testing.T) {
 	assert.NotEqual(t, voters1.Hash(), voters2.Hash())
 }
 
+func TestVoters_HashChangesWithConsensusKey(t *testing.T) {
+	address := voterSet[0].Address()
+	rotated := NewVoterWithConsensusKey(address, common.HexToAddress("0x5000000000000000000000000000000000000000"), big.NewInt(100), big.NewInt(100))
+
+	voters1, err := NewVoters([]*Voter{voterSet[0], voterSet[1]})
+	require.NoError(t, err)
+
+	voters2, err := NewVoters([]*Voter{rotated, voterSet[1]})
+	require.NoError(t, err)
+
+	voters3, err := NewVoters([]*Voter{NewVoterWithConsensusKey(address, address, big.NewInt(100), big.NewInt(100)), voterSet[1]})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, voters1.Hash(), voters2.Hash())
+	assert.Equal(t, voters1.Hash(), voters3.Hash())
+}
+
 func TestNewDeposit(t *testing.T) {
 	amount := new(big.Int).SetUint64(100)
 	now := time.Now().Unix()
@@ -0,0 +1,134 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// AggregateKeysAddress is the address of the contract keeping the BLS keys
+// used by the validators to sign aggregated commits.
+var AggregateKeysAddress = common.BytesToAddress([]byte{12})
+
+// AggregateKeysABI is the input ABI used to generate the binding from.
+const AggregateKeysABI = `[{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getAggregateKey","outputs":[{"name":"","type":"bytes"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"key","type":"bytes"},{"name":"proof","type":"bytes"}],"name":"setAggregateKey","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[],"name":"aggregateKeysChecksum","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"validator","type":"address"}],"name":"AggregateKeySet","type":"event"}]`
+
+var aggregateKeysABI = mustParseABI(AggregateKeysABI)
+
+// storage layout of the aggregate keys contract: the keys take four words
+// from the slot of the validator in the keys mapping on.
+var aggregateChecksumSlot = common.BigToHash(big.NewInt(0))
+
+const (
+	aggregateKeysSlot = iota + 1
+	aggregateKeyWords = bls.PublicKeyLength / common.HashLength
+)
+
+var (
+	errAggregateMethod    = errors.New("aggregate keys: unknown method")
+	errAggregateDelegated = errors.New("aggregate keys: delegated call")
+	errAggregateValue     = errors.New("aggregate keys: method is not payable")
+	errAggregateProof     = errors.New("aggregate keys: invalid proof of possession")
+)
+
+// aggregateKeys registers the BLS keys of the validators. A key is only
+// registered along with a valid proof of possession, which protects the
+// aggregated signatures from rogue key attacks. Any account can register a
+// key: only the keys of the voters are used by the consensus.
+type aggregateKeys struct{}
+
+func (c *aggregateKeys) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := aggregateKeysABI.MethodById(input[:4]); err == nil && method.Name == "setAggregateKey" {
+			return params.AggregateKeySetGas
+		}
+	}
+	return params.AggregateKeyQueryGas
+}
+
+func (c *aggregateKeys) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if contract.CodeAddr == nil || contract.Address() != *contract.CodeAddr {
+		return nil, errAggregateDelegated
+	}
+	if contract.Value().Sign() > 0 {
+		return nil, errAggregateValue
+	}
+	if len(input) < 4 {
+		return nil, errAggregateMethod
+	}
+	method, err := aggregateKeysABI.MethodById(input[:4])
+	if err != nil {
+		return nil, errAggregateMethod
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, errAggregateMethod
+	}
+
+	switch method.Name {
+	case "getAggregateKey":
+		return method.Outputs.Pack(AggregateKey(evm.StateDB, args[0].(common.Address)))
+	case "aggregateKeysChecksum":
+		return method.Outputs.Pack(AggregateKeysChecksum(evm.StateDB))
+	case "setAggregateKey":
+		return nil, c.setAggregateKey(evm, contract.Caller(), args[0].([]byte), args[1].([]byte))
+	}
+	return nil, errAggregateMethod
+}
+
+func (c *aggregateKeys) setAggregateKey(evm *EVM, validator common.Address, key []byte, proof []byte) error {
+	if err := nativeWrite(evm, AggregateKeysAddress); err != nil {
+		return err
+	}
+	pub, err := bls.UnmarshalPublicKey(key)
+	if err != nil {
+		return err
+	}
+	sig, err := bls.UnmarshalSignature(proof)
+	if err != nil {
+		return err
+	}
+	if !pub.VerifyPossession(sig) {
+		return errAggregateProof
+	}
+
+	base := nativeSlot(aggregateKeysSlot, validator.Hash()).Big()
+	for i := 0; i < aggregateKeyWords; i++ {
+		slot := common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i))))
+		evm.StateDB.SetState(AggregateKeysAddress, slot, common.BytesToHash(key[i*common.HashLength:(i+1)*common.HashLength]))
+	}
+	checksum := crypto.Keccak256Hash(AggregateKeysChecksum(evm.StateDB).Bytes(), validator.Bytes(), key)
+	evm.StateDB.SetState(AggregateKeysAddress, aggregateChecksumSlot, checksum)
+
+	nativeLog(evm, AggregateKeysAddress, aggregateKeysABI.Events["AggregateKeySet"], []common.Hash{validator.Hash()})
+	return nil
+}
+
+// AggregateKey returns the marshalled aggregate key registered by the
+// validator, or an empty key if there isn't any.
+func AggregateKey(db StateDB, validator common.Address) []byte {
+	var (
+		base = nativeSlot(aggregateKeysSlot, validator.Hash()).Big()
+		key  = make([]byte, 0, bls.PublicKeyLength)
+		set  bool
+	)
+	for i := 0; i < aggregateKeyWords; i++ {
+		word := db.GetState(AggregateKeysAddress, common.BigToHash(new(big.Int).Add(base, big.NewInt(int64(i)))))
+		set = set || word != (common.Hash{})
+		key = append(key, word.Bytes()...)
+	}
+	if !set {
+		return []byte{}
+	}
+	return key
+}
+
+// AggregateKeysChecksum returns a checksum changing whenever a validator
+// registers a key.
+func AggregateKeysChecksum(db StateDB) common.Hash {
+	return db.GetState(AggregateKeysAddress, aggregateChecksumSlot)
+}
@@ -0,0 +1,411 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/kowala-tech/kcoin/client/rlp"
+)
+
+var (
+	// BridgeLightClientAddress is the address of the light client tracking the
+	// committed headers of the remote network.
+	BridgeLightClientAddress = common.BytesToAddress([]byte{9})
+
+	// BridgeTokenAddress is the address of the contract locking the native
+	// currency and minting the wrapped currency of the remote network. The
+	// contract has the same address on both networks.
+	BridgeTokenAddress = common.BytesToAddress([]byte{10})
+)
+
+// BridgeLightClientABI is the input ABI used to generate the binding from.
+const BridgeLightClientABI = `[{"constant":false,"inputs":[{"name":"proof","type":"bytes"}],"name":"submitHeader","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[],"name":"latestNumber","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"trustedValidatorsHash","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"number","type":"uint256"}],"name":"receiptsRoot","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"number","type":"uint256"}],"name":"blockHash","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"number","type":"uint256"},{"indexed":false,"name":"hash","type":"bytes32"},{"indexed":false,"name":"validatorsHash","type":"bytes32"}],"name":"HeaderSubmitted","type":"event"}]`
+
+// BridgeTokenABI is the input ABI used to generate the binding from.
+const BridgeTokenABI = `[{"constant":false,"inputs":[{"name":"recipient","type":"address"}],"name":"lock","outputs":[],"payable":true,"stateMutability":"payable","type":"function"},{"constant":false,"inputs":[{"name":"recipient","type":"address"},{"name":"amount","type":"uint256"}],"name":"burn","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"proof","type":"bytes"}],"name":"claim","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"totalSupply","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"number","type":"uint256"},{"name":"txIndex","type":"uint256"},{"name":"logIndex","type":"uint256"}],"name":"claimed","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":true,"name":"recipient","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Locked","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"sender","type":"address"},{"indexed":true,"name":"recipient","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Burned","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"number","type":"uint256"},{"indexed":true,"name":"recipient","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Claimed","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}]`
+
+var (
+	lightClientABI = mustParseABI(BridgeLightClientABI)
+	bridgeTokenABI = mustParseABI(BridgeTokenABI)
+)
+
+// storage layout of the light client
+var (
+	lightClientTrustedSlot = common.BigToHash(big.NewInt(0))
+	lightClientLatestSlot  = common.BigToHash(big.NewInt(1))
+)
+
+const (
+	lightClientHashesSlot   = 2
+	lightClientReceiptsSlot = 3
+)
+
+// storage layout of the bridge token
+var bridgeTokenSupplySlot = common.BigToHash(big.NewInt(1))
+
+const (
+	bridgeTokenBalancesSlot = 0
+	bridgeTokenClaimedSlot  = 2
+)
+
+var (
+	errBridgeMethod          = errors.New("bridge: unknown method")
+	errBridgeDelegated       = errors.New("bridge: delegated call")
+	errBridgeNotPayable      = errors.New("bridge: method is not payable")
+	errStaleHeader           = errors.New("bridge: header is not newer than the latest header")
+	errValidatorsMismatch    = errors.New("bridge: voters don't match the validators hash of the header")
+	errUntrustedValidators   = errors.New("bridge: trusted voters don't match the trusted validators hash")
+	errMissingCommit         = errors.New("bridge: header without commit")
+	errAggregateCommit       = errors.New("bridge: aggregate commits are not supported")
+	errCommitMismatch        = errors.New("bridge: pre-commit doesn't commit the header")
+	errInsufficientCommit    = errors.New("bridge: header not committed by more than two thirds of the voters")
+	errInsufficientTrust     = errors.New("bridge: header not committed by more than a third of the trusted voters")
+	errUnknownBridgeBlock    = errors.New("bridge: block header not submitted")
+	errInvalidBridgeLog      = errors.New("bridge: log is not a bridge transfer")
+	errAlreadyClaimed        = errors.New("bridge: transfer already claimed")
+	errInsufficientWrapped   = errors.New("bridge: insufficient wrapped balance")
+	errInsufficientLocked    = errors.New("bridge: insufficient locked balance")
+	errBridgeInvalidArgument = errors.New("bridge: invalid argument")
+)
+
+// bridgeCall decodes the method and arguments of a call to a bridge contract
+// and checks that the call runs within the scope of the contract.
+func bridgeCall(contractABI abi.ABI, contract *Contract, input []byte) (*abi.Method, []interface{}, error) {
+	if contract.CodeAddr == nil || contract.Address() != *contract.CodeAddr {
+		return nil, nil, errBridgeDelegated
+	}
+	if len(input) < 4 {
+		return nil, nil, errBridgeMethod
+	}
+	method, err := contractABI.MethodById(input[:4])
+	if err != nil {
+		return nil, nil, errBridgeMethod
+	}
+	if contract.Value().Sign() > 0 && method.Name != "lock" {
+		return nil, nil, errBridgeNotPayable
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, nil, errBridgeInvalidArgument
+	}
+	return method, args, nil
+}
+
+// bridgeLightClient verifies the headers of the remote network through the
+// commits of its validators. Every header changing the validators set must be
+// committed by more than a third of the last trusted validators, so that a
+// header is only trusted if an honest validator trusted it.
+type bridgeLightClient struct{}
+
+func (c *bridgeLightClient) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := lightClientABI.MethodById(input[:4]); err == nil && method.Name == "submitHeader" {
+			return params.BridgeHeaderGas + uint64(len(input))*params.BridgeHeaderByteGas
+		}
+	}
+	return params.BridgeQueryGas
+}
+
+func (c *bridgeLightClient) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	method, args, err := bridgeCall(lightClientABI, contract, input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "submitHeader":
+		return nil, c.submitHeader(evm, args[0].([]byte))
+	case "latestNumber":
+		return method.Outputs.Pack(evm.StateDB.GetState(BridgeLightClientAddress, lightClientLatestSlot).Big())
+	case "trustedValidatorsHash":
+		return method.Outputs.Pack(trustedValidatorsHash(evm))
+	case "receiptsRoot":
+		return method.Outputs.Pack(bridgeReceiptsRoot(evm, args[0].(*big.Int)))
+	case "blockHash":
+		slot := nativeSlot(lightClientHashesSlot, common.BigToHash(args[0].(*big.Int)))
+		return method.Outputs.Pack(evm.StateDB.GetState(BridgeLightClientAddress, slot))
+	}
+	return nil, errBridgeMethod
+}
+
+func (c *bridgeLightClient) submitHeader(evm *EVM, data []byte) error {
+	if err := nativeWrite(evm, BridgeLightClientAddress); err != nil {
+		return err
+	}
+
+	var proof types.HeaderProof
+	if err := rlp.DecodeBytes(data, &proof); err != nil {
+		return err
+	}
+	header := proof.Header
+	if header == nil || header.Number == nil {
+		return errBridgeInvalidArgument
+	}
+	latest := evm.StateDB.GetState(BridgeLightClientAddress, lightClientLatestSlot).Big()
+	if header.Number.Cmp(latest) <= 0 {
+		return errStaleHeader
+	}
+
+	voters, err := proof.Voters.Voters()
+	if err != nil {
+		return err
+	}
+	if voters.Hash() != header.ValidatorsHash {
+		return errValidatorsMismatch
+	}
+	signers, err := commitSigners(types.NewAndromedaSigner(evm.chainConfig.Bridge.ChainID), header, proof.Commit)
+	if err != nil {
+		return err
+	}
+	if signed := countSigners(voters, signers); signed*3 <= voters.Len()*2 {
+		return errInsufficientCommit
+	}
+
+	// a new validators set must be vouched for by the trusted validators
+	if trusted := trustedValidatorsHash(evm); header.ValidatorsHash != trusted {
+		trustedVoters, err := proof.Trusted.Voters()
+		if err != nil {
+			return err
+		}
+		if trustedVoters.Hash() != trusted {
+			return errUntrustedValidators
+		}
+		if signed := countSigners(trustedVoters, signers); signed*3 <= trustedVoters.Len() {
+			return errInsufficientTrust
+		}
+	}
+
+	number := common.BigToHash(header.Number)
+	hash := header.Hash()
+	evm.StateDB.SetState(BridgeLightClientAddress, nativeSlot(lightClientHashesSlot, number), hash)
+	evm.StateDB.SetState(BridgeLightClientAddress, nativeSlot(lightClientReceiptsSlot, number), header.ReceiptHash)
+	evm.StateDB.SetState(BridgeLightClientAddress, lightClientLatestSlot, number)
+	evm.StateDB.SetState(BridgeLightClientAddress, lightClientTrustedSlot, header.ValidatorsHash)
+
+	nativeLog(evm, BridgeLightClientAddress, lightClientABI.Events["HeaderSubmitted"], []common.Hash{number}, hash, header.ValidatorsHash)
+	return nil
+}
+
+// trustedValidatorsHash returns the hash of the last trusted validators set
+// of the remote network, which starts with the set of the bridge config.
+func trustedValidatorsHash(evm *EVM) common.Hash {
+	trusted := evm.StateDB.GetState(BridgeLightClientAddress, lightClientTrustedSlot)
+	if trusted == (common.Hash{}) {
+		return evm.chainConfig.Bridge.ValidatorsHash
+	}
+	return trusted
+}
+
+// bridgeReceiptsRoot returns the receipts root of a submitted header of the
+// remote network.
+func bridgeReceiptsRoot(evm *EVM, number *big.Int) common.Hash {
+	return evm.StateDB.GetState(BridgeLightClientAddress, nativeSlot(lightClientReceiptsSlot, common.BigToHash(number)))
+}
+
+// commitSigners returns the consensus keys that signed the pre-commits of the
+// commit for the header. Only the individually signed pre-commits are accepted
+// as the aggregate keys of the validators aren't part of the header.
+func commitSigners(signer types.Signer, header *types.Header, commit *types.Commit) (map[common.Address]struct{}, error) {
+	if commit == nil || commit.First() == nil {
+		return nil, errMissingCommit
+	}
+	if commit.Aggregate() != nil {
+		return nil, errAggregateCommit
+	}
+
+	first := commit.First()
+	if first.Type() != types.PreCommit || first.BlockHash() != header.Hash() {
+		return nil, errCommitMismatch
+	}
+
+	signers := make(map[common.Address]struct{}, len(commit.Commits()))
+	for _, vote := range commit.Commits() {
+		if vote == nil {
+			continue
+		}
+		if vote.Type() != types.PreCommit || vote.BlockHash() != first.BlockHash() || vote.Round() != first.Round() {
+			return nil, errCommitMismatch
+		}
+		addr, err := types.VoteSender(signer, vote)
+		if err != nil {
+			return nil, err
+		}
+		signers[addr] = struct{}{}
+	}
+	return signers, nil
+}
+
+// countSigners returns the number of voters that signed the commit.
+func countSigners(voters types.Voters, signers map[common.Address]struct{}) int {
+	count := 0
+	for i := 0; i < voters.Len(); i++ {
+		if _, ok := signers[voters.At(i).ConsensusKey()]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+// bridgeToken locks the native currency to be claimed as wrapped currency on
+// the remote network, and mints the wrapped currency of the remote network.
+// The wrapped currency is burned to unlock the native currency on the remote
+// network.
+type bridgeToken struct{}
+
+func (c *bridgeToken) RequiredGas(input []byte) uint64 {
+	if len(input) < 4 {
+		return params.BridgeQueryGas
+	}
+	method, err := bridgeTokenABI.MethodById(input[:4])
+	if err != nil || method.Const {
+		return params.BridgeQueryGas
+	}
+	if method.Name == "claim" {
+		return params.BridgeClaimGas + uint64(len(input))*params.BridgeClaimByteGas
+	}
+	return params.BridgeTransferGas
+}
+
+func (c *bridgeToken) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	method, args, err := bridgeCall(bridgeTokenABI, contract, input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "lock":
+		return nil, c.lock(evm, contract.Caller(), args[0].(common.Address), contract.Value())
+	case "burn":
+		return nil, c.burn(evm, contract.Caller(), args[0].(common.Address), args[1].(*big.Int))
+	case "claim":
+		return nil, c.claim(evm, args[0].([]byte))
+	case "transfer":
+		if err := c.transfer(evm, contract.Caller(), args[0].(common.Address), args[1].(*big.Int)); err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack(true)
+	case "balanceOf":
+		return method.Outputs.Pack(wrappedBalance(evm, args[0].(common.Address)))
+	case "totalSupply":
+		return method.Outputs.Pack(evm.StateDB.GetState(BridgeTokenAddress, bridgeTokenSupplySlot).Big())
+	case "claimed":
+		slot := claimedSlot(args[0].(*big.Int), args[1].(*big.Int), args[2].(*big.Int))
+		return method.Outputs.Pack(evm.StateDB.GetState(BridgeTokenAddress, slot) != (common.Hash{}))
+	}
+	return nil, errBridgeMethod
+}
+
+func (c *bridgeToken) lock(evm *EVM, sender, recipient common.Address, amount *big.Int) error {
+	if err := nativeWrite(evm, BridgeTokenAddress); err != nil {
+		return err
+	}
+	if amount.Sign() == 0 {
+		return errBridgeInvalidArgument
+	}
+	// the value of the call was already transferred to the contract
+	nativeLog(evm, BridgeTokenAddress, bridgeTokenABI.Events["Locked"], []common.Hash{sender.Hash(), recipient.Hash()}, amount)
+	return nil
+}
+
+func (c *bridgeToken) burn(evm *EVM, sender, recipient common.Address, amount *big.Int) error {
+	if err := nativeWrite(evm, BridgeTokenAddress); err != nil {
+		return err
+	}
+	if amount.Sign() == 0 {
+		return errBridgeInvalidArgument
+	}
+	balance := wrappedBalance(evm, sender)
+	if balance.Cmp(amount) < 0 {
+		return errInsufficientWrapped
+	}
+	setWrappedBalance(evm, sender, balance.Sub(balance, amount))
+	supply := evm.StateDB.GetState(BridgeTokenAddress, bridgeTokenSupplySlot).Big()
+	evm.StateDB.SetState(BridgeTokenAddress, bridgeTokenSupplySlot, common.BigToHash(supply.Sub(supply, amount)))
+
+	nativeLog(evm, BridgeTokenAddress, bridgeTokenABI.Events["Transfer"], []common.Hash{sender.Hash(), {}}, amount)
+	nativeLog(evm, BridgeTokenAddress, bridgeTokenABI.Events["Burned"], []common.Hash{sender.Hash(), recipient.Hash()}, amount)
+	return nil
+}
+
+func (c *bridgeToken) transfer(evm *EVM, from, to common.Address, amount *big.Int) error {
+	if err := nativeWrite(evm, BridgeTokenAddress); err != nil {
+		return err
+	}
+	balance := wrappedBalance(evm, from)
+	if balance.Cmp(amount) < 0 {
+		return errInsufficientWrapped
+	}
+	setWrappedBalance(evm, from, balance.Sub(balance, amount))
+	setWrappedBalance(evm, to, wrappedBalance(evm, to).Add(wrappedBalance(evm, to), amount))
+
+	nativeLog(evm, BridgeTokenAddress, bridgeTokenABI.Events["Transfer"], []common.Hash{from.Hash(), to.Hash()}, amount)
+	return nil
+}
+
+// claim mints the wrapped currency locked on the remote network, or unlocks
+// the native currency burned as wrapped currency on the remote network.
+func (c *bridgeToken) claim(evm *EVM, data []byte) error {
+	if err := nativeWrite(evm, BridgeTokenAddress); err != nil {
+		return err
+	}
+
+	var proof types.ReceiptProof
+	if err := rlp.DecodeBytes(data, &proof); err != nil {
+		return err
+	}
+	if proof.Number == nil {
+		return errBridgeInvalidArgument
+	}
+	root := bridgeReceiptsRoot(evm, proof.Number)
+	if root == (common.Hash{}) {
+		return errUnknownBridgeBlock
+	}
+	log, err := proof.Log(root)
+	if err != nil {
+		return err
+	}
+
+	locked, burned := bridgeTokenABI.Events["Locked"].Id(), bridgeTokenABI.Events["Burned"].Id()
+	if log.Address != BridgeTokenAddress || len(log.Topics) != 3 || (log.Topics[0] != locked && log.Topics[0] != burned) {
+		return errInvalidBridgeLog
+	}
+	recipient := common.BytesToAddress(log.Topics[2].Bytes())
+	amount := new(big.Int).SetBytes(log.Data)
+
+	slot := claimedSlot(proof.Number, new(big.Int).SetUint64(proof.TxIndex), new(big.Int).SetUint64(proof.LogIndex))
+	if evm.StateDB.GetState(BridgeTokenAddress, slot) != (common.Hash{}) {
+		return errAlreadyClaimed
+	}
+	evm.StateDB.SetState(BridgeTokenAddress, slot, common.BigToHash(common.Big1))
+
+	if log.Topics[0] == locked {
+		setWrappedBalance(evm, recipient, wrappedBalance(evm, recipient).Add(wrappedBalance(evm, recipient), amount))
+		supply := evm.StateDB.GetState(BridgeTokenAddress, bridgeTokenSupplySlot).Big()
+		evm.StateDB.SetState(BridgeTokenAddress, bridgeTokenSupplySlot, common.BigToHash(supply.Add(supply, amount)))
+		nativeLog(evm, BridgeTokenAddress, bridgeTokenABI.Events["Transfer"], []common.Hash{{}, recipient.Hash()}, amount)
+	} else {
+		if !evm.CanTransfer(evm.StateDB, BridgeTokenAddress, amount) {
+			return errInsufficientLocked
+		}
+		evm.Transfer(evm.StateDB, BridgeTokenAddress, recipient, amount)
+	}
+
+	nativeLog(evm, BridgeTokenAddress, bridgeTokenABI.Events["Claimed"], []common.Hash{common.BigToHash(proof.Number), recipient.Hash()}, amount)
+	return nil
+}
+
+func wrappedBalance(evm *EVM, addr common.Address) *big.Int {
+	return evm.StateDB.GetState(BridgeTokenAddress, nativeSlot(bridgeTokenBalancesSlot, addr.Hash())).Big()
+}
+
+func setWrappedBalance(evm *EVM, addr common.Address, balance *big.Int) {
+	evm.StateDB.SetState(BridgeTokenAddress, nativeSlot(bridgeTokenBalancesSlot, addr.Hash()), common.BigToHash(balance))
+}
+
+func claimedSlot(number, txIndex, logIndex *big.Int) common.Hash {
+	return nativeSlot(bridgeTokenClaimedSlot, common.BigToHash(number), common.BigToHash(txIndex), common.BigToHash(logIndex))
+}
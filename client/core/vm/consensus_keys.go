@@ -0,0 +1,118 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// ConsensusKeysAddress is the address of the contract keeping the accounts
+// used by the validators to sign their proposals and votes.
+var ConsensusKeysAddress = common.BytesToAddress([]byte{13})
+
+// ConsensusKeysABI is the input ABI used to generate the binding from.
+const ConsensusKeysABI = `[{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getConsensusKey","outputs":[{"name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"key","type":"address"}],"name":"setConsensusKey","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[],"name":"consensusKeysChecksum","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"validator","type":"address"},{"indexed":true,"name":"key","type":"address"}],"name":"ConsensusKeySet","type":"event"}]`
+
+var consensusKeysABI = mustParseABI(ConsensusKeysABI)
+
+// storage layout of the consensus keys contract
+var consensusChecksumSlot = common.BigToHash(big.NewInt(0))
+
+const (
+	consensusKeysSlot = iota + 1
+	consensusOwnersSlot
+)
+
+var (
+	errConsensusMethod    = errors.New("consensus keys: unknown method")
+	errConsensusDelegated = errors.New("consensus keys: delegated call")
+	errConsensusValue     = errors.New("consensus keys: method is not payable")
+	errConsensusKeyEmpty  = errors.New("consensus keys: empty key")
+	errConsensusKeyUsed   = errors.New("consensus keys: key in use by another account")
+)
+
+// consensusKeys registers the accounts signing the proposals and votes of the
+// validators, so that the validators can keep the account holding their
+// deposit offline. A key is used by a single account at a time. Any account
+// can register a key: only the keys of the voters are used by the consensus.
+type consensusKeys struct{}
+
+func (c *consensusKeys) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := consensusKeysABI.MethodById(input[:4]); err == nil && method.Name == "setConsensusKey" {
+			return params.ConsensusKeySetGas
+		}
+	}
+	return params.ConsensusKeyQueryGas
+}
+
+func (c *consensusKeys) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if contract.CodeAddr == nil || contract.Address() != *contract.CodeAddr {
+		return nil, errConsensusDelegated
+	}
+	if contract.Value().Sign() > 0 {
+		return nil, errConsensusValue
+	}
+	if len(input) < 4 {
+		return nil, errConsensusMethod
+	}
+	method, err := consensusKeysABI.MethodById(input[:4])
+	if err != nil {
+		return nil, errConsensusMethod
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, errConsensusMethod
+	}
+
+	switch method.Name {
+	case "getConsensusKey":
+		return method.Outputs.Pack(ConsensusKey(evm.StateDB, args[0].(common.Address)))
+	case "consensusKeysChecksum":
+		return method.Outputs.Pack(ConsensusKeysChecksum(evm.StateDB))
+	case "setConsensusKey":
+		return nil, c.setConsensusKey(evm, contract.Caller(), args[0].(common.Address))
+	}
+	return nil, errConsensusMethod
+}
+
+func (c *consensusKeys) setConsensusKey(evm *EVM, validator common.Address, key common.Address) error {
+	if err := nativeWrite(evm, ConsensusKeysAddress); err != nil {
+		return err
+	}
+	if key == (common.Address{}) {
+		return errConsensusKeyEmpty
+	}
+	ownerSlot := nativeSlot(consensusOwnersSlot, key.Hash())
+	if owner := common.BytesToAddress(evm.StateDB.GetState(ConsensusKeysAddress, ownerSlot).Bytes()); owner != (common.Address{}) && owner != validator {
+		return errConsensusKeyUsed
+	}
+
+	keySlot := nativeSlot(consensusKeysSlot, validator.Hash())
+	if old := ConsensusKey(evm.StateDB, validator); old != (common.Address{}) {
+		evm.StateDB.SetState(ConsensusKeysAddress, nativeSlot(consensusOwnersSlot, old.Hash()), common.Hash{})
+	}
+	evm.StateDB.SetState(ConsensusKeysAddress, keySlot, key.Hash())
+	evm.StateDB.SetState(ConsensusKeysAddress, ownerSlot, validator.Hash())
+
+	checksum := crypto.Keccak256Hash(ConsensusKeysChecksum(evm.StateDB).Bytes(), validator.Bytes(), key.Bytes())
+	evm.StateDB.SetState(ConsensusKeysAddress, consensusChecksumSlot, checksum)
+
+	nativeLog(evm, ConsensusKeysAddress, consensusKeysABI.Events["ConsensusKeySet"], []common.Hash{validator.Hash(), key.Hash()})
+	return nil
+}
+
+// ConsensusKey returns the consensus key registered by the validator, or the
+// zero address if there isn't any.
+func ConsensusKey(db StateDB, validator common.Address) common.Address {
+	return common.BytesToAddress(db.GetState(ConsensusKeysAddress, nativeSlot(consensusKeysSlot, validator.Hash())).Bytes())
+}
+
+// ConsensusKeysChecksum returns a checksum changing whenever a validator
+// registers a key.
+func ConsensusKeysChecksum(db StateDB) common.Hash {
+	return db.GetState(ConsensusKeysAddress, consensusChecksumSlot)
+}
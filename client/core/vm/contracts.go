@@ -36,6 +36,15 @@ type PrecompiledContract interface {
 	RequiredGas(input []byte) uint64  // RequiredPrice calculates the contract gas use
 	Run(input []byte) ([]byte, error) // Run runs the precompiled contract
 }
+
+// NativeContract is the interface for native Go contracts with their own
+// storage. Unlike the precompiled contracts, they run within the scope of the
+// call so that they can read and modify the state.
+type NativeContract interface {
+	RequiredGas(input []byte) uint64                                // RequiredGas calculates the contract gas use
+	Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) // Run runs the native contract
+}
+
 // PrecompiledContractsAndromeda contains the default set of pre-compiled Kowala
 // contracts used in the Andromeda release.
 var PrecompiledContractsAndromeda = map[common.Address]PrecompiledContract{
@@ -49,6 +58,37 @@ var PrecompiledContractsAndromeda = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{8}): &bn256Pairing{},
 }
 
+// NativeContractsBridge contains the native contracts of the bridge to a
+// remote Kowala network, active from the bridge fork on.
+var NativeContractsBridge = map[common.Address]NativeContract{
+	BridgeLightClientAddress: &bridgeLightClient{},
+	BridgeTokenAddress:       &bridgeToken{},
+}
+
+// NativeContractsUptime contains the native contract of the validator uptime
+// tracking, active from the uptime fork on.
+var NativeContractsUptime = map[common.Address]NativeContract{
+	UptimeAddress: &validatorUptime{},
+}
+
+// NativeContractsAggregateCommit contains the native contract of the
+// aggregate signature keys, active from the aggregated commit fork on.
+var NativeContractsAggregateCommit = map[common.Address]NativeContract{
+	AggregateKeysAddress: &aggregateKeys{},
+}
+
+// NativeContractsConsensusKey contains the native contract of the validator
+// consensus keys, active from the consensus key fork on.
+var NativeContractsConsensusKey = map[common.Address]NativeContract{
+	ConsensusKeysAddress: &consensusKeys{},
+}
+
+// NativeContractsDelegatedStaking contains the native contract of the
+// delegations to the validators, active from the delegated staking fork on.
+var NativeContractsDelegatedStaking = map[common.Address]NativeContract{
+	DelegationAddress: &delegation{},
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	gas := p.RequiredGas(input)
@@ -58,6 +98,15 @@ func RunPrecompiledContract(p PrecompiledContract, input []byte, contract *Contr
 	return nil, ErrOutOfGas
 }
 
+// RunNativeContract runs and evaluates the output of a native contract.
+func RunNativeContract(evm *EVM, p NativeContract, input []byte, contract *Contract) (ret []byte, err error) {
+	gas := p.RequiredGas(input)
+	if contract.UseGas(gas) {
+		return p.Run(evm, contract, input)
+	}
+	return nil, ErrOutOfGas
+}
+
 // ECRECOVER implemented as a native contract.
 type ecrecover struct{}
 
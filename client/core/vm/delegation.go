@@ -0,0 +1,371 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// DelegationAddress is the address of the contract holding the tokens
+// delegated by the token holders to the validators.
+var DelegationAddress = common.BytesToAddress([]byte{14})
+
+// DelegationABI is the input ABI used to generate the binding from.
+const DelegationABI = `[{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getDelegated","outputs":[{"name":"amount","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getCommissionRate","outputs":[{"name":"rate","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getDelegatorCount","outputs":[{"name":"count","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"},{"name":"index","type":"uint256"}],"name":"getDelegatorAtIndex","outputs":[{"name":"delegator","type":"address"},{"name":"amount","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"delegator","type":"address"},{"name":"validator","type":"address"}],"name":"getDelegation","outputs":[{"name":"amount","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"delegator","type":"address"},{"name":"validator","type":"address"}],"name":"getUndelegationCount","outputs":[{"name":"count","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"delegator","type":"address"},{"name":"validator","type":"address"},{"name":"index","type":"uint256"}],"name":"getUndelegationAtIndex","outputs":[{"name":"amount","type":"uint256"},{"name":"availableAt","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"_from","type":"address"},{"name":"_value","type":"uint256"},{"name":"_validator","type":"address"}],"name":"delegate","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"validator","type":"address"},{"name":"amount","type":"uint256"}],"name":"undelegate","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"validator","type":"address"}],"name":"releaseDelegations","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"rate","type":"uint256"}],"name":"setCommissionRate","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"delegator","type":"address"},{"indexed":true,"name":"validator","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Delegated","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"delegator","type":"address"},{"indexed":true,"name":"validator","type":"address"},{"indexed":false,"name":"amount","type":"uint256"},{"indexed":false,"name":"availableAt","type":"uint256"}],"name":"Undelegated","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"delegator","type":"address"},{"indexed":true,"name":"validator","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"DelegationsReleased","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"validator","type":"address"},{"indexed":false,"name":"rate","type":"uint256"}],"name":"CommissionRateSet","type":"event"}]`
+
+var delegationABI = mustParseABI(DelegationABI)
+
+// delegationManagerABI and delegationTokenABI are the methods of the
+// validator manager and of the mining token called by the delegation contract.
+var (
+	delegationManagerABI = mustParseABI(`[{"constant":true,"inputs":[{"name":"code","type":"address"}],"name":"isValidator","outputs":[{"name":"isIndeed","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"freezePeriod","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"paused","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"}]`)
+	delegationTokenABI   = mustParseABI(`[{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transfer","outputs":[{"name":"success","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`)
+)
+
+// delegationCode is the code of the delegation contract account. The mining
+// token only calls the token fallback of the accounts holding code. The code
+// isn't executed: the native contract runs instead.
+var delegationCode = []byte{0xfe}
+
+// storage layout of the delegation contract
+var (
+	delegationManagerSlot = common.BigToHash(big.NewInt(0))
+	delegationTokenSlot   = common.BigToHash(big.NewInt(1))
+)
+
+const (
+	delegatedSlot          = iota + 2 // validator => total amount delegated
+	commissionRateSlot                // validator => commission rate
+	delegatorCountSlot                // validator => number of delegators
+	delegatorsSlot                    // validator, index => delegator
+	delegatorAmountSlot               // validator, delegator => amount delegated
+	delegatorIndexSlot                // validator, delegator => index of the delegator
+	undelegationHeadSlot              // delegator, validator => first pending undelegation
+	undelegationTailSlot              // delegator, validator => number of undelegations
+	undelegationAmountSlot            // delegator, validator, index => amount undelegated
+	undelegationTimeSlot              // delegator, validator, index => release time
+)
+
+const (
+	// maxCommissionRate is the commission rate of a validator keeping the
+	// whole reward of its delegators, in basis points.
+	maxCommissionRate = 10000
+
+	// maxDelegators bounds the number of delegators of a validator, which are
+	// rewarded on every block proposed by the validator.
+	maxDelegators = 64
+)
+
+var (
+	errDelegationMethod       = errors.New("delegation: unknown method")
+	errDelegationDelegated    = errors.New("delegation: delegated call")
+	errDelegationValue        = errors.New("delegation: method is not payable")
+	errDelegationNotInstalled = errors.New("delegation: contract not installed")
+	errDelegationToken        = errors.New("delegation: tokens not transferred by the mining token")
+	errDelegationPaused       = errors.New("delegation: validator manager paused")
+	errDelegationValidator    = errors.New("delegation: not a validator")
+	errDelegationSelf         = errors.New("delegation: validator delegating to itself")
+	errDelegationAmount       = errors.New("delegation: invalid amount")
+	errDelegationFull         = errors.New("delegation: too many delegators")
+	errDelegationIndex        = errors.New("delegation: index out of range")
+	errDelegationRate         = errors.New("delegation: commission rate above the maximum")
+)
+
+// delegation holds the tokens that the token holders stake through the
+// validators. The mining token transfers the delegated tokens with the
+// delegate token fallback. The undelegated tokens are locked for the freeze
+// period of the validator manager and then released to the delegators.
+type delegation struct{}
+
+func (c *delegation) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := delegationABI.MethodById(input[:4]); err == nil && !method.Const {
+			return params.DelegationUpdateGas
+		}
+	}
+	return params.DelegationQueryGas
+}
+
+func (c *delegation) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if contract.CodeAddr == nil || contract.Address() != *contract.CodeAddr {
+		return nil, errDelegationDelegated
+	}
+	if contract.Value().Sign() > 0 {
+		return nil, errDelegationValue
+	}
+	if len(input) < 4 {
+		return nil, errDelegationMethod
+	}
+	method, err := delegationABI.MethodById(input[:4])
+	if err != nil {
+		return nil, errDelegationMethod
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, errDelegationMethod
+	}
+
+	db := evm.StateDB
+	switch method.Name {
+	case "getDelegated":
+		return method.Outputs.Pack(delegationWord(db, nativeSlot(delegatedSlot, args[0].(common.Address).Hash())))
+	case "getCommissionRate":
+		return method.Outputs.Pack(delegationWord(db, nativeSlot(commissionRateSlot, args[0].(common.Address).Hash())))
+	case "getDelegatorCount":
+		return method.Outputs.Pack(delegationWord(db, nativeSlot(delegatorCountSlot, args[0].(common.Address).Hash())))
+	case "getDelegatorAtIndex":
+		validator, index := args[0].(common.Address), args[1].(*big.Int)
+		if index.Cmp(delegationWord(db, nativeSlot(delegatorCountSlot, validator.Hash()))) >= 0 {
+			return nil, errDelegationIndex
+		}
+		delegator := common.BytesToAddress(db.GetState(DelegationAddress, nativeSlot(delegatorsSlot, validator.Hash(), common.BigToHash(index))).Bytes())
+		return method.Outputs.Pack(delegator, delegationWord(db, nativeSlot(delegatorAmountSlot, validator.Hash(), delegator.Hash())))
+	case "getDelegation":
+		delegator, validator := args[0].(common.Address), args[1].(common.Address)
+		return method.Outputs.Pack(delegationWord(db, nativeSlot(delegatorAmountSlot, validator.Hash(), delegator.Hash())))
+	case "getUndelegationCount":
+		delegator, validator := args[0].(common.Address), args[1].(common.Address)
+		head := delegationWord(db, nativeSlot(undelegationHeadSlot, delegator.Hash(), validator.Hash()))
+		tail := delegationWord(db, nativeSlot(undelegationTailSlot, delegator.Hash(), validator.Hash()))
+		return method.Outputs.Pack(tail.Sub(tail, head))
+	case "getUndelegationAtIndex":
+		delegator, validator := args[0].(common.Address), args[1].(common.Address)
+		index := new(big.Int).Add(delegationWord(db, nativeSlot(undelegationHeadSlot, delegator.Hash(), validator.Hash())), args[2].(*big.Int))
+		if index.Cmp(delegationWord(db, nativeSlot(undelegationTailSlot, delegator.Hash(), validator.Hash()))) >= 0 {
+			return nil, errDelegationIndex
+		}
+		key := []common.Hash{delegator.Hash(), validator.Hash(), common.BigToHash(index)}
+		return method.Outputs.Pack(delegationWord(db, nativeSlot(undelegationAmountSlot, key...)), delegationWord(db, nativeSlot(undelegationTimeSlot, key...)))
+	case "delegate":
+		return nil, c.delegate(evm, contract, args[0].(common.Address), args[1].(*big.Int), args[2].(common.Address))
+	case "undelegate":
+		return nil, c.undelegate(evm, contract, args[0].(common.Address), args[1].(*big.Int))
+	case "releaseDelegations":
+		return nil, c.releaseDelegations(evm, contract, args[0].(common.Address))
+	case "setCommissionRate":
+		return nil, c.setCommissionRate(evm, contract, args[0].(*big.Int))
+	}
+	return nil, errDelegationMethod
+}
+
+// delegate registers the tokens transferred by the mining token on behalf of
+// the delegator.
+func (c *delegation) delegate(evm *EVM, contract *Contract, delegator common.Address, amount *big.Int, validator common.Address) error {
+	if err := c.checkWrite(evm, contract); err != nil {
+		return err
+	}
+	if contract.Caller() != delegationToken(evm.StateDB) {
+		return errDelegationToken
+	}
+	if amount.Sign() <= 0 {
+		return errDelegationAmount
+	}
+	if delegator == validator {
+		return errDelegationSelf
+	}
+	if err := c.checkValidator(evm, contract, validator); err != nil {
+		return err
+	}
+
+	db := evm.StateDB
+	amountSlot := nativeSlot(delegatorAmountSlot, validator.Hash(), delegator.Hash())
+	delegated := delegationWord(db, amountSlot)
+	if delegated.Sign() == 0 {
+		countSlot := nativeSlot(delegatorCountSlot, validator.Hash())
+		count := delegationWord(db, countSlot)
+		if count.Cmp(big.NewInt(maxDelegators)) >= 0 {
+			return errDelegationFull
+		}
+		db.SetState(DelegationAddress, nativeSlot(delegatorsSlot, validator.Hash(), common.BigToHash(count)), delegator.Hash())
+		db.SetState(DelegationAddress, nativeSlot(delegatorIndexSlot, validator.Hash(), delegator.Hash()), common.BigToHash(count))
+		db.SetState(DelegationAddress, countSlot, common.BigToHash(count.Add(count, common.Big1)))
+	}
+	db.SetState(DelegationAddress, amountSlot, common.BigToHash(delegated.Add(delegated, amount)))
+	addDelegationWord(db, nativeSlot(delegatedSlot, validator.Hash()), amount)
+
+	nativeLog(evm, DelegationAddress, delegationABI.Events["Delegated"], []common.Hash{delegator.Hash(), validator.Hash()}, amount)
+	return nil
+}
+
+// undelegate withdraws the given amount of tokens delegated by the caller to
+// the validator, locking them for the freeze period.
+func (c *delegation) undelegate(evm *EVM, contract *Contract, validator common.Address, amount *big.Int) error {
+	if err := c.checkWrite(evm, contract); err != nil {
+		return err
+	}
+	db, delegator := evm.StateDB, contract.Caller()
+	amountSlot := nativeSlot(delegatorAmountSlot, validator.Hash(), delegator.Hash())
+	delegated := delegationWord(db, amountSlot)
+	if amount.Sign() <= 0 || amount.Cmp(delegated) > 0 {
+		return errDelegationAmount
+	}
+	ret, err := nativeCall(evm, contract, delegationManager(db), delegationManagerABI, "freezePeriod")
+	if err != nil {
+		return err
+	}
+	availableAt := new(big.Int).Add(evm.Time, ret[0].(*big.Int))
+
+	db.SetState(DelegationAddress, amountSlot, common.BigToHash(delegated.Sub(delegated, amount)))
+	addDelegationWord(db, nativeSlot(delegatedSlot, validator.Hash()), new(big.Int).Neg(amount))
+	if delegated.Sign() == 0 {
+		removeDelegator(db, validator, delegator)
+	}
+
+	tailSlot := nativeSlot(undelegationTailSlot, delegator.Hash(), validator.Hash())
+	tail := delegationWord(db, tailSlot)
+	key := []common.Hash{delegator.Hash(), validator.Hash(), common.BigToHash(tail)}
+	db.SetState(DelegationAddress, nativeSlot(undelegationAmountSlot, key...), common.BigToHash(amount))
+	db.SetState(DelegationAddress, nativeSlot(undelegationTimeSlot, key...), common.BigToHash(availableAt))
+	db.SetState(DelegationAddress, tailSlot, common.BigToHash(tail.Add(tail, common.Big1)))
+
+	nativeLog(evm, DelegationAddress, delegationABI.Events["Undelegated"], []common.Hash{delegator.Hash(), validator.Hash()}, amount, availableAt)
+	return nil
+}
+
+// releaseDelegations transfers the tokens undelegated by the caller from the
+// validator that are past the freeze period back to the caller.
+func (c *delegation) releaseDelegations(evm *EVM, contract *Contract, validator common.Address) error {
+	if err := c.checkWrite(evm, contract); err != nil {
+		return err
+	}
+	db, delegator := evm.StateDB, contract.Caller()
+	headSlot := nativeSlot(undelegationHeadSlot, delegator.Hash(), validator.Hash())
+	head := delegationWord(db, headSlot)
+	tail := delegationWord(db, nativeSlot(undelegationTailSlot, delegator.Hash(), validator.Hash()))
+
+	refund := new(big.Int)
+	for ; head.Cmp(tail) < 0; head.Add(head, common.Big1) {
+		key := []common.Hash{delegator.Hash(), validator.Hash(), common.BigToHash(head)}
+		if delegationWord(db, nativeSlot(undelegationTimeSlot, key...)).Cmp(evm.Time) > 0 {
+			break
+		}
+		if !contract.UseGas(params.DelegationReleaseGas) {
+			return ErrOutOfGas
+		}
+		refund.Add(refund, delegationWord(db, nativeSlot(undelegationAmountSlot, key...)))
+		db.SetState(DelegationAddress, nativeSlot(undelegationAmountSlot, key...), common.Hash{})
+		db.SetState(DelegationAddress, nativeSlot(undelegationTimeSlot, key...), common.Hash{})
+	}
+	db.SetState(DelegationAddress, headSlot, common.BigToHash(head))
+	if refund.Sign() == 0 {
+		return nil
+	}
+
+	if _, err := nativeCall(evm, contract, delegationToken(db), delegationTokenABI, "transfer", delegator, refund); err != nil {
+		return err
+	}
+	nativeLog(evm, DelegationAddress, delegationABI.Events["DelegationsReleased"], []common.Hash{delegator.Hash(), validator.Hash()}, refund)
+	return nil
+}
+
+// setCommissionRate sets the share of the delegators rewards kept by the
+// calling validator.
+func (c *delegation) setCommissionRate(evm *EVM, contract *Contract, rate *big.Int) error {
+	if err := c.checkWrite(evm, contract); err != nil {
+		return err
+	}
+	if rate.Cmp(big.NewInt(maxCommissionRate)) > 0 {
+		return errDelegationRate
+	}
+	validator := contract.Caller()
+	if err := c.checkValidator(evm, contract, validator); err != nil {
+		return err
+	}
+	evm.StateDB.SetState(DelegationAddress, nativeSlot(commissionRateSlot, validator.Hash()), common.BigToHash(rate))
+
+	nativeLog(evm, DelegationAddress, delegationABI.Events["CommissionRateSet"], []common.Hash{validator.Hash()}, rate)
+	return nil
+}
+
+// checkWrite checks that the delegations can be modified: the contract must be
+// installed and the validator manager must not be paused.
+func (c *delegation) checkWrite(evm *EVM, contract *Contract) error {
+	if err := nativeWrite(evm, DelegationAddress); err != nil {
+		return err
+	}
+	if !DelegationInstalled(evm.StateDB) {
+		return errDelegationNotInstalled
+	}
+	ret, err := nativeCall(evm, contract, delegationManager(evm.StateDB), delegationManagerABI, "paused")
+	if err != nil {
+		return err
+	}
+	if ret[0].(bool) {
+		return errDelegationPaused
+	}
+	return nil
+}
+
+// checkValidator checks that the validator manager registers the validator.
+func (c *delegation) checkValidator(evm *EVM, contract *Contract, validator common.Address) error {
+	ret, err := nativeCall(evm, contract, delegationManager(evm.StateDB), delegationManagerABI, "isValidator", validator)
+	if err != nil {
+		return err
+	}
+	if !ret[0].(bool) {
+		return errDelegationValidator
+	}
+	return nil
+}
+
+// removeDelegator removes the delegator from the delegators of the validator,
+// moving the last delegator in its place.
+func removeDelegator(db StateDB, validator common.Address, delegator common.Address) {
+	countSlot := nativeSlot(delegatorCountSlot, validator.Hash())
+	last := delegationWord(db, countSlot)
+	last.Sub(last, common.Big1)
+
+	indexSlot := nativeSlot(delegatorIndexSlot, validator.Hash(), delegator.Hash())
+	index := db.GetState(DelegationAddress, indexSlot)
+	lastSlot := nativeSlot(delegatorsSlot, validator.Hash(), common.BigToHash(last))
+	moved := db.GetState(DelegationAddress, lastSlot)
+
+	db.SetState(DelegationAddress, nativeSlot(delegatorsSlot, validator.Hash(), index), moved)
+	db.SetState(DelegationAddress, nativeSlot(delegatorIndexSlot, validator.Hash(), moved), index)
+	db.SetState(DelegationAddress, lastSlot, common.Hash{})
+	db.SetState(DelegationAddress, indexSlot, common.Hash{})
+	db.SetState(DelegationAddress, countSlot, common.BigToHash(last))
+}
+
+func delegationWord(db StateDB, slot common.Hash) *big.Int {
+	return db.GetState(DelegationAddress, slot).Big()
+}
+
+func addDelegationWord(db StateDB, slot common.Hash, amount *big.Int) {
+	db.SetState(DelegationAddress, slot, common.BigToHash(new(big.Int).Add(delegationWord(db, slot), amount)))
+}
+
+func delegationManager(db StateDB) common.Address {
+	return common.BytesToAddress(db.GetState(DelegationAddress, delegationManagerSlot).Bytes())
+}
+
+func delegationToken(db StateDB) common.Address {
+	return common.BytesToAddress(db.GetState(DelegationAddress, delegationTokenSlot).Bytes())
+}
+
+// DelegationAccount returns the code and the storage of the delegation
+// contract of the validators of the given manager, delegating the given token.
+func DelegationAccount(manager common.Address, token common.Address) ([]byte, map[common.Hash]common.Hash) {
+	return common.CopyBytes(delegationCode), map[common.Hash]common.Hash{
+		delegationManagerSlot: manager.Hash(),
+		delegationTokenSlot:   token.Hash(),
+	}
+}
+
+// InstallDelegation installs the delegation contract of the validators of the
+// given manager, delegating the given token.
+func InstallDelegation(db StateDB, manager common.Address, token common.Address) {
+	code, storage := DelegationAccount(manager, token)
+	db.SetCode(DelegationAddress, code)
+	db.SetNonce(DelegationAddress, 1)
+	for key, value := range storage {
+		db.SetState(DelegationAddress, key, value)
+	}
+}
+
+// DelegationInstalled reports whether the delegation contract is installed.
+func DelegationInstalled(db StateDB) bool {
+	return db.GetCodeSize(DelegationAddress) > 0
+}
@@ -31,6 +31,9 @@ func run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
 		if p := precompiles[*contract.CodeAddr]; p != nil {
 			return RunPrecompiledContract(p, input, contract)
 		}
+		if p := evm.nativeContract(*contract.CodeAddr); p != nil {
+			return RunNativeContract(evm, p, input, contract)
+		}
 	}
 	return evm.interpreter.Run(contract, input)
 }
@@ -109,6 +112,35 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmCon
 	return evm
 }
 
+// nativeContract returns the native contract at the given address, if any
+// is active for the current epoch.
+func (evm *EVM) nativeContract(addr common.Address) NativeContract {
+	if evm.chainRules.IsBridge && evm.chainConfig.Bridge != nil {
+		if p := NativeContractsBridge[addr]; p != nil {
+			return p
+		}
+	}
+	if evm.chainRules.IsAggregateCommit {
+		if p := NativeContractsAggregateCommit[addr]; p != nil {
+			return p
+		}
+	}
+	if evm.chainRules.IsConsensusKey {
+		if p := NativeContractsConsensusKey[addr]; p != nil {
+			return p
+		}
+	}
+	if evm.chainRules.IsDelegatedStaking {
+		if p := NativeContractsDelegatedStaking[addr]; p != nil {
+			return p
+		}
+	}
+	if evm.chainRules.IsUptime {
+		return NativeContractsUptime[addr]
+	}
+	return nil
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
@@ -139,7 +171,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	)
 	if !evm.StateDB.Exist(addr) {
 		precompiles := PrecompiledContractsAndromeda
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if precompiles[addr] == nil && evm.nativeContract(addr) == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do antything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
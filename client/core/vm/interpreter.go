@@ -35,6 +35,10 @@ type Config struct {
 	NoRecursion bool
 	// Enable recording of SHA3/keccak preimages
 	EnablePreimageRecording bool
+	// Number of goroutines executing the transactions of a block
+	// speculatively. The transactions are executed sequentially
+	// if lower than two.
+	ParallelWorkers int
 	// JumpTable contains the EVM instruction table. This
 	// may be left uninitialised and will be set to the default
 	// table.
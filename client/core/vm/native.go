@@ -0,0 +1,74 @@
+package vm
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto"
+)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// nativeWrite checks that the state can be modified and makes sure that the
+// account of the contract isn't removed as an empty account.
+func nativeWrite(evm *EVM, addr common.Address) error {
+	if evm.interpreter.readOnly {
+		return errWriteProtection
+	}
+	if evm.StateDB.GetNonce(addr) == 0 {
+		evm.StateDB.SetNonce(addr, 1)
+	}
+	return nil
+}
+
+// nativeLog emits the given event of a native contract.
+func nativeLog(evm *EVM, addr common.Address, event abi.Event, topics []common.Hash, data ...interface{}) {
+	packed, _ := event.Inputs.NonIndexed().Pack(data...)
+	evm.StateDB.AddLog(&types.Log{
+		Address:     addr,
+		Topics:      append([]common.Hash{event.Id()}, topics...),
+		Data:        packed,
+		BlockNumber: evm.BlockNumber.Uint64(),
+	})
+}
+
+// nativeSlot returns the storage slot of the key in the mapping at the slot.
+func nativeSlot(slot uint64, keys ...common.Hash) common.Hash {
+	data := make([]byte, 0, (len(keys)+1)*common.HashLength)
+	for _, key := range keys {
+		data = append(data, key.Bytes()...)
+	}
+	data = append(data, common.BigToHash(new(big.Int).SetUint64(slot)).Bytes()...)
+	return crypto.Keccak256Hash(data)
+}
+
+// nativeCall calls a method of another contract on behalf of the native
+// contract, with the gas left to the native contract, and returns the
+// unpacked outputs of the method.
+func nativeCall(evm *EVM, contract *Contract, addr common.Address, definition abi.ABI, name string, args ...interface{}) ([]interface{}, error) {
+	input, err := definition.Pack(name, args...)
+	if err != nil {
+		return nil, err
+	}
+	method := definition.Methods[name]
+
+	var ret []byte
+	if method.Const {
+		ret, contract.Gas, err = evm.StaticCall(contract, addr, input, contract.Gas)
+	} else {
+		ret, contract.Gas, err = evm.Call(contract, addr, input, contract.Gas, new(big.Int))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.UnpackValues(ret)
+}
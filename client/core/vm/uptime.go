@@ -0,0 +1,247 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// UptimeAddress is the address of the contract keeping the signing statistics
+// of the validators and jailing the validators missing too many blocks.
+var UptimeAddress = common.BytesToAddress([]byte{11})
+
+// ValidatorUptimeABI is the input ABI used to generate the binding from.
+const ValidatorUptimeABI = `[{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"getUptime","outputs":[{"name":"blocks","type":"uint256"},{"name":"missed","type":"uint256"},{"name":"jailedUntil","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"validator","type":"address"}],"name":"isJailed","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"jailChecksum","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[],"name":"unjail","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"name":"validator","type":"address"}],"name":"Unjailed","type":"event"}]`
+
+var uptimeABI = mustParseABI(ValidatorUptimeABI)
+
+// storage layout of the uptime contract
+var uptimeChecksumSlot = common.BigToHash(big.NewInt(0))
+
+const (
+	uptimeBlocksSlot = iota + 1
+	uptimeMissedSlot
+	uptimeJailedSlot
+	uptimeBitmapSlot
+	uptimeVotersSlot
+)
+
+var (
+	errUptimeMethod    = errors.New("uptime: unknown method")
+	errUptimeDelegated = errors.New("uptime: delegated call")
+	errUptimeValue     = errors.New("uptime: method is not payable")
+	errNotJailed       = errors.New("uptime: validator is not jailed")
+	errStillJailed     = errors.New("uptime: jail duration has not elapsed")
+)
+
+// validatorUptime exposes the signing statistics kept by the consensus engine
+// and lets the jailed validators unjail themselves.
+type validatorUptime struct{}
+
+func (c *validatorUptime) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 {
+		if method, err := uptimeABI.MethodById(input[:4]); err == nil && method.Name == "unjail" {
+			return params.UptimeUnjailGas
+		}
+	}
+	return params.UptimeQueryGas
+}
+
+func (c *validatorUptime) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if contract.CodeAddr == nil || contract.Address() != *contract.CodeAddr {
+		return nil, errUptimeDelegated
+	}
+	if contract.Value().Sign() > 0 {
+		return nil, errUptimeValue
+	}
+	if len(input) < 4 {
+		return nil, errUptimeMethod
+	}
+	method, err := uptimeABI.MethodById(input[:4])
+	if err != nil {
+		return nil, errUptimeMethod
+	}
+	args, err := method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, errUptimeMethod
+	}
+
+	switch method.Name {
+	case "getUptime":
+		validator := args[0].(common.Address)
+		blocks := uptimeRecord(evm.StateDB, uptimeBlocksSlot, validator)
+		if window := evm.chainConfig.Konsensus.Window(); blocks > window {
+			blocks = window
+		}
+		missed := uptimeRecord(evm.StateDB, uptimeMissedSlot, validator)
+		jailedUntil := uptimeRecord(evm.StateDB, uptimeJailedSlot, validator)
+		return method.Outputs.Pack(new(big.Int).SetUint64(blocks), new(big.Int).SetUint64(missed), new(big.Int).SetUint64(jailedUntil))
+	case "isJailed":
+		return method.Outputs.Pack(IsJailed(evm.StateDB, args[0].(common.Address)))
+	case "jailChecksum":
+		return method.Outputs.Pack(JailChecksum(evm.StateDB))
+	case "unjail":
+		return nil, c.unjail(evm, contract.Caller())
+	}
+	return nil, errUptimeMethod
+}
+
+func (c *validatorUptime) unjail(evm *EVM, validator common.Address) error {
+	if err := nativeWrite(evm, UptimeAddress); err != nil {
+		return err
+	}
+	jailedUntil := uptimeRecord(evm.StateDB, uptimeJailedSlot, validator)
+	if jailedUntil == 0 {
+		return errNotJailed
+	}
+	if evm.BlockNumber.Uint64() < jailedUntil {
+		return errStillJailed
+	}
+
+	setUptimeRecord(evm.StateDB, uptimeJailedSlot, validator, 0)
+	updateJailChecksum(evm.StateDB, validator, 0)
+
+	nativeLog(evm, UptimeAddress, uptimeABI.Events["Unjailed"], []common.Hash{validator.Hash()})
+	return nil
+}
+
+// RecordUptime records whether each voter of a block signed its commit and
+// jails the voters that missed more blocks of the signing window than the
+// configured maximum. The last active voter is never jailed. It returns the
+// voters jailed by the block.
+func RecordUptime(db StateDB, config *params.KonsensusConfig, number *big.Int, voters []common.Address, signed []bool) []common.Address {
+	if db.GetNonce(UptimeAddress) == 0 {
+		db.SetNonce(UptimeAddress, 1)
+	}
+
+	var (
+		window    = config.Window()
+		maxMissed = config.MaxMissed()
+		active    = len(voters)
+		jailed    []common.Address
+	)
+	for i, voter := range voters {
+		blocks := uptimeRecord(db, uptimeBlocksSlot, voter)
+		missed := uptimeRecord(db, uptimeMissedSlot, voter)
+
+		// the bitmap keeps the missed blocks of the window, the oldest block
+		// of the window being replaced by the new one
+		index := blocks % window
+		slot := uptimeBitmapWord(voter, index/256)
+		bitmap := db.GetState(UptimeAddress, slot).Big()
+		wasMissed, isMissed := bitmap.Bit(int(index%256)) == 1, !signed[i]
+		if wasMissed != isMissed {
+			if isMissed {
+				bitmap.SetBit(bitmap, int(index%256), 1)
+				missed++
+			} else {
+				bitmap.SetBit(bitmap, int(index%256), 0)
+				missed--
+			}
+			db.SetState(UptimeAddress, slot, common.BigToHash(bitmap))
+		}
+		blocks++
+
+		if missed > maxMissed && active > 1 {
+			jailedUntil := number.Uint64() + config.Jail()
+			setUptimeRecord(db, uptimeJailedSlot, voter, jailedUntil)
+			updateJailChecksum(db, voter, jailedUntil)
+
+			// the window starts over once the validator is unjailed
+			for word := uint64(0); word <= (window-1)/256; word++ {
+				db.SetState(UptimeAddress, uptimeBitmapWord(voter, word), common.Hash{})
+			}
+			blocks, missed = 0, 0
+			active--
+			jailed = append(jailed, voter)
+		}
+
+		setUptimeRecord(db, uptimeBlocksSlot, voter, blocks)
+		setUptimeRecord(db, uptimeMissedSlot, voter, missed)
+	}
+	return jailed
+}
+
+// UptimeVoters returns the voters of the given block kept by SetUptimeVoters,
+// and the consensus keys they sign with. Only the voters of the last two
+// blocks are kept.
+func UptimeVoters(db StateDB, number *big.Int) (voters []common.Address, keys []common.Address) {
+	parity := uptimeVotersParity(number)
+	count := db.GetState(UptimeAddress, nativeSlot(uptimeVotersSlot, parity)).Big().Uint64()
+	for i := uint64(0); i < count; i++ {
+		voter := common.BytesToAddress(db.GetState(UptimeAddress, uptimeVoterEntry(parity, 2*i)).Bytes())
+		key := common.BytesToAddress(db.GetState(UptimeAddress, uptimeVoterEntry(parity, 2*i+1)).Bytes())
+		if key == (common.Address{}) {
+			key = voter
+		}
+		voters, keys = append(voters, voter), append(keys, key)
+	}
+	return voters, keys
+}
+
+// SetUptimeVoters keeps the voters of the given block, which sign its commit,
+// with the consensus keys they sign with. The commit is recorded by the next
+// block, whose state may be the first one held by the nodes after a fast sync
+// or a pruning, so the voters can't be loaded from an older state.
+func SetUptimeVoters(db StateDB, number *big.Int, voters []common.Address, keys []common.Address) {
+	if db.GetNonce(UptimeAddress) == 0 {
+		db.SetNonce(UptimeAddress, 1)
+	}
+
+	parity := uptimeVotersParity(number)
+	countSlot := nativeSlot(uptimeVotersSlot, parity)
+	count := db.GetState(UptimeAddress, countSlot).Big().Uint64()
+	for i, voter := range voters {
+		// the key is only kept once rotated
+		var key common.Hash
+		if keys[i] != voter {
+			key = keys[i].Hash()
+		}
+		db.SetState(UptimeAddress, uptimeVoterEntry(parity, 2*uint64(i)), voter.Hash())
+		db.SetState(UptimeAddress, uptimeVoterEntry(parity, 2*uint64(i)+1), key)
+	}
+	for i := uint64(len(voters)); i < count; i++ {
+		db.SetState(UptimeAddress, uptimeVoterEntry(parity, 2*i), common.Hash{})
+		db.SetState(UptimeAddress, uptimeVoterEntry(parity, 2*i+1), common.Hash{})
+	}
+	db.SetState(UptimeAddress, countSlot, common.BigToHash(big.NewInt(int64(len(voters)))))
+}
+
+// IsJailed returns whether the validator is jailed.
+func IsJailed(db StateDB, validator common.Address) bool {
+	return uptimeRecord(db, uptimeJailedSlot, validator) != 0
+}
+
+// JailChecksum returns a checksum changing whenever a validator is jailed or
+// unjailed.
+func JailChecksum(db StateDB) common.Hash {
+	return db.GetState(UptimeAddress, uptimeChecksumSlot)
+}
+
+func updateJailChecksum(db StateDB, validator common.Address, jailedUntil uint64) {
+	checksum := crypto.Keccak256Hash(JailChecksum(db).Bytes(), validator.Bytes(), new(big.Int).SetUint64(jailedUntil).Bytes())
+	db.SetState(UptimeAddress, uptimeChecksumSlot, checksum)
+}
+
+func uptimeRecord(db StateDB, slot uint64, validator common.Address) uint64 {
+	return db.GetState(UptimeAddress, nativeSlot(slot, validator.Hash())).Big().Uint64()
+}
+
+func setUptimeRecord(db StateDB, slot uint64, validator common.Address, value uint64) {
+	db.SetState(UptimeAddress, nativeSlot(slot, validator.Hash()), common.BigToHash(new(big.Int).SetUint64(value)))
+}
+
+func uptimeBitmapWord(validator common.Address, word uint64) common.Hash {
+	return nativeSlot(uptimeBitmapSlot, validator.Hash(), common.BigToHash(new(big.Int).SetUint64(word)))
+}
+
+func uptimeVotersParity(number *big.Int) common.Hash {
+	return common.BigToHash(big.NewInt(int64(number.Bit(0))))
+}
+
+func uptimeVoterEntry(parity common.Hash, index uint64) common.Hash {
+	return nativeSlot(uptimeVotersSlot, parity, common.BigToHash(new(big.Int).SetUint64(index)))
+}
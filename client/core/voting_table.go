@@ -9,9 +9,20 @@ import (
 	"github.com/kowala-tech/kcoin/client/log"
 )
 
+// NotVoterError is returned when a vote was signed by an address which isn't
+// the consensus key of any voter of the voting table.
+type NotVoterError struct {
+	Address common.Address
+}
+
+func (err *NotVoterError) Error() string {
+	return fmt.Sprintf("voter address not found in voting table: 0x%x", err.Address.Hash())
+}
+
 type VotingTable interface {
 	Add(vote types.AddressVote) error
 	Leader() common.Hash
+	Votes(blockHash common.Hash) types.Votes
 }
 
 type votingTable struct {
@@ -38,7 +49,7 @@ func NewVotingTable(voteType types.VoteType, voters types.Voters, majority Quoru
 
 func (table *votingTable) Add(voteAddressed types.AddressVote) error {
 	if !table.isVoter(voteAddressed.Address()) {
-		return fmt.Errorf("voter address not found in voting table: 0x%x", voteAddressed.Address().Hash())
+		return &NotVoterError{Address: voteAddressed.Address()}
 	}
 	if err := table.isDuplicate(voteAddressed); err != nil {
 		return err
@@ -59,6 +70,11 @@ func (table *votingTable) Leader() common.Hash {
 	return table.votes.Leader()
 }
 
+// Votes returns the votes of the table for the given block.
+func (table *votingTable) Votes(blockHash common.Hash) types.Votes {
+	return table.votes.ForBlock(blockHash)
+}
+
 func (table *votingTable) isDuplicate(voteAddressed types.AddressVote) error {
 	vote := voteAddressed.Vote()
 	err := table.votes.Contains(vote.Hash())
@@ -69,8 +85,10 @@ func (table *votingTable) isDuplicate(voteAddressed types.AddressVote) error {
 	return err
 }
 
+// isVoter reports whether the address is the consensus key of a voter, which
+// signs the votes on behalf of the validator.
 func (table *votingTable) isVoter(address common.Address) bool {
-	return table.voters.Contains(address)
+	return table.voters.GetByConsensusKey(address) != nil
 }
 
 func (table *votingTable) hasQuorum() bool {
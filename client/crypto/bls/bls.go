@@ -0,0 +1,233 @@
+// Package bls implements BLS signatures over the BN256 curve, allowing a set of
+// signatures on the same message to be aggregated into a single signature that
+// can be checked against the aggregate of the signers' public keys.
+//
+// Signatures live in G1 (64 bytes) and public keys in G2 (128 bytes). Since
+// public key aggregation is vulnerable to rogue key attacks, every key must be
+// registered together with a proof of possession (see ProvePossession).
+package bls
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/crypto/bn256"
+	"github.com/kowala-tech/kcoin/client/crypto/sha3"
+)
+
+const (
+	// SignatureLength is the length of a marshalled signature.
+	SignatureLength = 64
+	// PublicKeyLength is the length of a marshalled public key.
+	PublicKeyLength = 128
+	// PrivateKeyLength is the length of a marshalled private key.
+	PrivateKeyLength = 32
+)
+
+var (
+	// order is the number of elements in both G1 and G2.
+	order, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	// fieldPrime is the prime over which the curve is defined.
+	fieldPrime, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
+	curveB = big.NewInt(3)
+
+	// domains separate the hashes of signed messages and possession proofs.
+	messageDomain    = []byte("kcoin-bls-message")
+	possessionDomain = []byte("kcoin-bls-possession")
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New("invalid bls private key")
+	ErrInvalidPublicKey  = errors.New("invalid bls public key")
+	ErrInvalidSignature  = errors.New("invalid bls signature")
+	ErrNoSignatures      = errors.New("no signatures to aggregate")
+	ErrNoPublicKeys      = errors.New("no public keys to aggregate")
+)
+
+// PrivateKey is a BLS secret scalar.
+type PrivateKey struct {
+	k *big.Int
+}
+
+// PublicKey is a BLS public key, a point in G2.
+type PublicKey struct {
+	p *bn256.G2
+}
+
+// Signature is a BLS signature, a point in G1.
+type Signature struct {
+	p *bn256.G1
+}
+
+// GenerateKey creates a new private key using the given source of randomness.
+// If rand is nil, crypto/rand.Reader is used.
+func GenerateKey(random io.Reader) (*PrivateKey, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	for {
+		k, err := rand.Int(random, order)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return &PrivateKey{k: k}, nil
+		}
+	}
+}
+
+// ToPrivateKey creates a private key from its 32 byte big endian representation.
+func ToPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeyLength {
+		return nil, ErrInvalidPrivateKey
+	}
+	k := new(big.Int).SetBytes(b)
+	if k.Sign() == 0 || k.Cmp(order) >= 0 {
+		return nil, ErrInvalidPrivateKey
+	}
+	return &PrivateKey{k: k}, nil
+}
+
+// Marshal returns the 32 byte big endian representation of the private key.
+func (priv *PrivateKey) Marshal() []byte {
+	b := make([]byte, PrivateKeyLength)
+	kb := priv.k.Bytes()
+	copy(b[PrivateKeyLength-len(kb):], kb)
+	return b
+}
+
+// PublicKey returns the public key of the private key.
+func (priv *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{p: new(bn256.G2).ScalarBaseMult(priv.k)}
+}
+
+// Sign signs the given message.
+func (priv *PrivateKey) Sign(msg []byte) *Signature {
+	return &Signature{p: new(bn256.G1).ScalarMult(hashToG1(messageDomain, msg), priv.k)}
+}
+
+// ProvePossession signs the private key's own public key, proving to others
+// that the holder of the public key knows the matching private key.
+func (priv *PrivateKey) ProvePossession() *Signature {
+	pub := priv.PublicKey().Marshal()
+	return &Signature{p: new(bn256.G1).ScalarMult(hashToG1(possessionDomain, pub), priv.k)}
+}
+
+// UnmarshalPublicKey decodes a 128 byte public key.
+func UnmarshalPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeyLength {
+		return nil, ErrInvalidPublicKey
+	}
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{p: p}, nil
+}
+
+// Marshal returns the 128 byte representation of the public key.
+func (pub *PublicKey) Marshal() []byte {
+	return pub.p.Marshal()
+}
+
+// Verify checks that sig is a signature of msg by the given public key.
+func (pub *PublicKey) Verify(msg []byte, sig *Signature) bool {
+	return verify(pub, hashToG1(messageDomain, msg), sig)
+}
+
+// VerifyPossession checks a proof of possession created by ProvePossession.
+func (pub *PublicKey) VerifyPossession(proof *Signature) bool {
+	return verify(pub, hashToG1(possessionDomain, pub.Marshal()), proof)
+}
+
+// UnmarshalSignature decodes a 64 byte signature.
+func UnmarshalSignature(b []byte) (*Signature, error) {
+	if len(b) != SignatureLength {
+		return nil, ErrInvalidSignature
+	}
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(b); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &Signature{p: p}, nil
+}
+
+// Marshal returns the 64 byte representation of the signature.
+func (sig *Signature) Marshal() []byte {
+	return sig.p.Marshal()
+}
+
+// AggregateSignatures combines signatures of the same message into one.
+func AggregateSignatures(sigs []*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, ErrNoSignatures
+	}
+	agg := new(bn256.G1).ScalarMult(sigs[0].p, big.NewInt(1))
+	for _, sig := range sigs[1:] {
+		agg = new(bn256.G1).Add(agg, sig.p)
+	}
+	return &Signature{p: agg}, nil
+}
+
+// AggregatePublicKeys combines public keys into one that verifies the aggregate
+// of their signatures.
+func AggregatePublicKeys(pubs []*PublicKey) (*PublicKey, error) {
+	if len(pubs) == 0 {
+		return nil, ErrNoPublicKeys
+	}
+	agg := new(bn256.G2).ScalarMult(pubs[0].p, big.NewInt(1))
+	for _, pub := range pubs[1:] {
+		agg = new(bn256.G2).Add(agg, pub.p)
+	}
+	return &PublicKey{p: agg}, nil
+}
+
+// verify checks e(sig, g2) == e(h, pub).
+func verify(pub *PublicKey, h *bn256.G1, sig *Signature) bool {
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	return bn256.PairingCheck(
+		[]*bn256.G1{sig.p, new(bn256.G1).Neg(h)},
+		[]*bn256.G2{g2, pub.p},
+	)
+}
+
+// hashToG1 maps a message onto a point in G1 using try-and-increment: the
+// x coordinate is derived from the hash of the message and a counter until
+// x³+3 is a quadratic residue.
+func hashToG1(domain, msg []byte) *bn256.G1 {
+	var (
+		x, y = new(big.Int), new(big.Int)
+		buf  = make([]byte, 64)
+	)
+	for counter := byte(0); ; counter++ {
+		hw := sha3.NewKeccak256()
+		hw.Write(domain)
+		hw.Write([]byte{counter})
+		hw.Write(msg)
+		x.SetBytes(hw.Sum(nil))
+		x.Mod(x, fieldPrime)
+
+		// y² = x³ + 3
+		rhs := new(big.Int).Mul(x, x)
+		rhs.Mul(rhs, x)
+		rhs.Add(rhs, curveB)
+		rhs.Mod(rhs, fieldPrime)
+		if y.ModSqrt(rhs, fieldPrime) == nil {
+			continue
+		}
+		for i := range buf {
+			buf[i] = 0
+		}
+		xb, yb := x.Bytes(), y.Bytes()
+		copy(buf[32-len(xb):32], xb)
+		copy(buf[64-len(yb):], yb)
+
+		p := new(bn256.G1)
+		if _, err := p.Unmarshal(buf); err == nil {
+			return p
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package bls
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	priv, err := GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := priv.PublicKey()
+	msg := []byte("commit")
+
+	sig := priv.Sign(msg)
+	if !pub.Verify(msg, sig) {
+		t.Fatal("valid signature rejected")
+	}
+	if pub.Verify([]byte("other"), sig) {
+		t.Fatal("signature of another message accepted")
+	}
+
+	other, _ := GenerateKey(nil)
+	if other.PublicKey().Verify(msg, sig) {
+		t.Fatal("signature verified with the wrong key")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	msg := []byte("commit")
+
+	var (
+		pubs []*PublicKey
+		sigs []*Signature
+	)
+	for i := 0; i < 4; i++ {
+		priv, err := GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs = append(pubs, priv.PublicKey())
+		sigs = append(sigs, priv.Sign(msg))
+	}
+
+	aggSig, err := AggregateSignatures(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggPub, err := AggregatePublicKeys(pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !aggPub.Verify(msg, aggSig) {
+		t.Fatal("aggregate signature rejected")
+	}
+
+	partial, _ := AggregatePublicKeys(pubs[1:])
+	if partial.Verify(msg, aggSig) {
+		t.Fatal("aggregate signature accepted with a missing signer")
+	}
+
+	if _, err := AggregateSignatures(nil); err != ErrNoSignatures {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrNoSignatures)
+	}
+}
+
+func TestPossession(t *testing.T) {
+	priv, _ := GenerateKey(nil)
+	pub := priv.PublicKey()
+
+	if !pub.VerifyPossession(priv.ProvePossession()) {
+		t.Fatal("valid proof of possession rejected")
+	}
+	// a regular signature over the public key must not pass as a proof
+	if pub.VerifyPossession(priv.Sign(pub.Marshal())) {
+		t.Fatal("message signature accepted as proof of possession")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	priv, _ := GenerateKey(nil)
+
+	privDec, err := ToPrivateKey(priv.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDec, err := UnmarshalPublicKey(priv.PublicKey().Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pubDec.Marshal(), privDec.PublicKey().Marshal()) {
+		t.Fatal("public key mismatch after decoding")
+	}
+
+	msg := []byte("commit")
+	sigDec, err := UnmarshalSignature(priv.Sign(msg).Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pubDec.Verify(msg, sigDec) {
+		t.Fatal("decoded signature rejected")
+	}
+
+	if _, err := UnmarshalSignature(make([]byte, SignatureLength-1)); err != ErrInvalidSignature {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := ToPrivateKey(make([]byte, PrivateKeyLength)); err != ErrInvalidPrivateKey {
+		t.Fatalf("error mismatch: have %v, want %v", err, ErrInvalidPrivateKey)
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	priv, _ := GenerateKey(nil)
+	msg := []byte("commit")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		priv.Sign(msg)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	priv, _ := GenerateKey(nil)
+	msg := []byte("commit")
+	pub, sig := priv.PublicKey(), priv.Sign(msg)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pub.Verify(msg, sig)
+	}
+}
@@ -0,0 +1,464 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/bloombits"
+	"github.com/kowala-tech/kcoin/client/core/rawdb"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/kowala-tech/kcoin/client/rpc"
+)
+
+var (
+	// counterCode increments the first storage slot and logs the new value.
+	counterCode = common.FromHex("6000546001018060005560005260206000a000")
+
+	// counterInitCode deploys counterCode.
+	counterInitCode = append(common.FromHex("6013600c60003960136000f3"), counterCode...)
+
+	counterAddr   = common.HexToAddress("0x1c")
+	recipientAddr = common.HexToAddress("0x1d")
+	proposerAddr  = common.HexToAddress("0xc0")
+)
+
+// testBackend serves the resolvers from a generated chain. The methods that
+// aren't used by the resolvers panic through the nil embedded backend.
+type testBackend struct {
+	Backend
+	db     kcoindb.Database
+	chain  *core.BlockChain
+	voters types.Voters
+}
+
+// newTestBackend generates a chain of two blocks: the first one transfers
+// value and calls a logging contract, the second one creates a contract.
+func newTestBackend(t *testing.T) (*testBackend, []*types.Block) {
+	var (
+		db      = kcoindb.NewMemDatabase()
+		key, _  = crypto.GenerateKey()
+		sender  = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.MakeSigner(params.TestChainConfig, nil)
+		genesis = &core.Genesis{
+			Config:   params.TestChainConfig,
+			GasLimit: 10000000,
+			Alloc: core.GenesisAlloc{
+				sender:      {Balance: big.NewInt(1000000000000)},
+				counterAddr: {Code: counterCode, Balance: new(big.Int)},
+			},
+		}
+	)
+	parent := genesis.MustCommit(db)
+
+	blocks, _ := core.GenerateChain(params.TestChainConfig, parent, konsensus.NewFaker(), db, 2, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(proposerAddr)
+
+		var txs []*types.Transaction
+		switch i {
+		case 0:
+			txs = append(txs,
+				types.NewTransaction(b.TxNonce(sender), recipientAddr, big.NewInt(1000), params.TxGas, big.NewInt(1), nil),
+				types.NewTransaction(b.TxNonce(sender)+1, counterAddr, new(big.Int), 100000, big.NewInt(1), nil),
+			)
+		case 1:
+			txs = append(txs, types.NewContractCreation(b.TxNonce(sender), new(big.Int), 100000, big.NewInt(1), counterInitCode))
+		}
+		for _, tx := range txs {
+			signed, err := types.SignTx(tx, signer, key)
+			if err != nil {
+				t.Fatalf("failed to sign the transaction: %v", err)
+			}
+			b.AddTx(signed)
+		}
+	})
+
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create the chain: %v", err)
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	voters, err := types.NewVoters([]*types.Voter{
+		types.NewVoterWithConsensusKey(proposerAddr, recipientAddr, big.NewInt(100), big.NewInt(1)),
+	})
+	if err != nil {
+		t.Fatalf("failed to create the voters: %v", err)
+	}
+	return &testBackend{db: db, chain: chain, voters: voters}, blocks
+}
+
+func (b *testBackend) ChainDb() kcoindb.Database                         { return b.db }
+func (b *testBackend) ChainConfig() *params.ChainConfig                  { return params.TestChainConfig }
+func (b *testBackend) CurrentBlock() *types.Block                        { return b.chain.CurrentBlock() }
+func (b *testBackend) ProtocolVersion() int                              { return 1 }
+func (b *testBackend) Validators() (types.Voters, error)                 { return b.voters, nil }
+func (b *testBackend) BloomStatus() (uint64, uint64)                     { return params.BloomBitsBlocks, 0 }
+func (b *testBackend) GetPoolTransaction(common.Hash) *types.Transaction { return nil }
+
+func (b *testBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		return b.chain.CurrentHeader(), nil
+	}
+	return b.chain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		return b.chain.CurrentBlock(), nil
+	}
+	return b.chain.GetBlockByNumber(uint64(number)), nil
+}
+
+func (b *testBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	header, _ := b.HeaderByNumber(ctx, number)
+	if header == nil {
+		return nil, nil, nil
+	}
+	statedb, err := b.chain.StateAt(header.Root)
+	return statedb, header, err
+}
+
+func (b *testBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return b.chain.GetBlockByHash(hash), nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	if number := rawdb.ReadHeaderNumber(b.db, hash); number != nil {
+		return rawdb.ReadReceipts(b.db, hash, *number), nil
+	}
+	return nil, nil
+}
+
+func (b *testBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
+	receipts, _ := b.GetReceipts(ctx, hash)
+	logs := make([][]*types.Log, len(receipts))
+	for i, receipt := range receipts {
+		logs[i] = receipt.Logs
+	}
+	return logs, nil
+}
+
+func testQuery(t *testing.T, method string, query string) (int, map[string]interface{}) {
+	return testBackendQuery(t, nil, method, query)
+}
+
+func testBackendQuery(t *testing.T, backend Backend, method string, query string) (int, map[string]interface{}) {
+	handler, err := NewHandler(backend)
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	body, _ := json.Marshal(map[string]interface{}{"query": query})
+	request := httptest.NewRequest(method, "http://localhost:11225", strings.NewReader(string(body)))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		return recorder.Code, nil
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return recorder.Code, response
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	if code, _ := testQuery(t, http.MethodGet, "{ protocolVersion }"); code != http.StatusMethodNotAllowed {
+		t.Errorf("status code mismatch: have %d, want %d", code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHandlerRequestTooLarge(t *testing.T) {
+	query := "{ protocolVersion }" + strings.Repeat(" ", maxRequestSize)
+	if code, _ := testQuery(t, http.MethodPost, query); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status code mismatch: have %d, want %d", code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestBlocksRange(t *testing.T) {
+	_, response := testQuery(t, http.MethodPost, "{ blocks(from: 1, to: 3) { number } }")
+	if response["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", response["errors"])
+	}
+	blocks := response["data"].(map[string]interface{})["blocks"].([]interface{})
+	if len(blocks) != 3 {
+		t.Fatalf("blocks count mismatch: have %d, want 3", len(blocks))
+	}
+	for i, block := range blocks {
+		// Long values are serialised as JSON numbers
+		if number := block.(map[string]interface{})["number"].(float64); number != float64(i+1) {
+			t.Errorf("block %d: number mismatch: have %v, want %d", i, number, i+1)
+		}
+	}
+}
+
+func TestBlocksRangeLimit(t *testing.T) {
+	_, response := testQuery(t, http.MethodPost, "{ blocks(from: 0, to: 1024) { number } }")
+	errors, ok := response["errors"].([]interface{})
+	if !ok || len(errors) == 0 {
+		t.Fatal("expected block range error")
+	}
+	if msg := errors[0].(map[string]interface{})["message"]; msg != errBlockRange.Error() {
+		t.Errorf("error mismatch: have %v, want %v", msg, errBlockRange)
+	}
+}
+
+func TestQueryDepthLimit(t *testing.T) {
+	query := "{ block { " + strings.Repeat("parent { ", maxQueryDepth) + "number" + strings.Repeat(" }", maxQueryDepth) + " } }"
+	_, response := testQuery(t, http.MethodPost, query)
+	if errors, ok := response["errors"].([]interface{}); !ok || len(errors) == 0 {
+		t.Fatal("expected query depth error")
+	}
+	if response["data"] != nil {
+		t.Errorf("unexpected data: %v", response["data"])
+	}
+}
+
+// queryData runs a query against the backend and returns its data, failing
+// the test on any error.
+func queryData(t *testing.T, backend Backend, query string) map[string]interface{} {
+	_, response := testBackendQuery(t, backend, http.MethodPost, query)
+	if response["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", response["errors"])
+	}
+	return response["data"].(map[string]interface{})
+}
+
+// queryError runs a query against the backend and returns its first error
+// message.
+func queryError(t *testing.T, backend Backend, query string) interface{} {
+	_, response := testBackendQuery(t, backend, http.MethodPost, query)
+	errors, ok := response["errors"].([]interface{})
+	if !ok || len(errors) == 0 {
+		t.Fatalf("expected an error, have data %v", response["data"])
+	}
+	return errors[0].(map[string]interface{})["message"]
+}
+
+func TestBlockTransactions(t *testing.T) {
+	backend, blocks := newTestBackend(t)
+
+	signer := types.MakeSigner(params.TestChainConfig, nil)
+	sender, _ := types.TxSender(signer, blocks[0].Transactions()[0])
+
+	data := queryData(t, backend, `{ block(number: 1) {
+		hash
+		proposer { address }
+		transactionCount
+		transactions { hash index from { address } to { address } value status gasUsed cumulativeGasUsed logs { index data account { address } } }
+	} }`)
+	block := data["block"].(map[string]interface{})
+	if hash := block["hash"]; hash != blocks[0].Hash().Hex() {
+		t.Errorf("hash mismatch: have %v, want %s", hash, blocks[0].Hash().Hex())
+	}
+	if proposer := block["proposer"].(map[string]interface{})["address"]; proposer != strings.ToLower(proposerAddr.Hex()) {
+		t.Errorf("proposer mismatch: have %v, want %s", proposer, strings.ToLower(proposerAddr.Hex()))
+	}
+	if count := block["transactionCount"]; count != float64(2) {
+		t.Errorf("transaction count mismatch: have %v, want 2", count)
+	}
+
+	txs := block["transactions"].([]interface{})
+	if len(txs) != 2 {
+		t.Fatalf("transactions count mismatch: have %d, want 2", len(txs))
+	}
+	var cumulative float64
+	for i, item := range txs {
+		tx := item.(map[string]interface{})
+		if hash := tx["hash"]; hash != blocks[0].Transactions()[i].Hash().Hex() {
+			t.Errorf("tx %d: hash mismatch: have %v, want %s", i, hash, blocks[0].Transactions()[i].Hash().Hex())
+		}
+		if index := tx["index"]; index != float64(i) {
+			t.Errorf("tx %d: index mismatch: have %v, want %d", i, index, i)
+		}
+		if from := tx["from"].(map[string]interface{})["address"]; from != strings.ToLower(sender.Hex()) {
+			t.Errorf("tx %d: sender mismatch: have %v, want %s", i, from, strings.ToLower(sender.Hex()))
+		}
+		if status := tx["status"]; status != float64(types.ReceiptStatusSuccessful) {
+			t.Errorf("tx %d: status mismatch: have %v, want %d", i, status, types.ReceiptStatusSuccessful)
+		}
+		cumulative += tx["gasUsed"].(float64)
+		if used := tx["cumulativeGasUsed"]; used != cumulative {
+			t.Errorf("tx %d: cumulative gas mismatch: have %v, want %v", i, used, cumulative)
+		}
+	}
+
+	transfer := txs[0].(map[string]interface{})
+	if to := transfer["to"].(map[string]interface{})["address"]; to != strings.ToLower(recipientAddr.Hex()) {
+		t.Errorf("recipient mismatch: have %v, want %s", to, strings.ToLower(recipientAddr.Hex()))
+	}
+	if value := transfer["value"]; value != "0x3e8" {
+		t.Errorf("value mismatch: have %v, want 0x3e8", value)
+	}
+	if used := transfer["gasUsed"]; used != float64(params.TxGas) {
+		t.Errorf("gas used mismatch: have %v, want %d", used, params.TxGas)
+	}
+	if logs := transfer["logs"].([]interface{}); len(logs) != 0 {
+		t.Errorf("transfer logs count mismatch: have %d, want 0", len(logs))
+	}
+
+	logs := txs[1].(map[string]interface{})["logs"].([]interface{})
+	if len(logs) != 1 {
+		t.Fatalf("call logs count mismatch: have %d, want 1", len(logs))
+	}
+	log := logs[0].(map[string]interface{})
+	if account := log["account"].(map[string]interface{})["address"]; account != strings.ToLower(counterAddr.Hex()) {
+		t.Errorf("log account mismatch: have %v, want %s", account, strings.ToLower(counterAddr.Hex()))
+	}
+	if data := log["data"]; data != common.ToHex(common.LeftPadBytes([]byte{1}, 32)) {
+		t.Errorf("log data mismatch: have %v, want the counter value 1", data)
+	}
+}
+
+func TestTransactionReceipt(t *testing.T) {
+	backend, blocks := newTestBackend(t)
+
+	var (
+		tx        = blocks[1].Transactions()[0]
+		signer    = types.MakeSigner(params.TestChainConfig, nil)
+		sender, _ = types.TxSender(signer, tx)
+		contract  = crypto.CreateAddress(sender, tx.Nonce())
+	)
+	data := queryData(t, backend, fmt.Sprintf(`{ transaction(hash: "%s") {
+		index to { address } status block { number } createdContract { address code }
+	} }`, tx.Hash().Hex()))
+
+	receipt := data["transaction"].(map[string]interface{})
+	if receipt["to"] != nil {
+		t.Errorf("unexpected recipient: %v", receipt["to"])
+	}
+	if status := receipt["status"]; status != float64(types.ReceiptStatusSuccessful) {
+		t.Errorf("status mismatch: have %v, want %d", status, types.ReceiptStatusSuccessful)
+	}
+	if number := receipt["block"].(map[string]interface{})["number"]; number != float64(2) {
+		t.Errorf("block number mismatch: have %v, want 2", number)
+	}
+	created := receipt["createdContract"].(map[string]interface{})
+	if address := created["address"]; address != strings.ToLower(contract.Hex()) {
+		t.Errorf("contract address mismatch: have %v, want %s", address, strings.ToLower(contract.Hex()))
+	}
+	if code := created["code"]; code != common.ToHex(counterCode) {
+		t.Errorf("contract code mismatch: have %v, want %s", code, common.ToHex(counterCode))
+	}
+
+	data = queryData(t, backend, fmt.Sprintf(`{ transaction(hash: "%s") { hash } }`, common.Hash{1}.Hex()))
+	if data["transaction"] != nil {
+		t.Errorf("unexpected unknown transaction: %v", data["transaction"])
+	}
+}
+
+func TestLogs(t *testing.T) {
+	backend, blocks := newTestBackend(t)
+	call := blocks[0].Transactions()[1]
+
+	data := queryData(t, backend, fmt.Sprintf(`{ logs(filter: { fromBlock: 0, toBlock: 2, addresses: ["%s"] }) {
+		index data transaction { hash }
+	} }`, counterAddr.Hex()))
+	logs := data["logs"].([]interface{})
+	if len(logs) != 1 {
+		t.Fatalf("logs count mismatch: have %d, want 1", len(logs))
+	}
+	log := logs[0].(map[string]interface{})
+	if hash := log["transaction"].(map[string]interface{})["hash"]; hash != call.Hash().Hex() {
+		t.Errorf("log transaction mismatch: have %v, want %s", hash, call.Hash().Hex())
+	}
+
+	data = queryData(t, backend, `{ block(number: 1) { logs(filter: {}) { index } } }`)
+	if logs := data["block"].(map[string]interface{})["logs"].([]interface{}); len(logs) != 1 {
+		t.Errorf("block logs count mismatch: have %d, want 1", len(logs))
+	}
+	data = queryData(t, backend, `{ block(number: 2) { logs(filter: {}) { index } } }`)
+	if logs := data["block"].(map[string]interface{})["logs"].([]interface{}); len(logs) != 0 {
+		t.Errorf("block logs count mismatch: have %d, want 0", len(logs))
+	}
+}
+
+func TestLogsRangeLimit(t *testing.T) {
+	backend, _ := newTestBackend(t)
+
+	if msg := queryError(t, backend, "{ logs(filter: { fromBlock: 0, toBlock: 1024 }) { index } }"); msg != errBlockRange.Error() {
+		t.Errorf("error mismatch: have %v, want %v", msg, errBlockRange)
+	}
+	data := queryData(t, backend, "{ logs(filter: { fromBlock: 0, toBlock: 1023 }) { index } }")
+	if logs := data["logs"].([]interface{}); len(logs) != 1 {
+		t.Errorf("logs count mismatch: have %d, want 1", len(logs))
+	}
+}
+
+func TestValidators(t *testing.T) {
+	backend, _ := newTestBackend(t)
+
+	data := queryData(t, backend, "{ validators { address consensusKey deposit weight } }")
+	validators := data["validators"].([]interface{})
+	if len(validators) != 1 {
+		t.Fatalf("validators count mismatch: have %d, want 1", len(validators))
+	}
+	validator := validators[0].(map[string]interface{})
+	want := map[string]interface{}{
+		"address":      strings.ToLower(proposerAddr.Hex()),
+		"consensusKey": strings.ToLower(recipientAddr.Hex()),
+		"deposit":      "0x64",
+		"weight":       "0x1",
+	}
+	for field, value := range want {
+		if validator[field] != value {
+			t.Errorf("%s mismatch: have %v, want %v", field, validator[field], value)
+		}
+	}
+}
+
+func TestBackendBlocksRange(t *testing.T) {
+	backend, blocks := newTestBackend(t)
+
+	// The range ends at the head by default
+	data := queryData(t, backend, "{ blocks(from: 1) { hash proposer { address } } }")
+	items := data["blocks"].([]interface{})
+	if len(items) != len(blocks) {
+		t.Fatalf("blocks count mismatch: have %d, want %d", len(items), len(blocks))
+	}
+	for i, item := range items {
+		block := item.(map[string]interface{})
+		if hash := block["hash"]; hash != blocks[i].Hash().Hex() {
+			t.Errorf("block %d: hash mismatch: have %v, want %s", i+1, hash, blocks[i].Hash().Hex())
+		}
+		if proposer := block["proposer"].(map[string]interface{})["address"]; proposer != strings.ToLower(proposerAddr.Hex()) {
+			t.Errorf("block %d: proposer mismatch: have %v, want %s", i+1, proposer, strings.ToLower(proposerAddr.Hex()))
+		}
+	}
+	if msg := queryError(t, backend, "{ blocks(from: 0, to: 1024) { hash } }"); msg != errBlockRange.Error() {
+		t.Errorf("error mismatch: have %v, want %v", msg, errBlockRange)
+	}
+	if data := queryData(t, backend, "{ block(number: 3) { hash } }"); data["block"] != nil {
+		t.Errorf("unexpected unknown block: %v", data["block"])
+	}
+}
+
+func TestBackendQueryDepthLimit(t *testing.T) {
+	backend, _ := newTestBackend(t)
+
+	// The depth of the query counts the block and the number fields
+	depth := maxQueryDepth - 2
+	query := "{ block { " + strings.Repeat("parent { ", depth) + "number" + strings.Repeat(" }", depth) + " } }"
+	queryData(t, backend, query)
+
+	depth++
+	query = "{ block { " + strings.Repeat("parent { ", depth) + "number" + strings.Repeat(" }", depth) + " } }"
+	queryError(t, backend, query)
+}
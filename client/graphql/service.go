@@ -0,0 +1,134 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	graphqlgo "github.com/graph-gophers/graphql-go"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/p2p"
+	"github.com/kowala-tech/kcoin/client/rpc"
+)
+
+// Query cost limits enforced on every request.
+const (
+	maxQueryDepth   = 16               // Maximum nesting depth of a query
+	maxParallelism  = 32               // Maximum number of resolvers running concurrently
+	maxRequestSize  = 128 * 1024       // Maximum size of a request body
+	maxQueryRuntime = 10 * time.Second // Maximum time spent resolving a single request
+)
+
+// Service encapsulates a GraphQL service.
+type Service struct {
+	endpoint string       // The host:port endpoint for this service.
+	cors     []string     // Allowed CORS domains
+	vhosts   []string     // Recognised vhosts
+	backend  Backend      // The backend that queries will operate on.
+	handler  http.Handler // The `http.Handler` used to answer queries.
+	listener net.Listener // The listening socket.
+}
+
+// New constructs a new GraphQL service instance.
+func New(backend Backend, endpoint string, cors, vhosts []string) (*Service, error) {
+	handler, err := NewHandler(backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		endpoint: endpoint,
+		cors:     cors,
+		vhosts:   vhosts,
+		backend:  backend,
+		handler:  handler,
+	}, nil
+}
+
+// Protocols returns the list of protocols exported by this service.
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs returns the list of APIs exported by this service.
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Start is called after all services have been constructed and the networking
+// layer was also initialized to spawn any goroutines required by the service.
+func (s *Service) Start(server *p2p.Server) error {
+	listener, err := net.Listen("tcp", s.endpoint)
+	if err != nil {
+		return err
+	}
+	go rpc.NewHTTPServer(s.cors, s.vhosts, s.handler).Serve(listener)
+	s.listener = listener
+
+	log.Info("GraphQL endpoint opened", "url", fmt.Sprintf("http://%s", listener.Addr()), "cors", strings.Join(s.cors, ","), "vhosts", strings.Join(s.vhosts, ","))
+	return nil
+}
+
+// Stop terminates all goroutines belonging to the service, blocking until they
+// are all terminated.
+func (s *Service) Stop() error {
+	if s.listener != nil {
+		s.listener.Close()
+		s.listener = nil
+
+		log.Info("GraphQL endpoint closed", "url", fmt.Sprintf("http://%s", s.endpoint))
+	}
+	return nil
+}
+
+// NewHandler returns an http.Handler answering the GraphQL queries posted to
+// it against the given backend, within the query cost limits.
+func NewHandler(backend Backend) (http.Handler, error) {
+	schema, err := graphqlgo.ParseSchema(schema, &Resolver{backend},
+		graphqlgo.MaxDepth(maxQueryDepth),
+		graphqlgo.MaxParallelism(maxParallelism),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &handler{schema: schema}, nil
+}
+
+// handler answers the GraphQL queries posted over HTTP.
+type handler struct {
+	schema *graphqlgo.Schema
+}
+
+// request is the body of a GraphQL query posted over HTTP.
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.ContentLength > maxRequestSize {
+		http.Error(w, fmt.Sprintf("content length too large (%d>%d)", r.ContentLength, maxRequestSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	var params request
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize)).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), maxQueryRuntime)
+	defer cancel()
+
+	response := h.schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(responseJSON)
+}
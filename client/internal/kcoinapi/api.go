@@ -17,6 +17,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/common/math"
 	"github.com/kowala-tech/kcoin/client/core"
 	"github.com/kowala-tech/kcoin/client/core/rawdb"
+	"github.com/kowala-tech/kcoin/client/core/state"
 	"github.com/kowala-tech/kcoin/client/core/types"
 	"github.com/kowala-tech/kcoin/client/core/vm"
 	"github.com/kowala-tech/kcoin/client/crypto"
@@ -117,6 +118,83 @@ func (s *PublicTxPoolAPI) Content() map[string]map[string]map[string]*RPCTransac
 	return content
 }
 
+// NonceGap is an inclusive range of nonces missing between the pool nonce of an
+// account and its queued transactions.
+type NonceGap struct {
+	From hexutil.Uint64 `json:"from"`
+	To   hexutil.Uint64 `json:"to"`
+}
+
+// AccountContent is the content of the transaction pool for a single account.
+type AccountContent struct {
+	Nonce   hexutil.Uint64             `json:"nonce"`
+	Pending map[string]*RPCTransaction `json:"pending"`
+	Queued  map[string]*RPCTransaction `json:"queued"`
+	Gaps    []NonceGap                 `json:"gaps"`
+}
+
+// ContentFrom returns the transactions contained within the transaction pool for
+// the given account, along with the nonce gaps keeping its queued transactions
+// from being executed.
+func (s *PublicTxPoolAPI) ContentFrom(ctx context.Context, addr common.Address) (*AccountContent, error) {
+	nonce, err := s.b.GetPoolNonce(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	pending, queue := s.b.TxPoolContentFrom(addr)
+
+	content := &AccountContent{
+		Nonce:   hexutil.Uint64(nonce),
+		Pending: make(map[string]*RPCTransaction),
+		Queued:  make(map[string]*RPCTransaction),
+		Gaps:    []NonceGap{},
+	}
+	for _, tx := range pending {
+		content.Pending[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+	}
+	// Queued transactions are sorted by nonce, so gaps can be found in one pass
+	next := nonce
+	for _, tx := range queue {
+		content.Queued[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+		if tx.Nonce() < next {
+			continue
+		}
+		if tx.Nonce() > next {
+			content.Gaps = append(content.Gaps, NonceGap{From: hexutil.Uint64(next), To: hexutil.Uint64(tx.Nonce() - 1)})
+		}
+		next = tx.Nonce() + 1
+	}
+	return content, nil
+}
+
+// CheckResult describes whether a raw transaction would be accepted by the
+// transaction pool.
+type CheckResult struct {
+	Hash  common.Hash     `json:"hash"`
+	From  *common.Address `json:"from"`
+	Valid bool            `json:"valid"`
+	Error string          `json:"error,omitempty"`
+}
+
+// CheckRawTransaction runs a signed transaction through the transaction pool
+// validation without submitting it, explaining why it would be rejected.
+func (s *PublicTxPoolAPI) CheckRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (*CheckResult, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	result := &CheckResult{Hash: tx.Hash()}
+	if from, err := types.TxSender(types.NewAndromedaSigner(tx.ChainID()), tx); err == nil {
+		result.From = &from
+	}
+	if err := s.b.CheckTx(ctx, tx); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Valid = true
+	return result, nil
+}
+
 // Status returns the number of pending and queued transaction in the pool.
 func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
 	pending, queue := s.b.Stats()
@@ -533,6 +611,70 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// AccountResult is the Merkle proof of an account and some of its storage slots.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the Merkle proof of a storage slot of an account.
+type StorageResult struct {
+	Key   string          `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// GetProof returns the Merkle proofs of the given account and storage slots at
+// the given block number, which can be checked against the state root of the block.
+// The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block numbers are
+// also allowed.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNr rpc.BlockNumber) (*AccountResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	storageHash := types.EmptyRootHash
+	if storageTrie := state.StorageTrie(address); storageTrie != nil {
+		storageHash = storageTrie.Hash()
+	}
+	storageProof := make([]StorageResult, len(storageKeys))
+	for i, key := range storageKeys {
+		proof, err := state.GetStorageProof(address, common.HexToHash(key))
+		if err != nil {
+			return nil, err
+		}
+		value := state.GetState(address, common.HexToHash(key)).Big()
+		storageProof[i] = StorageResult{key, (*hexutil.Big)(value), toHexSlice(proof)}
+	}
+	accountProof, err := state.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountResult{
+		Address:      address,
+		AccountProof: toHexSlice(accountProof),
+		Balance:      (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:     state.GetCodeHash(address),
+		Nonce:        hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:  storageHash,
+		StorageProof: storageProof,
+	}, state.Error()
+}
+
+// toHexSlice converts the nodes of a Merkle proof for JSON encoding.
+func toHexSlice(proof [][]byte) []hexutil.Bytes {
+	nodes := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		nodes[i] = node
+	}
+	return nodes
+}
+
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
 	From     common.Address  `json:"from"`
@@ -543,7 +685,65 @@ type CallArgs struct {
 	Data     hexutil.Bytes   `json:"data"`
 }
 
-func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
+// ToMessage converts the call arguments into a message, using the default gas
+// allowance and price if none were set.
+func (args *CallArgs) ToMessage() types.Message {
+	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
+	if gas == 0 {
+		gas = math.MaxUint64 / 2
+	}
+	if gasPrice.Sign() == 0 {
+		gasPrice = new(big.Int).SetUint64(defaultGasPrice)
+	}
+	return types.NewMessage(args.From, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+}
+
+// OverrideAccount is the set of account fields overridden for the execution of
+// a call. State replaces the entire storage of the account, while StateDiff only
+// replaces the given slots, so the two can't be set at the same time.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce"`
+	Code      *hexutil.Bytes               `json:"code"`
+	Balance   *hexutil.Big                 `json:"balance"`
+	State     *map[common.Hash]common.Hash `json:"state"`
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the collection of accounts overridden for the execution of
+// a call.
+type StateOverride map[common.Address]OverrideAccount
+
+// Apply overrides the fields of the accounts in the given state.
+func (diff *StateOverride) Apply(state *state.StateDB) error {
+	if diff == nil {
+		return nil
+	}
+	for addr, account := range *diff {
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+		}
+		if account.Nonce != nil {
+			state.SetNonce(addr, uint64(*account.Nonce))
+		}
+		if account.Code != nil {
+			state.SetCode(addr, *account.Code)
+		}
+		if account.Balance != nil {
+			state.SetBalance(addr, account.Balance.ToInt())
+		}
+		if account.State != nil {
+			state.SetStorage(addr, *account.State)
+		}
+		if account.StateDiff != nil {
+			for key, value := range *account.StateDiff {
+				state.SetState(addr, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, vmCfg vm.Config, timeout time.Duration) ([]byte, uint64, bool, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
 	state, header, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
@@ -553,26 +753,19 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 		}
 		return nil, 0, false, err
 	}
+	if err := overrides.Apply(state); err != nil {
+		return nil, 0, false, err
+	}
 	// Set sender address or use a default if none specified
-	addr := args.From
-	if addr == (common.Address{}) {
+	if args.From == (common.Address{}) {
 		if wallets := s.b.AccountManager().Wallets(); len(wallets) > 0 {
 			if accounts := wallets[0].Accounts(); len(accounts) > 0 {
-				addr = accounts[0].Address
+				args.From = accounts[0].Address
 			}
 		}
 	}
-	// Set default gas & gas price if none were set
-	gas, gasPrice := uint64(args.Gas), args.GasPrice.ToInt()
-	if gas == 0 {
-		gas = math.MaxUint64 / 2
-	}
-	if gasPrice.Sign() == 0 {
-		gasPrice = new(big.Int).SetUint64(defaultGasPrice)
-	}
-
 	// Create new call message
-	msg := types.NewMessage(addr, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+	msg := args.ToMessage()
 
 	// Setup context so it may be cancelled the call has completed
 	// or, in case of unmetered gas, setup a context with a timeout.
@@ -614,14 +807,17 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
-	result, _, _, err := s.doCall(ctx, args, blockNr, vm.Config{}, 5*time.Second)
+//
+// The optional overrides replace the given account fields before the execution.
+func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride) (hexutil.Bytes, error) {
+	result, _, _, err := s.doCall(ctx, args, blockNr, overrides, vm.Config{}, 5*time.Second)
 	return (hexutil.Bytes)(result), err
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
+// given transaction against the current pending block, with the optional
+// overrides applied to its state.
+func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs, overrides *StateOverride) (hexutil.Uint64, error) {
 	// Binary search the gas requirement, as it may be higher than the amount used
 	var (
 		lo  uint64 = params.TxGas - 1
@@ -644,7 +840,7 @@ func (s *PublicBlockChainAPI) EstimateGas(ctx context.Context, args CallArgs) (h
 	executable := func(gas uint64) bool {
 		args.Gas = hexutil.Uint64(gas)
 
-		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, vm.Config{}, 0)
+		_, _, failed, err := s.doCall(ctx, args, rpc.PendingBlockNumber, overrides, vm.Config{}, 0)
 		if err != nil || failed {
 			if err != nil {
 				log.Error("can't estimate gas limit", "err", err)
@@ -793,20 +989,29 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash        common.Hash     `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Gas              hexutil.Uint64  `json:"gas"`
-	GasPrice         *hexutil.Big    `json:"gasPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
-	V                *hexutil.Big    `json:"v"`
-	R                *hexutil.Big    `json:"r"`
-	S                *hexutil.Big    `json:"s"`
+	BlockHash        common.Hash      `json:"blockHash"`
+	BlockNumber      *hexutil.Big     `json:"blockNumber"`
+	From             common.Address   `json:"from"`
+	Gas              hexutil.Uint64   `json:"gas"`
+	GasPrice         *hexutil.Big     `json:"gasPrice"`
+	Hash             common.Hash      `json:"hash"`
+	Input            hexutil.Bytes    `json:"input"`
+	Nonce            hexutil.Uint64   `json:"nonce"`
+	To               *common.Address  `json:"to"`
+	TransactionIndex hexutil.Uint     `json:"transactionIndex"`
+	Value            *hexutil.Big     `json:"value"`
+	V                *hexutil.Big     `json:"v"`
+	R                *hexutil.Big     `json:"r"`
+	S                *hexutil.Big     `json:"s"`
+	Sponsor          []RPCSponsorship `json:"sponsor,omitempty"`
+}
+
+// RPCSponsorship represents the fee payer of a sponsored transaction and its signature.
+type RPCSponsorship struct {
+	Payer common.Address `json:"payer"`
+	V     *hexutil.Big   `json:"v"`
+	R     *hexutil.Big   `json:"r"`
+	S     *hexutil.Big   `json:"s"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -830,6 +1035,15 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
 	}
+	if payer := tx.FeePayer(); payer != nil {
+		v, r, s := tx.RawPayerSignatureValues()
+		result.Sponsor = []RPCSponsorship{{
+			Payer: *payer,
+			V:     (*hexutil.Big)(v),
+			R:     (*hexutil.Big)(r),
+			S:     (*hexutil.Big)(s),
+		}}
+	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -1047,6 +1261,8 @@ type SendTxArgs struct {
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+	// FeePayer is the account paying for the gas of a sponsored transaction.
+	FeePayer *common.Address `json:"feePayer"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1097,10 +1313,16 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Input != nil {
 		input = *args.Input
 	}
+	var tx *types.Transaction
 	if args.To == nil {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+		tx = types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	} else {
+		tx = types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
 	}
-	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	if args.FeePayer != nil {
+		tx = tx.WithFeePayer(*args.FeePayer)
+	}
+	return tx
 }
 
 // submitTransaction is a helper function that submits tx to txPool and logs a message.
@@ -1154,9 +1376,60 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return common.Hash{}, err
 	}
+	// Sponsored transactions also need the fee payer to be managed by this node
+	if signed.Sponsored() {
+		if signed, err = s.signPayer(signed); err != nil {
+			return common.Hash{}, err
+		}
+	}
 	return submitTransaction(ctx, s.b, signed)
 }
 
+// signPayer is a helper function that co-signs a sponsored transaction with the
+// private key of its fee payer.
+func (s *PublicTransactionPoolAPI) signPayer(tx *types.Transaction) (*types.Transaction, error) {
+	payer := tx.FeePayer()
+	if payer == nil {
+		return nil, types.ErrNotSponsored
+	}
+	// Look up the wallet containing the fee payer
+	account := accounts.Account{Address: *payer}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.NewAndromedaSigner(s.b.ChainConfig().ChainID)
+
+	sig, err := wallet.SignHash(account, signer.PayerHash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithPayerSignature(signer, sig)
+}
+
+// SignSponsorship co-signs a sponsored transaction, already signed by its sender,
+// with the key of its fee payer. The fee payer must be managed by this node and
+// be unlocked.
+func (s *PublicTransactionPoolAPI) SignSponsorship(ctx context.Context, encodedTx hexutil.Bytes) (*SignTransactionResult, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	if _, err := types.TxSender(types.NewAndromedaSigner(s.b.ChainConfig().ChainID), tx); err != nil {
+		return nil, err
+	}
+	signed, err := s.signPayer(tx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, signed}, nil
+}
+
 // SendRawTransaction will add the signed transaction to the transaction pool.
 // The sender is responsible for signing the transaction and using the correct nonce.
 func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
@@ -1293,6 +1566,37 @@ func (s *PublicTransactionPoolAPI) Resend(ctx context.Context, sendArgs SendTxAr
 	return common.Hash{}, fmt.Errorf("Transaction %#x not found", matchTx.Hash())
 }
 
+// CancelTransaction replaces a pending transaction of a local account with a
+// zero value transfer to itself at the same nonce. The replacement pays at least
+// the price bump required by the transaction pool, or the given gas price if it
+// is higher.
+func (s *PublicTransactionPoolAPI) CancelTransaction(ctx context.Context, hash common.Hash, gasPrice *hexutil.Big) (common.Hash, error) {
+	tx := s.b.GetPoolTransaction(hash)
+	if tx == nil {
+		return common.Hash{}, fmt.Errorf("transaction %#x not found in the pool", hash)
+	}
+	from, err := types.TxSender(types.NewAndromedaSigner(tx.ChainID()), tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	// Prevent a concurrent send from the same account racing the replacement
+	s.nonceLock.LockAddr(from)
+	defer s.nonceLock.UnlockAddr(from)
+
+	price := s.b.ReplacementPrice(tx)
+	if gasPrice != nil && (*big.Int)(gasPrice).Sign() != 0 {
+		if (*big.Int)(gasPrice).Cmp(price) < 0 {
+			return common.Hash{}, fmt.Errorf("gas price too low to replace transaction: have %v, want at least %v", (*big.Int)(gasPrice), price)
+		}
+		price = (*big.Int)(gasPrice)
+	}
+	signed, err := s.sign(from, types.NewTransaction(tx.Nonce(), from, new(big.Int), params.TxGas, price, nil))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return submitTransaction(ctx, s.b, signed)
+}
+
 // PublicDebugAPI is the collection of Kowala APIs exposed over the public
 // debugging endpoint.
 type PublicDebugAPI struct {
@@ -0,0 +1,313 @@
+package kcoinapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts"
+	"github.com/kowala-tech/kcoin/client/accounts/keystore"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/event"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/kowala-tech/kcoin/client/rlp"
+)
+
+type testChain struct {
+	statedb       *state.StateDB
+	chainHeadFeed event.Feed
+}
+
+func (bc *testChain) CurrentBlock() *types.Block {
+	return types.NewBlock(&types.Header{GasLimit: 1000000}, nil, nil, nil)
+}
+
+func (bc *testChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.CurrentBlock()
+}
+
+func (bc *testChain) StateAt(common.Hash) (*state.StateDB, error) {
+	return bc.statedb, nil
+}
+
+func (bc *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return bc.chainHeadFeed.Subscribe(ch)
+}
+
+// testBackend serves the transaction pool APIs from a real transaction pool.
+// The other methods of the backend are not implemented.
+type testBackend struct {
+	Backend
+
+	pool     *core.TxPool
+	accounts *accounts.Manager
+}
+
+func newTestBackend(funded ...common.Address) *testBackend {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+	for _, addr := range funded {
+		statedb.AddBalance(addr, big.NewInt(1000000000))
+	}
+	config := core.DefaultTxPoolConfig
+	config.Journal = ""
+
+	return &testBackend{
+		pool: core.NewTxPool(config, params.TestChainConfig, &testChain{statedb: statedb}),
+	}
+}
+
+// withAccount adds an unlocked account holding the key to the backend.
+func (b *testBackend) withAccount(t *testing.T, key *ecdsa.PrivateKey) func() {
+	dir, err := ioutil.TempDir("", "kcoinapi-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Unlock(account, ""); err != nil {
+		t.Fatal(err)
+	}
+	b.accounts = accounts.NewManager(ks)
+	return func() {
+		b.accounts.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func (b *testBackend) AccountManager() *accounts.Manager { return b.accounts }
+
+func (b *testBackend) ChainConfig() *params.ChainConfig { return params.TestChainConfig }
+
+func (b *testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	return b.pool.AddLocal(signedTx)
+}
+
+func (b *testBackend) GetPoolTransaction(hash common.Hash) *types.Transaction {
+	return b.pool.Get(hash)
+}
+
+func (b *testBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	return b.pool.State().GetNonce(addr), nil
+}
+
+func (b *testBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.pool.ContentFrom(addr)
+}
+
+func (b *testBackend) CheckTx(ctx context.Context, signedTx *types.Transaction) error {
+	return b.pool.Check(signedTx, true)
+}
+
+func (b *testBackend) ReplacementPrice(tx *types.Transaction) *big.Int {
+	return b.pool.ReplacementPrice(tx)
+}
+
+func signedTransaction(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, gasPrice int64) *types.Transaction {
+	tx := types.NewTransaction(nonce, common.HexToAddress("0x01"), big.NewInt(1), params.TxGas, big.NewInt(gasPrice), nil)
+	signed, err := types.SignTx(tx, types.NewAndromedaSigner(params.TestChainConfig.ChainID), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestTxPoolContentFrom(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	b := newTestBackend(from)
+	defer b.pool.Stop()
+
+	for _, nonce := range []uint64{0, 2, 4, 5} {
+		if err := b.pool.AddRemote(signedTransaction(t, key, nonce, 1)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", nonce, err)
+		}
+	}
+
+	content, err := NewPublicTxPoolAPI(b).ContentFrom(context.Background(), from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.Nonce != 1 {
+		t.Errorf("nonce mismatch: have %d, want %d", content.Nonce, 1)
+	}
+	if len(content.Pending) != 1 || content.Pending["0"] == nil {
+		t.Errorf("pending transactions mismatch: have %v", content.Pending)
+	}
+	if len(content.Queued) != 3 || content.Queued["2"] == nil || content.Queued["4"] == nil || content.Queued["5"] == nil {
+		t.Errorf("queued transactions mismatch: have %v", content.Queued)
+	}
+	want := []NonceGap{{From: 1, To: 1}, {From: 3, To: 3}}
+	if len(content.Gaps) != len(want) {
+		t.Fatalf("nonce gaps mismatch: have %v, want %v", content.Gaps, want)
+	}
+	for i := range want {
+		if content.Gaps[i] != want[i] {
+			t.Errorf("nonce gap %d mismatch: have %v, want %v", i, content.Gaps[i], want[i])
+		}
+	}
+}
+
+func TestCheckRawTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	poor, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	b := newTestBackend(from)
+	defer b.pool.Stop()
+
+	api := NewPublicTxPoolAPI(b)
+	check := func(tx *types.Transaction) *CheckResult {
+		encoded, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := api.CheckRawTransaction(context.Background(), encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	valid := signedTransaction(t, key, 0, 1)
+	result := check(valid)
+	if !result.Valid || result.Error != "" || result.Hash != valid.Hash() || result.From == nil || *result.From != from {
+		t.Errorf("valid transaction result mismatch: %+v", result)
+	}
+	if b.pool.Get(valid.Hash()) != nil {
+		t.Error("checked transaction added to the pool")
+	}
+
+	result = check(signedTransaction(t, poor, 0, 1))
+	if result.Valid || result.Error != core.ErrInsufficientFunds.Error() {
+		t.Errorf("unfunded transaction result mismatch: %+v", result)
+	}
+
+	if _, err := api.CheckRawTransaction(context.Background(), hexutil.Bytes{0x01, 0x02}); err == nil {
+		t.Error("decoded an invalid transaction")
+	}
+}
+
+func TestCancelTransaction(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	b := newTestBackend(from)
+	defer b.pool.Stop()
+	defer b.withAccount(t, key)()
+
+	original := signedTransaction(t, key, 0, 100)
+	if err := b.pool.AddRemote(original); err != nil {
+		t.Fatal(err)
+	}
+	api := NewPublicTransactionPoolAPI(b, new(AddrLocker))
+
+	if _, err := api.CancelTransaction(context.Background(), common.HexToHash("0x01"), nil); err == nil {
+		t.Error("cancelled an unknown transaction")
+	}
+	if _, err := api.CancelTransaction(context.Background(), original.Hash(), (*hexutil.Big)(big.NewInt(105))); err == nil {
+		t.Error("cancelled a transaction below the replacement price")
+	}
+
+	hash, err := api.CancelTransaction(context.Background(), original.Hash(), nil)
+	if err != nil {
+		t.Fatalf("failed to cancel transaction: %v", err)
+	}
+	if b.pool.Get(original.Hash()) != nil {
+		t.Error("cancelled transaction still in the pool")
+	}
+	replacement := b.pool.Get(hash)
+	if replacement == nil {
+		t.Fatal("replacement not in the pool")
+	}
+	if replacement.Nonce() != 0 || *replacement.To() != from || replacement.Value().Sign() != 0 {
+		t.Errorf("replacement is not a self transfer: %v", replacement)
+	}
+	if replacement.GasPrice().Cmp(big.NewInt(110)) != 0 {
+		t.Errorf("replacement price mismatch: have %v, want %v", replacement.GasPrice(), 110)
+	}
+}
+
+func TestStateOverrideApply(t *testing.T) {
+	var (
+		addr  = common.HexToAddress("0x01")
+		other = common.HexToAddress("0x02")
+		slot1 = common.HexToHash("0x01")
+		slot2 = common.HexToHash("0x02")
+	)
+	newState := func() *state.StateDB {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+		statedb.SetState(addr, slot1, common.HexToHash("0x11"))
+		statedb.SetState(addr, slot2, common.HexToHash("0x22"))
+		return statedb
+	}
+
+	var (
+		nonce    = hexutil.Uint64(5)
+		balance  = (*hexutil.Big)(big.NewInt(1000))
+		code     = hexutil.Bytes{0x60, 0x00}
+		replaced = map[common.Hash]common.Hash{slot1: common.HexToHash("0x33")}
+		diff     = map[common.Hash]common.Hash{slot2: common.HexToHash("0x44")}
+	)
+
+	// the storage of an account is either replaced or patched
+	conflict := &StateOverride{addr: {State: &replaced, StateDiff: &diff}}
+	if err := conflict.Apply(newState()); err == nil {
+		t.Error("applied both state and stateDiff")
+	}
+
+	var none *StateOverride
+	if err := none.Apply(newState()); err != nil {
+		t.Errorf("failed to apply no overrides: %v", err)
+	}
+
+	statedb := newState()
+	overrides := &StateOverride{
+		addr:  {Nonce: &nonce, Balance: balance, Code: &code, State: &replaced},
+		other: {StateDiff: &diff},
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		t.Fatalf("failed to apply the overrides: %v", err)
+	}
+	if have := statedb.GetNonce(addr); have != uint64(nonce) {
+		t.Errorf("nonce mismatch: have %d, want %d", have, nonce)
+	}
+	if have := statedb.GetBalance(addr); have.Cmp(balance.ToInt()) != 0 {
+		t.Errorf("balance mismatch: have %v, want %v", have, balance.ToInt())
+	}
+	if have := statedb.GetCode(addr); !bytes.Equal(have, code) {
+		t.Errorf("code mismatch: have %x, want %x", have, []byte(code))
+	}
+	if have := statedb.GetState(addr, slot1); have != replaced[slot1] {
+		t.Errorf("replaced slot mismatch: have %x, want %x", have, replaced[slot1])
+	}
+	if have := statedb.GetState(addr, slot2); have != (common.Hash{}) {
+		t.Errorf("slot missing from the replaced storage: have %x, want empty", have)
+	}
+	if have := statedb.GetState(other, slot2); have != diff[slot2] {
+		t.Errorf("patched slot mismatch: have %x, want %x", have, diff[slot2])
+	}
+
+	// the slots not in the diff are left untouched
+	statedb = newState()
+	patch := &StateOverride{addr: {StateDiff: &diff}}
+	if err := patch.Apply(statedb); err != nil {
+		t.Fatalf("failed to apply the overrides: %v", err)
+	}
+	if have := statedb.GetState(addr, slot1); have != common.HexToHash("0x11") {
+		t.Errorf("unpatched slot mismatch: have %x, want %x", have, common.HexToHash("0x11"))
+	}
+	if have := statedb.GetState(addr, slot2); have != diff[slot2] {
+		t.Errorf("patched slot mismatch: have %x, want %x", have, diff[slot2])
+	}
+}
@@ -500,6 +500,27 @@ func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) er
 	return ec.SendRawTransaction(ctx, data)
 }
 
+// SignSponsorship asks the node to co-sign a sponsored transaction, already signed
+// by its sender, with the key of its fee payer. The fee payer must be unlocked on
+// the node; use types.SignPayer to co-sign with a local key instead.
+func (ec *Client) SignSponsorship(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := ec.c.CallContext(ctx, &result, "eth_signSponsorship", hexutil.Bytes(data)); err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(result.Raw, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
 // SendRawTransaction injects a raw signed transaction into the pending pool for execution.
 //
 // If the transaction was a contract creation use the TransactionReceipt method to get the
@@ -0,0 +1,111 @@
+package kcoinclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/rlp"
+	"github.com/kowala-tech/kcoin/client/trie"
+)
+
+// AccountResult is the Merkle proof of an account and some of its storage slots,
+// as returned by eth_getProof.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the Merkle proof of a storage slot of an account.
+type StorageResult struct {
+	Key   string          `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// GetProof returns the Merkle proofs of the given account and storage slots.
+// The block number can be nil, in which case the proofs are taken from the latest
+// known block. Use VerifyProof to check them against the state root of the block.
+func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []common.Hash, blockNumber *big.Int) (*AccountResult, error) {
+	storageKeys := make([]string, len(keys))
+	for i, key := range keys {
+		storageKeys[i] = key.Hex()
+	}
+	var result AccountResult
+	err := ec.c.CallContext(ctx, &result, "eth_getProof", account, storageKeys, toBlockNumArg(blockNumber))
+	return &result, err
+}
+
+// VerifyProof checks the account and storage proofs of an eth_getProof result
+// against the given state root, making sure every reported value is proven.
+func VerifyProof(stateRoot common.Hash, result *AccountResult) error {
+	value, err := verifyProof(stateRoot, crypto.Keccak256(result.Address.Bytes()), result.AccountProof)
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %v", err)
+	}
+	// Missing accounts are reported as empty ones with no code hash
+	account := state.Account{Balance: new(big.Int), Root: types.EmptyRootHash, CodeHash: common.Hash{}.Bytes()}
+	if value != nil {
+		if err := rlp.DecodeBytes(value, &account); err != nil {
+			return fmt.Errorf("invalid account proof: %v", err)
+		}
+	}
+	if uint64(result.Nonce) != account.Nonce {
+		return fmt.Errorf("nonce mismatch: have %d, proven %d", result.Nonce, account.Nonce)
+	}
+	if result.Balance == nil || result.Balance.ToInt().Cmp(account.Balance) != 0 {
+		return fmt.Errorf("balance mismatch: have %v, proven %v", result.Balance, account.Balance)
+	}
+	if !bytes.Equal(result.CodeHash.Bytes(), account.CodeHash) {
+		return fmt.Errorf("code hash mismatch: have %x, proven %x", result.CodeHash, account.CodeHash)
+	}
+	if result.StorageHash != account.Root {
+		return fmt.Errorf("storage hash mismatch: have %x, proven %x", result.StorageHash, account.Root)
+	}
+	for _, storage := range result.StorageProof {
+		key := common.HexToHash(storage.Key)
+		value, err := verifyProof(account.Root, crypto.Keccak256(key.Bytes()), storage.Proof)
+		if err != nil {
+			return fmt.Errorf("invalid storage proof for %s: %v", storage.Key, err)
+		}
+		proven := new(big.Int)
+		if value != nil {
+			var content []byte
+			if err := rlp.DecodeBytes(value, &content); err != nil {
+				return fmt.Errorf("invalid storage proof for %s: %v", storage.Key, err)
+			}
+			proven.SetBytes(content)
+		}
+		if storage.Value == nil || storage.Value.ToInt().Cmp(proven) != 0 {
+			return fmt.Errorf("storage mismatch for %s: have %v, proven %v", storage.Key, storage.Value, proven)
+		}
+	}
+	return nil
+}
+
+// verifyProof returns the value proven for key in the trie with the given root,
+// or nil if the proof shows the key is absent.
+func verifyProof(root common.Hash, key []byte, proof []hexutil.Bytes) ([]byte, error) {
+	// Empty tries have no nodes to prove anything with
+	if root == types.EmptyRootHash && len(proof) == 0 {
+		return nil, nil
+	}
+	db := kcoindb.NewMemDatabase()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	value, _, err := trie.VerifyProof(root, key, db)
+	return value, err
+}
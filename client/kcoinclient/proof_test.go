@@ -0,0 +1,88 @@
+package kcoinclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/stretchr/testify/require"
+)
+
+func proofResult(t *testing.T, statedb *state.StateDB, addr common.Address, keys ...common.Hash) *AccountResult {
+	accountProof, err := statedb.GetProof(addr)
+	require.NoError(t, err)
+
+	result := &AccountResult{
+		Address:      addr,
+		AccountProof: toHexSlice(accountProof),
+		Balance:      (*hexutil.Big)(statedb.GetBalance(addr)),
+		CodeHash:     statedb.GetCodeHash(addr),
+		Nonce:        hexutil.Uint64(statedb.GetNonce(addr)),
+		StorageHash:  types.EmptyRootHash,
+	}
+	if storageTrie := statedb.StorageTrie(addr); storageTrie != nil {
+		result.StorageHash = storageTrie.Hash()
+	}
+	for _, key := range keys {
+		proof, err := statedb.GetStorageProof(addr, key)
+		require.NoError(t, err)
+		result.StorageProof = append(result.StorageProof, StorageResult{
+			Key:   key.Hex(),
+			Value: (*hexutil.Big)(statedb.GetState(addr, key).Big()),
+			Proof: toHexSlice(proof),
+		})
+	}
+	return result
+}
+
+func toHexSlice(proof [][]byte) []hexutil.Bytes {
+	nodes := make([]hexutil.Bytes, len(proof))
+	for i, node := range proof {
+		nodes[i] = node
+	}
+	return nodes
+}
+
+func TestVerifyProof(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(kcoindb.NewMemDatabase()))
+
+	addr := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+	statedb.SetBalance(addr, big.NewInt(42))
+	statedb.SetNonce(addr, 3)
+	statedb.SetState(addr, common.HexToHash("0x01"), common.HexToHash("0xff"))
+	statedb.SetBalance(other, big.NewInt(1))
+	root, err := statedb.Commit(false)
+	require.NoError(t, err)
+
+	t.Run("It verifies accounts and storage", func(t *testing.T) {
+		result := proofResult(t, statedb, addr, common.HexToHash("0x01"), common.HexToHash("0x02"))
+		require.NoError(t, VerifyProof(root, result))
+	})
+
+	t.Run("It verifies missing accounts", func(t *testing.T) {
+		result := proofResult(t, statedb, common.HexToAddress("0x03"), common.HexToHash("0x01"))
+		require.NoError(t, VerifyProof(root, result))
+	})
+
+	t.Run("It rejects a tampered balance", func(t *testing.T) {
+		result := proofResult(t, statedb, addr)
+		result.Balance = (*hexutil.Big)(big.NewInt(43))
+		require.Error(t, VerifyProof(root, result))
+	})
+
+	t.Run("It rejects a tampered storage value", func(t *testing.T) {
+		result := proofResult(t, statedb, addr, common.HexToHash("0x01"))
+		result.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(1))
+		require.Error(t, VerifyProof(root, result))
+	})
+
+	t.Run("It rejects a proof for another state root", func(t *testing.T) {
+		result := proofResult(t, statedb, addr)
+		require.Error(t, VerifyProof(common.HexToHash("0x1234"), result))
+	})
+}
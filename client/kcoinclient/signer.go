@@ -35,9 +35,16 @@ func (s *senderFromServer) Sender(_ types.Sender) (common.Address, error) {
 	return s.addr, nil
 }
 
+func (s *senderFromServer) Payer(_ *types.Transaction) (common.Address, error) {
+	return common.Address{}, errNotCached
+}
+
 func (s *senderFromServer) Hash(_ types.Hasher) common.Hash {
 	panic("can't sign with senderFromServer")
 }
+func (s *senderFromServer) PayerHash(_ *types.Transaction) common.Hash {
+	panic("can't sign with senderFromServer")
+}
 func (s *senderFromServer) SignatureValues(_ []byte) (R, S, V *big.Int, err error) {
 	panic("can't sign with senderFromServer")
 }
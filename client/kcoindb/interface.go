@@ -34,3 +34,41 @@ type Batch interface {
 	// Reset resets the batch for reuse
 	Reset()
 }
+
+// AncientReader contains the methods required to read from the immutable
+// ancient data of a database.
+type AncientReader interface {
+	// HasAncient returns an indicator whether the specified data exists in the
+	// ancient store.
+	HasAncient(kind string, number uint64) (bool, error)
+
+	// Ancient retrieves an ancient binary blob from the append-only immutable files.
+	Ancient(kind string, number uint64) ([]byte, error)
+
+	// Ancients returns the number of items in the ancient store.
+	Ancients() (uint64, error)
+
+	// AncientSize returns the ancient size of the specified category.
+	AncientSize(kind string) (uint64, error)
+}
+
+// AncientWriter contains the methods required to write to the immutable
+// ancient data of a database.
+type AncientWriter interface {
+	// AppendAncient injects all binary blobs belonging to a block at the end of
+	// the append-only immutable table files.
+	AppendAncient(number uint64, hash, header, body, receipts []byte) error
+
+	// TruncateAncients discards all but the first n ancient data items.
+	TruncateAncients(n uint64) error
+
+	// Sync flushes all in-memory ancient store data to disk.
+	Sync() error
+}
+
+// AncientStore contains all the methods required to read from and write to the
+// immutable ancient data of a database.
+type AncientStore interface {
+	AncientReader
+	AncientWriter
+}
@@ -0,0 +1,49 @@
+package knode
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+)
+
+// datadirAggregateKey is the path within the datadir to the key signing the
+// pre-commits of the aggregated commits.
+const datadirAggregateKey = "aggregatekey"
+
+// loadAggregateKey loads the aggregate key of the validator from the given
+// file, generating and storing a new key the first time. An ephemeral key is
+// used if there isn't any file, as for nodes without a datadir.
+func loadAggregateKey(keyfile string) (*bls.PrivateKey, error) {
+	if keyfile == "" {
+		return bls.GenerateKey(nil)
+	}
+	buf, err := ioutil.ReadFile(keyfile)
+	switch {
+	case err == nil:
+		raw, err := hex.DecodeString(strings.TrimSpace(string(buf)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregate key file %s: %v", keyfile, err)
+		}
+		return bls.ToPrivateKey(raw)
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	// No persistent key found, generate and store a new one.
+	key, err := bls.GenerateKey(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyfile), 0700); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(keyfile, []byte(hex.EncodeToString(key.Marshal())), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,37 @@
+package knode
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAggregateKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "aggregatekey")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyfile := filepath.Join(dir, "kcoin", datadirAggregateKey)
+
+	key, err := loadAggregateKey(keyfile)
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	loaded, err := loadAggregateKey(keyfile)
+	if err != nil {
+		t.Fatalf("failed to load the key: %v", err)
+	}
+	if !bytes.Equal(key.Marshal(), loaded.Marshal()) {
+		t.Fatal("loaded key doesn't match the stored key")
+	}
+
+	if err := ioutil.WriteFile(keyfile, []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadAggregateKey(keyfile); err == nil {
+		t.Fatal("loaded an invalid key")
+	}
+}
@@ -110,6 +110,16 @@ func (api *PrivateValidatorAPI) SetCoinbase(coinbase common.Address) bool {
 	return true
 }
 
+// SetConsensusKey sets the account signing the proposals and votes of the
+// validator. The validator registers the key on the network, rotating the
+// previous one, the next time it starts.
+func (api *PrivateValidatorAPI) SetConsensusKey(key common.Address) (bool, error) {
+	if err := api.kcoin.SetConsensusKey(key); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // GetMinimumDeposit gets the minimum deposit required to take a slot as a validator
 func (api *PrivateValidatorAPI) GetMinimumDeposit() (*big.Int, error) {
 	return api.kcoin.GetMinimumDeposit()
@@ -165,12 +175,146 @@ func (api *PrivateValidatorAPI) IsRunning() bool {
 	return api.kcoin.IsRunning()
 }
 
+// ShadowStatus returns the elections followed by the validator in shadow mode,
+// with its agreement rate with the network.
+func (api *PrivateValidatorAPI) ShadowStatus() (*validator.ShadowStatus, error) {
+	return api.kcoin.Validator().ShadowStatus()
+}
+
 // RedeemDeposits requests a transfer of the unlocked deposits back
 // to the validator account
 func (api *PrivateValidatorAPI) RedeemDeposits() error {
 	return api.kcoin.Validator().RedeemDeposits()
 }
 
+// SetCommissionRate sets the share of the delegators rewards, in basis points,
+// kept by the validator.
+func (api *PrivateValidatorAPI) SetCommissionRate(rate hexutil.Uint64) (common.Hash, error) {
+	walletAccount, err := api.kcoin.getWalletAccount()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if uint64(rate) > consensus.MaxCommissionRate {
+		return common.Hash{}, fmt.Errorf("commission rate %d exceeds the maximum %d", rate, consensus.MaxCommissionRate)
+	}
+	return api.kcoin.Consensus().SetCommissionRate(walletAccount, new(big.Int).SetUint64(uint64(rate)))
+}
+
+// GetStakeResult is the result of a validator_getStake API call.
+type GetStakeResult struct {
+	Deposit        *big.Int         `json:"deposit"`
+	Delegated      *big.Int         `json:"delegated"`
+	CommissionRate *big.Int         `json:"commissionRate"`
+	Delegators     []delegatorEntry `json:"delegators"`
+}
+
+type delegatorEntry struct {
+	Address common.Address `json:"address"`
+	Amount  *big.Int       `json:"value"`
+}
+
+// GetStake returns the deposit of the validator along with the tokens
+// delegated to it.
+func (api *PrivateValidatorAPI) GetStake(validator common.Address) (GetStakeResult, error) {
+	stake, err := api.kcoin.Consensus().Stake(validator)
+	if err != nil {
+		return GetStakeResult{}, err
+	}
+
+	delegators := make([]delegatorEntry, len(stake.Delegators))
+	for i, delegator := range stake.Delegators {
+		delegators[i] = delegatorEntry{Address: delegator.Address, Amount: delegator.Amount}
+	}
+
+	return GetStakeResult{
+		Deposit:        stake.Deposit,
+		Delegated:      stake.Delegated,
+		CommissionRate: stake.CommissionRate,
+		Delegators:     delegators,
+	}, nil
+}
+
+// Delegate stakes the tokens of the account through the validator. The account
+// shares the rewards of the validator in proportion to its stake.
+func (api *PrivateValidatorAPI) Delegate(from common.Address, validator common.Address, value *hexutil.Big) (common.Hash, error) {
+	if value == nil {
+		return common.Hash{}, errors.New("a number of tokens should be specified")
+	}
+	walletAccount, err := api.kcoin.findWalletAccount(from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return api.kcoin.Consensus().Delegate(walletAccount, validator, value.ToInt())
+}
+
+// Undelegate withdraws tokens delegated by the account to the validator. The
+// tokens are locked for the freeze period.
+func (api *PrivateValidatorAPI) Undelegate(from common.Address, validator common.Address, value *hexutil.Big) (common.Hash, error) {
+	if value == nil {
+		return common.Hash{}, errors.New("a number of tokens should be specified")
+	}
+	walletAccount, err := api.kcoin.findWalletAccount(from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return api.kcoin.Consensus().Undelegate(walletAccount, validator, value.ToInt())
+}
+
+// GetUndelegations returns the tokens undelegated by the account from the
+// validator that haven't been released yet.
+func (api *PrivateValidatorAPI) GetUndelegations(from common.Address, validator common.Address) (GetDepositsResult, error) {
+	rawDeposits, err := api.kcoin.Consensus().Undelegations(from, validator)
+	if err != nil {
+		return GetDepositsResult{}, err
+	}
+
+	return depositsToResponse(rawDeposits), nil
+}
+
+// ReleaseDelegations requests a transfer of the undelegated tokens past the
+// freeze period back to the account.
+func (api *PrivateValidatorAPI) ReleaseDelegations(from common.Address, validator common.Address) (common.Hash, error) {
+	walletAccount, err := api.kcoin.findWalletAccount(from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return api.kcoin.Consensus().ReleaseDelegations(walletAccount, validator)
+}
+
+// GetUptimeResult is the result of a validator_getUptime API call.
+type GetUptimeResult struct {
+	Blocks      *big.Int `json:"blocks"`
+	Missed      *big.Int `json:"missed"`
+	Jailed      bool     `json:"jailed"`
+	JailedUntil *big.Int `json:"jailedUntil"`
+}
+
+// GetUptime returns the blocks of the uptime window signed and missed by the
+// validator, and whether the validator is jailed.
+func (api *PrivateValidatorAPI) GetUptime(validator common.Address) (GetUptimeResult, error) {
+	uptime, err := api.kcoin.Consensus().Uptime(validator)
+	if err != nil {
+		return GetUptimeResult{}, err
+	}
+
+	return GetUptimeResult{
+		Blocks:      uptime.Blocks,
+		Missed:      uptime.Missed,
+		Jailed:      uptime.JailedUntil.Sign() > 0,
+		JailedUntil: uptime.JailedUntil,
+	}, nil
+}
+
+// Unjail returns the validator to the voters set once its jail duration has
+// elapsed.
+func (api *PrivateValidatorAPI) Unjail() (common.Hash, error) {
+	walletAccount, err := api.kcoin.getWalletAccount()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return api.kcoin.Consensus().Unjail(walletAccount)
+}
+
 // TransferArgs represents the arguments to transfer tokens.
 type TransferArgs struct {
 	From           common.Address  `json:"from"`
@@ -461,6 +605,9 @@ func (api *PublicDebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error
 	}
 	stateDb, err := api.kcoin.BlockChain().StateAt(block.Root())
 	if err != nil {
+		if api.kcoin.BlockChain().StatePruned(block.NumberU64()) {
+			return state.Dump{}, core.ErrStatePruned
+		}
 		return state.Dump{}, err
 	}
 	return stateDb.RawDump(), nil
@@ -625,6 +772,9 @@ func (api *PrivateDebugAPI) getModifiedAccounts(startBlock, endBlock *types.Bloc
 	if startBlock.Number().Uint64() >= endBlock.Number().Uint64() {
 		return nil, fmt.Errorf("start block height (%d) must be less than end block height (%d)", startBlock.Number().Uint64(), endBlock.Number().Uint64())
 	}
+	if api.kcoin.blockchain.StatePruned(startBlock.NumberU64()) {
+		return nil, core.ErrStatePruned
+	}
 
 	oldTrie, err := trie.NewSecure(startBlock.Root(), trie.NewDatabase(api.kcoin.chainDb), 0)
 	if err != nil {
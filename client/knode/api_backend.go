@@ -171,6 +171,18 @@ func (b *KowalaAPIBackend) TxPoolContent() (map[common.Address]types.Transaction
 	return b.kcoin.TxPool().Content()
 }
 
+func (b *KowalaAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.kcoin.TxPool().ContentFrom(addr)
+}
+
+func (b *KowalaAPIBackend) CheckTx(ctx context.Context, signedTx *types.Transaction) error {
+	return b.kcoin.txPool.Check(signedTx, true)
+}
+
+func (b *KowalaAPIBackend) ReplacementPrice(tx *types.Transaction) *big.Int {
+	return b.kcoin.txPool.ReplacementPrice(tx)
+}
+
 func (b *KowalaAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.Subscription {
 	return b.kcoin.TxPool().SubscribeNewTxsEvent(ch)
 }
@@ -209,3 +221,8 @@ func (b *KowalaAPIBackend) ServiceFilter(ctx context.Context, session *bloombits
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.kcoin.bloomRequests)
 	}
 }
+
+// Validators returns the current consensus validator set.
+func (b *KowalaAPIBackend) Validators() (types.Voters, error) {
+	return b.kcoin.consensus.Validators()
+}
@@ -60,6 +60,13 @@ type TraceConfig struct {
 	Reexec  *uint64
 }
 
+// TraceCallConfig holds extra parameters to the call trace function, which are
+// the tracer configuration and the state overrides applied before the call.
+type TraceCallConfig struct {
+	TraceConfig
+	StateOverrides *kcoinapi.StateOverride
+}
+
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
 	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
@@ -164,6 +171,9 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 		if err != nil {
 			switch err.(type) {
 			case *trie.MissingNodeError:
+				if origin > 0 && api.kcoin.blockchain.StatePruned(origin-1) {
+					return nil, core.ErrStatePruned
+				}
 				return nil, errors.New("required historical state unavailable")
 			default:
 				return nil, err
@@ -492,6 +502,9 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 	if err != nil {
 		switch err.(type) {
 		case *trie.MissingNodeError:
+			if api.kcoin.blockchain.StatePruned(origin) {
+				return nil, core.ErrStatePruned
+			}
 			return nil, errors.New("required historical state unavailable")
 		default:
 			return nil, err
@@ -554,11 +567,55 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
 
+// TraceCall lets you trace a given call on top of the state of the given block,
+// with the optional state overrides applied first. The call isn't persisted and
+// its return value is tracer dependent, just as for TraceTransaction.
+func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args kcoinapi.CallArgs, number rpc.BlockNumber, config *TraceCallConfig) (interface{}, error) {
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	// Fetch the block and the state that we want to trace the call on
+	var (
+		block   *types.Block
+		statedb *state.StateDB
+		err     error
+	)
+	switch number {
+	case rpc.PendingBlockNumber:
+		block, statedb = api.kcoin.validator.Pending()
+	case rpc.LatestBlockNumber:
+		block = api.kcoin.blockchain.CurrentBlock()
+	default:
+		block = api.kcoin.blockchain.GetBlockByNumber(uint64(number))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	if statedb == nil {
+		if statedb, err = api.computeStateDB(block, reexec); err != nil {
+			return nil, err
+		}
+	}
+	var traceConfig *TraceConfig
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+		traceConfig = &config.TraceConfig
+	}
+	// Assemble the call message and trace it on top of the block
+	msg := args.ToMessage()
+	vmctx := core.NewEVMContext(msg, block.Header(), api.kcoin.blockchain, nil)
+
+	return api.traceTx(ctx, msg, vmctx, statedb, traceConfig)
+}
+
 // traceTx configures a new tracer according to the provided configuration, and
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.Context, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
-	// Assemble the structured logger or the JavaScript tracer
+	// Assemble the structured logger or the native or JavaScript tracer
 	var (
 		tracer vm.Tracer
 		err    error
@@ -572,15 +629,18 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 				return nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
-		if tracer, err = tracers.New(*config.Tracer); err != nil {
+		// Constuct the native or JavaScript tracer to execute with
+		txTracer, err := tracers.NewTracer(*config.Tracer)
+		if err != nil {
 			return nil, err
 		}
+		tracer = txTracer
+
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			txTracer.Stop(errors.New("execution timeout"))
 		}()
 		defer cancel()
 
@@ -607,7 +667,7 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 			StructLogs:  kcoinapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
-	case *tracers.Tracer:
+	case tracers.TxTracer:
 		return tracer.GetResult()
 
 	default:
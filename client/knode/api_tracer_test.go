@@ -0,0 +1,100 @@
+package knode
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/internal/kcoinapi"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/kowala-tech/kcoin/client/rpc"
+)
+
+// loadCode returns the first storage slot of the contract.
+var loadCode = common.FromHex("60005460005260206000f3")
+
+func newTraceCallAPI(t *testing.T, sender, contract common.Address) (*PrivateDebugAPI, func()) {
+	db := kcoindb.NewMemDatabase()
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: core.GenesisAlloc{
+			sender:   {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)},
+			contract: {Code: loadCode, Balance: new(big.Int), Storage: map[common.Hash]common.Hash{{}: common.HexToHash("0x01")}},
+		},
+	}
+	genesis.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, params.TestChainConfig, konsensus.NewFaker(), vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	return NewPrivateDebugAPI(params.TestChainConfig, &Kowala{blockchain: chain, chainDb: db}), chain.Stop
+}
+
+func TestTraceCall(t *testing.T) {
+	var (
+		sender   = common.HexToAddress("0x0a")
+		contract = common.HexToAddress("0x0100")
+	)
+	api, stop := newTraceCallAPI(t, sender, contract)
+	defer stop()
+
+	var (
+		ctx  = context.Background()
+		args = kcoinapi.CallArgs{From: sender, To: &contract, Gas: hexutil.Uint64(100000)}
+	)
+	call := func(config *TraceCallConfig) *kcoinapi.ExecutionResult {
+		res, err := api.TraceCall(ctx, args, rpc.LatestBlockNumber, config)
+		if err != nil {
+			t.Fatalf("failed to trace the call: %v", err)
+		}
+		return res.(*kcoinapi.ExecutionResult)
+	}
+
+	res := call(nil)
+	if res.Failed || !strings.HasSuffix(res.ReturnValue, "01") {
+		t.Errorf("return value mismatch: have %s (failed %v), want slot value 0x01", res.ReturnValue, res.Failed)
+	}
+	if len(res.StructLogs) == 0 {
+		t.Error("call not traced")
+	}
+
+	// the overrides are applied before the call, but not persisted
+	storage := map[common.Hash]common.Hash{{}: common.HexToHash("0x2a")}
+	res = call(&TraceCallConfig{StateOverrides: &kcoinapi.StateOverride{contract: {StateDiff: &storage}}})
+	if !strings.HasSuffix(res.ReturnValue, "2a") {
+		t.Errorf("overridden return value mismatch: have %s, want slot value 0x2a", res.ReturnValue)
+	}
+	if res = call(nil); !strings.HasSuffix(res.ReturnValue, "01") {
+		t.Errorf("overrides persisted: have %s, want slot value 0x01", res.ReturnValue)
+	}
+
+	// the code of any account can be replaced
+	other := common.HexToAddress("0x0200")
+	code := hexutil.Bytes(loadCode)
+	args.To = &other
+	res = call(&TraceCallConfig{StateOverrides: &kcoinapi.StateOverride{other: {Code: &code, State: &storage}}})
+	if !strings.HasSuffix(res.ReturnValue, "2a") {
+		t.Errorf("overridden code return value mismatch: have %s, want slot value 0x2a", res.ReturnValue)
+	}
+
+	// the JavaScript and native tracers are supported
+	tracer := "callTracer"
+	if _, err := api.TraceCall(ctx, args, rpc.LatestBlockNumber, &TraceCallConfig{TraceConfig: TraceConfig{Tracer: &tracer}}); err != nil {
+		t.Errorf("failed to trace the call with a tracer: %v", err)
+	}
+
+	conflict := &kcoinapi.StateOverride{contract: {State: &storage, StateDiff: &storage}}
+	if _, err := api.TraceCall(ctx, args, rpc.LatestBlockNumber, &TraceCallConfig{StateOverrides: conflict}); err == nil {
+		t.Error("traced a call with conflicting overrides")
+	}
+	if _, err := api.TraceCall(ctx, args, rpc.BlockNumber(10), nil); err == nil {
+		t.Error("traced a call on an unknown block")
+	}
+}
@@ -43,6 +43,14 @@ type Config struct {
 	SyncMode  downloader.SyncMode
 	NoPruning bool
 
+	// Number of recent blocks whose state is retained, the older state is
+	// pruned online. Zero disables the pruning.
+	StateRetention uint64 `toml:",omitempty"`
+
+	// Trusted finalized block to start a checkpoint sync from. If nil, the
+	// known checkpoint of the network (if any) is used.
+	Checkpoint *params.TrustedCheckpoint `toml:",omitempty"`
+
 	// Light client options
 	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
@@ -51,6 +59,7 @@ type Config struct {
 	SkipBcVersionCheck bool `toml:"-"`
 	DatabaseHandles    int  `toml:"-"`
 	DatabaseCache      int
+	DatabaseFreezer    string
 	TrieCache          int
 	TrieTimeout        time.Duration
 
@@ -60,6 +69,15 @@ type Config struct {
 	ExtraData []byte         `toml:",omitempty"`
 	GasPrice  *big.Int
 
+	// Account signing the proposals and votes of the validator, so that the
+	// coinbase holding the deposit doesn't need to be a hot key. Defaults to
+	// the coinbase.
+	ConsensusKey common.Address `toml:",omitempty"`
+
+	// Runs the validator without making a deposit nor broadcasting its
+	// proposals and votes, to compare them with the network's decisions.
+	ValidatorShadow bool `toml:",omitempty"`
+
 	// Transaction pool options
 	TxPool core.TxPoolConfig
 
@@ -69,6 +87,10 @@ type Config struct {
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
 
+	// Number of goroutines executing the transactions of a block speculatively.
+	// The transactions are executed sequentially if lower than two.
+	ParallelWorkers int `toml:",omitempty"`
+
 	// Miscellaneous options
 	DocRoot string `toml:"-"`
 
@@ -0,0 +1,279 @@
+package downloader
+
+import (
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/log"
+)
+
+// syncCheckpoint anchors the local chain at the trusted checkpoint: it retrieves
+// the checkpoint block and receipts, checks them against the trusted hashes,
+// downloads the state at the checkpoint and commits the block as the new head.
+// Nothing is done if the local chain is already past the checkpoint.
+func (d *Downloader) syncCheckpoint(p *peerConnection, latest *types.Header) error {
+	cp := d.checkpoint
+	if cp == nil {
+		return errNoCheckpoint
+	}
+	if d.blockchain.CurrentBlock().NumberU64() >= cp.Number {
+		return nil
+	}
+	if latest.Number.Uint64() < cp.Number {
+		return errBehindCheckpoint
+	}
+	p.log.Info("Synchronising from checkpoint", "number", cp.Number, "hash", cp.Hash)
+
+	header, err := d.fetchHeaderByNumber(p, cp.Number)
+	if err != nil {
+		return err
+	}
+	if header.Number.Uint64() != cp.Number || header.Hash() != cp.Hash {
+		p.log.Warn("Checkpoint mismatch", "number", header.Number, "hash", header.Hash(), "want", cp.Hash)
+		return errInvalidChain
+	}
+	if cp.ValidatorsHash != (common.Hash{}) && header.ValidatorsHash != cp.ValidatorsHash {
+		p.log.Warn("Checkpoint validators mismatch", "have", header.ValidatorsHash, "want", cp.ValidatorsHash)
+		return errInvalidChain
+	}
+
+	block, err := d.fetchBlockBody(p, header)
+	if err != nil {
+		return err
+	}
+	receipts, err := d.fetchBlockReceipts(p, header)
+	if err != nil {
+		return err
+	}
+
+	stateSync := d.syncState(header.Root)
+	defer stateSync.Cancel()
+	select {
+	case <-stateSync.done:
+		if stateSync.err != nil {
+			return stateSync.err
+		}
+	case <-d.cancelCh:
+		return errCancelStateFetch
+	}
+
+	return d.blockchain.InsertCheckpoint(block, receipts)
+}
+
+// backfillHeaders retrieves a bounded number of the headers below the checkpoint
+// tail, so that the header chain is completed over the following sync cycles.
+// Failures are not fatal, the next cycle simply tries again.
+func (d *Downloader) backfillHeaders(p *peerConnection) {
+	for i := 0; i < cpBackfillBatches; i++ {
+		tail := d.blockchain.CheckpointTail()
+		if tail == 0 {
+			return
+		}
+		go p.peer.RequestHeadersByNumber(tail-1, MaxHeaderFetch, 0, true)
+
+		headers, err := d.waitHeaders(p)
+		if err != nil || len(headers) == 0 {
+			p.log.Debug("Header backfill interrupted", "tail", tail, "err", err)
+			return
+		}
+		if _, err := d.blockchain.InsertCheckpointHeaders(headers); err != nil {
+			p.log.Debug("Header backfill failed", "tail", tail, "err", err)
+			return
+		}
+		log.Debug("Backfilled headers below the checkpoint", "count", len(headers), "tail", headers[len(headers)-1].Number)
+	}
+}
+
+// verifyCommits checks that every block carries the commit its header refers
+// to and that the commit finalizes the parent block, returning the index of
+// the first invalid block.
+func verifyCommits(results []*fetchResult) (int, error) {
+	for i, result := range results {
+		header, commit := result.Header, result.Commit
+		if header.LastCommitHash == (common.Hash{}) {
+			continue
+		}
+		if commit == nil || commit.Hash() != header.LastCommitHash {
+			return i, errInvalidCommit
+		}
+		if first := commit.First(); first == nil || first.BlockHash() != header.ParentHash {
+			return i, errInvalidCommit
+		}
+	}
+	return 0, nil
+}
+
+// verifyCommitSignatures checks the signatures of the commits carried by the
+// blocks against the voters of their parent block election, returning the
+// index of the first invalid block. The voters are loaded from the state of
+// the grandparent block, so right above the checkpoint the commits are checked
+// against the voters of the checkpoint state. The blocks whose election state
+// isn't in the local chain yet are checked on import.
+func (d *Downloader) verifyCommitSignatures(results []*fetchResult) (int, error) {
+	chain := &resultsChain{ChainReader: d.blockchain, headers: make(map[common.Hash]*types.Header, len(results))}
+	for _, result := range results {
+		chain.headers[result.Header.Hash()] = result.Header
+	}
+	engine := d.blockchain.Engine()
+	for i, result := range results {
+		parent := chain.GetHeaderByHash(result.Header.ParentHash)
+		if parent == nil {
+			continue
+		}
+		grandparent := d.blockchain.GetHeaderByHash(parent.ParentHash)
+		if grandparent == nil {
+			continue
+		}
+		statedb, err := d.blockchain.StateAt(grandparent.Root)
+		if err != nil {
+			continue
+		}
+		block := types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Commit)
+		if err := engine.VerifyBlockCommit(chain, block, statedb); err != nil {
+			return i, err
+		}
+	}
+	return 0, nil
+}
+
+// resultsChain is the local chain extended with the headers of the downloaded
+// blocks that are not imported yet.
+type resultsChain struct {
+	consensus.ChainReader
+	headers map[common.Hash]*types.Header
+}
+
+// GetHeader retrieves a header from the downloaded blocks or the local chain.
+func (rc *resultsChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header, ok := rc.headers[hash]; ok && header.Number.Uint64() == number {
+		return header
+	}
+	return rc.ChainReader.GetHeader(hash, number)
+}
+
+// GetHeaderByHash retrieves a header from the downloaded blocks or the local
+// chain.
+func (rc *resultsChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	if header, ok := rc.headers[hash]; ok {
+		return header
+	}
+	return rc.ChainReader.GetHeaderByHash(hash)
+}
+
+// fetchHeaderByNumber retrieves a single canonical header from the peer.
+func (d *Downloader) fetchHeaderByNumber(p *peerConnection, number uint64) (*types.Header, error) {
+	go p.peer.RequestHeadersByNumber(number, 1, 0, false)
+
+	headers, err := d.waitHeaders(p)
+	if err != nil {
+		return nil, err
+	}
+	return getHeader(headers, p.id, log.Debug)
+}
+
+// waitHeaders waits for the response of a header request sent to the peer.
+func (d *Downloader) waitHeaders(p *peerConnection) ([]*types.Header, error) {
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return nil, errCancelHeaderFetch
+
+		case packet := <-d.headerCh:
+			// Discard anything not from the origin peer
+			if packet.PeerID() != p.id {
+				log.Debug("Received headers from incorrect peer", "peer", packet.PeerID())
+				break
+			}
+			return packet.(*headerPack).headers, nil
+
+		case <-timeout:
+			p.log.Debug("Waiting for headers timed out", "elapsed", ttl)
+			return nil, errTimeout
+
+		case <-d.bodyCh:
+		case <-d.receiptCh:
+			// Out of bounds delivery, ignore
+		}
+	}
+}
+
+// fetchBlockBody retrieves the body of the given header from the peer and
+// assembles the full block.
+func (d *Downloader) fetchBlockBody(p *peerConnection, header *types.Header) (*types.Block, error) {
+	go p.peer.RequestBodies([]common.Hash{header.Hash()})
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return nil, errCancelBodyFetch
+
+		case packet := <-d.bodyCh:
+			if packet.PeerID() != p.id {
+				log.Debug("Received bodies from incorrect peer", "peer", packet.PeerID())
+				break
+			}
+			bodies := packet.(*bodyPack)
+			if len(bodies.transactions) != 1 || len(bodies.commits) != 1 {
+				return nil, errBadPeer
+			}
+			txs, commit := bodies.transactions[0], bodies.commits[0]
+			if types.DeriveSha(types.Transactions(txs)) != header.TxHash {
+				return nil, errInvalidBody
+			}
+			if _, err := verifyCommits([]*fetchResult{{Header: header, Commit: commit}}); err != nil {
+				return nil, err
+			}
+			return types.NewBlockWithHeader(header).WithBody(txs, commit), nil
+
+		case <-timeout:
+			p.log.Debug("Waiting for block body timed out", "elapsed", ttl)
+			return nil, errTimeout
+
+		case <-d.headerCh:
+		case <-d.receiptCh:
+			// Out of bounds delivery, ignore
+		}
+	}
+}
+
+// fetchBlockReceipts retrieves the receipts of the given header from the peer.
+func (d *Downloader) fetchBlockReceipts(p *peerConnection, header *types.Header) (types.Receipts, error) {
+	go p.peer.RequestReceipts([]common.Hash{header.Hash()})
+
+	ttl := d.requestTTL()
+	timeout := time.After(ttl)
+	for {
+		select {
+		case <-d.cancelCh:
+			return nil, errCancelReceiptFetch
+
+		case packet := <-d.receiptCh:
+			if packet.PeerID() != p.id {
+				log.Debug("Received receipts from incorrect peer", "peer", packet.PeerID())
+				break
+			}
+			receipts := packet.(*receiptPack).receipts
+			if len(receipts) != 1 {
+				return nil, errBadPeer
+			}
+			if types.DeriveSha(types.Receipts(receipts[0])) != header.ReceiptHash {
+				return nil, errInvalidReceipt
+			}
+			return receipts[0], nil
+
+		case <-timeout:
+			p.log.Debug("Waiting for block receipts timed out", "elapsed", ttl)
+			return nil, errTimeout
+
+		case <-d.headerCh:
+		case <-d.bodyCh:
+			// Out of bounds delivery, ignore
+		}
+	}
+}
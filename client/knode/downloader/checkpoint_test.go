@@ -0,0 +1,99 @@
+package downloader
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/consensus/konsensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/knode/genesis"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// newCommitChain generates a chain of blocks carrying the commits of their
+// parent blocks, signed by the given key.
+func newCommitChain(t *testing.T, parent *types.Block, gen *core.Genesis, db kcoindb.Database, n int, key *ecdsa.PrivateKey) []*types.Block {
+	var (
+		signer = types.NewAndromedaSigner(gen.Config.ChainID)
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	blocks, _ := core.GenerateChain(gen.Config, parent, konsensus.New(&params.KonsensusConfig{}), db, n, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(addr)
+		prev := b.PrevBlock(-1)
+		if prev.NumberU64() == 0 {
+			return
+		}
+		vote, err := types.SignVote(types.NewVote(prev.Number(), prev.Hash(), 0, types.PreCommit), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.SetCommit(&types.Commit{PreCommits: types.Votes{vote}, FirstPreCommit: vote})
+	})
+	return blocks
+}
+
+func newFetchResults(blocks []*types.Block) []*fetchResult {
+	results := make([]*fetchResult, len(blocks))
+	for i, block := range blocks {
+		results[i] = &fetchResult{
+			Hash:         block.Hash(),
+			Header:       block.Header(),
+			Commit:       block.LastCommit(),
+			Transactions: block.Transactions(),
+		}
+	}
+	return results
+}
+
+// Tests that the commits downloaded above a checkpoint are checked against the
+// voters of the checkpoint state.
+func TestVerifyCommitSignatures(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	forger, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	opts := genesis.Networks["kusd"][genesis.TestNetwork]
+	consensusOpts := *opts.Consensus
+	consensusOpts.Validators = []genesis.Validator{{Address: addr.Hex(), Deposit: consensusOpts.BaseDeposit}}
+	tokenOpts := *consensusOpts.MiningToken
+	tokenOpts.Holders = []genesis.TokenHolder{{Address: addr.Hex(), NumTokens: consensusOpts.BaseDeposit}}
+	consensusOpts.MiningToken = &tokenOpts
+	opts.Consensus = &consensusOpts
+
+	gen, err := genesis.Generate(opts)
+	if err != nil {
+		t.Fatalf("failed to generate the genesis: %v", err)
+	}
+	gen.Config.AggregateCommitBlock = big.NewInt(0)
+
+	db := kcoindb.NewMemDatabase()
+	blocks := newCommitChain(t, gen.MustCommit(db), gen, db, 4, key)
+	forged := newCommitChain(t, blocks[2], gen, db, 1, forger)
+
+	// the local chain holds the blocks up to the checkpoint
+	chain, err := core.NewBlockChain(db, nil, gen.Config, konsensus.New(&params.KonsensusConfig{}), vm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks[:2]); err != nil {
+		t.Fatalf("failed to insert the checkpoint blocks: %v", err)
+	}
+	d := &Downloader{blockchain: chain}
+
+	if index, err := d.verifyCommitSignatures(newFetchResults(blocks[2:])); err != nil {
+		t.Fatalf("valid commit at %d rejected: %v", index, err)
+	}
+	results := newFetchResults(append(blocks[2:3:3], forged...))
+	if _, err := verifyCommits(results); err != nil {
+		t.Fatalf("forged commit doesn't match the header: %v", err)
+	}
+	if index, err := d.verifyCommitSignatures(results); err != types.ErrUnknownCommitVoter || index != 1 {
+		t.Fatalf("forged commit mismatch: have %d/%v, want %d/%v", index, err, 1, types.ErrUnknownCommitVoter)
+	}
+}
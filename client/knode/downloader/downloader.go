@@ -11,7 +11,9 @@ import (
 
 	kcoin "github.com/kowala-tech/kcoin/client"
 	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/consensus"
 	"github.com/kowala-tech/kcoin/client/core/rawdb"
+	"github.com/kowala-tech/kcoin/client/core/state"
 	"github.com/kowala-tech/kcoin/client/core/types"
 	"github.com/kowala-tech/kcoin/client/event"
 	"github.com/kowala-tech/kcoin/client/kcoindb"
@@ -49,6 +51,8 @@ var (
 	fsHeaderForceVerify    = 24              // Number of headers to verify before and after the pivot to accept it
 	fsHeaderContCheck      = 3 * time.Second // Time interval to check for header continuations during state download
 	fsMinFullBlocks        = 64              // Number of blocks to retrieve fully even in fast sync
+
+	cpBackfillBatches = 8 // Number of header batches to backfill below the checkpoint per sync cycle
 )
 
 var (
@@ -74,8 +78,21 @@ var (
 	errCancelHeaderProcessing  = errors.New("header processing canceled (requested)")
 	errCancelContentProcessing = errors.New("content processing canceled (requested)")
 	errNoSyncActive            = errors.New("no sync active")
+	errNoCheckpoint            = errors.New("checkpoint sync requires a trusted checkpoint")
+	errBehindCheckpoint        = errors.New("peer is behind the checkpoint")
+	errInvalidCommit           = errors.New("retrieved block commit is invalid")
 )
 
+// ProtocolViolation reports whether a peer was dropped for sending invalid or
+// inconsistent data, as opposed to being slow or timing out.
+func ProtocolViolation(reason error) bool {
+	switch reason {
+	case errBadPeer, errEmptyHeaderSet, errInvalidAncestor, errInvalidChain:
+		return true
+	}
+	return false
+}
+
 type Downloader struct {
 	mode SyncMode       // Synchronisation mode defining the strategy used (per sync cycle)
 	mux  *event.TypeMux // Event multiplexer to announce sync operation events
@@ -84,6 +101,8 @@ type Downloader struct {
 	peers   *peerSet // Set of active peers from which download can proceed
 	stateDB kcoindb.Database
 
+	checkpoint *params.TrustedCheckpoint // Trusted finalized block to start a checkpoint sync from
+
 	rttEstimate   uint64 // Round trip time to target for download requests
 	rttConfidence uint64 // Confidence in the estimated RTT (unit: millionths to allow atomic ops)
 
@@ -155,6 +174,13 @@ type LightChain interface {
 // BlockChain encapsulates functions required to sync a (full or fast) blockchain.
 type BlockChain interface {
 	LightChain
+	consensus.ChainReader
+
+	// Engine retrieves the consensus engine verifying the block commits.
+	Engine() consensus.Engine
+
+	// StateAt retrieves the state at the given root from the local chain.
+	StateAt(common.Hash) (*state.StateDB, error)
 
 	// HasBlock verifies a block's presence in the local chain.
 	HasBlock(common.Hash, uint64) bool
@@ -171,6 +197,15 @@ type BlockChain interface {
 	// FastSyncCommitHead directly commits the head block to a certain entity.
 	FastSyncCommitHead(common.Hash) error
 
+	// InsertCheckpoint commits a trusted checkpoint block as the head block.
+	InsertCheckpoint(*types.Block, types.Receipts) error
+
+	// CheckpointTail retrieves the lowest block with a known header after a checkpoint sync.
+	CheckpointTail() uint64
+
+	// InsertCheckpointHeaders backfills the headers below the checkpoint tail.
+	InsertCheckpointHeaders([]*types.Header) (int, error)
+
 	// InsertChain inserts a batch of blocks into the local chain.
 	InsertChain(types.Blocks) (int, error)
 
@@ -179,13 +214,14 @@ type BlockChain interface {
 }
 
 // New creates a new downloader to fetch hashes and blocks from remote peers.
-func New(mode SyncMode, stateDb kcoindb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
+func New(mode SyncMode, checkpoint *params.TrustedCheckpoint, stateDb kcoindb.Database, mux *event.TypeMux, chain BlockChain, lightchain LightChain, dropPeer peerDropFn) *Downloader {
 	if lightchain == nil {
 		lightchain = chain
 	}
 
 	dl := &Downloader{
 		mode:           mode,
+		checkpoint:     checkpoint,
 		stateDB:        stateDb,
 		mux:            mux,
 		queue:          newQueue(),
@@ -230,7 +266,7 @@ func (d *Downloader) Progress() kcoin.SyncProgress {
 	switch d.mode {
 	case FullSync:
 		current = d.blockchain.CurrentBlock().NumberU64()
-	case FastSync:
+	case FastSync, CheckpointSync:
 		current = d.blockchain.CurrentFastBlock().NumberU64()
 	case LightSync:
 		current = d.lightchain.CurrentHeader().Number.Uint64()
@@ -309,7 +345,7 @@ func (d *Downloader) Synchronise(id string, head common.Hash, blockNumber *big.I
 			// Timeouts can occur if e.g. compaction hits at the wrong time, and can be ignored
 			log.Warn("Downloader wants to drop peer, but peerdrop-function is not set", "peer", id)
 		} else {
-			d.dropPeer(id)
+			d.dropPeer(id, err)
 		}
 	default:
 		log.Warn("Synchronisation failed, retrying", "err", err)
@@ -370,7 +406,9 @@ func (d *Downloader) synchronise(id string, hash common.Hash, blockNumber *big.I
 	defer d.Cancel() // No matter what, we can't leave the cancel channel open
 
 	// Set the requested sync mode, unless it's forbidden
+	d.syncStatsLock.Lock()
 	d.mode = mode
+	d.syncStatsLock.Unlock()
 
 	// Retrieve the origin peer and initiate the downloading process
 	p := d.peers.Peer(id)
@@ -405,6 +443,16 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, blockNumb
 	}
 	height := latest.Number.Uint64()
 
+	// Start from the trusted checkpoint if requested, syncing in full afterwards
+	if d.mode == CheckpointSync {
+		if err := d.syncCheckpoint(p, latest); err != nil {
+			return err
+		}
+		d.syncStatsLock.Lock()
+		d.mode = FullSync
+		d.syncStatsLock.Unlock()
+	}
+
 	origin, err := d.findAncestor(p, height)
 	if err != nil {
 		return err
@@ -449,7 +497,12 @@ func (d *Downloader) syncWithPeer(p *peerConnection, hash common.Hash, blockNumb
 	} else if d.mode == FullSync {
 		fetchers = append(fetchers, d.processFullSyncContent)
 	}
-	return d.spawnSync(fetchers)
+	if err := d.spawnSync(fetchers); err != nil {
+		return err
+	}
+	// Lazily fill in the headers skipped by a checkpoint sync
+	d.backfillHeaders(p)
+	return nil
 }
 
 // spawnSync runs d.process and all given fetcher functions to completion in
@@ -849,7 +902,7 @@ func (d *Downloader) fetchHeaders(p *peerConnection, from uint64, pivot uint64)
 			// Header retrieval timed out, consider the peer bad and drop
 			p.log.Debug("Header request timed out", "elapsed", ttl)
 			headerTimeoutMeter.Mark(1)
-			d.dropPeer(p.id)
+			d.dropPeer(p.id, errTimeout)
 
 			// Finish the sync gracefully instead of dumping the gathered data though
 			for _, ch := range []chan bool{d.bodyWakeCh, d.receiptWakeCh} {
@@ -1069,7 +1122,7 @@ func (d *Downloader) fetchParts(errCancel error, deliveryCh chan dataPack, deliv
 							// Timeouts can occur if e.g. compaction hits at the wrong time, and can be ignored
 							peer.log.Warn("Downloader wants to drop peer, but peerdrop-function is not set", "peer", pid)
 						} else {
-							d.dropPeer(pid)
+							d.dropPeer(pid, errStallingPeer)
 						}
 					}
 				}
@@ -1335,6 +1388,16 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 		"firstnum", first.Number, "firsthash", first.Hash(),
 		"lastnum", last.Number, "lasthash", last.Hash(),
 	)
+	if d.checkpoint != nil {
+		if index, err := verifyCommits(results); err != nil {
+			log.Debug("Downloaded block commit invalid", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
+			return errInvalidChain
+		}
+		if index, err := d.verifyCommitSignatures(results); err != nil {
+			log.Debug("Downloaded block commit signature invalid", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
+			return errInvalidChain
+		}
+	}
 	blocks := make([]*types.Block, len(results))
 	for i, result := range results {
 		blocks[i] = types.NewBlockWithHeader(result.Header).WithBody(result.Transactions, result.Commit)
@@ -6,8 +6,9 @@ import (
 	"github.com/kowala-tech/kcoin/client/core/types"
 )
 
-// peerDropFn is a callback type for dropping a peer detected as malicious.
-type peerDropFn func(id string)
+// peerDropFn is a callback type for dropping a peer, along with the reason it's
+// dropped for: a protocol violation or a timeout.
+type peerDropFn func(id string, reason error)
 
 // dataPack is a data message returned by a peer for some query.
 type dataPack interface {
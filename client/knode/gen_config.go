@@ -11,6 +11,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/core"
 	"github.com/kowala-tech/kcoin/client/knode/downloader"
 	"github.com/kowala-tech/kcoin/client/knode/gasprice"
+	"github.com/kowala-tech/kcoin/client/params"
 )
 
 var _ = (*configMarshaling)(nil)
@@ -22,20 +23,26 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NetworkId               uint64
 		SyncMode                downloader.SyncMode
 		NoPruning               bool
-		LightServ               int  `toml:",omitempty"`
-		LightPeers              int  `toml:",omitempty"`
-		SkipBcVersionCheck      bool `toml:"-"`
-		DatabaseHandles         int  `toml:"-"`
+		StateRetention          uint64                    `toml:",omitempty"`
+		Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"`
+		LightServ               int                       `toml:",omitempty"`
+		LightPeers              int                       `toml:",omitempty"`
+		SkipBcVersionCheck      bool                      `toml:"-"`
+		DatabaseHandles         int                       `toml:"-"`
 		DatabaseCache           int
+		DatabaseFreezer         string
 		TrieCache               int
 		TrieTimeout             time.Duration
 		Coinbase                common.Address `toml:",omitempty"`
 		Deposit                 *big.Int       `toml:",omitempty"`
 		ExtraData               hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
+		ConsensusKey            common.Address `toml:",omitempty"`
+		ValidatorShadow         bool           `toml:",omitempty"`
 		TxPool                  core.TxPoolConfig
 		GPO                     gasprice.Config
 		EnablePreimageRecording bool
+		ParallelWorkers         int    `toml:",omitempty"`
 		DocRoot                 string `toml:"-"`
 		Currency                string
 	}
@@ -44,20 +51,26 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NetworkId = c.NetworkId
 	enc.SyncMode = c.SyncMode
 	enc.NoPruning = c.NoPruning
+	enc.StateRetention = c.StateRetention
+	enc.Checkpoint = c.Checkpoint
 	enc.LightServ = c.LightServ
 	enc.LightPeers = c.LightPeers
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
+	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.TrieCache = c.TrieCache
 	enc.TrieTimeout = c.TrieTimeout
 	enc.Coinbase = c.Coinbase
 	enc.Deposit = c.Deposit
 	enc.ExtraData = c.ExtraData
 	enc.GasPrice = c.GasPrice
+	enc.ConsensusKey = c.ConsensusKey
+	enc.ValidatorShadow = c.ValidatorShadow
 	enc.TxPool = c.TxPool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
+	enc.ParallelWorkers = c.ParallelWorkers
 	enc.DocRoot = c.DocRoot
 	enc.Currency = c.Currency
 	return &enc, nil
@@ -70,20 +83,26 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		NetworkId               *uint64
 		SyncMode                *downloader.SyncMode
 		NoPruning               *bool
-		LightServ               *int  `toml:",omitempty"`
-		LightPeers              *int  `toml:",omitempty"`
-		SkipBcVersionCheck      *bool `toml:"-"`
-		DatabaseHandles         *int  `toml:"-"`
+		StateRetention          *uint64                   `toml:",omitempty"`
+		Checkpoint              *params.TrustedCheckpoint `toml:",omitempty"`
+		LightServ               *int                      `toml:",omitempty"`
+		LightPeers              *int                      `toml:",omitempty"`
+		SkipBcVersionCheck      *bool                     `toml:"-"`
+		DatabaseHandles         *int                      `toml:"-"`
 		DatabaseCache           *int
+		DatabaseFreezer         *string
 		TrieCache               *int
 		TrieTimeout             *time.Duration
 		Coinbase                *common.Address `toml:",omitempty"`
 		Deposit                 *big.Int        `toml:",omitempty"`
 		ExtraData               *hexutil.Bytes  `toml:",omitempty"`
 		GasPrice                *big.Int
+		ConsensusKey            *common.Address `toml:",omitempty"`
+		ValidatorShadow         *bool           `toml:",omitempty"`
 		TxPool                  *core.TxPoolConfig
 		GPO                     *gasprice.Config
 		EnablePreimageRecording *bool
+		ParallelWorkers         *int    `toml:",omitempty"`
 		DocRoot                 *string `toml:"-"`
 		Currency                *string
 	}
@@ -103,6 +122,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.NoPruning != nil {
 		c.NoPruning = *dec.NoPruning
 	}
+	if dec.StateRetention != nil {
+		c.StateRetention = *dec.StateRetention
+	}
+	if dec.Checkpoint != nil {
+		c.Checkpoint = dec.Checkpoint
+	}
 	if dec.LightServ != nil {
 		c.LightServ = *dec.LightServ
 	}
@@ -118,6 +143,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseCache != nil {
 		c.DatabaseCache = *dec.DatabaseCache
 	}
+	if dec.DatabaseFreezer != nil {
+		c.DatabaseFreezer = *dec.DatabaseFreezer
+	}
 	if dec.TrieCache != nil {
 		c.TrieCache = *dec.TrieCache
 	}
@@ -136,6 +164,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.GasPrice != nil {
 		c.GasPrice = dec.GasPrice
 	}
+	if dec.ConsensusKey != nil {
+		c.ConsensusKey = *dec.ConsensusKey
+	}
+	if dec.ValidatorShadow != nil {
+		c.ValidatorShadow = *dec.ValidatorShadow
+	}
 	if dec.TxPool != nil {
 		c.TxPool = *dec.TxPool
 	}
@@ -145,6 +179,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.EnablePreimageRecording != nil {
 		c.EnablePreimageRecording = *dec.EnablePreimageRecording
 	}
+	if dec.ParallelWorkers != nil {
+		c.ParallelWorkers = *dec.ParallelWorkers
+	}
 	if dec.DocRoot != nil {
 		c.DocRoot = *dec.DocRoot
 	}
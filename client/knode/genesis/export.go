@@ -0,0 +1,210 @@
+package genesis
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+	"github.com/kowala-tech/kcoin/client/common/kns"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm/runtime"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+// maxRegistrySlot is the number of storage slots of the validator manager
+// searched for the validator registry mapping. The second slot of a registry
+// entry packs the isValidator and isGenesis flags, in its first and second
+// bytes.
+const maxRegistrySlot = 64
+
+// Export writes the state at the given header as a genesis loadable by the
+// init command, along with the chain configuration. The accounts are written
+// one at a time, so that the state doesn't need to fit into memory.
+//
+// The validators registered at the given header become the genesis validators
+// of the new chain. Their addresses are returned.
+func Export(w io.Writer, config *params.ChainConfig, header *types.Header, statedb *state.StateDB) ([]common.Address, error) {
+	mgr, validators, err := currentValidators(config, header, statedb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the validators: %v", err)
+	}
+	overrides, err := genesisOverrides(config, header, statedb, mgr, validators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark the genesis validators: %v", err)
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "{\n")
+	fmt.Fprintf(out, "  \"config\": %s,\n", configJSON)
+	fmt.Fprintf(out, "  \"timestamp\": \"%s\",\n", hexutil.EncodeUint64(header.Time.Uint64()))
+	fmt.Fprintf(out, "  \"extraData\": \"%s\",\n", hexutil.Bytes(header.Extra))
+	fmt.Fprintf(out, "  \"gasLimit\": \"%s\",\n", hexutil.EncodeUint64(header.GasLimit))
+	fmt.Fprintf(out, "  \"coinbase\": \"%s\",\n", header.Coinbase.Hex())
+	fmt.Fprintf(out, "  \"alloc\": {")
+
+	first := true
+	err = statedb.IterativeDump(func(addr common.Address, data state.Account, code []byte, storage map[common.Hash]common.Hash) error {
+		if addr == mgr {
+			for slot, value := range overrides {
+				storage[slot] = value
+			}
+		}
+		account := core.GenesisAccount{
+			Code:    code,
+			Balance: data.Balance,
+			Nonce:   data.Nonce,
+		}
+		if len(storage) > 0 {
+			account.Storage = storage
+		}
+		accountJSON, err := json.Marshal(account)
+		if err != nil {
+			return err
+		}
+		if !first {
+			fmt.Fprintf(out, ",")
+		}
+		first = false
+
+		_, err = fmt.Fprintf(out, "\n    \"%x\": %s", addr, accountJSON)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(out, "\n  }\n}\n")
+
+	return validators, out.Flush()
+}
+
+// currentValidators returns the address of the validator manager along with
+// the validators registered in the given state.
+func currentValidators(config *params.ChainConfig, header *types.Header, statedb *state.StateDB) (common.Address, []common.Address, error) {
+	caller := &stateCaller{config: config, header: header, statedb: statedb}
+
+	mgr, err := kns.GetAddressFromDomain(params.KNSDomains[params.ValidatorMgrDomain].FullDomain(), caller)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	manager, err := consensus.NewValidatorMgrCaller(mgr, caller)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	count, err := manager.GetValidatorCount(&bind.CallOpts{})
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	validators := make([]common.Address, 0, count.Uint64())
+	for i := int64(0); i < count.Int64(); i++ {
+		validator, err := manager.GetValidatorAtIndex(&bind.CallOpts{}, big.NewInt(i))
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+		validators = append(validators, validator.Code)
+	}
+	return mgr, validators, nil
+}
+
+// genesisOverrides returns the storage of the validator manager to overwrite
+// so that the given validators are genesis validators. The validators that
+// aren't yet are marked in a copy of the state, and checked through the
+// validator manager against it.
+func genesisOverrides(config *params.ChainConfig, header *types.Header, statedb *state.StateDB, mgr common.Address, validators []common.Address) (map[common.Hash]common.Hash, error) {
+	marked := statedb.Copy()
+	manager, err := consensus.NewValidatorMgrCaller(mgr, &stateCaller{config: config, header: header, statedb: marked})
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[common.Hash]common.Hash)
+	for _, validator := range validators {
+		genesis, err := manager.IsGenesisValidator(&bind.CallOpts{}, validator)
+		if err != nil {
+			return nil, err
+		}
+		if genesis {
+			continue
+		}
+		slot, err := markGenesisValidator(manager, marked, mgr, validator)
+		if err != nil {
+			return nil, err
+		}
+		overrides[slot] = marked.GetState(mgr, slot)
+	}
+	return overrides, nil
+}
+
+// markGenesisValidator sets the genesis flag of a validator in the state. The
+// registry slot is unknown to the bindings, the candidate slots are tried until
+// the validator manager reports the validator as a genesis validator.
+func markGenesisValidator(manager *consensus.ValidatorMgrCaller, statedb *state.StateDB, mgr common.Address, validator common.Address) (common.Hash, error) {
+	for i := int64(0); i < maxRegistrySlot; i++ {
+		slot := validatorFlagsSlot(validator, i)
+		flags := statedb.GetState(mgr, slot)
+		if flags[common.HashLength-1] != 1 {
+			continue
+		}
+		genesisFlags := flags
+		genesisFlags[common.HashLength-2] = 1
+		statedb.SetState(mgr, slot, genesisFlags)
+
+		if genesis, err := manager.IsGenesisValidator(&bind.CallOpts{}, validator); err == nil && genesis {
+			return slot, nil
+		}
+		statedb.SetState(mgr, slot, flags)
+	}
+	return common.Hash{}, fmt.Errorf("registry entry of validator %x not found", validator)
+}
+
+// validatorFlagsSlot returns the storage slot holding the flags of a validator
+// in a registry mapping at the given slot.
+func validatorFlagsSlot(validator common.Address, registrySlot int64) common.Hash {
+	entry := crypto.Keccak256Hash(
+		common.LeftPadBytes(validator[:], common.HashLength),
+		common.LeftPadBytes(big.NewInt(registrySlot).Bytes(), common.HashLength),
+	)
+	return common.BigToHash(new(big.Int).Add(entry.Big(), common.Big1))
+}
+
+// stateCaller is a contract caller executing the calls against a state, without
+// modifying it.
+type stateCaller struct {
+	config  *params.ChainConfig
+	header  *types.Header
+	statedb *state.StateDB
+}
+
+// CodeAt returns the code of the given account in the state.
+func (c *stateCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return c.statedb.GetCode(contract), nil
+}
+
+// CallContract executes a contract call against a copy of the state.
+func (c *stateCaller) CallContract(ctx context.Context, call kowala.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil {
+		return nil, fmt.Errorf("contract creation not supported")
+	}
+	ret, _, err := runtime.Call(*call.To, call.Data, &runtime.Config{
+		ChainConfig: c.config,
+		Origin:      call.From,
+		Coinbase:    c.header.Coinbase,
+		BlockNumber: c.header.Number,
+		Time:        c.header.Time,
+		State:       c.statedb.Copy(),
+	})
+	return ret, err
+}
@@ -0,0 +1,99 @@
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/state"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/kcoindb"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	generated, err := Generate(Networks["kusd"][TestNetwork])
+	require.NoError(t, err)
+
+	db := kcoindb.NewMemDatabase()
+	block := generated.MustCommit(db)
+
+	statedb, err := state.New(block.Root(), state.NewDatabase(db))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	validators, err := Export(&out, generated.Config, block.Header(), statedb)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{common.HexToAddress("0x2429f4aa5cf9d23fea0961780ffb4ff8916a26a0")}, validators)
+
+	exported := new(core.Genesis)
+	require.NoError(t, json.Unmarshal(out.Bytes(), exported))
+	assert.Equal(t, block.Hash(), exported.ToBlock(nil).Hash())
+}
+
+func TestExportGenesisValidators(t *testing.T) {
+	generated, err := Generate(Networks["kusd"][TestNetwork])
+	require.NoError(t, err)
+
+	db := kcoindb.NewMemDatabase()
+	block := generated.MustCommit(db)
+
+	// Turn the genesis validator into a regular one, as if it had joined later
+	statedb, err := state.New(block.Root(), state.NewDatabase(db))
+	require.NoError(t, err)
+
+	mgr, validators, err := currentValidators(generated.Config, block.Header(), statedb)
+	require.NoError(t, err)
+	require.Len(t, validators, 1)
+
+	// The registry is the ninth state variable of the validator manager
+	slot := validatorFlagsSlot(validators[0], 8)
+	flags := statedb.GetState(mgr, slot)
+	require.Equal(t, byte(1), flags[common.HashLength-2])
+
+	flags[common.HashLength-2] = 0
+	statedb.SetState(mgr, slot, flags)
+	root, err := statedb.Commit(true)
+	require.NoError(t, err)
+	require.NoError(t, statedb.Database().TrieDB().Commit(root, true))
+
+	statedb, err = state.New(root, state.NewDatabase(db))
+	require.NoError(t, err)
+	require.False(t, isGenesisValidator(t, generated.Config, block, statedb, mgr, validators[0]))
+
+	var out bytes.Buffer
+	_, err = Export(&out, generated.Config, block.Header(), statedb)
+	require.NoError(t, err)
+
+	exported := new(core.Genesis)
+	require.NoError(t, json.Unmarshal(out.Bytes(), exported))
+	assert.Equal(t, block.Root(), exported.ToBlock(nil).Root())
+
+	// The exported state registers the validator as a genesis validator
+	exportedDb := kcoindb.NewMemDatabase()
+	exportedBlock := exported.MustCommit(exportedDb)
+	exportedState, err := state.New(exportedBlock.Root(), state.NewDatabase(exportedDb))
+	require.NoError(t, err)
+	assert.True(t, isGenesisValidator(t, exported.Config, exportedBlock, exportedState, mgr, validators[0]))
+}
+
+// isGenesisValidator reports whether the validator manager registers the
+// validator as a genesis validator in the given state.
+func isGenesisValidator(t *testing.T, config *params.ChainConfig, block *types.Block, statedb *state.StateDB, mgr common.Address, validator common.Address) bool {
+	manager, err := consensus.NewValidatorMgrCaller(mgr, &stateCaller{config: config, header: block.Header(), statedb: statedb})
+	require.NoError(t, err)
+
+	isValidator, err := manager.IsValidator(&bind.CallOpts{}, validator)
+	require.NoError(t, err)
+	require.True(t, isValidator)
+
+	genesis, err := manager.IsGenesisValidator(&bind.CallOpts{}, validator)
+	require.NoError(t, err)
+	return genesis
+}
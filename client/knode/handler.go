@@ -35,6 +35,16 @@ const (
 	txChanSize = 4096
 )
 
+// Peer score penalties for misbehaviour. Peers get banned by the p2p server
+// once their score falls below its threshold (-100 by default).
+const (
+	invalidProposalPenalty = -20 // Proposal rejected by the validator
+	invalidVotePenalty     = -20 // Vote with an invalid signature or from a non-voter
+	invalidFragmentPenalty = -10 // Block fragment that failed to add, assemble or verify
+	invalidBlockPenalty    = -50 // Propagated block or header rejected by the fetcher
+	syncFailurePenalty     = -25 // Invalid chain or inconsistent data during synchronisation
+)
+
 // errIncompatibleConfig is returned if the requested protocols and configs are
 // not compatible (low protocol version restrictions and high requirements).
 var errIncompatibleConfig = errors.New("incompatible configuration")
@@ -46,8 +56,9 @@ func errResp(code errCode, format string, v ...interface{}) error {
 type ProtocolManager struct {
 	networkID uint64
 
-	fastSync  uint32 // Flag whether fast sync is enabled (gets disabled if we already have blocks)
-	acceptTxs uint32 // Flag whether we're considered synchronised (enables transaction processing)
+	fastSync       uint32 // Flag whether fast sync is enabled (gets disabled if we already have blocks)
+	checkpointSync uint32 // Flag whether checkpoint sync is enabled (gets disabled once past the checkpoint)
+	acceptTxs      uint32 // Flag whether we're considered synchronised (enables transaction processing)
 
 	txpool      txPool
 	blockchain  *core.BlockChain
@@ -80,7 +91,7 @@ type ProtocolManager struct {
 
 // NewProtocolManager returns a new kowala sub protocol manager. The Kowala sub protocol manages peers capable
 // with the kowala network.
-func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, networkID uint64, mux *event.TypeMux, txpool txPool, engine consensus.Engine, blockchain *core.BlockChain, chaindb kcoindb.Database, validator validator.Validator) (*ProtocolManager, error) {
+func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, checkpoint *params.TrustedCheckpoint, networkID uint64, mux *event.TypeMux, txpool txPool, engine consensus.Engine, blockchain *core.BlockChain, chaindb kcoindb.Database, validator validator.Validator) (*ProtocolManager, error) {
 	// Create the protocol manager with the base fields
 	manager := &ProtocolManager{
 		networkID:   networkID,
@@ -103,6 +114,14 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 	if mode == downloader.FastSync {
 		manager.fastSync = uint32(1)
 	}
+	// Checkpoint sync only makes sense while the chain is below the checkpoint
+	if mode == downloader.CheckpointSync && blockchain.CurrentBlock().NumberU64() >= checkpoint.Number {
+		log.Warn("Blockchain past the checkpoint, checkpoint sync disabled")
+		mode = downloader.FullSync
+	}
+	if mode == downloader.CheckpointSync {
+		manager.checkpointSync = uint32(1)
+	}
 	// Initiate a sub-protocol for every implemented version we can handle
 	manager.SubProtocols = make([]p2p.Protocol, 0, len(protocol.Constants.Versions))
 	for i, version := range protocol.Constants.Versions {
@@ -138,7 +157,7 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		return nil, errIncompatibleConfig
 	}
 	// Construct the different synchronisation mechanisms
-	manager.downloader = downloader.New(mode, chaindb, manager.eventMux, blockchain, nil, manager.removePeer)
+	manager.downloader = downloader.New(mode, checkpoint, chaindb, manager.eventMux, blockchain, nil, manager.syncFailureRemover)
 
 	verifyHeader := func(header *types.Header) error {
 		return engine.VerifyHeader(blockchain, header, true)
@@ -147,15 +166,15 @@ func NewProtocolManager(config *params.ChainConfig, mode downloader.SyncMode, ne
 		return blockchain.CurrentBlock().NumberU64()
 	}
 	inserter := func(blocks types.Blocks) (int, error) {
-		// If fast sync is running, deny importing weird blocks
-		if atomic.LoadUint32(&manager.fastSync) == 1 {
+		// If fast or checkpoint sync is running, deny importing weird blocks
+		if atomic.LoadUint32(&manager.fastSync) == 1 || atomic.LoadUint32(&manager.checkpointSync) == 1 {
 			log.Warn("Discarded bad propagated block", "number", blocks[0].Number(), "hash", blocks[0].Hash())
 			return 0, nil
 		}
 		atomic.StoreUint32(&manager.acceptTxs, 1) // Mark initial sync done on any fetcher import
 		return manager.blockchain.InsertChain(blocks)
 	}
-	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, verifyHeader, manager.BroadcastBlock, heighter, inserter, manager.removePeer)
+	manager.fetcher = fetcher.New(blockchain.GetBlockByHash, verifyHeader, manager.BroadcastBlock, heighter, inserter, manager.penalisingRemover(invalidBlockPenalty, "invalid block"))
 
 	return manager, nil
 }
@@ -179,6 +198,27 @@ func (pm *ProtocolManager) removePeer(id string) {
 	}
 }
 
+// penalisingRemover returns a peer drop callback for the synchronisation
+// mechanisms, which lowers the score of the peer before removing it.
+func (pm *ProtocolManager) penalisingRemover(penalty int, reason string) func(id string) {
+	return func(id string) {
+		if peer := pm.peers.Peer(id); peer != nil {
+			peer.AdjustScore(penalty, reason)
+		}
+		pm.removePeer(id)
+	}
+}
+
+// syncFailureRemover is the peer drop callback of the downloader. Only the peers
+// violating the protocol are penalised, the slow ones are simply removed.
+func (pm *ProtocolManager) syncFailureRemover(id string, reason error) {
+	if downloader.ProtocolViolation(reason) {
+		pm.penalisingRemover(syncFailurePenalty, reason.Error())(id)
+		return
+	}
+	pm.removePeer(id)
+}
+
 func (pm *ProtocolManager) Start(maxPeers int) {
 	pm.maxPeers = maxPeers
 
@@ -632,7 +672,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		p.MarkProposal(proposal.Hash())
 
 		if err := pm.validator.AddProposal(&proposal); err != nil {
-			// ignore
+			if err != validator.ErrCantAddProposalNotValidating {
+				p.AdjustScore(invalidProposalPenalty, err.Error())
+			}
 			break
 		}
 
@@ -649,7 +691,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		p.MarkVote(vote.Hash())
 
 		if err := pm.validator.AddVote(&vote); err != nil {
-			// ignore
+			if err != validator.ErrCantVoteNotValidating {
+				p.AdjustScore(invalidVotePenalty, err.Error())
+			}
 			break
 		}
 
@@ -668,7 +712,9 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		if err := pm.validator.AddBlockFragment(request.BlockNumber, request.Round, request.Data); err != nil {
 			log.Error("error while adding a new block fragment", "err", err, "round", request.Round, "block", request.BlockNumber, "fragment", request.Data)
-			// ignore
+			if err != validator.ErrCantAddBlockFragmentNotValidating {
+				p.AdjustScore(invalidFragmentPenalty, err.Error())
+			}
 			break
 		}
 
@@ -25,6 +25,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/core/rawdb"
 	"github.com/kowala-tech/kcoin/client/core/types"
 	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
 	"github.com/kowala-tech/kcoin/client/event"
 	"github.com/kowala-tech/kcoin/client/internal/kcoinapi"
 	"github.com/kowala-tech/kcoin/client/kcoindb"
@@ -37,6 +38,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/node"
 	"github.com/kowala-tech/kcoin/client/p2p"
 	"github.com/kowala-tech/kcoin/client/p2p/discv5"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/params"
 	"github.com/kowala-tech/kcoin/client/rlp"
 	"github.com/kowala-tech/kcoin/client/rpc"
@@ -77,12 +79,15 @@ type Kowala struct {
 	bindingFuncs []BindingConstructor // binding constructors (in dependency order)
 	contracts    map[reflect.Type]bindings.Binding
 
-	gasPrice *big.Int
-	coinbase common.Address
-	deposit  *big.Int
+	gasPrice     *big.Int
+	coinbase     common.Address
+	consensusKey common.Address // signs the proposals and votes, the coinbase if unset
+	deposit      *big.Int
+	shadow       bool // whether the validator runs in shadow mode
 
 	networkID     uint64
 	netRPCService *kcoinapi.PublicNetAPI
+	p2pServer     *p2p.Server // set on start, used to advertise the node capabilities
 
 	lock       sync.RWMutex // Protects the variadic fields (e.g. gas price and coinbase)
 	serverPool *serverPool
@@ -107,6 +112,14 @@ func New(ctx *node.ServiceContext, config *Config) (*Kowala, error) {
 	}
 	log.Info("Initialised chain configuration", "config", chainConfig)
 
+	checkpoint := config.Checkpoint
+	if checkpoint == nil {
+		checkpoint = params.TrustedCheckpoints[genesisHash]
+	}
+	if config.SyncMode == downloader.CheckpointSync && checkpoint == nil {
+		return nil, errors.New("checkpoint sync requires a trusted checkpoint")
+	}
+
 	kcoin := &Kowala{
 		config:         config,
 		chainDb:        chainDb,
@@ -117,6 +130,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Kowala, error) {
 		networkID:      config.NetworkId,
 		gasPrice:       config.GasPrice,
 		coinbase:       config.Coinbase,
+		consensusKey:   config.ConsensusKey,
 		deposit:        config.Deposit,
 		bloomRequests:  make(chan chan *bloombits.Retrieval),
 		bloomIndexer:   NewBloomIndexer(chainDb, params.BloomBitsBlocks),
@@ -143,8 +157,8 @@ func New(ctx *node.ServiceContext, config *Config) (*Kowala, error) {
 		rawdb.WriteDatabaseVersion(chainDb, core.BlockChainVersion)
 	}
 
-	vmConfig := vm.Config{EnablePreimageRecording: config.EnablePreimageRecording}
-	cacheConfig := &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout}
+	vmConfig := vm.Config{EnablePreimageRecording: config.EnablePreimageRecording, ParallelWorkers: config.ParallelWorkers}
+	cacheConfig := &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout, StateRetention: config.StateRetention}
 	kcoin.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, kcoin.chainConfig, kcoin.engine, vmConfig)
 	if err != nil {
 		return nil, err
@@ -198,8 +212,17 @@ func New(ctx *node.ServiceContext, config *Config) (*Kowala, error) {
 
 	kcoin.validator = validator.New(kcoin, kcoin.consensus, kcoin.chainConfig, kcoin.EventMux(), kcoin.engine, vmConfig)
 	kcoin.validator.SetExtra(makeExtraData(config.ExtraData))
+	aggregateKey, err := loadAggregateKey(ctx.ResolvePath(datadirAggregateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the aggregate key: %v", err)
+	}
+	kcoin.validator.SetAggregateKey(aggregateKey)
+	if config.ValidatorShadow {
+		kcoin.shadow = true
+		kcoin.validator.SetShadow(true)
+	}
 
-	if kcoin.protocolManager, err = NewProtocolManager(kcoin.chainConfig, config.SyncMode, config.NetworkId, kcoin.eventMux, kcoin.txPool, kcoin.engine, kcoin.blockchain, chainDb, kcoin.validator); err != nil {
+	if kcoin.protocolManager, err = NewProtocolManager(kcoin.chainConfig, config.SyncMode, checkpoint, config.NetworkId, kcoin.eventMux, kcoin.txPool, kcoin.engine, kcoin.blockchain, chainDb, kcoin.validator); err != nil {
 		return nil, err
 	}
 
@@ -227,13 +250,10 @@ func makeExtraData(extra []byte) []byte {
 
 // CreateDB creates the chain database.
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) (kcoindb.Database, error) {
-	db, err := ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
+	db, err := ctx.OpenDatabaseWithFreezer(name, config.DatabaseCache, config.DatabaseHandles, config.DatabaseFreezer, "kcoin/db/chaindata/")
 	if err != nil {
 		return nil, err
 	}
-	if db, ok := db.(*kcoindb.LDBDatabase); ok {
-		db.Meter("kcoin/db/chaindata/")
-	}
 	return db, nil
 }
 
@@ -344,8 +364,44 @@ func (s *Kowala) SetCoinbase(coinbase common.Address) {
 	}
 }
 
+// ConsensusKey returns the account signing the proposals and votes of the
+// validator.
+func (s *Kowala) ConsensusKey() (common.Address, error) {
+	s.lock.RLock()
+	key := s.consensusKey
+	s.lock.RUnlock()
+
+	if key != (common.Address{}) {
+		return key, nil
+	}
+	return s.Coinbase()
+}
+
+// SetConsensusKey sets the account signing the proposals and votes of the
+// validator. The key is registered on the network from the consensus key fork
+// on, once the validation starts.
+func (s *Kowala) SetConsensusKey(key common.Address) error {
+	walletAccount, err := s.findWalletAccount(key)
+	if err != nil {
+		return err
+	}
+	if err := s.validator.SetConsensusKey(walletAccount); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	s.consensusKey = key
+	s.lock.Unlock()
+
+	return nil
+}
+
 func (s *Kowala) getWalletAccount() (accounts.WalletAccount, error) {
-	account := accounts.Account{Address: s.coinbase}
+	return s.findWalletAccount(s.coinbase)
+}
+
+func (s *Kowala) findWalletAccount(address common.Address) (accounts.WalletAccount, error) {
+	account := accounts.Account{Address: address}
 	wallet, err := s.accountManager.Find(account)
 	if err != nil {
 		return nil, err
@@ -389,10 +445,49 @@ func (s *Kowala) StartValidating() error {
 		return fmt.Errorf("error starting validating: %v", err)
 	}
 
+	consensusKey, err := s.ConsensusKey()
+	if err != nil {
+		return fmt.Errorf("consensus key missing: %v", err)
+	}
+	consensusAccount, err := s.findWalletAccount(consensusKey)
+	if err != nil {
+		return fmt.Errorf("error starting validating with the consensus key %s: %v", consensusKey.Hex(), err)
+	}
+	if err := s.validator.SetConsensusKey(consensusAccount); err != nil {
+		return fmt.Errorf("error starting validating: %v", err)
+	}
+
 	s.validator.Start(walletAccount, deposit)
+	if !s.shadow {
+		s.advertiseValidator(walletAccount)
+	}
 	return nil
 }
 
+// advertiseValidator adds a proof that the node is operated by the validator
+// account to the node record, so that the other validators can find it.
+func (s *Kowala) advertiseValidator(walletAccount accounts.WalletAccount) {
+	s.lock.RLock()
+	srvr := s.p2pServer
+	s.lock.RUnlock()
+
+	if srvr == nil {
+		return
+	}
+	account := walletAccount.Account()
+	self := srvr.Self()
+	proof, err := enr.SignValidator(crypto.Keccak256(self.ID[:]), account.Address, func(hash []byte) ([]byte, error) {
+		return walletAccount.SignHash(account, hash)
+	})
+	if err != nil {
+		log.Warn("Failed to sign the validator proof", "err", err)
+		return
+	}
+	if err := srvr.SetRecordEntries(proof); err != nil {
+		log.Warn("Failed to update the node record", "err", err)
+	}
+}
+
 func (s *Kowala) StopValidating() {
 	if err := s.validator.Stop(); err != nil {
 		log.Error("Error stopping Consensus", "err", err)
@@ -456,6 +551,16 @@ func (s *Kowala) Start(srvr *p2p.Server) error {
 	// Start the RPC service
 	s.netRPCService = kcoinapi.NewPublicNetAPI(srvr, s.NetVersion())
 
+	// Advertise the chain and the consensus relaying capability in the node record
+	s.lock.Lock()
+	s.p2pServer = srvr
+	s.lock.Unlock()
+
+	fork := enr.NewForkID(s.blockchain.Genesis().Hash(), s.networkID)
+	if err := srvr.SetRecordEntries(fork, enr.Relay(true)); err != nil {
+		log.Warn("Failed to update the node record", "err", err)
+	}
+
 	// Figure out a max peers count based on the server limits
 	maxPeers := srvr.MaxPeers
 	if s.config.LightServ > 0 {
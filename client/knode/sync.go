@@ -168,7 +168,10 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 
 	// Otherwise try to sync with the downloader
 	mode := downloader.FullSync
-	if atomic.LoadUint32(&pm.fastSync) == 1 {
+	if atomic.LoadUint32(&pm.checkpointSync) == 1 {
+		// Checkpoint sync was explicitly requested, start from the trusted state
+		mode = downloader.CheckpointSync
+	} else if atomic.LoadUint32(&pm.fastSync) == 1 {
 		// Fast sync was explicitly requested, and explicitly granted
 		mode = downloader.FastSync
 	} else if currentBlock.NumberU64() == 0 && pm.blockchain.CurrentFastBlock().NumberU64() > 0 {
@@ -195,6 +198,10 @@ func (pm *ProtocolManager) synchronise(peer *peer) {
 		log.Info("Fast sync complete, auto disabling")
 		atomic.StoreUint32(&pm.fastSync, 0)
 	}
+	if atomic.LoadUint32(&pm.checkpointSync) == 1 {
+		log.Info("Checkpoint sync complete, auto disabling")
+		atomic.StoreUint32(&pm.checkpointSync, 0)
+	}
 	atomic.StoreUint32(&pm.acceptTxs, 1) // Mark initial sync done
 	if head := pm.blockchain.CurrentBlock(); head.NumberU64() > 0 {
 		// We've completed a sync cycle, notify all peers of new state. This path is
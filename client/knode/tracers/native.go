@@ -0,0 +1,60 @@
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/log"
+)
+
+// natives contains the built in native tracers by name. They produce the same
+// output as the JavaScript tracers of the same name, without the overhead of
+// running every opcode through the JavaScript VM.
+var natives = map[string]func() TxTracer{
+	"callTracer":     newCallTracer,
+	"prestateTracer": newPrestateTracer,
+}
+
+// TxTracer is a transaction tracer assembling its result into JSON once the
+// execution finishes.
+type TxTracer interface {
+	vm.Tracer
+
+	// GetResult returns the JSON result of the trace, or any accumulated error.
+	GetResult() (json.RawMessage, error)
+
+	// Stop terminates execution of the tracer at the first opportune moment.
+	Stop(err error)
+}
+
+// NewTracer creates a transaction tracer. code is either the name of a built in
+// tracer or a JavaScript snippet as accepted by New. The native tracers take
+// precedence over the JavaScript tracers of the same name.
+func NewTracer(code string) (TxTracer, error) {
+	if ctor, ok := natives[code]; ok {
+		return ctor(), nil
+	}
+	return New(code)
+}
+
+// peekStack returns the nth-from-the-top element of the stack, or zero if the
+// stack isn't deep enough.
+func peekStack(stack *vm.Stack, idx int) *big.Int {
+	data := stack.Data()
+	if len(data) <= idx {
+		log.Warn("Tracer accessed out of bound stack", "size", len(data), "index", idx)
+		return new(big.Int)
+	}
+	return data[len(data)-idx-1]
+}
+
+// sliceMemory returns a copy of the [begin, end) range of the memory, or nil if
+// the range is out of bounds.
+func sliceMemory(memory *vm.Memory, begin, end *big.Int) []byte {
+	if !end.IsInt64() || memory.Len() < int(end.Int64()) || begin.Cmp(end) > 0 {
+		log.Warn("Tracer accessed out of bound memory", "available", memory.Len(), "offset", begin, "end", end)
+		return nil
+	}
+	return memory.Get(begin.Int64(), end.Int64()-begin.Int64())
+}
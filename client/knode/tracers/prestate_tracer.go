@@ -0,0 +1,222 @@
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+)
+
+// errNoPrestate is returned by the prestate tracer if no code was executed, so
+// no state was ever accessed.
+var errNoPrestate = errors.New("no state accessed, prestate unavailable")
+
+// prestateAccount is the state of an account before the traced transaction.
+type prestateAccount struct {
+	balance *big.Int
+	nonce   int64
+	code    []byte
+	storage *prestateStorage
+}
+
+// MarshalJSON encodes the account in the output order of the JavaScript
+// prestateTracer.
+func (acc *prestateAccount) MarshalJSON() ([]byte, error) {
+	return encodeJSON(&struct {
+		Balance string           `json:"balance"`
+		Nonce   int64            `json:"nonce"`
+		Code    string           `json:"code"`
+		Storage *prestateStorage `json:"storage"`
+	}{
+		Balance: hexutil.EncodeBig(acc.balance),
+		Nonce:   acc.nonce,
+		Code:    hexutil.Encode(acc.code),
+		Storage: acc.storage,
+	})
+}
+
+// prestateStorage is the storage of an account before the traced transaction,
+// retaining the order in which the slots were accessed.
+type prestateStorage struct {
+	keys  []common.Hash
+	slots map[common.Hash]common.Hash
+}
+
+// MarshalJSON encodes the storage slots in the order they were accessed.
+func (s *prestateStorage) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, key := range s.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		val := s.slots[key]
+		buf.WriteString(`"` + key.Hex() + `":"` + val.Hex() + `"`)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// prestateTracer is the native implementation of the prestateTracer, collecting
+// the state accessed by a transaction as it was before the execution. The order
+// of the accounts follows the JavaScript tracer, which reports them in the order
+// they were accessed.
+type prestateTracer struct {
+	addrs    []common.Address
+	prestate map[common.Address]*prestateAccount
+	db       vm.StateDB
+
+	create bool           // Whether the outer call creates a contract
+	from   common.Address // Sender of the outer call
+	to     common.Address // Recipient (or created contract) of the outer call
+	value  *big.Int       // Value transferred by the outer call
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+	failure   error  // Error, if the tracing was interrupted
+}
+
+// newPrestateTracer creates a native prestate tracer.
+func newPrestateTracer() TxTracer {
+	return new(prestateTracer)
+}
+
+// lookupAccount injects the specified account into the prestate.
+func (t *prestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.prestate[addr]; ok {
+		return
+	}
+	t.addrs = append(t.addrs, addr)
+	t.prestate[addr] = &prestateAccount{
+		balance: new(big.Int).Set(t.db.GetBalance(addr)),
+		nonce:   int64(t.db.GetNonce(addr)),
+		code:    common.CopyBytes(t.db.GetCode(addr)),
+		storage: &prestateStorage{slots: make(map[common.Hash]common.Hash)},
+	}
+}
+
+// lookupStorage injects the specified storage slot of the given account into the
+// prestate. Empty slots aren't reported.
+func (t *prestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+
+	storage := t.prestate[addr].storage
+	if _, ok := storage.slots[key]; ok {
+		return
+	}
+	if val := t.db.GetState(addr, key); val != (common.Hash{}) {
+		storage.keys = append(storage.keys, key)
+		storage.slots[key] = val
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *prestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.create = create
+	t.from, t.to = from, to
+	t.value = new(big.Int).Set(value)
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *prestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if t.failure != nil {
+		return nil
+	}
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.failure = t.reason
+		return nil
+	}
+	// Add the current account if we just started tracing
+	if t.prestate == nil {
+		t.prestate = make(map[common.Address]*prestateAccount)
+		t.db = env.StateDB
+
+		// Balance will potentially be wrong here, since this will include the value
+		// sent along with the message. We fix that in GetResult.
+		t.lookupAccount(contract.Address())
+	}
+	// Whenever new state is accessed, add it to the prestate
+	switch op {
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.BALANCE:
+		t.lookupAccount(common.BigToAddress(peekStack(stack, 0)))
+	case vm.CREATE:
+		from := contract.Address()
+		t.lookupAccount(crypto.CreateAddress(from, t.db.GetNonce(from)))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.lookupAccount(common.BigToAddress(peekStack(stack, 1)))
+	case vm.SSTORE, vm.SLOAD:
+		t.lookupStorage(contract.Address(), common.BigToHash(peekStack(stack, 0)))
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (t *prestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *prestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetResult returns the assembled prestate, or the error that interrupted the
+// tracing.
+func (t *prestateTracer) GetResult() (json.RawMessage, error) {
+	if t.prestate == nil {
+		return nil, errNoPrestate
+	}
+	// At this point, we need to deduct the 'value' from the outer transaction,
+	// and move it back to the origin
+	t.lookupAccount(t.from)
+	t.lookupAccount(t.to)
+
+	to, from := t.prestate[t.to], t.prestate[t.from]
+	toBal, fromBal := to.balance, from.balance
+
+	to.balance = new(big.Int).Sub(toBal, t.value)
+	from.balance = new(big.Int).Add(fromBal, t.value)
+
+	// Decrement the caller's nonce, and remove empty create targets
+	from.nonce--
+	if t.create {
+		// We can blindly delete the contract prestate, as any existing state would
+		// have caused the transaction to be rejected as invalid in the first place.
+		delete(t.prestate, t.to)
+	}
+	// Assemble the allocations in the order they were accessed
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for _, addr := range t.addrs {
+		acc, ok := t.prestate[addr]
+		if !ok {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		blob, err := acc.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`"` + hexutil.Encode(addr.Bytes()) + `":`)
+		buf.Write(blob)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), t.failure
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *prestateTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
@@ -17,11 +17,13 @@
 package tracers
 
 import (
+	"bytes"
 	"encoding/json"
 	"io/ioutil"
 	"math/big"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -117,6 +119,16 @@ type callTracerTest struct {
 // Iterates over all the input-output datasets in the tracer test harness and
 // runs the JavaScript tracers against them.
 func TestCallTracer(t *testing.T) {
+	testCallTracer(t, func() (TxTracer, error) { return New("callTracer") })
+}
+
+// Iterates over all the input-output datasets in the tracer test harness and
+// runs the native call tracer against them.
+func TestNativeCallTracer(t *testing.T) {
+	testCallTracer(t, func() (TxTracer, error) { return newCallTracer(), nil })
+}
+
+func testCallTracer(t *testing.T, newTracer func() (TxTracer, error)) {
 	files, err := ioutil.ReadDir("testdata")
 	if err != nil {
 		t.Fatalf("failed to retrieve tracer test suite: %v", err)
@@ -129,50 +141,13 @@ func TestCallTracer(t *testing.T) {
 		t.Run(camel(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "call_tracer_"), ".json")), func(t *testing.T) {
 			t.Parallel()
 
-			// Call tracer test found, read if from disk
-			blob, err := ioutil.ReadFile(filepath.Join("testdata", file.Name()))
-			if err != nil {
-				t.Fatalf("failed to read testcase: %v", err)
-			}
-			test := new(callTracerTest)
-			if err := json.Unmarshal(blob, test); err != nil {
-				t.Fatalf("failed to parse testcase: %v", err)
-			}
-			// Configure a blockchain with the given prestate
-			tx := new(types.Transaction)
-			if err := rlp.DecodeBytes(common.FromHex(test.Input), tx); err != nil {
-				t.Fatalf("failed to parse testcase input: %v", err)
-			}
-			signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
-			origin, _ := signer.Sender(tx)
-
-			context := vm.Context{
-				CanTransfer: core.CanTransfer,
-				Transfer:    core.Transfer,
-				Origin:      origin,
-				Coinbase:    test.Context.Miner,
-				BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
-				Time:        new(big.Int).SetUint64(uint64(test.Context.Time)),
-				GasLimit:    uint64(test.Context.GasLimit),
-				GasPrice:    tx.GasPrice(),
-			}
-			statedb := tests.MakePreState(kcoindb.NewMemDatabase(), test.Genesis.Alloc)
-
-			// Create the tracer, the EVM environment and run it
-			tracer, err := New("callTracer")
+			// Create the tracer and run the test case through it
+			tracer, err := newTracer()
 			if err != nil {
 				t.Fatalf("failed to create call tracer: %v", err)
 			}
-			evm := vm.NewEVM(context, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
+			test := runTracerTest(t, file.Name(), tracer)
 
-			msg, err := tx.AsMessage(signer)
-			if err != nil {
-				t.Fatalf("failed to prepare transaction for tracing: %v", err)
-			}
-			st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
-			if _, _, _, err = st.TransitionDb(); err != nil {
-				t.Fatalf("failed to execute transaction: %v", err)
-			}
 			// Retrieve the trace result and compare against the etalon
 			res, err := tracer.GetResult()
 			if err != nil {
@@ -188,3 +163,90 @@ func TestCallTracer(t *testing.T) {
 		})
 	}
 }
+
+// Tests that the native tracers produce exactly the same output as the
+// JavaScript tracers of the same name.
+func TestNativeTracersCompatibility(t *testing.T) {
+	files, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatalf("failed to retrieve tracer test suite: %v", err)
+	}
+	// The execution time is the only field expected to differ
+	timeField := regexp.MustCompile(`"time":"[^"]*"`)
+
+	for name, ctor := range natives {
+		for _, file := range files {
+			name, ctor, file := name, ctor, file // capture range variables
+			t.Run(name+"/"+strings.TrimSuffix(file.Name(), ".json"), func(t *testing.T) {
+				t.Parallel()
+
+				jsTracer, err := New(name)
+				if err != nil {
+					t.Fatalf("failed to create JavaScript tracer: %v", err)
+				}
+				runTracerTest(t, file.Name(), jsTracer)
+				want, err := jsTracer.GetResult()
+				if err != nil {
+					t.Fatalf("failed to retrieve JavaScript trace result: %v", err)
+				}
+				nativeTracer := ctor()
+				runTracerTest(t, file.Name(), nativeTracer)
+				have, err := nativeTracer.GetResult()
+				if err != nil {
+					t.Fatalf("failed to retrieve native trace result: %v", err)
+				}
+				want = timeField.ReplaceAll(want, nil)
+				have = timeField.ReplaceAll(have, nil)
+				if !bytes.Equal(have, want) {
+					t.Fatalf("trace mismatch:\nhave %s\nwant %s", have, want)
+				}
+			})
+		}
+	}
+}
+
+// runTracerTest executes the transaction of a test case with the given tracer
+// attached, returning the test case.
+func runTracerTest(t *testing.T, file string, tracer vm.Tracer) *callTracerTest {
+	// Call tracer test found, read if from disk
+	blob, err := ioutil.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatalf("failed to read testcase: %v", err)
+	}
+	test := new(callTracerTest)
+	if err := json.Unmarshal(blob, test); err != nil {
+		t.Fatalf("failed to parse testcase: %v", err)
+	}
+	// Configure a blockchain with the given prestate
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(test.Input), tx); err != nil {
+		t.Fatalf("failed to parse testcase input: %v", err)
+	}
+	signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
+	origin, _ := signer.Sender(tx)
+
+	context := vm.Context{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		Origin:      origin,
+		Coinbase:    test.Context.Miner,
+		BlockNumber: new(big.Int).SetUint64(uint64(test.Context.Number)),
+		Time:        new(big.Int).SetUint64(uint64(test.Context.Time)),
+		GasLimit:    uint64(test.Context.GasLimit),
+		GasPrice:    tx.GasPrice(),
+	}
+	statedb := tests.MakePreState(kcoindb.NewMemDatabase(), test.Genesis.Alloc)
+
+	// Create the EVM environment and run the tracer through it
+	evm := vm.NewEVM(context, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
+
+	msg, err := tx.AsMessage(signer)
+	if err != nil {
+		t.Fatalf("failed to prepare transaction for tracing: %v", err)
+	}
+	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
+	if _, _, _, err = st.TransitionDb(); err != nil {
+		t.Fatalf("failed to execute transaction: %v", err)
+	}
+	return test
+}
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/params"
+)
+
+var errInsufficientPreCommits = errors.New("pre-commits do not have a +2/3 majority")
+
+// newCommit builds the commit of the elected block out of the pre-commits of
+// the commit round. The commit is carried by the next block.
+func (val *validator) newCommit() *types.Commit {
+	precommits, err := val.votingSystem.PreCommits(val.round, val.block.Hash())
+	if err != nil {
+		log.Warn("Failed to retrieve the pre-commits", "number", val.blockNumber, "err", err)
+		return nil
+	}
+	next := new(big.Int).Add(val.blockNumber, common.Big1)
+	commit, err := buildCommit(val.config, val.signer, val.voters, next, precommits)
+	if err != nil {
+		log.Warn("Failed to build the commit", "number", val.blockNumber, "err", err)
+		return nil
+	}
+	return commit
+}
+
+// buildCommit builds the commit carried by the block number out of the
+// pre-commits of its parent block. From the aggregated commit fork on, the
+// commit is aggregated if more than two thirds of the voters signed their
+// pre-commits with their aggregate keys. Otherwise the commit holds the
+// individually signed pre-commits.
+func buildCommit(config *params.ChainConfig, signer types.Signer, voters types.Voters, number *big.Int, precommits types.Votes) (*types.Commit, error) {
+	// the voting tables accept several votes of the same voter, as long as
+	// they differ, so only the first pre-commit of each voter is kept
+	var (
+		signed  = make(map[int]bool, len(precommits))
+		senders = make(map[*types.Vote]common.Address, len(precommits))
+		votes   = make(types.Votes, 0, len(precommits))
+	)
+	for _, vote := range precommits {
+		addr, err := types.VoteSender(signer, vote)
+		if err != nil {
+			continue
+		}
+		index := voterIndex(voters, addr)
+		if index < 0 || signed[index] {
+			continue
+		}
+		signed[index] = true
+		senders[vote] = addr
+		votes = append(votes, vote)
+	}
+	if !hasMajority(len(votes), voters.Len()) {
+		return nil, errInsufficientPreCommits
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		return bytes.Compare(senders[votes[i]].Bytes(), senders[votes[j]].Bytes()) < 0
+	})
+	first := votes[0]
+
+	if config.IsAggregateCommit(number) {
+		if aggregated := aggregatablePreCommits(signer, voters, votes, senders); hasMajority(len(aggregated), voters.Len()) {
+			return types.NewAggregateCommit(voters, signer, first, aggregated)
+		}
+	}
+	return &types.Commit{
+		PreCommits:     votes,
+		FirstPreCommit: first,
+	}, nil
+}
+
+// aggregatablePreCommits returns the pre-commits carrying a valid signature of
+// the aggregate key of their voter.
+func aggregatablePreCommits(signer types.Signer, voters types.Voters, votes types.Votes, senders map[*types.Vote]common.Address) types.Votes {
+	aggregated := make(types.Votes, 0, len(votes))
+	for _, vote := range votes {
+		key := voters.GetByConsensusKey(senders[vote]).AggregateKey()
+		raw := vote.AggregateSignature()
+		if key == nil || raw == nil {
+			continue
+		}
+		sig, err := bls.UnmarshalSignature(raw)
+		if err != nil {
+			continue
+		}
+		h := signer.Hash(vote)
+		if !key.Verify(h[:], sig) {
+			continue
+		}
+		aggregated = append(aggregated, vote)
+	}
+	return aggregated
+}
+
+// voterIndex returns the index of the voter signing with the given key, -1 if
+// there isn't any.
+func voterIndex(voters types.Voters, key common.Address) int {
+	for i := 0; i < voters.Len(); i++ {
+		if voters.At(i).ConsensusKey() == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasMajority reports whether count is more than two thirds of total.
+func hasMajority(count, total int) bool {
+	return count*3 > total*2
+}
@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCommitVoters creates n voters with aggregate keys and their pre-commits
+// for the same block.
+func newCommitVoters(t *testing.T, signer types.Signer, n int) (types.Voters, types.Votes) {
+	var (
+		voters     = make([]*types.Voter, n)
+		precommits = make(types.Votes, n)
+		keys       = make([]*ecdsa.PrivateKey, n)
+	)
+	for i := range voters {
+		keys[i], _ = crypto.GenerateKey()
+		aggKey, _ := bls.GenerateKey(nil)
+		voters[i] = types.NewVoterWithAggregateKey(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1), big.NewInt(0), aggKey.PublicKey())
+
+		vote, err := types.SignVote(types.NewVote(big.NewInt(10), common.HexToHash("0x01"), 0, types.PreCommit), signer, keys[i])
+		require.NoError(t, err)
+		precommits[i] = types.SignVoteAggregate(vote, signer, aggKey)
+	}
+	set, err := types.NewVoters(voters)
+	require.NoError(t, err)
+	return set, precommits
+}
+
+func TestBuildCommit_AggregatesAfterTheFork(t *testing.T) {
+	signer := types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+	voters, precommits := newCommitVoters(t, signer, 4)
+
+	commit, err := buildCommit(params.TestChainConfig, signer, voters, big.NewInt(11), precommits[:3])
+	require.NoError(t, err)
+	require.NotNil(t, commit.Aggregate())
+	assert.Equal(t, 3, commit.Aggregate().Count())
+	assert.Empty(t, commit.Commits())
+}
+
+func TestBuildCommit_LegacyBeforeTheFork(t *testing.T) {
+	signer := types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+	voters, precommits := newCommitVoters(t, signer, 4)
+	config := *params.TestChainConfig
+	config.AggregateCommitBlock = big.NewInt(100)
+
+	// the duplicate pre-commits of a voter are left out
+	commit, err := buildCommit(&config, signer, voters, big.NewInt(11), append(precommits[:3:3], precommits[0]))
+	require.NoError(t, err)
+	assert.Nil(t, commit.Aggregate())
+	assert.Len(t, commit.Commits(), 3)
+}
+
+func TestBuildCommit_LegacyWithoutAggregateSignatures(t *testing.T) {
+	signer := types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+	voters, precommits := newCommitVoters(t, signer, 4)
+
+	// only two of the three pre-commits carry a valid aggregate signature
+	forged := precommits[2].WithAggregateSignature(precommits[1].AggregateSignature())
+	commit, err := buildCommit(params.TestChainConfig, signer, voters, big.NewInt(11), types.Votes{precommits[0], precommits[1], forged})
+	require.NoError(t, err)
+	assert.Nil(t, commit.Aggregate())
+	assert.Len(t, commit.Commits(), 3)
+}
+
+func TestBuildCommit_InsufficientPreCommits(t *testing.T) {
+	signer := types.NewAndromedaSigner(params.TestChainConfig.ChainID)
+	voters, precommits := newCommitVoters(t, signer, 4)
+
+	_, err := buildCommit(params.TestChainConfig, signer, voters, big.NewInt(11), precommits[:2])
+	assert.Equal(t, errInsufficientPreCommits, err)
+}
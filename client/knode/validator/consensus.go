@@ -29,6 +29,9 @@ type VotingState struct {
 
 	start time.Time // used to sync the validator nodes
 
+	roundStart time.Time // start of the current round, used by the metrics
+	rounds     uint64    // number of rounds started at the current height
+
 	commitRound int
 
 	// inputs
@@ -128,6 +131,16 @@ func (vs *VotingSystem) Leader(round uint64, voteType types.VoteType) (common.Ha
 	return votingTable.Leader(), nil
 }
 
+// PreCommits returns the pre-commits of the round for the given block.
+func (vs *VotingSystem) PreCommits(round uint64, blockHash common.Hash) (types.Votes, error) {
+	votingTable, err := vs.getVoteSet(round, types.PreCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	return votingTable.Votes(blockHash), nil
+}
+
 func (vs *VotingSystem) getVoteSet(round uint64, voteType types.VoteType) (core.VotingTable, error) {
 	votingTables, ok := vs.votesPerRound[round]
 	if !ok {
@@ -0,0 +1,24 @@
+package validator
+
+import (
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/metrics"
+)
+
+var (
+	roundDurationTimer    = metrics.NewRegisteredTimer("validator/round/duration", nil)
+	heightRoundsHistogram = metrics.NewRegisteredHistogram("validator/height/rounds", nil, metrics.NewExpDecaySample(1028, 0.015))
+	commitSizeHistogram   = metrics.NewRegisteredHistogram("validator/commit/size", nil, metrics.NewExpDecaySample(1028, 0.015))
+
+	voteLatencyTimers = map[types.VoteType]metrics.Timer{
+		types.PreVote:   metrics.NewRegisteredTimer("validator/votes/prevote/latency", nil),
+		types.PreCommit: metrics.NewRegisteredTimer("validator/votes/precommit/latency", nil),
+	}
+)
+
+// proposerMissCounter returns the counter of the proposals missed by the given
+// validator, registering it on first use.
+func proposerMissCounter(proposer common.Address) metrics.Counter {
+	return metrics.GetOrRegisterCounter("validator/proposer/misses/"+proposer.Hex(), nil)
+}
@@ -0,0 +1,198 @@
+package validator
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/metrics"
+)
+
+var ErrNotShadowing = errors.New("validator is not running in shadow mode")
+
+const (
+	// shadowHistory is the number of recent elections kept by a shadow validator.
+	shadowHistory = 256
+
+	// shadowCommitTimeout is the time a shadow validator waits for the network
+	// to commit the block it pre-committed, before starting a new round.
+	shadowCommitTimeout = 5 * time.Second
+)
+
+var (
+	shadowElectionCounter    = metrics.NewRegisteredCounter("validator/shadow/elections", nil)
+	shadowAgreementCounter   = metrics.NewRegisteredCounter("validator/shadow/agreements", nil)
+	shadowMissedRoundCounter = metrics.NewRegisteredCounter("validator/shadow/rounds/missed", nil)
+	shadowProposalTimer      = metrics.NewRegisteredTimer("validator/shadow/proposal/duration", nil)
+	shadowCommitLagTimer     = metrics.NewRegisteredTimer("validator/shadow/commit/lag", nil)
+
+	shadowVoteDelayTimers = map[types.VoteType]metrics.Timer{
+		types.PreVote:   metrics.NewRegisteredTimer("validator/shadow/prevote/delay", nil),
+		types.PreCommit: metrics.NewRegisteredTimer("validator/shadow/precommit/delay", nil),
+	}
+)
+
+// ShadowElection is an election as seen by a validator in shadow mode, next to
+// the block the network committed.
+type ShadowElection struct {
+	BlockNumber uint64        `json:"blockNumber"`
+	Rounds      int           `json:"rounds"`             // Rounds the validator took part in
+	Proposal    *common.Hash  `json:"proposal,omitempty"` // Block the validator would have proposed
+	PreVote     *common.Hash  `json:"preVote,omitempty"`  // Last pre-vote of the validator
+	PreCommit   *common.Hash  `json:"preCommit,omitempty"`
+	Committed   *common.Hash  `json:"committed,omitempty"` // Block committed by the network
+	Agreed      bool          `json:"agreed"`              // Whether the validator pre-committed the committed block
+	Missed      int           `json:"missed"`              // Rounds in which the validator would have pre-committed nil
+	VoteDelay   time.Duration `json:"voteDelay"`           // Time from the start of the round to the last pre-commit
+	CommitLag   time.Duration `json:"commitLag"`           // Time from the last pre-commit to the commit
+
+	precommitted time.Time
+}
+
+// ShadowStatus summarizes the elections followed by a validator in shadow mode.
+type ShadowStatus struct {
+	Elections     uint64            `json:"elections"`     // Elections whose commit was observed
+	Agreements    uint64            `json:"agreements"`    // Elections in which the validator pre-committed the committed block
+	AgreementRate float64           `json:"agreementRate"` // Ratio of agreements to elections
+	MissedRounds  uint64            `json:"missedRounds"`  // Rounds in which the validator would have pre-committed nil
+	Recent        []*ShadowElection `json:"recent"`        // Most recent elections, the ongoing one first
+}
+
+// shadowTracker records the proposals and votes of a validator in shadow mode,
+// which are built and signed but never broadcast, and compares them with the
+// blocks committed by the network.
+type shadowTracker struct {
+	elections  []*ShadowElection // Recent elections, oldest first
+	observed   uint64
+	agreements uint64
+	missed     uint64
+
+	lock sync.Mutex
+}
+
+func newShadowTracker() *shadowTracker {
+	return &shadowTracker{}
+}
+
+// election returns the record of the election at the given height, creating it
+// unless the height was already committed.
+func (t *shadowTracker) election(number uint64) *ShadowElection {
+	for i := len(t.elections) - 1; i >= 0; i-- {
+		if election := t.elections[i]; election.BlockNumber == number {
+			if election.Committed != nil {
+				return nil
+			}
+			return election
+		}
+	}
+	election := &ShadowElection{BlockNumber: number}
+	t.elections = append(t.elections, election)
+	if len(t.elections) > shadowHistory {
+		t.elections = t.elections[len(t.elections)-shadowHistory:]
+	}
+	return election
+}
+
+// newRound records the start of a round at the given height.
+func (t *shadowTracker) newRound(number uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if election := t.election(number); election != nil {
+		election.Rounds++
+	}
+}
+
+// propose records the block the validator would have proposed.
+func (t *shadowTracker) propose(number uint64, block common.Hash, elapsed time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if election := t.election(number); election != nil {
+		election.Proposal = &block
+		shadowProposalTimer.Update(elapsed)
+	}
+}
+
+// vote records a vote the validator would have cast, since the given start of
+// the round.
+func (t *shadowTracker) vote(vote *types.Vote, roundStart time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	election := t.election(vote.BlockNumber().Uint64())
+	if election == nil {
+		return
+	}
+	block := vote.BlockHash()
+	switch vote.Type() {
+	case types.PreVote:
+		election.PreVote = &block
+	case types.PreCommit:
+		election.PreCommit = &block
+		election.precommitted = time.Now()
+		election.VoteDelay = election.precommitted.Sub(roundStart)
+		if block == (common.Hash{}) {
+			election.Missed++
+			shadowMissedRoundCounter.Inc(1)
+		}
+	}
+	if timer, ok := shadowVoteDelayTimers[vote.Type()]; ok {
+		timer.UpdateSince(roundStart)
+	}
+}
+
+// commit records a block committed by the network, closing its election.
+// Blocks of elections the validator didn't take part in are ignored.
+func (t *shadowTracker) commit(block *types.Block) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var election *ShadowElection
+	for i := len(t.elections) - 1; i >= 0; i-- {
+		if t.elections[i].BlockNumber == block.NumberU64() {
+			election = t.elections[i]
+			break
+		}
+	}
+	if election == nil || election.Committed != nil {
+		return
+	}
+	hash := block.Hash()
+	election.Committed = &hash
+
+	t.observed++
+	shadowElectionCounter.Inc(1)
+	if election.PreCommit != nil && *election.PreCommit == hash {
+		election.Agreed = true
+		election.CommitLag = time.Since(election.precommitted)
+		shadowCommitLagTimer.Update(election.CommitLag)
+
+		t.agreements++
+		shadowAgreementCounter.Inc(1)
+	}
+	t.missed += uint64(election.Missed)
+}
+
+// status returns a summary of the elections followed so far.
+func (t *shadowTracker) status() *ShadowStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	status := &ShadowStatus{
+		Elections:    t.observed,
+		Agreements:   t.agreements,
+		MissedRounds: t.missed,
+		Recent:       make([]*ShadowElection, 0, len(t.elections)),
+	}
+	if t.observed > 0 {
+		status.AgreementRate = float64(t.agreements) / float64(t.observed)
+	}
+	for i := len(t.elections) - 1; i >= 0; i-- {
+		election := *t.elections[i]
+		status.Recent = append(status.Recent, &election)
+	}
+	return status
+}
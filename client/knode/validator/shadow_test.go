@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func shadowBlock(number int64) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number)})
+}
+
+func TestShadowTracker_AgreementAndMissedRounds(t *testing.T) {
+	tracker := newShadowTracker()
+	start := time.Now()
+
+	// height 1: pre-commits the committed block
+	block1 := shadowBlock(1)
+	tracker.newRound(1)
+	tracker.vote(types.NewVote(big.NewInt(1), block1.Hash(), 0, types.PreVote), start)
+	tracker.vote(types.NewVote(big.NewInt(1), block1.Hash(), 0, types.PreCommit), start)
+	tracker.commit(block1)
+
+	// height 2: pre-commits nil in the first round, another block in the second
+	block2 := shadowBlock(2)
+	tracker.newRound(2)
+	tracker.propose(2, common.HexToHash("0x01"), time.Millisecond)
+	tracker.vote(types.NewVote(big.NewInt(2), common.Hash{}, 0, types.PreCommit), start)
+	tracker.newRound(2)
+	tracker.vote(types.NewVote(big.NewInt(2), common.HexToHash("0x01"), 0, types.PreCommit), start)
+	tracker.commit(block2)
+
+	// votes after the commit are too late to count
+	tracker.vote(types.NewVote(big.NewInt(2), block2.Hash(), 0, types.PreCommit), start)
+
+	// blocks of elections not followed are ignored
+	tracker.commit(shadowBlock(5))
+
+	// height 3 is ongoing
+	tracker.newRound(3)
+
+	status := tracker.status()
+	assert.Equal(t, uint64(2), status.Elections)
+	assert.Equal(t, uint64(1), status.Agreements)
+	assert.Equal(t, 0.5, status.AgreementRate)
+	assert.Equal(t, uint64(1), status.MissedRounds)
+
+	require.Len(t, status.Recent, 3)
+	assert.Equal(t, uint64(3), status.Recent[0].BlockNumber)
+	assert.Nil(t, status.Recent[0].Committed)
+
+	second := status.Recent[1]
+	assert.Equal(t, 2, second.Rounds)
+	assert.Equal(t, 1, second.Missed)
+	assert.False(t, second.Agreed)
+	assert.Equal(t, common.HexToHash("0x01"), *second.Proposal)
+	assert.Equal(t, common.HexToHash("0x01"), *second.PreCommit)
+	assert.Equal(t, block2.Hash(), *second.Committed)
+
+	first := status.Recent[2]
+	assert.True(t, first.Agreed)
+	assert.Equal(t, block1.Hash(), *first.PreVote)
+}
+
+func TestShadowTracker_History(t *testing.T) {
+	tracker := newShadowTracker()
+	for number := uint64(1); number <= shadowHistory+10; number++ {
+		tracker.newRound(number)
+	}
+	status := tracker.status()
+	require.Len(t, status.Recent, shadowHistory)
+	assert.Equal(t, uint64(shadowHistory+10), status.Recent[0].BlockNumber)
+	assert.Equal(t, uint64(11), status.Recent[shadowHistory-1].BlockNumber)
+}
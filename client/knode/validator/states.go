@@ -14,6 +14,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/core/types"
 	"github.com/kowala-tech/kcoin/client/log"
 	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/kowala-tech/kcoin/client/rlp"
 
 	"github.com/davecgh/go-spew/spew"
 )
@@ -40,6 +41,11 @@ func (val *validator) genesisNotLoggedInState() stateFn {
 }
 
 func (val *validator) notLoggedInState() stateFn {
+	if val.shadow != nil {
+		log.Info("No deposit is necessary in shadow mode")
+		return val.startValidating
+	}
+
 	isValidator, err := val.consensus.IsValidator(val.walletAccount.Account().Address)
 	if err != nil {
 		log.Crit("Failed to verify if account is already a validator")
@@ -73,6 +79,60 @@ func (val *validator) makeDeposit() error {
 	return nil
 }
 
+// registerConsensusKey registers the consensus key of the node, from the
+// consensus key fork on, if the validator has a different key on the network.
+// The registration isn't awaited, the validator keeps signing with its
+// account until the key is part of the voters set.
+func (val *validator) registerConsensusKey() {
+	if val.consensusAccount == nil || val.consensusKeyRegistered || val.shadow != nil || !val.config.IsConsensusKey(val.blockNumber) {
+		return
+	}
+	val.consensusKeyRegistered = true
+
+	key := val.consensusAccount.Account().Address
+	registered, err := val.consensus.ConsensusKey(val.walletAccount.Account().Address)
+	if err != nil {
+		log.Error("Failed to retrieve the consensus key", "err", err)
+		return
+	}
+	if registered == key {
+		return
+	}
+	txHash, err := val.consensus.SetConsensusKey(val.walletAccount, key)
+	if err != nil {
+		log.Error("Failed to register the consensus key", "err", err)
+		return
+	}
+	log.Info("Registering the consensus key", "key", key, "tx", txHash)
+}
+
+// registerAggregateKey registers the aggregate key of the node, from the
+// aggregated commit fork on, if the validator has a different key on the
+// network. The registration isn't awaited, the validator keeps signing its
+// pre-commits with the key meanwhile.
+func (val *validator) registerAggregateKey() {
+	if val.aggregateKey == nil || val.aggregateKeyRegistered || val.shadow != nil || !val.config.IsAggregateCommit(val.blockNumber) {
+		return
+	}
+	val.aggregateKeyRegistered = true
+
+	key := val.aggregateKey.PublicKey().Marshal()
+	registered, err := val.consensus.AggregateKey(val.walletAccount.Account().Address)
+	if err != nil {
+		log.Error("Failed to retrieve the aggregate key", "err", err)
+		return
+	}
+	if registered != nil && bytes.Equal(registered.Marshal(), key) {
+		return
+	}
+	txHash, err := val.consensus.SetAggregateKey(val.walletAccount, val.aggregateKey)
+	if err != nil {
+		log.Error("Failed to register the aggregate key", "err", err)
+		return
+	}
+	log.Info("Registering the aggregate key", "tx", txHash)
+}
+
 func (val *validator) startValidating() stateFn {
 	log.Info("Starting validation operation")
 	atomic.StoreInt32(&val.validating, 1)
@@ -93,6 +153,8 @@ func (val *validator) newElectionState() stateFn {
 	if err := val.init(); err != nil {
 		return nil
 	}
+	val.registerConsensusKey()
+	val.registerAggregateKey()
 
 	<-time.NewTimer(val.start.Sub(time.Now())).C
 
@@ -112,12 +174,32 @@ func (val *validator) newElectionState() stateFn {
 }
 
 func (val *validator) newRoundState() stateFn {
+	if val.shadow != nil {
+		if !val.Validating() {
+			return val.loggedOutState
+		}
+		// the network may have moved on without the shadow validator
+		if val.committedByNetwork() {
+			val.majority.Unsubscribe()
+			return val.newElectionState
+		}
+		val.shadow.newRound(val.blockNumber.Uint64())
+	}
+
 	log.Info("Starting a new voting round", "start time", val.start, "block number", val.blockNumber, "round", val.round)
 
+	// the round metrics are read by the vote handler
+	val.handleMutex.Lock()
+	val.roundStart = time.Now()
+	val.rounds++
+	val.handleMutex.Unlock()
+
 	val.voters.NextProposer()
 
 	if val.round != 0 {
+		val.handleMutex.Lock()
 		val.round++
+		val.handleMutex.Unlock()
 		val.proposal = nil
 		val.block = nil
 		val.blockFragments = nil
@@ -131,17 +213,22 @@ func (val *validator) newRoundState() stateFn {
 
 func (val *validator) newProposalState() stateFn {
 	proposer := val.voters.NextProposer()
-	if proposer.Address() == val.walletAccount.Account().Address {
+	switch {
+	case proposer.Address() == val.walletAccount.Account().Address && val.shadow != nil:
+		log.Info("Building the block that would be proposed")
+		val.shadowPropose()
+		val.waitForProposal(proposer)
+	case proposer.Address() == val.walletAccount.Account().Address:
 		log.Info("Proposing a new block")
 		val.propose()
-	} else {
+	default:
 		log.Info("Waiting for the proposal", "addr", proposer.Address())
-		val.waitForProposal()
+		val.waitForProposal(proposer)
 	}
 	return val.preVoteState
 }
 
-func (val *validator) waitForProposal() {
+func (val *validator) waitForProposal(proposer *types.Voter) {
 	timeout := time.Duration(params.ProposeDuration+val.round*params.ProposeDeltaDuration) * time.Millisecond
 	select {
 	case block := <-val.blockCh:
@@ -149,6 +236,7 @@ func (val *validator) waitForProposal() {
 		log.Info("Received the block", "hash", val.block.Hash())
 	case <-time.After(timeout):
 		log.Info("Timeout expired", "duration", timeout)
+		proposerMissCounter(proposer.Address()).Inc(1)
 	}
 }
 
@@ -185,6 +273,7 @@ func (val *validator) preCommitWaitState() stateFn {
 	log.Info("Waiting for a majority in the pre-commit sub-election")
 	timeout := time.Duration(params.PreCommitDuration+val.round+params.PreCommitDeltaDuration) * time.Millisecond
 	defer val.majority.Unsubscribe()
+	defer roundDurationTimer.UpdateSince(val.roundStart)
 
 	select {
 	case event := <-val.majority.Chan():
@@ -193,6 +282,9 @@ func (val *validator) preCommitWaitState() stateFn {
 			log.Debug("No one block wins!")
 			return val.newRoundState
 		}
+		if val.shadow != nil {
+			return val.shadowCommitState
+		}
 		return val.commitState
 	case <-time.After(timeout):
 		log.Info("Timeout expired", "duration", timeout)
@@ -235,6 +327,15 @@ func (val *validator) commitState() stateFn {
 	// election state updates
 	val.commitRound = int(val.round)
 
+	val.lastCommit = val.newCommit()
+
+	heightRoundsHistogram.Update(int64(val.rounds))
+	if commit := val.lastCommit; commit != nil {
+		if blob, err := rlp.EncodeToBytes(commit); err == nil {
+			commitSizeHistogram.Update(int64(len(blob)))
+		}
+	}
+
 	voter, err := val.consensus.IsValidator(val.walletAccount.Account().Address)
 	if err != nil {
 		log.Crit("Failed to verify if the validator is a voter", "err", err)
@@ -247,6 +348,30 @@ func (val *validator) commitState() stateFn {
 	return val.newElectionState
 }
 
+// shadowCommitState waits for the network to commit the block of the election
+// in shadow mode, instead of committing it.
+func (val *validator) shadowCommitState() stateFn {
+	log.Info("Waiting for the network to commit the block")
+
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := val.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	timeout := time.After(shadowCommitTimeout)
+	for !val.committedByNetwork() {
+		select {
+		case <-heads:
+		case <-timeout:
+			log.Info("Timeout expired", "duration", shadowCommitTimeout)
+			return val.newRoundState
+		}
+	}
+	val.commitRound = int(val.round)
+	heightRoundsHistogram.Update(int64(val.rounds))
+
+	return val.newElectionState
+}
+
 func (val *validator) loggedOutState() stateFn {
 	log.Info("Logged out")
 
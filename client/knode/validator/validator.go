@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/core/state"
 	"github.com/kowala-tech/kcoin/client/core/types"
 	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto/bls"
 	"github.com/kowala-tech/kcoin/client/event"
 	"github.com/kowala-tech/kcoin/client/kcoindb"
 	"github.com/kowala-tech/kcoin/client/log"
@@ -25,13 +27,14 @@ import (
 )
 
 var (
-	ErrCantStopNonStartedValidator       = errors.New("can't stop validator, not started")
-	ErrCantVoteNotValidating             = errors.New("can't vote, not validating")
-	ErrCantSetCoinbaseOnStartedValidator = errors.New("can't set coinbase, already started validating")
-	ErrCantAddProposalNotValidating      = errors.New("can't add proposal, not validating")
-	ErrCantAddBlockFragmentNotValidating = errors.New("can't add block fragment, not validating")
-	ErrIsNotRunning                      = errors.New("validator is not running")
-	ErrIsRunning                         = errors.New("validator is running, cannot change its parameters")
+	ErrCantStopNonStartedValidator           = errors.New("can't stop validator, not started")
+	ErrCantVoteNotValidating                 = errors.New("can't vote, not validating")
+	ErrCantSetCoinbaseOnStartedValidator     = errors.New("can't set coinbase, already started validating")
+	ErrCantSetConsensusKeyOnStartedValidator = errors.New("can't set consensus key, already started validating")
+	ErrCantAddProposalNotValidating          = errors.New("can't add proposal, not validating")
+	ErrCantAddBlockFragmentNotValidating     = errors.New("can't add block fragment, not validating")
+	ErrIsNotRunning                          = errors.New("validator is not running")
+	ErrIsRunning                             = errors.New("validator is running, cannot change its parameters")
 )
 
 var (
@@ -52,7 +55,11 @@ type Validator interface {
 	Stop() error
 	SetExtra(extra []byte) error
 	SetCoinbase(walletAccount accounts.WalletAccount) error
+	SetConsensusKey(walletAccount accounts.WalletAccount) error
+	SetAggregateKey(key *bls.PrivateKey) error
 	SetDeposit(deposit *big.Int) error
+	SetShadow(shadow bool) error
+	ShadowStatus() (*ShadowStatus, error)
 	Pending() (*types.Block, *state.StateDB)
 	PendingBlock() *types.Block
 	Deposits(address *common.Address) ([]*types.Deposit, error)
@@ -85,10 +92,20 @@ type validator struct {
 	engine   engine.Engine
 	vmConfig vm.Config
 
-	walletAccount accounts.WalletAccount
+	walletAccount    accounts.WalletAccount // holds the deposit and receives the rewards
+	consensusAccount accounts.WalletAccount // signs the proposals and votes once registered, the wallet account if nil
+
+	consensusKeyRegistered bool // whether the consensus key was registered since the start
+
+	aggregateKey           *bls.PrivateKey // signs the pre-commits of the aggregated commits, if set
+	aggregateKeyRegistered bool            // whether the aggregate key was registered since the start
+
+	lastCommit *types.Commit // commit of the last block committed by the validator
 
 	consensus *consensus.Consensus // consensus binding
 
+	shadow *shadowTracker // elections followed in shadow mode, nil otherwise
+
 	// sync
 	canStart    int32 // can start indicates whether we can start the validation operation
 	shouldStart int32 // should start indicates whether we should start after sync
@@ -166,6 +183,13 @@ func (val *validator) run() {
 		atomic.StoreInt32(&val.running, 0)
 	}()
 
+	if val.shadow != nil {
+		log.Info("Running in shadow mode, the proposals and votes won't be broadcast")
+		stop := make(chan struct{})
+		defer close(stop)
+		go val.observeCommits(stop)
+	}
+
 	initialStateFunc := val.notLoggedInState
 	if isGenesisValidator, err := val.isGenesisValidator(); err != nil && isGenesisValidator {
 		initialStateFunc = val.genesisNotLoggedInState
@@ -195,7 +219,12 @@ func (val *validator) Stop() error {
 	}
 	log.Info("Stopping consensus validator")
 
-	val.leave()
+	if val.shadow != nil {
+		// there's no election to leave, the state machine stops at the next round
+		atomic.StoreInt32(&val.validating, 0)
+	} else {
+		val.leave()
+	}
 	val.wg.Wait() // waits until the validator is no longer registered as a voter.
 
 	atomic.StoreInt32(&val.shouldStart, 0)
@@ -221,6 +250,44 @@ func (val *validator) SetCoinbase(walletAccount accounts.WalletAccount) error {
 	return nil
 }
 
+// SetConsensusKey sets the account that signs the proposals and votes of the
+// validator. It's registered, or rotated, once the consensus key fork is
+// reached.
+func (val *validator) SetConsensusKey(walletAccount accounts.WalletAccount) error {
+	if val.Validating() {
+		return ErrCantSetConsensusKeyOnStartedValidator
+	}
+	val.consensusAccount = walletAccount
+	val.consensusKeyRegistered = false
+	return nil
+}
+
+// SetAggregateKey sets the key signing the pre-commits of the aggregated
+// commits. It's registered, or replaced, once the aggregated commit fork is
+// reached.
+func (val *validator) SetAggregateKey(key *bls.PrivateKey) error {
+	if val.Validating() {
+		return ErrIsRunning
+	}
+	val.aggregateKey = key
+	val.aggregateKeyRegistered = false
+	return nil
+}
+
+// signingAccount returns the account that signs the proposals and votes: the
+// consensus account once the voters set holds it as the key of the validator,
+// the wallet account otherwise.
+func (val *validator) signingAccount() accounts.WalletAccount {
+	if val.consensusAccount == nil || val.voters == nil {
+		return val.walletAccount
+	}
+	voter := val.voters.Get(val.walletAccount.Account().Address)
+	if voter == nil || voter.ConsensusKey() != val.consensusAccount.Account().Address {
+		return val.walletAccount
+	}
+	return val.consensusAccount
+}
+
 func (val *validator) SetDeposit(deposit *big.Int) error {
 	if val.Validating() {
 		return ErrIsRunning
@@ -231,6 +298,32 @@ func (val *validator) SetDeposit(deposit *big.Int) error {
 	return nil
 }
 
+// SetShadow enables or disables the shadow mode, in which the validator runs
+// the consensus without making a deposit nor broadcasting its proposals and
+// votes, recording them next to the blocks committed by the network instead.
+func (val *validator) SetShadow(shadow bool) error {
+	if val.Validating() {
+		return ErrIsRunning
+	}
+
+	if shadow {
+		val.shadow = newShadowTracker()
+	} else {
+		val.shadow = nil
+	}
+
+	return nil
+}
+
+// ShadowStatus returns the elections followed in shadow mode.
+func (val *validator) ShadowStatus() (*ShadowStatus, error) {
+	shadow := val.shadow
+	if shadow == nil {
+		return nil, ErrNotShadowing
+	}
+	return shadow.status(), nil
+}
+
 // Pending returns the currently pending block and associated state.
 func (val *validator) Pending() (*types.Block, *state.StateDB) {
 	state, err := val.chain.State()
@@ -277,8 +370,11 @@ func (val *validator) init() error {
 
 	start := time.Unix(parent.Time().Int64(), 0)
 	val.start = start.Add(time.Duration(params.BlockTime) * time.Millisecond)
+	val.handleMutex.Lock()
 	val.blockNumber = parent.Number().Add(parent.Number(), big.NewInt(1))
 	val.round = 0
+	val.rounds = 0
+	val.handleMutex.Unlock()
 
 	val.proposal = nil
 	val.block = nil
@@ -335,13 +431,39 @@ func (val *validator) AddVote(vote *types.Vote) error {
 
 	if err := val.votingSystem.Add(addressVote); err != nil {
 		log.Error("cannot add the vote", "err", err)
+		// votes from non-voters are reported back, as they can't be honest
+		if _, ok := err.(*core.NotVoterError); ok {
+			return err
+		}
+		return nil
+	}
+	// measure the arrival latency of the votes for the ongoing round
+	if vote.BlockNumber().Cmp(val.blockNumber) == 0 && vote.Round() == val.round {
+		if timer, ok := voteLatencyTimers[vote.Type()]; ok {
+			timer.UpdateSince(val.roundStart)
+		}
 	}
 
 	return nil
 }
 
-func (val *validator) commitTransactions(mux *event.TypeMux, txs *types.TransactionsByPriceAndNonce, bc *core.BlockChain, coinbase common.Address) {
-	gp := new(core.GasPool).AddGas(val.header.GasLimit)
+func (val *validator) commitTransactions(w *work, mux *event.TypeMux, pending map[common.Address]types.Transactions, bc *core.BlockChain, coinbase common.Address) {
+	gp := new(core.GasPool).AddGas(w.header.GasLimit)
+
+	// Execute the first transaction of every account speculatively if enabled,
+	// the next ones depend on their nonce and would be executed again anyway
+	heads := make(types.TxByPrice, 0, len(pending))
+	for _, accTxs := range pending {
+		if len(accTxs) > 0 {
+			heads = append(heads, accTxs[0])
+		}
+	}
+	sort.Sort(heads)
+
+	executor := core.NewParallelExecutor(val.config, bc, &coinbase, w.state, w.header, heads, vm.Config{ParallelWorkers: val.vmConfig.ParallelWorkers})
+	defer executor.Close()
+
+	txs := types.NewTransactionsByPriceAndNonce(val.signer, pending)
 
 	var coalescedLogs []*types.Log
 
@@ -358,9 +480,9 @@ func (val *validator) commitTransactions(mux *event.TypeMux, txs *types.Transact
 		from, _ := types.TxSender(val.signer, tx)
 
 		// Start executing the transaction
-		val.state.Prepare(tx.Hash(), common.Hash{}, val.tcount)
+		w.state.Prepare(tx.Hash(), common.Hash{}, w.tcount)
 
-		err, logs := val.commitTransaction(tx, bc, coinbase, gp)
+		err, logs := w.commitTransaction(executor, tx, gp)
 		switch err {
 		case core.ErrGasLimitReached:
 			// Pop the current out-of-gas transaction without shifting in the next from the account
@@ -380,7 +502,7 @@ func (val *validator) commitTransactions(mux *event.TypeMux, txs *types.Transact
 		case nil:
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
-			val.tcount++
+			w.tcount++
 			txs.Shift()
 
 		default:
@@ -391,7 +513,7 @@ func (val *validator) commitTransactions(mux *event.TypeMux, txs *types.Transact
 		}
 	}
 
-	if len(coalescedLogs) > 0 || val.tcount > 0 {
+	if len(coalescedLogs) > 0 || w.tcount > 0 {
 		// make a copy, the state caches the logs and these logs get "upgraded" from pending to mined
 		// logs by filling in the block hash when the block was mined by the local miner. This can
 		// cause a race condition if a log was "upgraded" before the PendingLogsEvent is processed.
@@ -407,20 +529,20 @@ func (val *validator) commitTransactions(mux *event.TypeMux, txs *types.Transact
 			if tcount > 0 {
 				mux.Post(core.PendingStateEvent{})
 			}
-		}(cpy, val.tcount)
+		}(cpy, w.tcount)
 	}
 }
 
-func (val *validator) commitTransaction(tx *types.Transaction, bc *core.BlockChain, coinbase common.Address, gp *core.GasPool) (error, []*types.Log) {
-	snap := val.state.Snapshot()
+func (w *work) commitTransaction(executor *core.ParallelExecutor, tx *types.Transaction, gp *core.GasPool) (error, []*types.Log) {
+	snap := w.state.Snapshot()
 
-	receipt, _, err := core.ApplyTransaction(val.config, bc, &coinbase, gp, val.state, val.header, tx, &val.header.GasUsed, vm.Config{})
+	receipt, _, err := executor.ApplyTransaction(gp, tx, &w.header.GasUsed)
 	if err != nil {
-		val.state.RevertToSnapshot(snap)
+		w.state.RevertToSnapshot(snap)
 		return err, nil
 	}
-	val.txs = append(val.txs, tx)
-	val.receipts = append(val.receipts, receipt)
+	w.txs = append(w.txs, tx)
+	w.receipts = append(w.receipts, receipt)
 
 	return nil, receipt.Logs
 }
@@ -448,6 +570,12 @@ func (val *validator) createProposalBlock() *types.Block {
 }
 
 func (val *validator) createBlock() *types.Block {
+	return val.buildBlock(val.work)
+}
+
+// buildBlock builds a new block on top of the current block, executing the
+// pending transactions on the given work environment.
+func (val *validator) buildBlock(w *work) *types.Block {
 	log.Info("Creating a new block")
 	// new block header
 	parent := val.chain.CurrentBlock()
@@ -465,18 +593,13 @@ func (val *validator) createBlock() *types.Block {
 		Time:           big.NewInt(tstamp),
 		ValidatorsHash: val.voters.Hash(),
 	}
-	val.header = header
+	w.header = header
 
-	var commit *types.Commit
-
-	first := types.NewVote(blockNumber, parent.Hash(), 0, types.PreCommit)
-
-	if blockNumber.Cmp(big.NewInt(1)) == 0 {
-		commit = &types.Commit{
-			PreCommits:     types.Votes{first},
-			FirstPreCommit: first,
-		}
-	} else {
+	commit := val.lastCommit
+	if commit == nil || commit.First() == nil || commit.First().BlockHash() != parent.Hash() {
+		// the pre-commits of the parent block aren't known, as for the genesis
+		// block or after a restart
+		first := types.NewVote(parent.Number(), parent.Hash(), 0, types.PreCommit)
 		commit = &types.Commit{
 			PreCommits:     types.Votes{first},
 			FirstPreCommit: first,
@@ -493,12 +616,11 @@ func (val *validator) createBlock() *types.Block {
 		log.Crit("Failed to fetch pending transactions", "err", err)
 	}
 
-	txs := types.NewTransactionsByPriceAndNonce(val.signer, pending)
-	val.commitTransactions(val.eventMux, txs, val.chain, val.walletAccount.Account().Address)
+	val.commitTransactions(w, val.eventMux, pending, val.chain, val.walletAccount.Account().Address)
 
 	// Create the new block to seal with the consensus engine
 	var block *types.Block
-	if block, err = val.engine.Finalize(val.chain, header, val.state, val.txs, commit, val.receipts); err != nil {
+	if block, err = val.engine.Finalize(val.chain, header, w.state, w.txs, commit, w.receipts); err != nil {
 		log.Crit("Failed to finalize block for sealing", "err", err)
 	}
 
@@ -518,7 +640,7 @@ func (val *validator) propose() {
 
 	proposal := types.NewProposal(val.blockNumber, val.round, fragments.Metadata(), lockedRound, lockedBlock)
 
-	signedProposal, err := val.walletAccount.SignProposal(val.walletAccount.Account(), proposal, val.config.ChainID)
+	signedProposal, err := val.signingAccount().SignProposal(val.signingAccount().Account(), proposal, val.config.ChainID)
 	if err != nil {
 		log.Crit("Failed to sign the proposal", "err", err)
 	}
@@ -538,6 +660,37 @@ func (val *validator) propose() {
 
 }
 
+// shadowPropose builds and signs the proposal of the validator in shadow mode,
+// without broadcasting it. The block is built on its own work environment, as
+// the current one processes the block proposed by the network.
+func (val *validator) shadowPropose() {
+	start := time.Now()
+
+	block := val.lockedBlock
+	if block == nil {
+		work, err := val.newWork(val.chain.CurrentBlock())
+		if err != nil {
+			log.Error("Failed to create the shadow proposal context", "err", err)
+			return
+		}
+		if block = val.buildBlock(work); block == nil {
+			return
+		}
+	}
+	fragments, err := block.AsFragments(int(block.Size()))
+	if err != nil {
+		log.Error("Failed to get the shadow block as a set of fragments", "err", err)
+		return
+	}
+	proposal := types.NewProposal(val.blockNumber, val.round, fragments.Metadata(), 1, common.Hash{})
+	if _, err := val.signingAccount().SignProposal(val.signingAccount().Account(), proposal, val.config.ChainID); err != nil {
+		log.Error("Failed to sign the shadow proposal", "err", err)
+		return
+	}
+	log.Info("Built the shadow proposal", "number", val.blockNumber, "hash", block.Hash())
+	val.shadow.propose(val.blockNumber.Uint64(), block.Hash(), time.Since(start))
+}
+
 func (val *validator) preVote() {
 	var vote common.Hash
 	switch {
@@ -603,10 +756,19 @@ func (val *validator) preCommit() {
 }
 
 func (val *validator) vote(vote *types.Vote) {
-	signedVote, err := val.walletAccount.SignVote(val.walletAccount.Account(), vote, val.config.ChainID)
+	signedVote, err := val.signingAccount().SignVote(val.signingAccount().Account(), vote, val.config.ChainID)
 	if err != nil {
 		log.Crit("Failed to sign the vote", "err", err)
 	}
+	// the pre-commits of a block are aggregated in the commit of the next block
+	if vote.Type() == types.PreCommit && val.aggregateKey != nil && val.config.IsAggregateCommit(new(big.Int).Add(vote.BlockNumber(), common.Big1)) {
+		signedVote = types.SignVoteAggregate(signedVote, val.signer, val.aggregateKey)
+	}
+
+	if val.shadow != nil {
+		val.shadow.vote(signedVote, val.roundStart)
+		return
+	}
 
 	addressVote, err := types.NewAddressVote(val.signer, signedVote)
 	if err != nil {
@@ -694,19 +856,62 @@ func (val *validator) AddBlockFragment(blockNumber *big.Int, round uint64, fragm
 	return nil
 }
 
+// observeCommits records the blocks committed by the network in shadow mode,
+// until stop is closed.
+func (val *validator) observeCommits(stop chan struct{}) {
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := val.chain.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	last := val.chain.CurrentBlock().NumberU64()
+	for {
+		select {
+		case head := <-heads:
+			// several blocks may have been imported at once
+			for number := last + 1; number <= head.Block.NumberU64(); number++ {
+				if block := val.chain.GetBlockByNumber(number); block != nil {
+					val.shadow.commit(block)
+				}
+			}
+			if number := head.Block.NumberU64(); number > last {
+				last = number
+			}
+		case <-sub.Err():
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// committedByNetwork reports whether the network committed the block of the
+// current election, in shadow mode.
+func (val *validator) committedByNetwork() bool {
+	return val.chain.CurrentBlock().Number().Cmp(val.blockNumber) >= 0
+}
+
 func (val *validator) makeCurrent(parent *types.Block) error {
-	state, err := val.chain.StateAt(parent.Root())
+	work, err := val.newWork(parent)
 	if err != nil {
 		return err
 	}
+	val.work = work
+	return nil
+}
+
+// newWork creates a work environment on top of the given block.
+func (val *validator) newWork(parent *types.Block) (*work, error) {
+	state, err := val.chain.StateAt(parent.Root())
+	if err != nil {
+		return nil, err
+	}
 	work := &work{
 		state: state,
 	}
 
 	// Keep track of transactions which return errors so they can be removed
 	work.tcount = 0
-	val.work = work
-	return nil
+	return work, nil
 }
 
 func (val *validator) updateValidators(checksum [32]byte, genesis bool) error {
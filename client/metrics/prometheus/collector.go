@@ -0,0 +1,120 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/metrics"
+)
+
+var (
+	// quantiles reported for histograms and timers
+	quantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999, 0.9999}
+
+	// percentiles reported for resetting timers, which use a 0-100 scale
+	resettingPercentiles = []float64{50, 95, 99}
+)
+
+// collector accumulates the metrics of a registry in the Prometheus text
+// exposition format. Durations are reported in seconds, the Prometheus base
+// unit for time.
+type collector struct {
+	buff *bytes.Buffer
+}
+
+// newCollector creates an empty Prometheus metric collector.
+func newCollector() *collector {
+	return &collector{buff: new(bytes.Buffer)}
+}
+
+func (c *collector) addCounter(name string, m metrics.Counter) {
+	c.writeType(name, "counter")
+	c.writeValue(name, "", m.Count())
+}
+
+func (c *collector) addGauge(name string, m metrics.Gauge) {
+	c.writeType(name, "gauge")
+	c.writeValue(name, "", m.Value())
+}
+
+func (c *collector) addGaugeFloat64(name string, m metrics.GaugeFloat64) {
+	c.writeType(name, "gauge")
+	c.writeValue(name, "", m.Value())
+}
+
+func (c *collector) addHistogram(name string, m metrics.Histogram) {
+	ps := m.Percentiles(quantiles)
+
+	c.writeType(name, "summary")
+	for i, q := range quantiles {
+		c.writeValue(name, quantileLabel(q), ps[i])
+	}
+	c.writeValue(name+"_sum", "", m.Sum())
+	c.writeValue(name+"_count", "", m.Count())
+}
+
+func (c *collector) addMeter(name string, m metrics.Meter) {
+	c.writeType(name, "counter")
+	c.writeValue(name, "", m.Count())
+}
+
+func (c *collector) addTimer(name string, m metrics.Timer) {
+	ps := m.Percentiles(quantiles)
+
+	c.writeType(name, "summary")
+	for i, q := range quantiles {
+		c.writeValue(name, quantileLabel(q), seconds(ps[i]))
+	}
+	c.writeValue(name+"_sum", "", seconds(float64(m.Sum())))
+	c.writeValue(name+"_count", "", m.Count())
+}
+
+func (c *collector) addResettingTimer(name string, m metrics.ResettingTimer) {
+	values := m.Values()
+	if len(values) == 0 {
+		return
+	}
+	ps := m.Percentiles(resettingPercentiles)
+
+	c.writeType(name, "summary")
+	for i, p := range resettingPercentiles {
+		c.writeValue(name, quantileLabel(p/100), seconds(float64(ps[i])))
+	}
+	c.writeValue(name+"_sum", "", seconds(m.Mean()*float64(len(values))))
+	c.writeValue(name+"_count", "", len(values))
+}
+
+func (c *collector) writeType(name, kind string) {
+	fmt.Fprintf(c.buff, "# TYPE %s %s\n", mutateKey(name), kind)
+}
+
+func (c *collector) writeValue(name, labels string, value interface{}) {
+	fmt.Fprintf(c.buff, "%s%s %v\n", mutateKey(name), labels, value)
+}
+
+// quantileLabel formats the label set of a summary quantile.
+func quantileLabel(q float64) string {
+	return `{quantile="` + strconv.FormatFloat(q, 'f', -1, 64) + `"}`
+}
+
+// seconds converts a duration in nanoseconds into seconds.
+func seconds(ns float64) float64 {
+	return ns / float64(time.Second)
+}
+
+// mutateKey converts a metric name into a valid Prometheus one, replacing all
+// the unsupported characters (such as the path separators) with underscores.
+func mutateKey(key string) string {
+	name := []byte(key)
+	for i, ch := range name {
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch == '_', ch == ':':
+		case ch >= '0' && ch <= '9' && i > 0:
+		default:
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
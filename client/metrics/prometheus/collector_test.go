@@ -0,0 +1,106 @@
+package prometheus
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/metrics"
+)
+
+func TestMain(m *testing.M) {
+	metrics.Enabled = true
+	m.Run()
+}
+
+func TestCollector(t *testing.T) {
+	reg := metrics.NewRegistry()
+
+	counter := metrics.NewRegisteredCounter("test/counter", reg)
+	counter.Inc(12345)
+
+	gauge := metrics.NewRegisteredGauge("test/gauge", reg)
+	gauge.Update(23456)
+
+	gaugeFloat64 := metrics.NewRegisteredGaugeFloat64("test/gauge_float64", reg)
+	gaugeFloat64.Update(34567.89)
+
+	histogram := metrics.NewRegisteredHistogram("test/histogram", reg, metrics.NewUniformSample(2))
+	histogram.Update(4)
+	histogram.Update(6)
+
+	meter := metrics.NewRegisteredMeter("test/meter", reg)
+	defer meter.Stop()
+	meter.Mark(9999999)
+
+	timer := metrics.NewRegisteredTimer("test/timer", reg)
+	defer timer.Stop()
+	timer.Update(time.Second)
+	timer.Update(3 * time.Second)
+
+	resettingTimer := metrics.NewRegisteredResettingTimer("test/resetting_timer", reg)
+	resettingTimer.Update(10 * time.Millisecond)
+	resettingTimer.Update(30 * time.Millisecond)
+
+	emptyResettingTimer := metrics.NewRegisteredResettingTimer("test/empty_resetting_timer", reg)
+	emptyResettingTimer.Update(time.Second)
+	emptyResettingTimer.Snapshot()
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	const want = `# TYPE test_counter counter
+test_counter 12345
+# TYPE test_gauge gauge
+test_gauge 23456
+# TYPE test_gauge_float64 gauge
+test_gauge_float64 34567.89
+# TYPE test_histogram summary
+test_histogram{quantile="0.5"} 5
+test_histogram{quantile="0.75"} 6
+test_histogram{quantile="0.95"} 6
+test_histogram{quantile="0.99"} 6
+test_histogram{quantile="0.999"} 6
+test_histogram{quantile="0.9999"} 6
+test_histogram_sum 10
+test_histogram_count 2
+# TYPE test_meter counter
+test_meter 9999999
+# TYPE test_resetting_timer summary
+test_resetting_timer{quantile="0.5"} 0.01
+test_resetting_timer{quantile="0.95"} 0.03
+test_resetting_timer{quantile="0.99"} 0.03
+test_resetting_timer_sum 0.04
+test_resetting_timer_count 2
+# TYPE test_timer summary
+test_timer{quantile="0.5"} 2
+test_timer{quantile="0.75"} 3
+test_timer{quantile="0.95"} 3
+test_timer{quantile="0.99"} 3
+test_timer{quantile="0.999"} 3
+test_timer{quantile="0.9999"} 3
+test_timer_sum 4
+test_timer_count 2
+`
+	if have := rec.Body.String(); have != want {
+		t.Fatalf("exposition mismatch:\nhave:\n%s\nwant:\n%s", have, want)
+	}
+	if have := rec.Header().Get("Content-Type"); have != "text/plain; version=0.0.4" {
+		t.Errorf("content type mismatch: have %q", have)
+	}
+}
+
+func TestMutateKey(t *testing.T) {
+	tests := map[string]string{
+		"chain/inserts":             "chain_inserts",
+		"eth/downloader/headers/in": "eth_downloader_headers_in",
+		"p2p/InboundTraffic":        "p2p_InboundTraffic",
+		"1st.metric-name":           "_st_metric_name",
+		"valid_name:total":          "valid_name:total",
+	}
+	for key, want := range tests {
+		if have := mutateKey(key); have != want {
+			t.Errorf("%q: name mismatch: have %q, want %q", key, have, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package prometheus exposes the go-metrics registries in the Prometheus text
+// exposition format.
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/kowala-tech/kcoin/client/metrics"
+)
+
+// Handler returns an HTTP handler serving the metrics of the given registry in
+// the Prometheus text exposition format.
+//
+// Note, resetting timers are cleared on every snapshot, so each scrape only
+// reports the durations measured since the previous one.
+func Handler(reg metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Gather the metrics in a stable order
+		var (
+			names []string
+			items = make(map[string]interface{})
+		)
+		reg.Each(func(name string, i interface{}) {
+			names = append(names, name)
+			items[name] = i
+		})
+		sort.Strings(names)
+
+		// Aggregate all the metrics into a Prometheus collector
+		c := newCollector()
+		for _, name := range names {
+			switch m := items[name].(type) {
+			case metrics.Counter:
+				c.addCounter(name, m.Snapshot())
+			case metrics.Gauge:
+				c.addGauge(name, m.Snapshot())
+			case metrics.GaugeFloat64:
+				c.addGaugeFloat64(name, m.Snapshot())
+			case metrics.Histogram:
+				c.addHistogram(name, m.Snapshot())
+			case metrics.Meter:
+				c.addMeter(name, m.Snapshot())
+			case metrics.Timer:
+				c.addTimer(name, m.Snapshot())
+			case metrics.ResettingTimer:
+				c.addResettingTimer(name, m.Snapshot())
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Header().Set("Content-Length", fmt.Sprint(c.buff.Len()))
+		w.Write(c.buff.Bytes())
+	})
+}
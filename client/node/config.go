@@ -17,6 +17,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/log"
 	"github.com/kowala-tech/kcoin/client/p2p"
 	"github.com/kowala-tech/kcoin/client/p2p/discover"
+	"github.com/kowala-tech/kcoin/client/rpc"
 )
 
 const (
@@ -122,6 +123,12 @@ type Config struct {
 	// exposed.
 	WSModules []string `toml:",omitempty"`
 
+	// RPCAuth requires the clients of the HTTP and websocket RPC interfaces to
+	// authenticate with an API key or a JWT token, subjecting them to the access
+	// policy of the key. The privileged calls received over IPC and in-process
+	// are recorded in the audit log. If nil, the clients are not authenticated.
+	RPCAuth *rpc.AuthConfig `toml:",omitempty"`
+
 	// WSExposeAll exposes all API modules via the WebSocket RPC interface rather
 	// than just the public ones.
 	//
@@ -129,6 +136,29 @@ type Config struct {
 	// private APIs to untrusted users is a major security risk.
 	WSExposeAll bool `toml:",omitempty"`
 
+	// GraphQLHost is the host interface on which to start the GraphQL server. If
+	// this field is empty, no GraphQL endpoint will be started.
+	GraphQLHost string `toml:",omitempty"`
+
+	// GraphQLPort is the TCP port number on which to start the GraphQL server. The
+	// default zero value is valid and will pick a port number randomly (useful for
+	// ephemeral nodes).
+	GraphQLPort int `toml:",omitempty"`
+
+	// GraphQLCors is the Cross-Origin Resource Sharing header to send to requesting
+	// clients. Please be aware that CORS is a browser enforced security, it's fully
+	// useless for custom HTTP clients.
+	GraphQLCors []string `toml:",omitempty"`
+
+	// GraphQLVirtualHosts is the list of virtual hostnames which are allowed on
+	// incoming requests. It is enforced the same way as HTTPVirtualHosts.
+	GraphQLVirtualHosts []string `toml:",omitempty"`
+
+	// MetricsAddr is the network address on which to start the HTTP server serving
+	// the metrics in the Prometheus exposition format at /metrics. If this field is
+	// empty, no metrics endpoint will be started.
+	MetricsAddr string `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 }
@@ -193,6 +223,15 @@ func DefaultHTTPEndpoint() string {
 	return config.HTTPEndpoint()
 }
 
+// GraphQLEndpoint resolves a GraphQL endpoint based on the configured host
+// interface and port parameters.
+func (c *Config) GraphQLEndpoint() string {
+	if c.GraphQLHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.GraphQLHost, c.GraphQLPort)
+}
+
 // WSEndpoint resolves a websocket endpoint based on the configured host interface
 // and port parameters.
 func (c *Config) WSEndpoint() string {
@@ -269,6 +308,18 @@ func (c *Config) resolvePath(path string) string {
 	return filepath.Join(c.instanceDir(), path)
 }
 
+// resolveFreezer resolves the directory of the ancient store of the database
+// at root, which defaults to the ancient folder within the database itself.
+func (c *Config) resolveFreezer(root string, freezer string) string {
+	switch {
+	case freezer == "":
+		return filepath.Join(root, "ancient")
+	case !filepath.IsAbs(freezer):
+		return c.resolvePath(freezer)
+	}
+	return freezer
+}
+
 func (c *Config) instanceDir() string {
 	if c.DataDir == "" {
 		return ""
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -11,10 +12,13 @@ import (
 	"sync"
 
 	"github.com/kowala-tech/kcoin/client/accounts"
+	"github.com/kowala-tech/kcoin/client/core/rawdb"
 	"github.com/kowala-tech/kcoin/client/event"
 	"github.com/kowala-tech/kcoin/client/internal/debug"
 	"github.com/kowala-tech/kcoin/client/kcoindb"
 	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/metrics"
+	"github.com/kowala-tech/kcoin/client/metrics/prometheus"
 	"github.com/kowala-tech/kcoin/client/p2p"
 	"github.com/kowala-tech/kcoin/client/rpc"
 	"github.com/prometheus/prometheus/util/flock"
@@ -35,8 +39,9 @@ type Node struct {
 	serviceFuncs []ServiceConstructor     // Service constructors (in dependency order)
 	services     map[reflect.Type]Service // Currently running services
 
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
+	rpcAPIs       []rpc.API          // List of APIs currently provided by the node
+	rpcAuth       *rpc.Authenticator // Authenticator shared by the RPC endpoints (nil = no authentication)
+	inprocHandler *rpc.Server        // In-process RPC request handler to process the API requests
 
 	ipcEndpoint string       // IPC endpoint to listen at (empty = IPC disabled)
 	ipcListener net.Listener // IPC RPC listener socket to serve API requests
@@ -51,6 +56,8 @@ type Node struct {
 	wsListener net.Listener // Websocket RPC listener socket to server API requests
 	wsHandler  *rpc.Server  // Websocket RPC request handler to process the API requests
 
+	metricsListener net.Listener // Metrics HTTP listener socket to serve the Prometheus scrapes
+
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
 
@@ -195,8 +202,17 @@ func (n *Node) Start() error {
 		// Mark the service started for potential cleanup
 		started = append(started, kind)
 	}
+	// Expose the metrics if requested
+	if err := n.startMetrics(n.config.MetricsAddr); err != nil {
+		for _, service := range services {
+			service.Stop()
+		}
+		running.Stop()
+		return err
+	}
 	// Lastly start the configured RPC interfaces
 	if err := n.startRPC(services); err != nil {
+		n.stopMetrics()
 		for _, service := range services {
 			service.Stop()
 		}
@@ -239,6 +255,16 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
 	}
+	// Build the authenticator shared by the endpoints, so that the limits of
+	// an API key apply across all of them
+	n.rpcAuth = nil
+	if n.config.RPCAuth != nil {
+		auth, err := rpc.NewAuthenticator(n.config.RPCAuth)
+		if err != nil {
+			return err
+		}
+		n.rpcAuth = auth
+	}
 	// Start the various API endpoints, terminating all in case of errors
 	if err := n.startInProc(apis); err != nil {
 		return err
@@ -273,6 +299,9 @@ func (n *Node) startInProc(apis []rpc.API) error {
 		}
 		n.log.Debug("InProc registered", "service", api.Service, "namespace", api.Namespace)
 	}
+	if n.rpcAuth != nil {
+		handler.EnableAuth(n.rpcAuth)
+	}
 	n.inprocHandler = handler
 	return nil
 }
@@ -290,7 +319,7 @@ func (n *Node) startIPC(apis []rpc.API) error {
 	if n.ipcEndpoint == "" {
 		return nil // IPC disabled.
 	}
-	listener, handler, err := rpc.StartIPCEndpoint(n.ipcEndpoint, apis)
+	listener, handler, err := rpc.StartIPCEndpoint(n.ipcEndpoint, apis, n.rpcAuth)
 	if err != nil {
 		return err
 	}
@@ -320,7 +349,7 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts)
+	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, n.rpcAuth)
 	if err != nil {
 		return err
 	}
@@ -353,7 +382,7 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, n.rpcAuth)
 	if err != nil {
 		return err
 	}
@@ -380,6 +409,37 @@ func (n *Node) stopWS() {
 	}
 }
 
+// startMetrics initializes and starts the HTTP endpoint serving the metrics of
+// the default registry in the Prometheus exposition format.
+func (n *Node) startMetrics(endpoint string) error {
+	// Short circuit if the metrics endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prometheus.Handler(metrics.DefaultRegistry))
+	go http.Serve(listener, mux)
+
+	n.log.Info("Metrics endpoint opened", "url", fmt.Sprintf("http://%s/metrics", listener.Addr()))
+	n.metricsListener = listener
+
+	return nil
+}
+
+// stopMetrics terminates the metrics HTTP endpoint.
+func (n *Node) stopMetrics() {
+	if n.metricsListener != nil {
+		n.log.Info("Metrics endpoint closed", "url", fmt.Sprintf("http://%s/metrics", n.metricsListener.Addr()))
+
+		n.metricsListener.Close()
+		n.metricsListener = nil
+	}
+}
+
 // Stop terminates a running node along with all it's services. In the node was
 // not started, an error is returned.
 func (n *Node) Stop() error {
@@ -395,6 +455,7 @@ func (n *Node) Stop() error {
 	n.stopWS()
 	n.stopHTTP()
 	n.stopIPC()
+	n.stopMetrics()
 	n.rpcAPIs = nil
 	failure := &StopError{
 		Services: make(map[reflect.Type]error),
@@ -557,6 +618,19 @@ func (n *Node) OpenDatabase(name string, cache, handles int) (kcoindb.Database,
 	return kcoindb.NewLDBDatabase(n.config.resolvePath(name), cache, handles)
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's instance
+// directory, also attaching a chain freezer to it that moves ancient chain data
+// from the database to immutable append-only files. If the node is ephemeral, a
+// memory database is returned.
+func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, freezer, namespace string) (kcoindb.Database, error) {
+	if n.config.DataDir == "" {
+		return kcoindb.NewMemDatabase(), nil
+	}
+	root := n.config.resolvePath(name)
+	return rawdb.NewLevelDBDatabaseWithFreezer(root, cache, handles, n.config.resolveFreezer(root, freezer), namespace)
+}
+
 // ResolvePath returns the absolute path of a resource in the instance directory.
 func (n *Node) ResolvePath(x string) string {
 	return n.config.resolvePath(x)
@@ -4,6 +4,7 @@ import (
 	"reflect"
 
 	"github.com/kowala-tech/kcoin/client/accounts"
+	"github.com/kowala-tech/kcoin/client/core/rawdb"
 	"github.com/kowala-tech/kcoin/client/event"
 	"github.com/kowala-tech/kcoin/client/kcoindb"
 	"github.com/kowala-tech/kcoin/client/p2p"
@@ -34,6 +35,19 @@ func (ctx *ServiceContext) OpenDatabase(name string, cache int, handles int) (kc
 	return db, nil
 }
 
+// OpenDatabaseWithFreezer opens an existing database with the given name (or
+// creates one if no previous can be found) from within the node's data directory,
+// also attaching a chain freezer to it that moves ancient chain data from the
+// database to immutable append-only files. If the node is an ephemeral one, a
+// memory database is returned.
+func (ctx *ServiceContext) OpenDatabaseWithFreezer(name string, cache int, handles int, freezer string, namespace string) (kcoindb.Database, error) {
+	if ctx.config.DataDir == "" {
+		return kcoindb.NewMemDatabase(), nil
+	}
+	root := ctx.config.resolvePath(name)
+	return rawdb.NewLevelDBDatabaseWithFreezer(root, cache, handles, ctx.config.resolveFreezer(root, freezer), namespace)
+}
+
 // ResolvePath resolves a user path into the data directory if that was relative
 // and if the user actually uses persistent storage. It will return an empty string
 // for emphemeral storage and the user's own input for absolute paths.
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"time"
 
 	"github.com/kowala-tech/kcoin/client/log"
 	"github.com/kowala-tech/kcoin/client/p2p/discover"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/p2p/netutil"
 )
 
@@ -66,6 +68,10 @@ type dialstate struct {
 
 	start     time.Time        // time when the dialer was first used
 	bootnodes []*discover.Node // default dials when there are no peers
+
+	records    recordStore        // records of the discovered nodes, nil if unavailable
+	local      func() *enr.Record // record of the local node
+	relayPeers int                // number of consensus-relaying peers to keep
 }
 
 type discoverTable interface {
@@ -204,14 +210,16 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	randomCandidates := needDynDials / 2
 	if randomCandidates > 0 {
 		n := s.ntab.ReadRandomNodes(s.randomNodes)
-		for i := 0; i < randomCandidates && i < n; i++ {
-			if addDial(dynDialedConn, s.randomNodes[i]) {
+		candidates := s.prefer(s.randomNodes[:n], peers)
+		for i := 0; i < randomCandidates && i < len(candidates); i++ {
+			if addDial(dynDialedConn, candidates[i]) {
 				needDynDials--
 			}
 		}
 	}
 	// Create dynamic dials from random lookup results, removing tried
 	// items from the result buffer.
+	s.lookupBuf = s.prefer(s.lookupBuf, peers)
 	i := 0
 	for ; i < len(s.lookupBuf) && needDynDials > 0; i++ {
 		if addDial(dynDialedConn, s.lookupBuf[i]) {
@@ -236,6 +244,54 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	return newtasks
 }
 
+// prefer reorders the dynamic dial candidates in place based on their node
+// records: the nodes on another fork are dropped, the ones known to be on the
+// same fork come first and, until enough of them are connected, the ones
+// relaying consensus traffic are put ahead.
+func (s *dialstate) prefer(nodes []*discover.Node, peers map[discover.NodeID]*Peer) []*discover.Node {
+	if s.records == nil || len(nodes) == 0 {
+		return nodes
+	}
+	var (
+		fork    enr.ForkID
+		hasFork bool
+	)
+	if s.local != nil {
+		fork, hasFork = recordFork(s.local())
+	}
+	relays := 0
+	for _, p := range peers {
+		if p.Relay() {
+			relays++
+		}
+	}
+	wantRelays := relays < s.relayPeers
+
+	// Rank the candidates, lower is better
+	var (
+		kept  = nodes[:0]
+		ranks = make(map[discover.NodeID]int, len(nodes))
+	)
+	for _, n := range nodes {
+		r := s.records.Record(n.ID)
+		rank := 3
+		if remote, ok := recordFork(r); ok && hasFork {
+			if remote != fork {
+				log.Trace("Skipping dial candidate on another fork", "id", n.ID, "fork", remote)
+				continue
+			}
+			rank--
+		}
+		if wantRelays && recordRelay(r) {
+			rank -= 2
+		}
+		ranks[n.ID] = rank
+		kept = append(kept, n)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return ranks[kept[i].ID] < ranks[kept[j].ID] })
+	return kept
+}
+
 var (
 	errSelf             = errors.New("is self")
 	errAlreadyDialing   = errors.New("already dialing")
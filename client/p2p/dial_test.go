@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
 	"github.com/kowala-tech/kcoin/client/p2p/discover"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/p2p/netutil"
 )
 
@@ -639,6 +642,73 @@ func TestDialResolve(t *testing.T) {
 	}
 }
 
+// recordTable is a record store serving fixed node records.
+type recordTable map[discover.NodeID]*enr.Record
+
+func (t recordTable) Record(id discover.NodeID) *enr.Record { return t[id] }
+func (t recordTable) SetLocalRecord(*enr.Record)            {}
+
+func signedRecord(t *testing.T, entries ...enr.Entry) *enr.Record {
+	key, _ := crypto.GenerateKey()
+
+	var r enr.Record
+	for _, e := range entries {
+		r.Set(e)
+	}
+	if err := enr.SignV4(&r, key); err != nil {
+		t.Fatal(err)
+	}
+	return &r
+}
+
+// This test checks that dial candidates on another fork are skipped and that
+// the consensus-relaying ones are preferred until enough are connected.
+func TestDialStatePrefer(t *testing.T) {
+	var (
+		fork  = enr.NewForkID(common.HexToHash("0x01"), 1)
+		other = enr.NewForkID(common.HexToHash("0x01"), 2)
+		local = signedRecord(t, fork)
+	)
+	s := newDialState(nil, nil, fakeTable{}, 5, nil)
+	s.records = recordTable{
+		uintID(1): signedRecord(t, other, enr.Relay(true)),
+		uintID(2): signedRecord(t, fork, enr.Relay(true)),
+		uintID(4): signedRecord(t, fork),
+		uintID(5): signedRecord(t, enr.Relay(true)),
+	}
+	s.local = func() *enr.Record { return local }
+	s.relayPeers = 1
+
+	candidates := func() []*discover.Node {
+		var nodes []*discover.Node
+		for i := uint32(1); i <= 5; i++ {
+			nodes = append(nodes, &discover.Node{ID: uintID(i)})
+		}
+		return nodes
+	}
+	ids := func(nodes []*discover.Node) []discover.NodeID {
+		var ids []discover.NodeID
+		for _, n := range nodes {
+			ids = append(ids, n.ID)
+		}
+		return ids
+	}
+
+	// Without relaying peers, the relays come first.
+	have := ids(s.prefer(candidates(), nil))
+	want := []discover.NodeID{uintID(2), uintID(5), uintID(4), uintID(3)}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("candidate order mismatch without relays:\nhave %v\nwant %v", have, want)
+	}
+	// With enough relaying peers, only the fork matters.
+	relay := &Peer{rw: &conn{id: uintID(6), record: signedRecord(t, enr.Relay(true))}}
+	have = ids(s.prefer(candidates(), map[discover.NodeID]*Peer{relay.ID(): relay}))
+	want = []discover.NodeID{uintID(2), uintID(4), uintID(3), uintID(5)}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("candidate order mismatch with relays:\nhave %v\nwant %v", have, want)
+	}
+}
+
 // compares task lists but doesn't care about the order.
 func sametasks(a, b []task) bool {
 	if len(a) != len(b) {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/kowala-tech/kcoin/client/crypto"
 	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/rlp"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
@@ -46,6 +47,11 @@ var (
 	nodeDBDiscoverPing      = nodeDBDiscoverRoot + ":lastping"
 	nodeDBDiscoverPong      = nodeDBDiscoverRoot + ":lastpong"
 	nodeDBDiscoverFindFails = nodeDBDiscoverRoot + ":findfail"
+	nodeDBDiscoverRecord    = nodeDBDiscoverRoot + ":enr"
+
+	nodeDBPeerRoot   = ":peer"
+	nodeDBPeerScore  = nodeDBPeerRoot + ":score"
+	nodeDBPeerBanned = nodeDBPeerRoot + ":banned"
 )
 
 // newNodeDB creates a new node database for storing and retrieving infos about
@@ -246,6 +252,10 @@ func (db *nodeDB) expireNodes() error {
 				continue
 			}
 		}
+		// Skip the node if it's still banned, so the ban isn't forgotten
+		if db.bannedUntil(id).After(time.Now()) {
+			continue
+		}
 		// Otherwise delete all associated information
 		db.deleteNode(id)
 	}
@@ -287,6 +297,49 @@ func (db *nodeDB) updateFindFails(id NodeID, fails int) error {
 	return db.storeInt64(makeKey(id, nodeDBDiscoverFindFails), int64(fails))
 }
 
+// record retrieves the last signed node record received from a node.
+func (db *nodeDB) record(id NodeID) *enr.Record {
+	blob, err := db.lvl.Get(makeKey(id, nodeDBDiscoverRecord), nil)
+	if err != nil {
+		return nil
+	}
+	r, err := DecodeRecord(id, blob)
+	if err != nil {
+		log.Error("Failed to decode node record", "err", err)
+		return nil
+	}
+	return r
+}
+
+// updateRecord stores the signed node record of a node.
+func (db *nodeDB) updateRecord(id NodeID, r *enr.Record) error {
+	blob, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return err
+	}
+	return db.lvl.Put(makeKey(id, nodeDBDiscoverRecord), blob, nil)
+}
+
+// peerScore retrieves the reputation score of a peer.
+func (db *nodeDB) peerScore(id NodeID) int {
+	return int(db.fetchInt64(makeKey(id, nodeDBPeerScore)))
+}
+
+// updatePeerScore updates the reputation score of a peer.
+func (db *nodeDB) updatePeerScore(id NodeID, score int) error {
+	return db.storeInt64(makeKey(id, nodeDBPeerScore), int64(score))
+}
+
+// bannedUntil retrieves the time until which a peer is banned.
+func (db *nodeDB) bannedUntil(id NodeID) time.Time {
+	return time.Unix(db.fetchInt64(makeKey(id, nodeDBPeerBanned)), 0)
+}
+
+// updateBannedUntil updates the time until which a peer is banned.
+func (db *nodeDB) updateBannedUntil(id NodeID, instance time.Time) error {
+	return db.storeInt64(makeKey(id, nodeDBPeerBanned), instance.Unix())
+}
+
 // querySeeds retrieves random nodes to be used as potential seed nodes
 // for bootstrapping.
 func (db *nodeDB) querySeeds(n int, maxAge time.Duration) []*Node {
@@ -1,6 +1,7 @@
 package discover
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"encoding/hex"
@@ -18,6 +19,8 @@ import (
 	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/crypto"
 	"github.com/kowala-tech/kcoin/client/crypto/secp256k1"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
+	"github.com/kowala-tech/kcoin/client/rlp"
 )
 
 const NodeIDBits = 512
@@ -312,6 +315,19 @@ func (id NodeID) Pubkey() (*ecdsa.PublicKey, error) {
 	return p, nil
 }
 
+// DecodeRecord decodes a signed node record, ensuring that it belongs to the
+// node with the given ID.
+func DecodeRecord(id NodeID, blob []byte) (*enr.Record, error) {
+	r := new(enr.Record)
+	if err := rlp.DecodeBytes(blob, r); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(r.NodeAddr(), crypto.Keccak256(id[:])) {
+		return nil, errors.New("record signed by another node")
+	}
+	return r, nil
+}
+
 // recoverNodeID computes the public key used to sign the
 // given hash from the signature.
 func recoverNodeID(hash, sig []byte) (id NodeID, err error) {
@@ -19,6 +19,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/crypto"
 	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/p2p/netutil"
 )
 
@@ -61,8 +62,9 @@ type Table struct {
 
 	nodeAddedHook func(*Node) // for testing
 
-	net  transport
-	self *Node // metadata of the local node
+	net    transport
+	self   *Node       // metadata of the local node
+	record *enr.Record // signed record of the local node, protected by mutex
 }
 
 // transport is implemented by the UDP transport.
@@ -173,6 +175,41 @@ func (tab *Table) ReadRandomNodes(buf []*Node) (n int) {
 	return i + 1
 }
 
+// Reputation retrieves the stored score of a peer and the time until which
+// it's banned.
+func (tab *Table) Reputation(id NodeID) (score int, bannedUntil time.Time) {
+	return tab.db.peerScore(id), tab.db.bannedUntil(id)
+}
+
+// SetReputation stores the score of a peer and the time until which it's banned.
+func (tab *Table) SetReputation(id NodeID, score int, bannedUntil time.Time) error {
+	if err := tab.db.updatePeerScore(id, score); err != nil {
+		return err
+	}
+	return tab.db.updateBannedUntil(id, bannedUntil)
+}
+
+// Record retrieves the last signed record advertised by a node, or nil if the
+// node didn't advertise any.
+func (tab *Table) Record(id NodeID) *enr.Record {
+	return tab.db.record(id)
+}
+
+// SetLocalRecord sets the signed record advertised by the local node to the
+// nodes pinging it.
+func (tab *Table) SetLocalRecord(r *enr.Record) {
+	tab.mutex.Lock()
+	defer tab.mutex.Unlock()
+	tab.record = r
+}
+
+// localRecord returns the signed record of the local node.
+func (tab *Table) localRecord() *enr.Record {
+	tab.mutex.Lock()
+	defer tab.mutex.Unlock()
+	return tab.record
+}
+
 // Close terminates the network listener and flushes the node database.
 func (tab *Table) Close() {
 	select {
@@ -587,11 +587,18 @@ func (req *ping) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) er
 	if expired(req.Expiration) {
 		return errExpired
 	}
-	t.send(from, pongPacket, &pong{
+	reply := &pong{
 		To:         makeEndpoint(from, req.From.TCP),
 		ReplyTok:   mac,
 		Expiration: uint64(time.Now().Add(expiration).Unix()),
-	})
+	}
+	// Advertise the local node record, if any, as an additional field
+	if r := t.localRecord(); r != nil {
+		if blob, err := rlp.EncodeToBytes(r); err == nil {
+			reply.Rest = []rlp.RawValue{blob}
+		}
+	}
+	t.send(from, pongPacket, reply)
 	t.handleReply(fromID, pingPacket, req)
 
 	// Add the node to the table. Before doing so, ensure that we have a recent enough pong
@@ -616,6 +623,16 @@ func (req *pong) handle(t *udp, from *net.UDPAddr, fromID NodeID, mac []byte) er
 		return errUnsolicitedReply
 	}
 	t.db.updateLastPongReceived(fromID, time.Now())
+
+	// Store the node record advertised by the remote node, if any
+	if len(req.Rest) > 0 {
+		r, err := DecodeRecord(fromID, req.Rest[0])
+		if err != nil {
+			log.Trace("Invalid node record", "id", fromID, "err", err)
+			return nil
+		}
+		t.db.updateRecord(fromID, r)
+	}
 	return nil
 }
 
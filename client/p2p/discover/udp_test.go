@@ -36,6 +36,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/rlp"
 )
 
@@ -388,6 +389,70 @@ func TestUDP_successfulPing(t *testing.T) {
 	}
 }
 
+func TestUDP_recordExchange(t *testing.T) {
+	test := newUDPTest(t)
+	defer test.table.Close()
+
+	// Advertise a local record in the pong replies.
+	var local enr.Record
+	local.Set(enr.Relay(true))
+	if err := enr.SignV4(&local, test.localkey); err != nil {
+		t.Fatal(err)
+	}
+	test.table.SetLocalRecord(&local)
+
+	go test.packetIn(nil, pingPacket, &ping{From: testRemote, To: testLocalAnnounced, Version: 4, Expiration: futureExp})
+	test.waitPacketOut(func(p *pong) {
+		if len(p.Rest) == 0 {
+			t.Fatal("pong carries no record")
+		}
+		r, err := DecodeRecord(PubkeyID(&test.localkey.PublicKey), p.Rest[0])
+		if err != nil {
+			t.Fatalf("invalid record in pong: %v", err)
+		}
+		var relay enr.Relay
+		if err := r.Load(&relay); err != nil || !relay {
+			t.Errorf("relay entry mismatch: have %v, err %v", relay, err)
+		}
+	})
+
+	// The remote record is stored when the pong arrives.
+	var remote enr.Record
+	remote.Set(enr.NewForkID(common.Hash{}, 1))
+	if err := enr.SignV4(&remote, test.remotekey); err != nil {
+		t.Fatal(err)
+	}
+	blob, _ := rlp.EncodeToBytes(&remote)
+
+	hash, _ := test.waitPacketOut(func(p *ping) error { return nil })
+	test.packetIn(nil, pongPacket, &pong{ReplyTok: hash, Expiration: futureExp, Rest: []rlp.RawValue{blob}})
+
+	r := test.table.Record(PubkeyID(&test.remotekey.PublicKey))
+	if r == nil {
+		t.Fatal("remote record not stored")
+	}
+	var fork enr.ForkID
+	if err := r.Load(&fork); err != nil || fork != enr.NewForkID(common.Hash{}, 1) {
+		t.Errorf("fork entry mismatch: have %x, err %v", fork, err)
+	}
+}
+
+func TestDecodeRecord(t *testing.T) {
+	key, other := newkey(), newkey()
+
+	var r enr.Record
+	if err := enr.SignV4(&r, key); err != nil {
+		t.Fatal(err)
+	}
+	blob, _ := rlp.EncodeToBytes(&r)
+	if _, err := DecodeRecord(PubkeyID(&key.PublicKey), blob); err != nil {
+		t.Errorf("valid record rejected: %v", err)
+	}
+	if _, err := DecodeRecord(PubkeyID(&other.PublicKey), blob); err == nil {
+		t.Error("record of another node accepted")
+	}
+}
+
 var testPackets = []struct {
 	input      string
 	wantPacket interface{}
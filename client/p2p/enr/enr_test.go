@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/crypto"
 	"github.com/kowala-tech/kcoin/client/rlp"
 	"github.com/stretchr/testify/assert"
@@ -221,6 +222,58 @@ func TestNodeAddr(t *testing.T) {
 
 var pyRecord, _ = hex.DecodeString("f884b8407098ad865b00a582051940cb9cf36836572411a47278783077011599ed5cd16b76f2635f4e234738f30813a89eb9137e3e3df5266e3a1f11df72ecf1145ccb9c01826964827634826970847f00000189736563703235366b31a103ca634cae0d49acb401d8a4c6b6fe8c55b70d115bf400769cc1400f3258cd31388375647082765f")
 
+// TestGetSetCapabilities tests encoding/decoding and setting/getting of the
+// fork and relay keys.
+func TestGetSetCapabilities(t *testing.T) {
+	fork := NewForkID(common.HexToHash("0x01"), 1)
+	var r Record
+	r.Set(fork)
+	r.Set(Relay(true))
+	require.NoError(t, SignV4(&r, privkey))
+
+	blob, err := rlp.EncodeToBytes(r)
+	require.NoError(t, err)
+	var r2 Record
+	require.NoError(t, rlp.DecodeBytes(blob, &r2))
+
+	var (
+		fork2 ForkID
+		relay Relay
+	)
+	require.NoError(t, r2.Load(&fork2))
+	require.NoError(t, r2.Load(&relay))
+	assert.Equal(t, fork, fork2)
+	assert.True(t, bool(relay))
+	assert.NotEqual(t, fork, NewForkID(common.HexToHash("0x01"), 2))
+}
+
+// TestValidatorProof tests signing and verification of the validator key.
+func TestValidatorProof(t *testing.T) {
+	account, _ := crypto.GenerateKey()
+	sign := func(hash []byte) ([]byte, error) { return crypto.Sign(hash, account) }
+
+	var r Record
+	require.NoError(t, SignV4(&r, privkey))
+	proof, err := SignValidator(r.NodeAddr(), crypto.PubkeyToAddress(account.PublicKey), sign)
+	require.NoError(t, err)
+	r.Set(proof)
+	require.NoError(t, SignV4(&r, privkey))
+
+	var proof2 Validator
+	require.NoError(t, r.Load(&proof2))
+	assert.NoError(t, proof2.Verify(r.NodeAddr()))
+
+	// The proof must not be valid for any other node.
+	other, _ := crypto.GenerateKey()
+	var r2 Record
+	require.NoError(t, SignV4(&r2, other))
+	assert.Error(t, proof2.Verify(r2.NodeAddr()))
+
+	// Nor for any other validator account.
+	proof2.Address = common.HexToAddress("0x01")
+	assert.Error(t, proof2.Verify(r.NodeAddr()))
+}
+
 // TestPythonInterop checks that we can decode and verify a record produced by the Python
 // implementation.
 func TestPythonInterop(t *testing.T) {
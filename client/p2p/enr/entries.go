@@ -2,10 +2,14 @@ package enr
 
 import (
 	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"net"
 
+	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/client/crypto"
 	"github.com/kowala-tech/kcoin/client/rlp"
 )
@@ -106,6 +110,75 @@ func (v *Secp256k1) DecodeRLP(s *rlp.Stream) error {
 	return nil
 }
 
+// ForkID is the "fork" key, which holds the identifier of the chain the node
+// is following.
+type ForkID [4]byte
+
+func (v ForkID) ENRKey() string { return "fork" }
+
+// NewForkID computes the fork identifier of the chain with the given genesis
+// block hash and network ID.
+func NewForkID(genesis common.Hash, networkID uint64) ForkID {
+	var (
+		id  ForkID
+		buf [8]byte
+	)
+	binary.BigEndian.PutUint64(buf[:], networkID)
+	binary.BigEndian.PutUint32(id[:], crc32.ChecksumIEEE(append(genesis[:], buf[:]...)))
+	return id
+}
+
+// Relay is the "relay" key, which holds whether the node relays consensus
+// traffic (proposals, votes and block fragments).
+type Relay bool
+
+func (v Relay) ENRKey() string { return "relay" }
+
+// Validator is the "validator" key, which holds a proof that the node is
+// operated by the owner of a validator account.
+type Validator struct {
+	Address common.Address // validator account
+	Sig     []byte         // signature of the node address by the validator account
+}
+
+func (v Validator) ENRKey() string { return "validator" }
+
+var errInvalidValidatorProof = errors.New("invalid validator proof")
+
+// validatorProofHash returns the hash signed by a validator account to prove
+// that it operates the node with the given address.
+func validatorProofHash(nodeAddr []byte) []byte {
+	return crypto.Keccak256([]byte("validator"), nodeAddr)
+}
+
+// SignValidator creates a proof that the node with the given address is
+// operated by the owner of the validator account, using the given function to
+// sign the proof hash with the account key.
+func SignValidator(nodeAddr []byte, account common.Address, sign func(hash []byte) ([]byte, error)) (Validator, error) {
+	sig, err := sign(validatorProofHash(nodeAddr))
+	if err != nil {
+		return Validator{}, err
+	}
+	v := Validator{Address: account, Sig: sig}
+	return v, v.Verify(nodeAddr)
+}
+
+// Verify checks that the proof was signed by the validator account for the
+// node with the given address.
+func (v Validator) Verify(nodeAddr []byte) error {
+	if len(v.Sig) != 65 {
+		return errInvalidValidatorProof
+	}
+	pubkey, err := crypto.SigToPub(validatorProofHash(nodeAddr), v.Sig)
+	if err != nil {
+		return err
+	}
+	if crypto.PubkeyToAddress(*pubkey) != v.Address {
+		return errInvalidValidatorProof
+	}
+	return nil
+}
+
 // KeyError is an error related to a key.
 type KeyError struct {
 	Key string
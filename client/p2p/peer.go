@@ -95,6 +95,9 @@ type Peer struct {
 
 	// events receives message send / receive events if set
 	events *event.Feed
+
+	// reputation tracks the peer score if set
+	reputation *reputation
 }
 
 // NewPeer returns a peer for testing purposes.
@@ -169,6 +172,25 @@ func (p *Peer) Log() log.Logger {
 	return p.log
 }
 
+// Score returns the current reputation score of the peer.
+func (p *Peer) Score() int {
+	return p.reputation.score(p.ID())
+}
+
+// AdjustScore changes the reputation score of the peer by delta. Sub-protocols
+// use negative deltas to report misbehaviour. Peers falling below the ban
+// threshold get disconnected and are refused until the ban expires, unless
+// they're trusted.
+func (p *Peer) AdjustScore(delta int, reason string) {
+	score, banned := p.reputation.adjust(p.ID(), delta)
+	p.log.Debug("Adjusted peer score", "delta", delta, "score", score, "reason", reason)
+
+	if banned && !p.rw.is(trustedConn) {
+		p.log.Debug("Banning misbehaving peer", "reason", reason)
+		p.Disconnect(DiscUselessPeer)
+	}
+}
+
 func (p *Peer) run() (remoteRequested bool, err error) {
 	var (
 		writeStart = make(chan struct{}, 1)
@@ -400,10 +422,13 @@ func (rw *protoRW) ReadMsg() (Msg, error) {
 // peer. Sub-protocol independent fields are contained and initialized here, with
 // protocol specifics delegated to all connected sub-protocols.
 type PeerInfo struct {
-	ID      string   `json:"id"`   // Unique node identifier (also the encryption key)
-	Name    string   `json:"name"` // Name of the node, including client type, version, OS, custom data
-	Caps    []string `json:"caps"` // Sum-protocols advertised by this particular peer
-	Network struct {
+	ID        string   `json:"id"`                  // Unique node identifier (also the encryption key)
+	Name      string   `json:"name"`                // Name of the node, including client type, version, OS, custom data
+	Caps      []string `json:"caps"`                // Sum-protocols advertised by this particular peer
+	Score     int      `json:"score"`               // Reputation score of the peer
+	Relay     bool     `json:"relay"`               // Whether the peer relays consensus traffic
+	Validator string   `json:"validator,omitempty"` // Validator account operating the peer, if proven
+	Network   struct {
 		LocalAddress  string `json:"localAddress"`  // Local endpoint of the TCP data connection
 		RemoteAddress string `json:"remoteAddress"` // Remote endpoint of the TCP data connection
 		Inbound       bool   `json:"inbound"`
@@ -425,8 +450,13 @@ func (p *Peer) Info() *PeerInfo {
 		ID:        p.ID().String(),
 		Name:      p.Name(),
 		Caps:      caps,
+		Score:     p.Score(),
+		Relay:     p.Relay(),
 		Protocols: make(map[string]interface{}),
 	}
+	if validator, ok := p.Validator(); ok {
+		info.Validator = validator.Hex()
+	}
 	info.Network.LocalAddress = p.LocalAddr().String()
 	info.Network.RemoteAddress = p.RemoteAddr().String()
 	info.Network.Inbound = p.rw.is(inboundConn)
@@ -0,0 +1,133 @@
+package p2p
+
+import (
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/p2p/discover"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
+	"github.com/kowala-tech/kcoin/client/rlp"
+)
+
+// recordStore gives access to the signed node records learnt through the
+// discovery protocol. It is implemented by the discovery table.
+type recordStore interface {
+	Record(id discover.NodeID) *enr.Record
+	SetLocalRecord(r *enr.Record)
+}
+
+// LocalRecord returns the signed record advertised by the local node, or nil
+// if the server was never started.
+func (srv *Server) LocalRecord() *enr.Record {
+	srv.recordLock.Lock()
+	defer srv.recordLock.Unlock()
+	return srv.record
+}
+
+// SetRecordEntries adds or updates entries of the signed record advertised by
+// the local node, both in discovery and in the protocol handshake of the new
+// connections. The record is re-signed with the node key.
+func (srv *Server) SetRecordEntries(entries ...enr.Entry) error {
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if !srv.running {
+		return errServerStopped
+	}
+	return srv.setRecordEntries(srv.makeSelf(srv.listener, srv.ntab), entries)
+}
+
+// setRecordEntries updates and re-signs the local node record, filling in the
+// endpoint of the given local node.
+func (srv *Server) setRecordEntries(self *discover.Node, entries []enr.Entry) error {
+	srv.recordLock.Lock()
+	defer srv.recordLock.Unlock()
+
+	var r enr.Record
+	if srv.record != nil {
+		r = *srv.record
+	}
+	if self.IP != nil && !self.IP.IsUnspecified() {
+		r.Set(enr.IP(self.IP))
+	}
+	if self.TCP != 0 {
+		r.Set(enr.TCP(self.TCP))
+	}
+	if self.UDP != 0 {
+		r.Set(enr.UDP(self.UDP))
+	}
+	for _, e := range entries {
+		r.Set(e)
+	}
+	if err := enr.SignV4(&r, srv.PrivateKey); err != nil {
+		return err
+	}
+	srv.record = &r
+
+	if store, ok := srv.ntab.(recordStore); ok {
+		store.SetLocalRecord(&r)
+	}
+	return nil
+}
+
+// localHandshake returns the protocol handshake of the local node, carrying
+// its signed record as an additional field.
+func (srv *Server) localHandshake() *protoHandshake {
+	r := srv.LocalRecord()
+	if r == nil {
+		return srv.ourHandshake
+	}
+	blob, err := rlp.EncodeToBytes(r)
+	if err != nil {
+		return srv.ourHandshake
+	}
+	hs := *srv.ourHandshake
+	hs.Rest = []rlp.RawValue{blob}
+	return &hs
+}
+
+// Record returns the signed record advertised by the peer in the protocol
+// handshake, or nil if it didn't advertise any.
+func (p *Peer) Record() *enr.Record {
+	return p.rw.record
+}
+
+// Relay reports whether the peer advertises relaying consensus traffic.
+func (p *Peer) Relay() bool {
+	return recordRelay(p.Record())
+}
+
+// Validator returns the validator account the peer proved to be operated by,
+// if any.
+func (p *Peer) Validator() (common.Address, bool) {
+	r := p.Record()
+	if r == nil {
+		return common.Address{}, false
+	}
+	var proof enr.Validator
+	if err := r.Load(&proof); err != nil {
+		return common.Address{}, false
+	}
+	if err := proof.Verify(crypto.Keccak256(p.ID().Bytes())); err != nil {
+		return common.Address{}, false
+	}
+	return proof.Address, true
+}
+
+// recordFork returns the fork identifier advertised in a node record.
+func recordFork(r *enr.Record) (enr.ForkID, bool) {
+	var fork enr.ForkID
+	if r == nil || r.Load(&fork) != nil {
+		return fork, false
+	}
+	return fork, true
+}
+
+// recordRelay reports whether a node record advertises relaying consensus
+// traffic.
+func recordRelay(r *enr.Record) bool {
+	var relay enr.Relay
+	if r == nil || r.Load(&relay) != nil {
+		return false
+	}
+	return bool(relay)
+}
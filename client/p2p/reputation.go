@@ -0,0 +1,156 @@
+package p2p
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/p2p/discover"
+)
+
+const (
+	// Peers whose score falls below this threshold get disconnected and banned.
+	defaultBanThreshold = -100
+
+	// Amount of time a banned peer is refused by the server.
+	defaultBanDuration = time.Hour
+
+	// Amount of time after which a peer score is halved, so that occasional
+	// misbehaviour is forgotten.
+	scoreHalfLife = time.Hour
+
+	// Number of peer reputations kept in memory, the least recently used ones
+	// are reloaded from the store when needed.
+	maxReputationRecords = 1024
+)
+
+// reputationStore persists the peer scores and bans across restarts. It is
+// implemented by the discovery table on top of the node database.
+type reputationStore interface {
+	Reputation(id discover.NodeID) (score int, bannedUntil time.Time)
+	SetReputation(id discover.NodeID, score int, bannedUntil time.Time) error
+}
+
+// peerRecord is the reputation known about a single peer.
+type peerRecord struct {
+	score       int
+	bannedUntil time.Time
+	updated     time.Time // Time the score was last adjusted at
+}
+
+// current returns the score of the record, halved for every half-life elapsed
+// since the last adjustment.
+func (rec *peerRecord) current(now time.Time) int {
+	elapsed := now.Sub(rec.updated)
+	if elapsed <= 0 || rec.score == 0 {
+		return rec.score
+	}
+	return int(math.Round(float64(rec.score) * math.Pow(0.5, float64(elapsed)/float64(scoreHalfLife))))
+}
+
+// reputation tracks the score of the remote peers, as reported by the
+// sub-protocols, and bans the ones misbehaving repeatedly. A nil reputation
+// tracks nothing and never bans.
+type reputation struct {
+	threshold int           // Score below which peers get banned
+	duration  time.Duration // Amount of time a ban lasts
+	store     reputationStore
+	now       func() time.Time // Clock, overridden in tests
+
+	records *lru.Cache // Recently used peer records, by node id
+	lock    sync.Mutex
+}
+
+// newReputation creates a peer reputation tracker. If the store is nil, scores
+// and bans are only kept in memory.
+func newReputation(threshold int, duration time.Duration, store reputationStore) *reputation {
+	if threshold == 0 {
+		threshold = defaultBanThreshold
+	}
+	if duration == 0 {
+		duration = defaultBanDuration
+	}
+	records, _ := lru.New(maxReputationRecords)
+	return &reputation{
+		threshold: threshold,
+		duration:  duration,
+		store:     store,
+		now:       time.Now,
+		records:   records,
+	}
+}
+
+// lookup retrieves the reputation of a peer, loading it from the store if it's
+// not known yet. Peers that aren't known are not tracked until their score is
+// adjusted. The lock must be held.
+func (r *reputation) lookup(id discover.NodeID) (rec *peerRecord, known bool) {
+	if cached, ok := r.records.Get(id); ok {
+		return cached.(*peerRecord), true
+	}
+	rec = &peerRecord{updated: r.now()}
+	if r.store != nil {
+		rec.score, rec.bannedUntil = r.store.Reputation(id)
+	}
+	return rec, false
+}
+
+// persist writes the reputation of a peer into the store. The lock must be held.
+func (r *reputation) persist(id discover.NodeID, rec *peerRecord) {
+	if r.store == nil {
+		return
+	}
+	if err := r.store.SetReputation(id, rec.score, rec.bannedUntil); err != nil {
+		log.Warn("Failed to store peer reputation", "id", id, "err", err)
+	}
+}
+
+// score returns the current score of a peer.
+func (r *reputation) score(id discover.NodeID) int {
+	if r == nil {
+		return 0
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	rec, _ := r.lookup(id)
+	return rec.current(r.now())
+}
+
+// banned reports whether a peer is currently banned.
+func (r *reputation) banned(id discover.NodeID) bool {
+	if r == nil {
+		return false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	rec, _ := r.lookup(id)
+	return r.now().Before(rec.bannedUntil)
+}
+
+// adjust changes the score of a peer by delta, banning it if the score falls
+// below the threshold. The score of a banned peer is reset, so it starts from
+// scratch once the ban expires. The scores recover over time, halving every
+// scoreHalfLife.
+func (r *reputation) adjust(id discover.NodeID, delta int) (score int, banned bool) {
+	if r == nil {
+		return 0, false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	rec, known := r.lookup(id)
+	if !known {
+		r.records.Add(id, rec)
+	}
+	rec.score, rec.updated = rec.current(r.now())+delta, r.now()
+	if rec.score < r.threshold {
+		rec.score, rec.bannedUntil = 0, r.now().Add(r.duration)
+		banned = true
+	}
+	r.persist(id, rec)
+
+	return rec.score, banned
+}
@@ -0,0 +1,124 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/p2p/discover"
+)
+
+// memReputationStore is an in-memory reputation store.
+type memReputationStore map[discover.NodeID]peerRecord
+
+func (s memReputationStore) Reputation(id discover.NodeID) (int, time.Time) {
+	return s[id].score, s[id].bannedUntil
+}
+
+func (s memReputationStore) SetReputation(id discover.NodeID, score int, bannedUntil time.Time) error {
+	s[id] = peerRecord{score: score, bannedUntil: bannedUntil}
+	return nil
+}
+
+// Tests that peers get banned once their score falls below the threshold, and
+// that the ban expires after the configured duration.
+func TestReputationBan(t *testing.T) {
+	var (
+		now = time.Unix(1000, 0)
+		id  = discover.NodeID{0x01}
+		rep = newReputation(-10, time.Minute, nil)
+	)
+	rep.now = func() time.Time { return now }
+
+	if score, banned := rep.adjust(id, -10); score != -10 || banned {
+		t.Fatalf("score/ban mismatch at threshold: have %d/%v, want %d/%v", score, banned, -10, false)
+	}
+	if score, banned := rep.adjust(id, -1); score != 0 || !banned {
+		t.Fatalf("score/ban mismatch below threshold: have %d/%v, want %d/%v", score, banned, 0, true)
+	}
+	if !rep.banned(id) {
+		t.Fatalf("peer not banned")
+	}
+	if rep.banned(discover.NodeID{0x02}) {
+		t.Fatalf("unrelated peer banned")
+	}
+	now = now.Add(time.Minute)
+	if rep.banned(id) {
+		t.Fatalf("ban didn't expire")
+	}
+}
+
+// Tests that the scores and bans are loaded back from the store.
+func TestReputationPersistence(t *testing.T) {
+	var (
+		store = make(memReputationStore)
+		id    = discover.NodeID{0x01}
+	)
+	rep := newReputation(0, 0, store)
+	rep.adjust(id, -20)
+	rep.adjust(id, defaultBanThreshold)
+
+	rep = newReputation(0, 0, store)
+	if !rep.banned(id) {
+		t.Fatalf("ban not restored")
+	}
+	rep.adjust(id, -30)
+
+	rep = newReputation(0, 0, store)
+	if score := rep.score(id); score != -30 {
+		t.Fatalf("score mismatch: have %d, want %d", score, -30)
+	}
+}
+
+// Tests that the scores recover over time.
+func TestReputationDecay(t *testing.T) {
+	var (
+		now = time.Unix(1000, 0)
+		id  = discover.NodeID{0x01}
+		rep = newReputation(-100, time.Minute, nil)
+	)
+	rep.now = func() time.Time { return now }
+
+	rep.adjust(id, -80)
+	for i := 0; i < 100; i++ {
+		now = now.Add(time.Second)
+		rep.score(id)
+	}
+	now = time.Unix(1000, 0).Add(scoreHalfLife)
+	if score := rep.score(id); score != -40 {
+		t.Fatalf("score mismatch after a half-life: have %d, want %d", score, -40)
+	}
+	// the decayed score is the base of the next adjustments
+	if score, banned := rep.adjust(id, -50); score != -90 || banned {
+		t.Fatalf("score/ban mismatch: have %d/%v, want %d/%v", score, banned, -90, false)
+	}
+	now = now.Add(10 * scoreHalfLife)
+	if score := rep.score(id); score != 0 {
+		t.Fatalf("score mismatch after ten half-lives: have %d, want %d", score, 0)
+	}
+}
+
+// Tests that looking up the reputation of peers doesn't track them, and that
+// the number of tracked peers is bounded.
+func TestReputationRecords(t *testing.T) {
+	store := make(memReputationStore)
+	rep := newReputation(0, 0, store)
+
+	for i := 0; i < 2*maxReputationRecords; i++ {
+		id := discover.NodeID{byte(i), byte(i >> 8)}
+		rep.banned(id)
+		rep.score(id)
+	}
+	if n := rep.records.Len(); n != 0 {
+		t.Fatalf("looked up peers tracked: have %d, want %d", n, 0)
+	}
+	for i := 0; i < 2*maxReputationRecords; i++ {
+		rep.adjust(discover.NodeID{byte(i), byte(i >> 8)}, -1)
+	}
+	if n := rep.records.Len(); n != maxReputationRecords {
+		t.Fatalf("tracked peers mismatch: have %d, want %d", n, maxReputationRecords)
+	}
+	// the evicted peers are reloaded from the store
+	if score := rep.score(discover.NodeID{0x00, 0x00}); score != -1 {
+		t.Fatalf("evicted peer score mismatch: have %d, want %d", score, -1)
+	}
+}
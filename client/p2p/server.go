@@ -15,6 +15,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/log"
 	"github.com/kowala-tech/kcoin/client/p2p/discover"
 	"github.com/kowala-tech/kcoin/client/p2p/discv5"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/p2p/nat"
 	"github.com/kowala-tech/kcoin/client/p2p/netutil"
 )
@@ -94,9 +95,26 @@ type Config struct {
 	NetRestrict *netutil.Netlist `toml:",omitempty"`
 
 	// NodeDatabase is the path to the database containing the previously seen
-	// live nodes in the network.
+	// live nodes in the network. The peer scores and bans are kept there too.
 	NodeDatabase string `toml:",omitempty"`
 
+	// BanThreshold is the score below which a misbehaving peer gets disconnected
+	// and banned. Zero defaults to preset values.
+	BanThreshold int `toml:",omitempty"`
+
+	// BanDuration is the amount of time a banned peer is refused.
+	// Zero defaults to preset values.
+	BanDuration time.Duration `toml:",omitempty"`
+
+	// Record contains additional entries advertised in the signed record of
+	// the local node, such as the fork identifier of the chain. If the record
+	// holds a fork identifier, the dialer skips the nodes on other forks.
+	Record []enr.Entry `toml:"-"`
+
+	// RelayPeers is the number of consensus-relaying peers the dialer tries to
+	// keep connected, preferring them over the other candidates.
+	RelayPeers int `toml:",omitempty"`
+
 	// Protocols should contain the protocols supported
 	// by the server. Matching protocols are launched for
 	// each peer.
@@ -144,9 +162,12 @@ type Server struct {
 	running bool
 
 	ntab         discoverTable
+	reputation   *reputation
 	listener     net.Listener
 	ourHandshake *protoHandshake
 	lastLookup   time.Time
+	record       *enr.Record // signed record of the local node
+	recordLock   sync.Mutex  // protects record
 	DiscV5       *discv5.Network
 
 	// These are for Peers, PeerCount (and nothing else).
@@ -186,11 +207,12 @@ const (
 type conn struct {
 	fd net.Conn
 	transport
-	flags connFlag
-	cont  chan error      // The run loop uses cont to signal errors to SetupConn.
-	id    discover.NodeID // valid after the encryption handshake
-	caps  []Cap           // valid after the protocol handshake
-	name  string          // valid after the protocol handshake
+	flags  connFlag
+	cont   chan error      // The run loop uses cont to signal errors to SetupConn.
+	id     discover.NodeID // valid after the encryption handshake
+	caps   []Cap           // valid after the protocol handshake
+	name   string          // valid after the protocol handshake
+	record *enr.Record     // valid after the protocol handshake, nil if not advertised
 }
 
 type transport interface {
@@ -469,8 +491,19 @@ func (srv *Server) Start() (err error) {
 		srv.DiscV5 = ntab
 	}
 
+	// peer reputation, persisted in the node database if discovery is enabled
+	var store reputationStore
+	if srv.ntab != nil {
+		store, _ = srv.ntab.(reputationStore)
+	}
+	srv.reputation = newReputation(srv.BanThreshold, srv.BanDuration, store)
+
 	dynPeers := srv.maxDialedConns()
 	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, dynPeers, srv.NetRestrict)
+	if srv.ntab != nil {
+		dialer.records, _ = srv.ntab.(recordStore)
+	}
+	dialer.local, dialer.relayPeers = srv.LocalRecord, srv.RelayPeers
 
 	// handshake
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name, ID: discover.PubkeyID(&srv.PrivateKey.PublicKey)}
@@ -486,6 +519,11 @@ func (srv *Server) Start() (err error) {
 	if srv.NoDial && srv.ListenAddr == "" {
 		srv.log.Warn("P2P server will be useless, neither dialing nor listening")
 	}
+	// signed node record, advertised in discovery and in the handshake
+	srv.record = nil
+	if err := srv.setRecordEntries(srv.makeSelf(srv.listener, srv.ntab), srv.Record); err != nil {
+		return err
+	}
 
 	srv.loopWG.Add(1)
 	go srv.run(dialer)
@@ -623,6 +661,7 @@ running:
 			if err == nil {
 				// The handshakes are done and it passed all checks.
 				p := newPeer(c, srv.Protocols)
+				p.reputation = srv.reputation
 				// If message events are enabled, pass the peerFeed
 				// to the peer
 				if srv.EnableMsgEvents {
@@ -698,6 +737,8 @@ func (srv *Server) encHandshakeChecks(peers map[discover.NodeID]*Peer, inboundCo
 		return DiscAlreadyConnected
 	case c.id == srv.Self().ID:
 		return DiscSelf
+	case !c.is(trustedConn) && srv.reputation.banned(c.id):
+		return DiscUselessPeer
 	default:
 		return nil
 	}
@@ -819,7 +860,7 @@ func (srv *Server) setupConn(c *conn, flags connFlag, dialDest *discover.Node) e
 		return err
 	}
 	// Run the protocol handshake
-	phs, err := c.doProtoHandshake(srv.ourHandshake)
+	phs, err := c.doProtoHandshake(srv.localHandshake())
 	if err != nil {
 		clog.Trace("Failed proto handshake", "err", err)
 		return err
@@ -829,6 +870,12 @@ func (srv *Server) setupConn(c *conn, flags connFlag, dialDest *discover.Node) e
 		return DiscUnexpectedIdentity
 	}
 	c.caps, c.name = phs.Caps, phs.Name
+	if len(phs.Rest) > 0 {
+		if c.record, err = discover.DecodeRecord(c.id, phs.Rest[0]); err != nil {
+			clog.Trace("Invalid node record", "err", err)
+			c.record = nil
+		}
+	}
 	err = srv.checkpoint(c, srv.addpeer)
 	if err != nil {
 		clog.Trace("Rejected peer", "err", err)
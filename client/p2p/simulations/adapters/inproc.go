@@ -94,6 +94,7 @@ func (s *SimAdapter) NewNode(config *NodeConfig) (Node, error) {
 			NoDiscovery:     true,
 			Dialer:          s,
 			EnableMsgEvents: config.EnableMsgEvents,
+			Record:          config.Record,
 		},
 		NoUSB:  true,
 		Logger: log.New("node.id", id.String()),
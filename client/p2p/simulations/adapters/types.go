@@ -30,6 +30,7 @@ import (
 	"github.com/kowala-tech/kcoin/client/node"
 	"github.com/kowala-tech/kcoin/client/p2p"
 	"github.com/kowala-tech/kcoin/client/p2p/discover"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/rpc"
 )
 
@@ -99,6 +100,10 @@ type NodeConfig struct {
 	// function to sanction or prevent suggesting a peer
 	Reachable func(id discover.NodeID) bool
 
+	// Record contains additional entries advertised in the signed record of
+	// the node (only supported by SimNodes, not encoded as JSON)
+	Record []enr.Entry
+
 	Port uint16
 }
 
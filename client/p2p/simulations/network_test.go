@@ -22,7 +22,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/p2p"
 	"github.com/kowala-tech/kcoin/client/p2p/discover"
+	"github.com/kowala-tech/kcoin/client/p2p/enr"
 	"github.com/kowala-tech/kcoin/client/p2p/simulations/adapters"
 )
 
@@ -140,6 +143,70 @@ func TestNetworkSimulation(t *testing.T) {
 	}
 }
 
+// TestNetworkRecords checks that the signed node records, carrying the fork
+// identifier and the consensus relaying capability, are exchanged in the
+// handshake of in-process nodes.
+func TestNetworkRecords(t *testing.T) {
+	adapter := adapters.NewSimAdapter(adapters.Services{
+		"test": newTestService,
+	})
+	network := NewNetwork(adapter, &NetworkConfig{
+		DefaultService: "test",
+	})
+	defer network.Shutdown()
+
+	fork := enr.NewForkID(common.HexToHash("0x01"), 1)
+	records := [][]enr.Entry{
+		{fork, enr.Relay(true)},
+		{fork},
+	}
+	ids := make([]discover.NodeID, len(records))
+	for i, record := range records {
+		conf := adapters.RandomNodeConfig()
+		conf.Record = record
+		node, err := network.NewNodeWithConfig(conf)
+		if err != nil {
+			t.Fatalf("error creating node: %s", err)
+		}
+		if err := network.Start(node.ID()); err != nil {
+			t.Fatalf("error starting node: %s", err)
+		}
+		ids[i] = node.ID()
+	}
+	if err := network.Connect(ids[0], ids[1]); err != nil {
+		t.Fatalf("error connecting nodes: %s", err)
+	}
+
+	// wait for each node to see the other one as a peer
+	peerOf := func(id, other discover.NodeID) *p2p.Peer {
+		srv := network.GetNode(id).Node.(*adapters.SimNode).Server()
+		for _, p := range srv.Peers() {
+			if p.ID() == other {
+				return p
+			}
+		}
+		return nil
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for peerOf(ids[0], ids[1]) == nil || peerOf(ids[1], ids[0]) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("nodes didn't connect in time")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	for i, want := range []bool{false, true} {
+		p := peerOf(ids[i], ids[1-i])
+		var have enr.ForkID
+		if err := p.Record().Load(&have); err != nil || have != fork {
+			t.Errorf("node %d: peer fork mismatch: have %x, want %x (err %v)", i, have, fork, err)
+		}
+		if p.Relay() != want {
+			t.Errorf("node %d: peer relay mismatch: have %v, want %v", i, p.Relay(), want)
+		}
+	}
+}
+
 func triggerChecks(ctx context.Context, ids []discover.NodeID, trigger chan discover.NodeID, interval time.Duration) {
 	tick := time.NewTicker(interval)
 	defer tick.Stop()
@@ -0,0 +1,68 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/common/hexutil"
+)
+
+// TrustedCheckpoints associates each known checkpoint with the genesis hash of
+// the chain it belongs to.
+var TrustedCheckpoints = map[common.Hash]*TrustedCheckpoint{}
+
+var errInvalidCheckpoint = errors.New(`invalid checkpoint, want "<number>:<hash>[:<validators hash>]"`)
+
+// TrustedCheckpoint represents a finalized block that a node can start syncing
+// from, instead of processing the chain from genesis.
+type TrustedCheckpoint struct {
+	Number         uint64      `toml:",omitempty"` // Block number of the checkpoint
+	Hash           common.Hash `toml:",omitempty"` // Block hash of the checkpoint
+	ValidatorsHash common.Hash `toml:",omitempty"` // Hash of the validators set at the checkpoint (optional)
+}
+
+// ParseCheckpoint parses a checkpoint in the "<number>:<hash>[:<validators hash>]"
+// format.
+func ParseCheckpoint(s string) (*TrustedCheckpoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, errInvalidCheckpoint
+	}
+	number, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, errInvalidCheckpoint
+	}
+	cp := &TrustedCheckpoint{Number: number}
+	if err := parseHash(parts[1], &cp.Hash); err != nil {
+		return nil, err
+	}
+	if len(parts) == 3 {
+		if err := parseHash(parts[2], &cp.ValidatorsHash); err != nil {
+			return nil, err
+		}
+	}
+	if cp.Number == 0 || cp.Hash == (common.Hash{}) {
+		return nil, errInvalidCheckpoint
+	}
+	return cp, nil
+}
+
+func parseHash(s string, hash *common.Hash) error {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != common.HashLength {
+		return errInvalidCheckpoint
+	}
+	*hash = common.BytesToHash(b)
+	return nil
+}
+
+// String implements the fmt.Stringer interface.
+func (c *TrustedCheckpoint) String() string {
+	if c.ValidatorsHash == (common.Hash{}) {
+		return fmt.Sprintf("%d:%s", c.Number, c.Hash.Hex())
+	}
+	return fmt.Sprintf("%d:%s:%s", c.Number, c.Hash.Hex(), c.ValidatorsHash.Hex())
+}
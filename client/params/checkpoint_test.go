@@ -0,0 +1,44 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+)
+
+func TestParseCheckpoint(t *testing.T) {
+	hash := common.HexToHash("0x0102")
+	vhash := common.HexToHash("0x0304")
+
+	tests := []struct {
+		input string
+		want  *TrustedCheckpoint
+	}{
+		{"100:" + hash.Hex(), &TrustedCheckpoint{Number: 100, Hash: hash}},
+		{"100:" + hash.Hex() + ":" + vhash.Hex(), &TrustedCheckpoint{Number: 100, Hash: hash, ValidatorsHash: vhash}},
+		{"100", nil},
+		{"0:" + hash.Hex(), nil},
+		{"abc:" + hash.Hex(), nil},
+		{"100:0x0102", nil},
+		{"100:" + hash.Hex() + ":" + vhash.Hex() + ":1", nil},
+	}
+	for _, tt := range tests {
+		cp, err := ParseCheckpoint(tt.input)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("%q: expected error, got %v", tt.input, cp)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if *cp != *tt.want {
+			t.Errorf("%q: checkpoint mismatch: have %v, want %v", tt.input, cp, tt.want)
+		}
+		if cp.String() != tt.input {
+			t.Errorf("%q: string mismatch: have %q", tt.input, cp.String())
+		}
+	}
+}
@@ -33,8 +33,8 @@ var (
 	// means that all fields must be set at all times. This forces
 	// anyone adding flags to the config to also have to set these
 	// fields.
-	AllKonsensusProtocolChanges = &ChainConfig{big.NewInt(2), new(KonsensusConfig)}
-	TestChainConfig             = &ChainConfig{big.NewInt(1), new(KonsensusConfig)}
+	AllKonsensusProtocolChanges = &ChainConfig{big.NewInt(2), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), new(KonsensusConfig), nil}
+	TestChainConfig             = &ChainConfig{big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), new(KonsensusConfig), nil}
 	TestRules                   = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -46,12 +46,66 @@ var (
 type ChainConfig struct {
 	ChainID *big.Int `json:"chainID"` // Chain id identifies the current chain and is used for replay protection
 
+	SponsoredTxBlock      *big.Int `json:"sponsoredTxBlock,omitempty"`      // Sponsored (fee payer) transactions switch block (nil = no fork, 0 = already activated)
+	AggregateCommitBlock  *big.Int `json:"aggregateCommitBlock,omitempty"`  // Aggregated commit signatures switch block (nil = no fork, 0 = already activated)
+	DelegatedStakingBlock *big.Int `json:"delegatedStakingBlock,omitempty"` // Delegated staking rewards switch block (nil = no fork, 0 = already activated)
+	BridgeBlock           *big.Int `json:"bridgeBlock,omitempty"`           // Cross-chain bridge contracts switch block (nil = no fork, 0 = already activated)
+	UptimeBlock           *big.Int `json:"uptimeBlock,omitempty"`           // Validator uptime tracking and jailing switch block (nil = no fork, 0 = already activated)
+	ConsensusKeyBlock     *big.Int `json:"consensusKeyBlock,omitempty"`     // Validator consensus keys switch block (nil = no fork, 0 = already activated)
+
 	// Various consensus engines
 	Konsensus *KonsensusConfig `json:"konsensus,omitempty"`
+
+	// Bridge to another Kowala chain, the bridge contracts are disabled if nil
+	Bridge *BridgeConfig `json:"bridge,omitempty"`
+}
+
+// BridgeConfig is the remote chain connected by the bridge contracts. The
+// light client starts trusting the validators set of the remote chain given
+// here and follows its changes through the headers submitted by the relayers.
+type BridgeConfig struct {
+	ChainID        *big.Int    `json:"chainID"`        // Chain id of the remote chain, which signs the votes
+	ValidatorsHash common.Hash `json:"validatorsHash"` // Initially trusted validators set of the remote chain
+}
+
+// String implements the stringer interface.
+func (c *BridgeConfig) String() string {
+	return fmt.Sprintf("{ChainID: %v Validators: %x}", c.ChainID, c.ValidatorsHash)
 }
 
 // KonsensusConfig is the consensus engine configs for proof-of-stake based sealing.
-type KonsensusConfig struct{}
+type KonsensusConfig struct {
+	UptimeWindow  uint64 `json:"uptimeWindow,omitempty"`  // Number of blocks of the signing window of the validators (0 = default)
+	MaxMissedRate uint64 `json:"maxMissedRate,omitempty"` // Missed blocks of the window, in basis points, jailing a validator (0 = default)
+	JailDuration  uint64 `json:"jailDuration,omitempty"`  // Number of blocks before a jailed validator can unjail itself (0 = default)
+}
+
+// Window returns the number of blocks of the signing window of the validators.
+func (c *KonsensusConfig) Window() uint64 {
+	if c == nil || c.UptimeWindow == 0 {
+		return DefaultUptimeWindow
+	}
+	return c.UptimeWindow
+}
+
+// MaxMissed returns the number of missed blocks of the window above which a
+// validator is jailed.
+func (c *KonsensusConfig) MaxMissed() uint64 {
+	rate := DefaultMaxMissedRate
+	if c != nil && c.MaxMissedRate != 0 {
+		rate = c.MaxMissedRate
+	}
+	return c.Window() * rate / 10000
+}
+
+// Jail returns the number of blocks before a jailed validator can unjail
+// itself.
+func (c *KonsensusConfig) Jail() uint64 {
+	if c == nil || c.JailDuration == 0 {
+		return DefaultJailDuration
+	}
+	return c.JailDuration
+}
 
 // String implements the stringer interface, returning the consensus engine details.
 func (c *KonsensusConfig) String() string {
@@ -67,8 +121,14 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v SponsoredTx: %v AggregateCommit: %v DelegatedStaking: %v Bridge: %v Uptime: %v ConsensusKey: %v Engine: %v}",
 		c.ChainID,
+		c.SponsoredTxBlock,
+		c.AggregateCommitBlock,
+		c.DelegatedStakingBlock,
+		c.BridgeBlock,
+		c.UptimeBlock,
+		c.ConsensusKeyBlock,
 		engine,
 	)
 }
@@ -80,6 +140,42 @@ func (c *ChainConfig) GasTable(num *big.Int) GasTable {
 	return GasTableAndromeda
 }
 
+// IsSponsoredTx returns whether num is either equal to the sponsored transactions
+// fork block or greater.
+func (c *ChainConfig) IsSponsoredTx(num *big.Int) bool {
+	return isForked(c.SponsoredTxBlock, num)
+}
+
+// IsAggregateCommit returns whether num is either equal to the aggregated commit
+// signatures fork block or greater.
+func (c *ChainConfig) IsAggregateCommit(num *big.Int) bool {
+	return isForked(c.AggregateCommitBlock, num)
+}
+
+// IsDelegatedStaking returns whether num is either equal to the delegated staking
+// fork block or greater.
+func (c *ChainConfig) IsDelegatedStaking(num *big.Int) bool {
+	return isForked(c.DelegatedStakingBlock, num)
+}
+
+// IsBridge returns whether num is either equal to the cross-chain bridge fork
+// block or greater.
+func (c *ChainConfig) IsBridge(num *big.Int) bool {
+	return isForked(c.BridgeBlock, num)
+}
+
+// IsUptime returns whether num is either equal to the validator uptime fork
+// block or greater.
+func (c *ChainConfig) IsUptime(num *big.Int) bool {
+	return isForked(c.UptimeBlock, num)
+}
+
+// IsConsensusKey returns whether num is either equal to the validator consensus
+// keys fork block or greater.
+func (c *ChainConfig) IsConsensusKey(num *big.Int) bool {
+	return isForked(c.ConsensusKeyBlock, num)
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
@@ -102,9 +198,41 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if !configNumEqual(c.ChainID, newcfg.ChainID) {
 		return newCompatError("Chain ID", c.ChainID, newcfg.ChainID)
 	}
+	if isForkIncompatible(c.SponsoredTxBlock, newcfg.SponsoredTxBlock, head) {
+		return newCompatError("Sponsored transactions fork block", c.SponsoredTxBlock, newcfg.SponsoredTxBlock)
+	}
+	if isForkIncompatible(c.AggregateCommitBlock, newcfg.AggregateCommitBlock, head) {
+		return newCompatError("Aggregated commit fork block", c.AggregateCommitBlock, newcfg.AggregateCommitBlock)
+	}
+	if isForkIncompatible(c.DelegatedStakingBlock, newcfg.DelegatedStakingBlock, head) {
+		return newCompatError("Delegated staking fork block", c.DelegatedStakingBlock, newcfg.DelegatedStakingBlock)
+	}
+	if isForkIncompatible(c.BridgeBlock, newcfg.BridgeBlock, head) {
+		return newCompatError("Bridge fork block", c.BridgeBlock, newcfg.BridgeBlock)
+	}
+	if isForkIncompatible(c.UptimeBlock, newcfg.UptimeBlock, head) {
+		return newCompatError("Uptime fork block", c.UptimeBlock, newcfg.UptimeBlock)
+	}
+	if isForkIncompatible(c.ConsensusKeyBlock, newcfg.ConsensusKeyBlock, head) {
+		return newCompatError("Consensus key fork block", c.ConsensusKeyBlock, newcfg.ConsensusKeyBlock)
+	}
 	return nil
 }
 
+// isForkIncompatible returns true if a fork scheduled at s1 cannot be rescheduled to
+// block s2 because head is already past the fork.
+func isForkIncompatible(s1, s2, head *big.Int) bool {
+	return (isForked(s1, head) || isForked(s2, head)) && !configNumEqual(s1, s2)
+}
+
+// isForked returns whether a fork scheduled at block s is active at the given head block.
+func isForked(s, head *big.Int) bool {
+	if s == nil || head == nil {
+		return false
+	}
+	return s.Cmp(head) <= 0
+}
+
 func configNumEqual(x, y *big.Int) bool {
 	if x == nil {
 		return y == nil
@@ -152,7 +280,13 @@ func (err *ConfigCompatError) Error() string {
 // Rules is a one time interface meaning that it shouldn't be used in between transition
 // phases.
 type Rules struct {
-	ChainId *big.Int
+	ChainId            *big.Int
+	IsSponsoredTx      bool
+	IsAggregateCommit  bool
+	IsDelegatedStaking bool
+	IsBridge           bool
+	IsUptime           bool
+	IsConsensusKey     bool
 }
 
 func (c *ChainConfig) Rules(num *big.Int) Rules {
@@ -160,5 +294,13 @@ func (c *ChainConfig) Rules(num *big.Int) Rules {
 	if chainID == nil {
 		chainID = new(big.Int)
 	}
-	return Rules{ChainId: new(big.Int).Set(chainID)}
+	return Rules{
+		ChainId:            new(big.Int).Set(chainID),
+		IsSponsoredTx:      c.IsSponsoredTx(num),
+		IsAggregateCommit:  c.IsAggregateCommit(num),
+		IsDelegatedStaking: c.IsDelegatedStaking(num),
+		IsBridge:           c.IsBridge(num),
+		IsUptime:           c.IsUptime(num),
+		IsConsensusKey:     c.IsConsensusKey(num),
+	}
 }
@@ -13,3 +13,8 @@ const (
 	// HDCoinType hierarchical deterministic wallet coin_type (SLIP-44)
 	HDCoinType = 91927009
 )
+
+// ImmutabilityThreshold is the number of blocks after which a finalized block
+// is moved from the key-value store to the ancient store. Committed blocks are
+// final, the threshold only keeps the recent history in the faster store.
+const ImmutabilityThreshold = 90000
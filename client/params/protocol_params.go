@@ -56,6 +56,41 @@ const (
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
 
+	// Bridge contract gas prices
+
+	BridgeHeaderGas     uint64 = 50000 // Base price for the verification of a header of the remote chain
+	BridgeHeaderByteGas uint64 = 32    // Per-byte price of a header proof, mostly signatures to recover
+	BridgeClaimGas      uint64 = 60000 // Base price for the verification of a receipt of the remote chain
+	BridgeClaimByteGas  uint64 = 16    // Per-byte price of a receipt proof
+	BridgeTransferGas   uint64 = 30000 // Price of a lock, burn or transfer of the bridge token
+	BridgeQueryGas      uint64 = 1000  // Price of a read-only call to a bridge contract
+
+	// Aggregate keys contract gas prices
+
+	AggregateKeySetGas   uint64 = 300000 // Price of the registration of a key, mostly the verification of its proof of possession
+	AggregateKeyQueryGas uint64 = 1000   // Price of a read-only call to the aggregate keys contract
+
+	// Consensus keys contract gas prices
+
+	ConsensusKeySetGas   uint64 = 40000 // Price of the registration of a key, releasing the previous one
+	ConsensusKeyQueryGas uint64 = 1000  // Price of a read-only call to the consensus keys contract
+
+	// Delegation contract gas prices
+
+	DelegationUpdateGas  uint64 = 60000 // Price of a delegation, an undelegation or a commission rate change
+	DelegationReleaseGas uint64 = 20000 // Price of the release of an undelegated amount past its freeze period
+	DelegationQueryGas   uint64 = 1000  // Price of a read-only call to the delegation contract
+
+	// Uptime contract gas prices
+
+	UptimeUnjailGas uint64 = 20000 // Price of the unjailing of a validator
+	UptimeQueryGas  uint64 = 1000  // Price of a read-only call to the uptime contract
+
+	// Proof of Stake - validator uptime
+	DefaultUptimeWindow  uint64 = 1000 // Number of blocks of the signing window of the validators
+	DefaultMaxMissedRate uint64 = 5000 // Missed blocks of the window, in basis points, jailing a validator
+	DefaultJailDuration  uint64 = 600  // Number of blocks before a jailed validator can unjail itself
+
 	// Proof of Stake - timeouts
 	ProposeDuration        uint64 = 500
 	ProposeDeltaDuration   uint64 = 25
@@ -0,0 +1,259 @@
+// Package relayer relays the committed headers and the bridge transfers of a
+// Kowala network to the bridge contracts of another Kowala network.
+package relayer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/bridge"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/log"
+	"github.com/kowala-tech/kcoin/client/rlp"
+)
+
+var errValidatorsMismatch = errors.New("voters don't match the validators hash of the header")
+
+var (
+	lightClientABI, _ = abi.JSON(strings.NewReader(bridge.LightClientABI))
+	bridgeTokenABI, _ = abi.JSON(strings.NewReader(bridge.BridgeTokenABI))
+
+	lockedEvent = bridgeTokenABI.Events["Locked"].Id()
+	burnedEvent = bridgeTokenABI.Events["Burned"].Id()
+)
+
+// Source is the network the headers and transfers are relayed from.
+type Source interface {
+	// LatestNumber returns the number of the latest block with a known commit.
+	LatestNumber(ctx context.Context) (*big.Int, error)
+
+	// CommittedHeader returns the header of the block along with its commit.
+	CommittedHeader(ctx context.Context, number *big.Int) (*types.Header, *types.Commit, error)
+
+	// Voters returns the validators set of the block.
+	Voters(ctx context.Context, number *big.Int) (types.VoterKeys, error)
+
+	// Receipts returns the receipts of the transactions of the block.
+	Receipts(ctx context.Context, number *big.Int) (types.Receipts, error)
+}
+
+// Relayer submits to the light client of the destination network the headers
+// of the source network that change the validators set or include bridge
+// transfers, and claims the transfers once their headers are trusted.
+type Relayer struct {
+	source  Source
+	backend bind.ContractBackend
+	bridge  *bridge.Bridge
+	opts    *bind.TransactOpts
+
+	next    *big.Int        // next block of the source network to relay
+	trusted types.VoterKeys // validators set of the last relayed block
+	claims  []*types.ReceiptProof
+}
+
+// New returns a relayer of the blocks of the source network from the given
+// block on. The validators set of the block must be the set trusted by the
+// light client of the destination network.
+func New(source Source, destination bind.ContractBackend, opts *bind.TransactOpts, from *big.Int) (*Relayer, error) {
+	binding, err := bridge.Bind(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Relayer{
+		source:  source,
+		backend: destination,
+		bridge:  binding,
+		opts:    opts,
+		next:    new(big.Int).Set(from),
+	}, nil
+}
+
+// Relay relays the new blocks of the source network and claims the transfers
+// of the blocks trusted by the light client. The claims of the headers
+// submitted by a call are sent by the following calls, once the headers are
+// mined.
+func (r *Relayer) Relay(ctx context.Context) error {
+	latest, err := r.source.LatestNumber(ctx)
+	if err != nil {
+		return err
+	}
+	submitted, err := r.bridge.LightClient.LatestNumber(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	for ; r.next.Cmp(latest) <= 0; r.next.Add(r.next, common.Big1) {
+		if err := r.relayBlock(ctx, new(big.Int).Set(r.next), submitted); err != nil {
+			return err
+		}
+	}
+
+	return r.claim(ctx)
+}
+
+// relayBlock submits the header of the block if required and queues the
+// claims of its transfers.
+func (r *Relayer) relayBlock(ctx context.Context, number *big.Int, submitted *big.Int) error {
+	receipts, err := r.source.Receipts(ctx, number)
+	if err != nil {
+		return err
+	}
+	claims, err := transfers(number, receipts)
+	if err != nil {
+		return err
+	}
+
+	voters, err := r.source.Voters(ctx, number)
+	if err != nil {
+		return err
+	}
+	if r.trusted == nil {
+		r.trusted = voters
+	}
+	hash, err := votersHash(voters)
+	if err != nil {
+		return err
+	}
+	trustedHash, err := votersHash(r.trusted)
+	if err != nil {
+		return err
+	}
+	changed := hash != trustedHash
+
+	if (changed || len(claims) > 0) && number.Cmp(submitted) > 0 {
+		header, commit, err := r.source.CommittedHeader(ctx, number)
+		if err != nil {
+			return err
+		}
+		if header.ValidatorsHash != hash {
+			return errValidatorsMismatch
+		}
+
+		proof := &types.HeaderProof{Header: header, Commit: commit, Voters: voters}
+		if changed {
+			proof.Trusted = r.trusted
+		}
+		data, err := rlp.EncodeToBytes(proof)
+		if err != nil {
+			return err
+		}
+		opts, err := r.transactOpts(ctx, vm.BridgeLightClientAddress, lightClientABI, "submitHeader", data)
+		if err != nil {
+			return err
+		}
+		tx, err := r.bridge.LightClient.SubmitHeader(opts, data)
+		if err != nil {
+			return err
+		}
+		log.Info("Submitted bridge header", "number", number, "hash", header.Hash(), "validators", changed, "tx", tx.Hash())
+	}
+
+	r.trusted = voters
+	r.claims = append(r.claims, claims...)
+	return nil
+}
+
+// claim claims the queued transfers of the blocks trusted by the light client.
+func (r *Relayer) claim(ctx context.Context) error {
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	var pending []*types.ReceiptProof
+	for i, proof := range r.claims {
+		root, err := r.bridge.LightClient.ReceiptsRoot(callOpts, proof.Number)
+		if err != nil {
+			r.claims = append(pending, r.claims[i:]...)
+			return err
+		}
+		if root == (common.Hash{}) {
+			pending = append(pending, proof)
+			continue
+		}
+
+		txIndex, logIndex := new(big.Int).SetUint64(proof.TxIndex), new(big.Int).SetUint64(proof.LogIndex)
+		claimed, err := r.bridge.Token.Claimed(callOpts, proof.Number, txIndex, logIndex)
+		if err != nil {
+			r.claims = append(pending, r.claims[i:]...)
+			return err
+		}
+		if claimed {
+			continue
+		}
+
+		data, err := rlp.EncodeToBytes(proof)
+		if err != nil {
+			return err
+		}
+		opts, err := r.transactOpts(ctx, vm.BridgeTokenAddress, bridgeTokenABI, "claim", data)
+		if err == nil {
+			var tx *types.Transaction
+			if tx, err = r.bridge.Token.Claim(opts, data); err == nil {
+				log.Info("Claimed bridge transfer", "number", proof.Number, "tx", txIndex, "log", logIndex, "hash", tx.Hash())
+			}
+		}
+		if err != nil {
+			r.claims = append(pending, r.claims[i:]...)
+			return err
+		}
+	}
+	r.claims = pending
+	return nil
+}
+
+// transactOpts returns the options of a transaction to a bridge contract. The
+// bridge contracts are native contracts without code, so the gas limit isn't
+// estimated by the bindings.
+func (r *Relayer) transactOpts(ctx context.Context, to common.Address, contractABI abi.ABI, method string, args ...interface{}) (*bind.TransactOpts, error) {
+	input, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := r.backend.EstimateGas(ctx, kowala.CallMsg{From: r.opts.From, To: &to, Data: input})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := *r.opts
+	opts.Context = ctx
+	opts.GasLimit = gas
+	return &opts, nil
+}
+
+// transfers returns the proofs of the bridge transfers of the block.
+func transfers(number *big.Int, receipts types.Receipts) ([]*types.ReceiptProof, error) {
+	var proofs []*types.ReceiptProof
+	for i, receipt := range receipts {
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		for j, log := range receipt.Logs {
+			if log.Address != vm.BridgeTokenAddress || len(log.Topics) == 0 {
+				continue
+			}
+			if log.Topics[0] != lockedEvent && log.Topics[0] != burnedEvent {
+				continue
+			}
+			proof, err := types.NewReceiptProof(number, receipts, uint64(i), uint64(j))
+			if err != nil {
+				return nil, err
+			}
+			proofs = append(proofs, proof)
+		}
+	}
+	return proofs, nil
+}
+
+func votersHash(keys types.VoterKeys) (common.Hash, error) {
+	voters, err := keys.Voters()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return voters.Hash(), nil
+}
@@ -0,0 +1,222 @@
+package relayer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind/backends"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/bridge"
+	"github.com/kowala-tech/kcoin/client/core"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/core/vm"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/client/params"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	chainIDA = big.NewInt(1)
+	chainIDB = big.NewInt(2)
+
+	userKey, _    = crypto.GenerateKey()
+	relayerKey, _ = crypto.GenerateKey()
+	user          = crypto.PubkeyToAddress(userKey.PublicKey)
+)
+
+// testSource is a source network simulated in-process. The simulated chain
+// isn't run by validators, so the headers are committed by the given keys.
+type testSource struct {
+	backend  *backends.SimulatedBackend
+	chainID  *big.Int
+	keys     []*ecdsa.PrivateKey
+	rotated  []*ecdsa.PrivateKey
+	rotateAt *big.Int
+}
+
+func (src *testSource) validators(number *big.Int) []*ecdsa.PrivateKey {
+	if src.rotateAt != nil && number.Cmp(src.rotateAt) >= 0 {
+		return src.rotated
+	}
+	return src.keys
+}
+
+func (src *testSource) LatestNumber(ctx context.Context) (*big.Int, error) {
+	return src.backend.CurrentBlock().Number(), nil
+}
+
+func (src *testSource) CommittedHeader(ctx context.Context, number *big.Int) (*types.Header, *types.Commit, error) {
+	keys := src.validators(number)
+	hash, err := votersHash(voterKeys(keys))
+	if err != nil {
+		return nil, nil, err
+	}
+	header := src.backend.GetBlockByNumber(number.Uint64()).Header()
+	header.ValidatorsHash = hash
+
+	signer := types.NewAndromedaSigner(src.chainID)
+	votes := make(types.Votes, len(keys))
+	for i, key := range keys {
+		if votes[i], err = types.SignVote(types.NewVote(number, header.Hash(), 0, types.PreCommit), signer, key); err != nil {
+			return nil, nil, err
+		}
+	}
+	return header, &types.Commit{PreCommits: votes, FirstPreCommit: votes[0]}, nil
+}
+
+func (src *testSource) Voters(ctx context.Context, number *big.Int) (types.VoterKeys, error) {
+	return voterKeys(src.validators(number)), nil
+}
+
+func (src *testSource) Receipts(ctx context.Context, number *big.Int) (types.Receipts, error) {
+	block := src.backend.GetBlockByNumber(number.Uint64())
+	return src.backend.GetReceiptsByHash(block.Hash()), nil
+}
+
+func voterKeys(keys []*ecdsa.PrivateKey) types.VoterKeys {
+	voters := make(types.VoterKeys, len(keys))
+	for i, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		voters[i] = types.VoterKey{Address: addr, ConsensusKey: addr}
+	}
+	return voters
+}
+
+func newKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	keys := make([]*ecdsa.PrivateKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		keys[i] = key
+	}
+	return keys
+}
+
+// newChain returns a simulated chain bridged to the remote chain trusting the
+// given validators of the remote chain.
+func newChain(t *testing.T, chainID, remoteChainID *big.Int, remoteValidators []*ecdsa.PrivateKey) *backends.SimulatedBackend {
+	hash, err := votersHash(voterKeys(remoteValidators))
+	require.NoError(t, err)
+
+	config := *params.AllKonsensusProtocolChanges
+	config.ChainID = chainID
+	config.Bridge = &params.BridgeConfig{ChainID: remoteChainID, ValidatorsHash: hash}
+
+	funds := new(big.Int).Mul(big.NewInt(1000), new(big.Int).SetUint64(params.Kcoin))
+	return backends.NewSimulatedBackendWithConfig(&config, core.GenesisAlloc{
+		user: {Balance: funds},
+		crypto.PubkeyToAddress(relayerKey.PublicKey): {Balance: funds},
+	})
+}
+
+func transactOpts(key *ecdsa.PrivateKey, value *big.Int) *bind.TransactOpts {
+	opts := bind.NewKeyedTransactor(key)
+	opts.Value = value
+	opts.GasLimit = 100000
+	return opts
+}
+
+func relay(t *testing.T, r *Relayer, destination *backends.SimulatedBackend) {
+	require.NoError(t, r.Relay(context.Background()))
+	destination.Commit()
+}
+
+func TestRelayTransfers(t *testing.T) {
+	validatorsA, validatorsB := newKeys(t, 3), newKeys(t, 3)
+	rotatedA := append(validatorsA[:2:2], newKeys(t, 1)...)
+
+	chainA := newChain(t, chainIDA, chainIDB, validatorsB)
+	chainB := newChain(t, chainIDB, chainIDA, validatorsA)
+	sourceA := &testSource{backend: chainA, chainID: chainIDA, keys: validatorsA, rotated: rotatedA, rotateAt: big.NewInt(4)}
+	sourceB := &testSource{backend: chainB, chainID: chainIDB, keys: validatorsB}
+
+	relayerAB, err := New(sourceA, chainB, bind.NewKeyedTransactor(relayerKey), common.Big1)
+	require.NoError(t, err)
+	relayerBA, err := New(sourceB, chainA, bind.NewKeyedTransactor(relayerKey), common.Big1)
+	require.NoError(t, err)
+
+	bridgeA, err := bridge.Bind(chainA)
+	require.NoError(t, err)
+	bridgeB, err := bridge.Bind(chainB)
+	require.NoError(t, err)
+	callOpts := &bind.CallOpts{}
+
+	// lock the native currency of A to mint wrapped currency on B
+	_, err = bridgeA.Token.Lock(transactOpts(userKey, big.NewInt(1000)), user)
+	require.NoError(t, err)
+	chainA.Commit()
+
+	relay(t, relayerAB, chainB) // header
+	relay(t, relayerAB, chainB) // claim
+
+	balance, err := bridgeB.Token.BalanceOf(callOpts, user)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), balance)
+	supply, err := bridgeB.Token.TotalSupply(callOpts)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), supply)
+
+	// the transfer is only claimed once
+	relay(t, relayerAB, chainB)
+	balance, err = bridgeB.Token.BalanceOf(callOpts, user)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), balance)
+
+	// burn the wrapped currency on B to unlock the native currency of A
+	beneficiary := common.HexToAddress("0xbeef")
+	_, err = bridgeB.Token.Burn(transactOpts(userKey, nil), beneficiary, big.NewInt(400))
+	require.NoError(t, err)
+	chainB.Commit()
+
+	relay(t, relayerBA, chainA)
+	relay(t, relayerBA, chainA)
+
+	unlocked, err := chainA.BalanceAt(context.Background(), beneficiary, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(400), unlocked)
+	locked, err := chainA.BalanceAt(context.Background(), vm.BridgeTokenAddress, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(600), locked)
+
+	// the validators set of A changes before the next transfer
+	_, err = bridgeA.Token.Lock(transactOpts(userKey, big.NewInt(50)), user)
+	require.NoError(t, err)
+	chainA.Commit()
+	require.Equal(t, big.NewInt(4), chainA.CurrentBlock().Number())
+
+	relay(t, relayerAB, chainB)
+	relay(t, relayerAB, chainB)
+
+	balance, err = bridgeB.Token.BalanceOf(callOpts, user)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(650), balance)
+
+	trusted, err := bridgeB.LightClient.TrustedValidatorsHash(callOpts)
+	require.NoError(t, err)
+	rotatedHash, err := votersHash(voterKeys(rotatedA))
+	require.NoError(t, err)
+	require.Equal(t, rotatedHash, common.Hash(trusted))
+}
+
+func TestRelayUntrustedValidators(t *testing.T) {
+	validatorsA := newKeys(t, 3)
+
+	// B trusts a validators set without any of the validators of A
+	chainA := newChain(t, chainIDA, chainIDB, newKeys(t, 3))
+	chainB := newChain(t, chainIDB, chainIDA, newKeys(t, 3))
+	sourceA := &testSource{backend: chainA, chainID: chainIDA, keys: validatorsA}
+
+	relayerAB, err := New(sourceA, chainB, bind.NewKeyedTransactor(relayerKey), common.Big1)
+	require.NoError(t, err)
+	bridgeA, err := bridge.Bind(chainA)
+	require.NoError(t, err)
+
+	_, err = bridgeA.Token.Lock(transactOpts(userKey, big.NewInt(1000)), user)
+	require.NoError(t, err)
+	chainA.Commit()
+
+	require.Error(t, relayerAB.Relay(context.Background()))
+}
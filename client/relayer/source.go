@@ -0,0 +1,126 @@
+package relayer
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	kowala "github.com/kowala-tech/kcoin/client"
+	"github.com/kowala-tech/kcoin/client/accounts/abi/bind"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/client/core/types"
+	"github.com/kowala-tech/kcoin/client/kcoinclient"
+	"github.com/kowala-tech/kcoin/client/rlp"
+	"github.com/kowala-tech/kcoin/client/rpc"
+)
+
+var errGenesisVoters = errors.New("the genesis block doesn't have voters")
+
+// rpcSource reads the source network through the RPC API of one of its
+// nodes. The commit of a block is only part of the RLP encoding of the next
+// block, so the API must include the debug module.
+type rpcSource struct {
+	rpc     *rpc.Client
+	client  *kcoinclient.Client
+	chainID *big.Int
+}
+
+// NewRPCSource returns a source reading the network with the given chain ID
+// through the RPC client.
+func NewRPCSource(client *rpc.Client, chainID *big.Int) Source {
+	return &rpcSource{
+		rpc:     client,
+		client:  kcoinclient.NewClient(client),
+		chainID: chainID,
+	}
+}
+
+func (src *rpcSource) LatestNumber(ctx context.Context) (*big.Int, error) {
+	number, err := src.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the commit of the latest block is included in the next block
+	if number.Sign() > 0 {
+		number.Sub(number, common.Big1)
+	}
+	return number, nil
+}
+
+func (src *rpcSource) CommittedHeader(ctx context.Context, number *big.Int) (*types.Header, *types.Commit, error) {
+	header, err := src.client.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var encoded string
+	if err := src.rpc.CallContext(ctx, &encoded, "debug_getBlockRlp", number.Uint64()+1); err != nil {
+		return nil, nil, err
+	}
+	data, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, nil, err
+	}
+	next := new(types.Block)
+	if err := rlp.DecodeBytes(data, next); err != nil {
+		return nil, nil, err
+	}
+	if next.ParentHash() != header.Hash() {
+		return nil, nil, fmt.Errorf("block #%d is not a child of the header %x", next.NumberU64(), header.Hash())
+	}
+
+	return header, next.LastCommit(), nil
+}
+
+func (src *rpcSource) Voters(ctx context.Context, number *big.Int) (types.VoterKeys, error) {
+	if number.Sign() == 0 {
+		return nil, errGenesisVoters
+	}
+	// the voters of a block are the validators at its parent block
+	parent := &historicalBackend{
+		ContractBackend: src.client,
+		number:          new(big.Int).Sub(number, common.Big1),
+	}
+	binding, err := consensus.Bind(parent, src.chainID)
+	if err != nil {
+		return nil, err
+	}
+	voters, err := binding.(*consensus.Consensus).Validators()
+	if err != nil {
+		return nil, err
+	}
+	return types.NewVoterKeys(voters), nil
+}
+
+func (src *rpcSource) Receipts(ctx context.Context, number *big.Int) (types.Receipts, error) {
+	block, err := src.client.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make(types.Receipts, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if receipts[i], err = src.client.TransactionReceipt(ctx, tx.Hash()); err != nil {
+			return nil, err
+		}
+	}
+	return receipts, nil
+}
+
+// historicalBackend runs the calls of the contract bindings on the state of
+// a past block.
+type historicalBackend struct {
+	bind.ContractBackend
+	number *big.Int
+}
+
+func (b *historicalBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.ContractBackend.CodeAt(ctx, contract, b.number)
+}
+
+func (b *historicalBackend) CallContract(ctx context.Context, call kowala.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return b.ContractBackend.CallContract(ctx, call, b.number)
+}
@@ -0,0 +1,276 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/kowala-tech/kcoin/client/log"
+)
+
+var (
+	errMissingToken    = errors.New("missing authorization token")
+	errUnknownToken    = errors.New("unknown authorization token")
+	errMissingSubject  = errors.New("authorization token has no subject")
+	errDuplicateKey    = errors.New("duplicate API key")
+	errUnnamedKey      = errors.New("API key has no name")
+	errNoCredentials   = errors.New("API key has neither a secret nor a JWT secret to verify it")
+	errUnexpectedToken = errors.New("unexpected authorization token signing method")
+)
+
+// privilegedNamespaces are the namespaces whose calls are recorded in the audit
+// log if the auth configuration doesn't list any.
+var privilegedNamespaces = []string{"admin", "debug", "personal", "validator", "mtoken"}
+
+// AccessPolicy restricts the calls a client is allowed to make. Methods are
+// matched by their full name (eth_getBalance), their namespace (eth) or by the
+// "*" wildcard.
+type AccessPolicy struct {
+	Allow []string `toml:",omitempty"` // Methods the client may call, every method if empty
+	Deny  []string `toml:",omitempty"` // Methods the client may not call, taking precedence over Allow
+
+	RequestRate     float64 `toml:",omitempty"` // Sustained number of requests per second, unlimited if zero
+	RequestBurst    int     `toml:",omitempty"` // Number of requests allowed in a burst, at least the sustained rate
+	MaxBatchSize    int     `toml:",omitempty"` // Maximum number of requests in a batch, unlimited if zero
+	MaxResponseSize int     `toml:",omitempty"` // Maximum size in bytes of a response, unlimited if zero
+}
+
+// allowed reports whether the policy permits calling the given method.
+func (p *AccessPolicy) allowed(method string) bool {
+	if matchMethod(p.Deny, method) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchMethod(p.Allow, method)
+}
+
+// APIKey grants an access policy to the clients presenting it.
+type APIKey struct {
+	Name   string       // Name of the key, identifying its clients in the audit log
+	Secret string       `toml:",omitempty"` // Static bearer token, the key is only usable via JWT if empty
+	Policy AccessPolicy // Calls the clients of the key are allowed to make
+}
+
+// AuthConfig configures the authentication of the clients of an RPC server and
+// the access policies they are subjected to.
+type AuthConfig struct {
+	// Keys are the API keys accepted by the server.
+	Keys []APIKey
+
+	// JWTSecret is the HMAC secret verifying the JWT bearer tokens. The subject
+	// claim of a token names the API key whose policy applies. If empty, only
+	// the static secrets of the keys are accepted.
+	JWTSecret string `toml:",omitempty"`
+
+	// Anonymous is the policy of the clients presenting no token. If nil, these
+	// clients are rejected.
+	Anonymous *AccessPolicy `toml:",omitempty"`
+
+	// Audit lists the methods whose calls are recorded in the audit log. If
+	// empty, the calls to the privileged namespaces are.
+	Audit []string `toml:",omitempty"`
+}
+
+// authClient is an authenticated client of the RPC servers. Its rate limiter is
+// shared among all the connections using the same key, over every transport of
+// the servers sharing the authenticator.
+type authClient struct {
+	name    string
+	policy  *AccessPolicy
+	limiter *rateLimiter
+}
+
+// authClientKey is the context key of the authenticated client of a request.
+type authClientKey struct{}
+
+// Authenticator resolves the bearer tokens presented by the clients into the
+// access policies they are granted. A single authenticator is shared by all the
+// servers of a node, so that the limits of a key apply across its transports.
+type Authenticator struct {
+	secrets   map[string]*authClient // static secret -> client
+	clients   map[string]*authClient // key name -> client
+	anonymous *authClient
+	jwtSecret []byte
+	audit     []string
+	log       log.Logger
+}
+
+// NewAuthenticator creates the authenticator of the given configuration.
+func NewAuthenticator(config *AuthConfig) (*Authenticator, error) {
+	auth := &Authenticator{
+		secrets:   make(map[string]*authClient),
+		clients:   make(map[string]*authClient),
+		jwtSecret: []byte(config.JWTSecret),
+		audit:     config.Audit,
+		log:       log.New("module", "rpc-audit"),
+	}
+	if len(auth.audit) == 0 {
+		auth.audit = privilegedNamespaces
+	}
+	for i := range config.Keys {
+		key := &config.Keys[i]
+		switch {
+		case key.Name == "":
+			return nil, errUnnamedKey
+		case auth.clients[key.Name] != nil:
+			return nil, fmt.Errorf("%v: %s", errDuplicateKey, key.Name)
+		case key.Secret == "" && config.JWTSecret == "":
+			return nil, fmt.Errorf("%v: %s", errNoCredentials, key.Name)
+		}
+		client := newAuthClient(key.Name, key.Policy)
+		auth.clients[key.Name] = client
+		if key.Secret != "" {
+			auth.secrets[key.Secret] = client
+		}
+	}
+	if config.Anonymous != nil {
+		auth.anonymous = newAuthClient("anonymous", *config.Anonymous)
+	}
+	return auth, nil
+}
+
+func newAuthClient(name string, policy AccessPolicy) *authClient {
+	return &authClient{
+		name:    name,
+		policy:  &policy,
+		limiter: newRateLimiter(policy.RequestRate, policy.RequestBurst),
+	}
+}
+
+// authenticate resolves the value of an Authorization header into the client
+// presenting it.
+func (auth *Authenticator) authenticate(header string) (*authClient, error) {
+	token := strings.TrimSpace(header)
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	if token == "" {
+		if auth.anonymous == nil {
+			return nil, errMissingToken
+		}
+		return auth.anonymous, nil
+	}
+	if client, ok := auth.secrets[token]; ok {
+		return client, nil
+	}
+	if len(auth.jwtSecret) == 0 {
+		return nil, errUnknownToken
+	}
+	return auth.verifyJWT(token)
+}
+
+// verifyJWT checks the signature and the time claims of a JWT token, returning
+// the client named by its subject.
+func (auth *Authenticator) verifyJWT(token string) (*authClient, error) {
+	parsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errUnexpectedToken
+		}
+		return auth.jwtSecret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errMissingSubject
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errMissingSubject
+	}
+	client, ok := auth.clients[subject]
+	if !ok {
+		return nil, errUnknownToken
+	}
+	return client, nil
+}
+
+// authorize checks the requests of a client against its policy, flagging the
+// ones it isn't allowed to make and recording the privileged ones in the audit
+// log.
+func (auth *Authenticator) authorize(ctx context.Context, client *authClient, reqs []*serverRequest) {
+	name := "local"
+	if client != nil {
+		name = client.name
+	}
+	for _, req := range reqs {
+		if req.err != nil || req.isUnsubscribe {
+			continue
+		}
+		allowed := client == nil || client.policy.allowed(req.method)
+		if !allowed {
+			req.err = &unauthorizedError{req.method}
+		}
+		if matchMethod(auth.audit, req.method) {
+			auth.log.Info("Privileged RPC call", "key", name, "method", req.method, "remote", ctx.Value("remote"), "allowed", allowed)
+		}
+	}
+}
+
+// clientFromContext returns the authenticated client of a request, or nil if
+// it was received over a transport that doesn't authenticate its clients.
+func clientFromContext(ctx context.Context) *authClient {
+	client, _ := ctx.Value(authClientKey{}).(*authClient)
+	return client
+}
+
+// matchMethod reports whether a method matches any of the given patterns.
+func matchMethod(patterns []string, method string) bool {
+	namespace := method
+	if i := strings.Index(method, serviceMethodSeparator); i >= 0 {
+		namespace = method[:i]
+	}
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == method || pattern == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimiter is a token bucket limiting the rate of the requests of a client.
+type rateLimiter struct {
+	rate   float64 // tokens added per second, no limit if zero
+	burst  float64 // capacity of the bucket
+	tokens float64
+	last   time.Time
+	lock   sync.Mutex
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	capacity := float64(burst)
+	if capacity < rate {
+		capacity = rate
+	}
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &rateLimiter{rate: rate, burst: capacity, tokens: capacity, last: time.Now()}
+}
+
+// allow reports whether n requests can be made now, consuming their tokens if
+// so.
+func (l *rateLimiter) allow(n int) bool {
+	if l.rate <= 0 {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens < float64(n) {
+		return false
+	}
+	l.tokens -= float64(n)
+	return true
+}
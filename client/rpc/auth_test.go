@@ -0,0 +1,236 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func newAuthTestServer(t *testing.T, config *AuthConfig) *httptest.Server {
+	server := NewServer()
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := NewAuthenticator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.EnableAuth(auth)
+	return httptest.NewServer(server)
+}
+
+// postAuth posts a JSON-RPC request with the given token, returning the status
+// code and the decoded response.
+func postAuth(t *testing.T, url, token, body string) (int, interface{}) {
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("content-type", contentType)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	var result interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, result
+}
+
+// errorCode returns the code of an error response, or zero if it succeeded.
+func errorCode(response interface{}) int {
+	msg, ok := response.(map[string]interface{})
+	if !ok || msg["error"] == nil {
+		return 0
+	}
+	return int(msg["error"].(map[string]interface{})["code"].(float64))
+}
+
+const (
+	echoRequest  = `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["hello",1,{"S":"x"}]}`
+	retsRequest  = `{"jsonrpc":"2.0","id":2,"method":"test_rets","params":[]}`
+	batchRequest = `[` + echoRequest + `,` + echoRequest + `,` + echoRequest + `]`
+)
+
+func TestAuthMissingToken(t *testing.T) {
+	srv := newAuthTestServer(t, &AuthConfig{Keys: []APIKey{{Name: "explorer", Secret: "secret"}}})
+	defer srv.Close()
+
+	if code, _ := postAuth(t, srv.URL, "", echoRequest); code != http.StatusUnauthorized {
+		t.Errorf("missing token: status mismatch: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, _ := postAuth(t, srv.URL, "invalid", echoRequest); code != http.StatusUnauthorized {
+		t.Errorf("invalid token: status mismatch: have %d, want %d", code, http.StatusUnauthorized)
+	}
+	if code, resp := postAuth(t, srv.URL, "secret", echoRequest); code != http.StatusOK || errorCode(resp) != 0 {
+		t.Errorf("valid token: unexpected failure: status %d, response %v", code, resp)
+	}
+}
+
+func TestAuthMethodPolicy(t *testing.T) {
+	srv := newAuthTestServer(t, &AuthConfig{
+		Keys:      []APIKey{{Name: "explorer", Secret: "secret", Policy: AccessPolicy{Allow: []string{"test"}, Deny: []string{"test_rets"}}}},
+		Anonymous: &AccessPolicy{Allow: []string{"test_rets"}},
+	})
+	defer srv.Close()
+
+	tests := []struct {
+		token, request string
+		code           int
+	}{
+		{"secret", echoRequest, 0},
+		{"secret", retsRequest, -32001},
+		{"", echoRequest, -32001},
+		{"", retsRequest, 0},
+	}
+	for i, tt := range tests {
+		_, resp := postAuth(t, srv.URL, tt.token, tt.request)
+		if code := errorCode(resp); code != tt.code {
+			t.Errorf("test %d: error code mismatch: have %d, want %d", i, code, tt.code)
+		}
+	}
+}
+
+func TestAuthBatchLimit(t *testing.T) {
+	srv := newAuthTestServer(t, &AuthConfig{
+		Keys: []APIKey{{Name: "explorer", Secret: "secret", Policy: AccessPolicy{MaxBatchSize: 2}}},
+	})
+	defer srv.Close()
+
+	_, resp := postAuth(t, srv.URL, "secret", batchRequest)
+	batch, ok := resp.([]interface{})
+	if !ok || len(batch) != 3 {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	for i, r := range batch {
+		if code := errorCode(r); code != -32005 {
+			t.Errorf("response %d: error code mismatch: have %d, want %d", i, code, -32005)
+		}
+	}
+}
+
+func TestAuthRateLimit(t *testing.T) {
+	srv := newAuthTestServer(t, &AuthConfig{
+		Keys: []APIKey{{Name: "explorer", Secret: "secret", Policy: AccessPolicy{RequestRate: 0.1, RequestBurst: 2}}},
+	})
+	defer srv.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, resp := postAuth(t, srv.URL, "secret", echoRequest); errorCode(resp) != 0 {
+			t.Fatalf("request %d: unexpected failure: %v", i, resp)
+		}
+	}
+	if _, resp := postAuth(t, srv.URL, "secret", echoRequest); errorCode(resp) != -32005 {
+		t.Errorf("expected rate limit error, got %v", resp)
+	}
+}
+
+func TestAuthResponseLimit(t *testing.T) {
+	srv := newAuthTestServer(t, &AuthConfig{
+		Keys: []APIKey{{Name: "explorer", Secret: "secret", Policy: AccessPolicy{MaxResponseSize: 32}}},
+	})
+	defer srv.Close()
+
+	if _, resp := postAuth(t, srv.URL, "secret", echoRequest); errorCode(resp) != -32005 {
+		t.Errorf("expected response size error, got %v", resp)
+	}
+}
+
+func TestAuthJWT(t *testing.T) {
+	secret := []byte("jwt-secret")
+	srv := newAuthTestServer(t, &AuthConfig{
+		Keys:      []APIKey{{Name: "wallet", Policy: AccessPolicy{Allow: []string{"test_echo"}}}},
+		JWTSecret: string(secret),
+	})
+	defer srv.Close()
+
+	sign := func(claims jwt.MapClaims, key []byte) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	expiry := time.Now().Add(time.Minute).Unix()
+
+	if _, resp := postAuth(t, srv.URL, sign(jwt.MapClaims{"sub": "wallet", "exp": expiry}, secret), echoRequest); errorCode(resp) != 0 {
+		t.Errorf("valid token: unexpected failure: %v", resp)
+	}
+	if _, resp := postAuth(t, srv.URL, sign(jwt.MapClaims{"sub": "wallet", "exp": expiry}, secret), retsRequest); errorCode(resp) != -32001 {
+		t.Errorf("valid token: expected denied method, got %v", resp)
+	}
+	invalid := []string{
+		sign(jwt.MapClaims{"sub": "wallet", "exp": expiry}, []byte("other-secret")),
+		sign(jwt.MapClaims{"sub": "wallet", "exp": time.Now().Add(-time.Minute).Unix()}, secret),
+		sign(jwt.MapClaims{"sub": "unknown", "exp": expiry}, secret),
+		sign(jwt.MapClaims{"exp": expiry}, secret),
+	}
+	for i, token := range invalid {
+		if code, _ := postAuth(t, srv.URL, token, echoRequest); code != http.StatusUnauthorized {
+			t.Errorf("invalid token %d: status mismatch: have %d, want %d", i, code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestAuthConfigValidation(t *testing.T) {
+	configs := []*AuthConfig{
+		{Keys: []APIKey{{Secret: "secret"}}},
+		{Keys: []APIKey{{Name: "a", Secret: "x"}, {Name: "a", Secret: "y"}}},
+		{Keys: []APIKey{{Name: "a"}}},
+	}
+	for i, config := range configs {
+		if _, err := NewAuthenticator(config); err == nil {
+			t.Errorf("config %d: expected error", i)
+		}
+	}
+}
+
+func TestAuthSharedAcrossServers(t *testing.T) {
+	auth, err := NewAuthenticator(&AuthConfig{
+		Keys: []APIKey{{Name: "explorer", Secret: "secret", Policy: AccessPolicy{RequestRate: 0.1, RequestBurst: 2}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var servers []*Server
+	for i := 0; i < 2; i++ {
+		server := NewServer()
+		if err := server.RegisterName("test", new(Service)); err != nil {
+			t.Fatal(err)
+		}
+		server.EnableAuth(auth)
+		servers = append(servers, server)
+	}
+	first, second := httptest.NewServer(servers[0]), httptest.NewServer(servers[1])
+	defer first.Close()
+	defer second.Close()
+
+	// the rate limit of a key applies across the servers
+	for i, srv := range []*httptest.Server{first, second} {
+		if _, resp := postAuth(t, srv.URL, "secret", echoRequest); errorCode(resp) != 0 {
+			t.Fatalf("server %d: unexpected failure: %v", i, resp)
+		}
+	}
+	if _, resp := postAuth(t, first.URL, "secret", echoRequest); errorCode(resp) != -32005 {
+		t.Errorf("expected rate limit error, got %v", resp)
+	}
+
+	// the local clients aren't restricted, their calls are only audited
+	client := DialInProc(servers[1])
+	defer client.Close()
+	var result Result
+	if err := client.Call(&result, "test_echo", "hello", 1, &Args{"x"}); err != nil {
+		t.Errorf("local call failed: %v", err)
+	}
+}
@@ -7,7 +7,8 @@ import (
 )
 
 // StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules
-func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string) (net.Listener, *Server, error) {
+// and optionally requiring the clients to authenticate.
+func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string, auth *Authenticator) (net.Listener, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -23,6 +24,9 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 			log.Debug("HTTP registered", "namespace", api.Namespace)
 		}
 	}
+	if auth != nil {
+		handler.EnableAuth(auth)
+	}
 	// All APIs registered, start the HTTP listener
 	var (
 		listener net.Listener
@@ -35,8 +39,9 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 	return listener, handler, err
 }
 
-// StartWSEndpoint starts a websocket endpoint
-func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool) (net.Listener, *Server, error) {
+// StartWSEndpoint starts a websocket endpoint, optionally requiring the clients to
+// authenticate.
+func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool, auth *Authenticator) (net.Listener, *Server, error) {
 
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
@@ -53,6 +58,9 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 			log.Debug("WebSocket registered", "service", api.Service, "namespace", api.Namespace)
 		}
 	}
+	if auth != nil {
+		handler.EnableAuth(auth)
+	}
 	// All APIs registered, start the HTTP listener
 	var (
 		listener net.Listener
@@ -66,8 +74,10 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 
 }
 
-// StartIPCEndpoint starts an IPC endpoint.
-func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, error) {
+// StartIPCEndpoint starts an IPC endpoint. The clients of IPC aren't
+// authenticated, the authenticator only records their privileged calls in the
+// audit log.
+func StartIPCEndpoint(ipcEndpoint string, apis []API, auth *Authenticator) (net.Listener, *Server, error) {
 	// Register all the APIs exposed by the services.
 	handler := NewServer()
 	for _, api := range apis {
@@ -76,6 +86,9 @@ func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, er
 		}
 		log.Debug("IPC registered", "namespace", api.Namespace)
 	}
+	if auth != nil {
+		handler.EnableAuth(auth)
+	}
 	// All APIs registered, start the IPC listener.
 	listener, err := ipcListen(ipcEndpoint)
 	if err != nil {
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"runtime"
@@ -159,18 +160,17 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 		// check if server is ordered to shutdown and return an error
 		// telling the client that his request failed.
 		if atomic.LoadInt32(&s.run) != 1 {
-			err = &shutdownError{}
-			if batch {
-				resps := make([]interface{}, len(reqs))
-				for i, r := range reqs {
-					resps[i] = codec.CreateErrorResponse(&r.id, err)
-				}
-				codec.Write(resps)
-			} else {
-				codec.Write(codec.CreateErrorResponse(&reqs[0].id, err))
-			}
+			writeErrors(codec, reqs, batch, &shutdownError{})
 			return nil
 		}
+		// refuse the whole request if the client exceeds its limits
+		if err := s.checkAccess(ctx, reqs); err != nil {
+			writeErrors(codec, reqs, batch, err)
+			if singleShot {
+				return nil
+			}
+			continue
+		}
 		// If a single shot request is executing, run and return immediately
 		if singleShot {
 			if batch {
@@ -195,6 +195,64 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 	return nil
 }
 
+// writeErrors answers all the requests of a (batch) request with the given error.
+func writeErrors(codec ServerCodec, reqs []*serverRequest, batch bool, err Error) {
+	if batch {
+		resps := make([]interface{}, len(reqs))
+		for i, r := range reqs {
+			resps[i] = codec.CreateErrorResponse(&r.id, err)
+		}
+		codec.Write(resps)
+	} else {
+		codec.Write(codec.CreateErrorResponse(&reqs[0].id, err))
+	}
+}
+
+// EnableAuth requires the clients of the server to authenticate and subjects
+// them to the access policies of the given authenticator. It must be called
+// before the server starts serving requests.
+//
+// Only the HTTP and websocket transports authenticate their clients, the
+// requests received over IPC and in-process connections are not restricted
+// but the privileged ones are still recorded in the audit log.
+func (s *Server) EnableAuth(auth *Authenticator) {
+	s.auth = auth
+}
+
+// checkAccess enforces the batch size and rate limits of the client on a (batch)
+// request, returning an error if the whole request must be refused. Otherwise
+// the requests the client isn't allowed to make are flagged individually.
+func (s *Server) checkAccess(ctx context.Context, reqs []*serverRequest) Error {
+	if s.auth == nil {
+		return nil
+	}
+	client := clientFromContext(ctx)
+	if client != nil {
+		if max := client.policy.MaxBatchSize; max > 0 && len(reqs) > max {
+			return &limitExceededError{fmt.Sprintf("batch too large (%d>%d)", len(reqs), max)}
+		}
+		if !client.limiter.allow(len(reqs)) {
+			return &limitExceededError{"request rate limit exceeded"}
+		}
+	}
+	s.auth.authorize(ctx, client, reqs)
+	return nil
+}
+
+// capResponse replaces a response larger than the limit of the client with an
+// error.
+func (s *Server) capResponse(ctx context.Context, codec ServerCodec, req *serverRequest, response interface{}) interface{} {
+	client := clientFromContext(ctx)
+	if client == nil || client.policy.MaxResponseSize <= 0 {
+		return response
+	}
+	blob, err := json.Marshal(response)
+	if err != nil || len(blob) <= client.policy.MaxResponseSize {
+		return response
+	}
+	return codec.CreateErrorResponse(&req.id, &limitExceededError{fmt.Sprintf("response too large (%d>%d)", len(blob), client.policy.MaxResponseSize)})
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes the
 // response back using the given codec. It will block until the codec is closed or the server is
 // stopped. In either case the codec is closed.
@@ -315,6 +373,7 @@ func (s *Server) exec(ctx context.Context, codec ServerCodec, req *serverRequest
 		response = codec.CreateErrorResponse(&req.id, req.err)
 	} else {
 		response, callback = s.handle(ctx, codec, req)
+		response = s.capResponse(ctx, codec, req, response)
 	}
 
 	if err := codec.Write(response); err != nil {
@@ -341,6 +400,7 @@ func (s *Server) execBatch(ctx context.Context, codec ServerCodec, requests []*s
 			if responses[i], callback = s.handle(ctx, codec, req); callback != nil {
 				callbacks = append(callbacks, callback)
 			}
+			responses[i] = s.capResponse(ctx, codec, req, responses[i])
 		}
 	}
 
@@ -394,7 +454,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 
 		if r.isPubSub { // eth_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
-				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+				requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.service + subscribeMethodSuffix, callb: callb}
 				if r.params != nil && len(callb.argTypes) > 0 {
 					argTypes := []reflect.Type{reflect.TypeOf("")}
 					argTypes = append(argTypes, callb.argTypes...)
@@ -411,7 +471,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 		}
 
 		if callb, ok := svc.callbacks[r.method]; ok { // lookup RPC method
-			requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+			requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.service + serviceMethodSeparator + r.method, callb: callb}
 			if r.params != nil && len(callb.argTypes) > 0 {
 				if args, err := codec.ParseRequestArguments(callb.argTypes, r.params); err == nil {
 					requests[i].args = args
@@ -41,6 +41,7 @@ type service struct {
 type serverRequest struct {
 	id            interface{}
 	svcname       string
+	method        string // full method name, checked against the access policies
 	callb         *callback
 	args          []reflect.Value
 	isUnsubscribe bool
@@ -54,6 +55,7 @@ type subscriptions map[string]*callback  // collection of subscription callbacks
 // Server represents a RPC server
 type Server struct {
 	services serviceRegistry
+	auth     *Authenticator
 
 	run      int32
 	codecsMu sync.Mutex
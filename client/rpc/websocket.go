@@ -40,19 +40,41 @@ var websocketJSONCodec = websocket.Codec{
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
 // To allow connections with any origin, pass "*".
 func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	validateOrigin := wsHandshakeValidator(allowedOrigins)
 	return websocket.Server{
-		Handshake: wsHandshakeValidator(allowedOrigins),
+		Handshake: func(cfg *websocket.Config, req *http.Request) error {
+			if err := validateOrigin(cfg, req); err != nil {
+				return err
+			}
+			if srv.auth != nil {
+				_, err := srv.auth.authenticate(req.Header.Get("Authorization"))
+				return err
+			}
+			return nil
+		},
 		Handler: func(conn *websocket.Conn) {
 			// Create a custom encode/decode pair to enforce payload size and number encoding
 			conn.MaxPayloadBytes = maxRequestContentLength
 
+			ctx := context.WithValue(context.Background(), "remote", conn.Request().RemoteAddr)
+			if srv.auth != nil {
+				client, err := srv.auth.authenticate(conn.Request().Header.Get("Authorization"))
+				if err != nil {
+					conn.Close()
+					return
+				}
+				ctx = context.WithValue(ctx, authClientKey{}, client)
+			}
+
 			encoder := func(v interface{}) error {
 				return websocketJSONCodec.Send(conn, v)
 			}
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			codec := NewCodec(conn, encoder, decoder)
+			defer codec.Close()
+			srv.serveRequest(ctx, codec, false, OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
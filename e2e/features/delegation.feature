@@ -0,0 +1,35 @@
+Feature: Delegating tokens to a validator
+  As a token holder
+  I want to stake my tokens through a validator
+  So that I share its rewards without running a node
+
+  Background:
+    Given the network is running
+    And I have the following accounts:
+      | account | password | tokens | funds | validator |
+      | A       | test     | 20     | 10    | true      |
+      | B       | test     | 10     | 10    | false     |
+
+  Scenario: Delegate tokens
+    Given I wait for my node to be synced
+    And I start validator with 5 mTokens deposit
+    And I unlock the account B with password 'test'
+    When B delegates 3 mTokens to A
+    Then the token balance of B should be 7 mTokens
+    And the tokens delegated to A should be 3 mTokens
+
+  Scenario: Undelegate tokens
+    Given I wait for my node to be synced
+    And I start validator with 5 mTokens deposit
+    And I unlock the account B with password 'test'
+    And B delegates 3 mTokens to A
+    When B undelegates 2 mTokens from A
+    Then the tokens delegated to A should be 1 mToken
+    And B should have 2 mTokens undelegated from A
+    And the token balance of B should be 7 mTokens
+
+  Scenario: Validator commission rate
+    Given I wait for my node to be synced
+    And I start validator with 5 mTokens deposit
+    When I set the commission rate of my validator to 1000 basis points
+    Then the commission rate of A should be 1000 basis points
@@ -87,6 +87,14 @@ func FeatureContext(opts *FeatureContextOpts) {
 	opts.suite.Step(`^I transfer (\d+) mTokens? from (\w+) to (\w+)$`, context.ITransferMTokens)
 	opts.suite.Step(`^(\d+) of (\d+) governance accounts? mints? (\d+) mTokens? to (\w+)$`, context.MintMTokens)
 
+	// Delegation
+	opts.suite.Step(`^(\w+) delegates (\d+) mTokens? to (\w+)$`, context.IDelegateMTokens)
+	opts.suite.Step(`^(\w+) undelegates (\d+) mTokens? from (\w+)$`, context.IUndelegateMTokens)
+	opts.suite.Step(`^the tokens delegated to (\w+) should be (\d+) mTokens?$`, context.IsDelegatedMTokensExact)
+	opts.suite.Step(`^(\w+) should have (\d+) mTokens? undelegated from (\w+)$`, context.IsUndelegatedMTokensExact)
+	opts.suite.Step(`^I set the commission rate of my validator to (\d+) basis points$`, validationCtx.ISetTheCommissionRate)
+	opts.suite.Step(`^the commission rate of (\w+) should be (\d+) basis points$`, context.IsCommissionRateExact)
+
 	// Nodes
 	opts.suite.Step(`^I start a new node$`, context.IStartANewNode)
 	opts.suite.Step(`^my node should sync with the network$`, context.MyNodeShouldSyncWithTheNetwork)
@@ -3,6 +3,7 @@ package impl
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
@@ -344,6 +345,8 @@ func (ctx *Context) buildGenesis() error {
 	if err != nil {
 		return err
 	}
+	// the delegation scenarios need the delegation contract
+	newGenesis.Config.DelegatedStakingBlock = big.NewInt(0)
 
 	rawJson, err := json.Marshal(newGenesis)
 	if err != nil {
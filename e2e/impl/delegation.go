@@ -0,0 +1,161 @@
+package impl
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/kowala-tech/kcoin/client/accounts"
+	"github.com/kowala-tech/kcoin/client/contracts/bindings/consensus"
+	"github.com/kowala-tech/kcoin/e2e/cluster"
+)
+
+func (ctx *Context) IDelegateMTokens(from string, tokens int64, validator string) error {
+	css, fromAccount, validatorAccount, err := ctx.delegationAccounts(from, validator)
+	if err != nil {
+		return err
+	}
+	walletAccount, err := ctx.walletAccountFor(fromAccount)
+	if err != nil {
+		return err
+	}
+
+	stake, err := css.Stake(validatorAccount.Address)
+	if err != nil {
+		return err
+	}
+	expected := new(big.Int).Add(stake.Delegated, toWei(tokens))
+
+	return ctx.waiter.Do(
+		func() error {
+			_, err := css.Delegate(walletAccount, validatorAccount.Address, toWei(tokens))
+			return err
+		},
+		func() error {
+			return ctx.checkDelegatedTokens(css, validatorAccount, expected)
+		})
+}
+
+func (ctx *Context) IUndelegateMTokens(from string, tokens int64, validator string) error {
+	css, fromAccount, validatorAccount, err := ctx.delegationAccounts(from, validator)
+	if err != nil {
+		return err
+	}
+	walletAccount, err := ctx.walletAccountFor(fromAccount)
+	if err != nil {
+		return err
+	}
+
+	stake, err := css.Stake(validatorAccount.Address)
+	if err != nil {
+		return err
+	}
+	expected := new(big.Int).Sub(stake.Delegated, toWei(tokens))
+
+	return ctx.waiter.Do(
+		func() error {
+			_, err := css.Undelegate(walletAccount, validatorAccount.Address, toWei(tokens))
+			return err
+		},
+		func() error {
+			return ctx.checkDelegatedTokens(css, validatorAccount, expected)
+		})
+}
+
+func (ctx *Context) IsDelegatedMTokensExact(validator string, tokens int64) error {
+	css, _, validatorAccount, err := ctx.delegationAccounts(validator, validator)
+	if err != nil {
+		return err
+	}
+
+	return ctx.checkDelegatedTokens(css, validatorAccount, toWei(tokens))
+}
+
+func (ctx *Context) IsUndelegatedMTokensExact(from string, tokens int64, validator string) error {
+	css, fromAccount, validatorAccount, err := ctx.delegationAccounts(from, validator)
+	if err != nil {
+		return err
+	}
+
+	undelegations, err := css.Undelegations(fromAccount.Address, validatorAccount.Address)
+	if err != nil {
+		return err
+	}
+	total := new(big.Int)
+	for _, undelegation := range undelegations {
+		total.Add(total, undelegation.Amount())
+	}
+	if total.Cmp(toWei(tokens)) != 0 {
+		return fmt.Errorf("account %s have %v mTokens undelegated from %s, expected %v",
+			fromAccount.Address.String(), total, validatorAccount.Address.String(), toWei(tokens))
+	}
+
+	return nil
+}
+
+func (ctx *Context) IsCommissionRateExact(validator string, rate int64) error {
+	css, _, validatorAccount, err := ctx.delegationAccounts(validator, validator)
+	if err != nil {
+		return err
+	}
+
+	stake, err := css.Stake(validatorAccount.Address)
+	if err != nil {
+		return err
+	}
+	if stake.CommissionRate.Cmp(big.NewInt(rate)) != 0 {
+		return fmt.Errorf("validator %s have a commission rate of %v, expected %v",
+			validatorAccount.Address.String(), stake.CommissionRate, rate)
+	}
+
+	return nil
+}
+
+func (ctx *ValidationContext) ISetTheCommissionRate(rate int64) error {
+	return ctx.waiter.Do(
+		ctx.globalCtx.makeExecFunc(ctx.nodeID(), setCommissionRateCommand(rate)),
+		func() error {
+			return ctx.globalCtx.IsCommissionRateExact("A", rate)
+		})
+}
+
+func (ctx *Context) checkDelegatedTokens(css *consensus.Consensus, validator accounts.Account, expected *big.Int) error {
+	stake, err := css.Stake(validator.Address)
+	if err != nil {
+		return err
+	}
+	if stake.Delegated.Cmp(expected) != 0 {
+		return fmt.Errorf("validator %s have %v mTokens delegated, expected %v", validator.Address.String(), stake.Delegated, expected)
+	}
+
+	return nil
+}
+
+func (ctx *Context) delegationAccounts(from, validator string) (*consensus.Consensus, accounts.Account, accounts.Account, error) {
+	fromAccount, ok := ctx.accounts[from]
+	if !ok {
+		return nil, accounts.Account{}, accounts.Account{}, fmt.Errorf("can't get account for %q", from)
+	}
+	validatorAccount, ok := ctx.accounts[validator]
+	if !ok {
+		return nil, accounts.Account{}, accounts.Account{}, fmt.Errorf("can't get account for %q", validator)
+	}
+
+	c, err := consensus.Bind(ctx.client, ctx.chainID)
+	if err != nil {
+		return nil, accounts.Account{}, accounts.Account{}, err
+	}
+
+	return c.(*consensus.Consensus), fromAccount, validatorAccount, nil
+}
+
+func (ctx *Context) walletAccountFor(acct accounts.Account) (accounts.WalletAccount, error) {
+	wallet, err := ctx.findWalletFor(acct)
+	if err != nil {
+		return nil, err
+	}
+	return accounts.NewWalletAccount(wallet, acct)
+}
+
+func setCommissionRateCommand(rate int64) []string {
+	return cluster.KcoinExecCommand(fmt.Sprintf("validator.setCommissionRate(%d)", rate))
+}
@@ -17,7 +17,7 @@ var (
 //
 //         // make and configure a mocked BlockHandler
 //         mockedBlockHandler := &BlockHandlerMock{
-//             HandleBlockFunc: func(in1 *Block)  {
+//             HandleBlockFunc: func(in1 *Block) error {
 // 	               panic("TODO: mock out the HandleBlock method")
 //             },
 //         }
@@ -28,7 +28,7 @@ var (
 //     }
 type BlockHandlerMock struct {
 	// HandleBlockFunc mocks the HandleBlock method.
-	HandleBlockFunc func(in1 *Block)
+	HandleBlockFunc func(in1 *Block) error
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -41,7 +41,7 @@ type BlockHandlerMock struct {
 }
 
 // HandleBlock calls HandleBlockFunc.
-func (mock *BlockHandlerMock) HandleBlock(in1 *Block) {
+func (mock *BlockHandlerMock) HandleBlock(in1 *Block) error {
 	if mock.HandleBlockFunc == nil {
 		panic("BlockHandlerMock.HandleBlockFunc: method is nil but BlockHandler.HandleBlock was just called")
 	}
@@ -53,7 +53,7 @@ func (mock *BlockHandlerMock) HandleBlock(in1 *Block) {
 	lockBlockHandlerMockHandleBlock.Lock()
 	mock.calls.HandleBlock = append(mock.calls.HandleBlock, callInfo)
 	lockBlockHandlerMockHandleBlock.Unlock()
-	mock.HandleBlockFunc(in1)
+	return mock.HandleBlockFunc(in1)
 }
 
 // HandleBlockCalls gets all the calls that were made to HandleBlock.
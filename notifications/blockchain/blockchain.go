@@ -3,17 +3,21 @@ package blockchain
 import (
 	"math/big"
 
+	"github.com/kowala-tech/kcoin/client/common"
 	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
 )
 
 type Block struct {
 	Number       *big.Int
+	Hash         common.Hash
 	Transactions []*protocolbuffer.Transaction
 }
 
 //go:generate moq -out block_handler_mock.go . BlockHandler
 type BlockHandler interface {
-	HandleBlock(*Block)
+	// HandleBlock returns an error if the block was not fully processed,
+	// in which case the same block is delivered again.
+	HandleBlock(*Block) error
 }
 
 //go:generate moq -out blockchain_mock.go . Blockchain
@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"errors"
 	"math/big"
 	"time"
 
@@ -28,8 +29,11 @@ type kcoin struct {
 	handlers  map[BlockHandler]struct{}
 
 	latestBlock *big.Int
+	latestHash  common.Hash
 }
 
+var errUnexpectedParent = errors.New("block does not extend the last processed block")
+
 func NewKcoin(rpcAddr string, pollingIntervalSeconds int, logger *logrus.Entry) Blockchain {
 	return &kcoin{
 		rpcAddr:         rpcAddr,
@@ -52,13 +56,13 @@ func (k *kcoin) Start() error {
 	k.ctx, k.ctxCancel = context.WithCancel(context.Background())
 
 	if k.latestBlock == nil {
-		k.logger.Debug("Setting starting block to first")
+		k.logger.Debug("No checkpoint, starting from the genesis block")
 		block, err := k.getBlock(big.NewInt(0))
 		if err != nil {
 			return err
 		}
 		k.latestBlock = block.Number()
-		k.logger.WithField("blockNum", block.Number).Info("Starting block set to latest")
+		k.logger.WithField("blockNum", block.Number()).Info("Starting block set to the genesis block")
 	}
 
 	k.pollingLoop()
@@ -77,7 +81,8 @@ func (k *kcoin) Stop() {
 }
 
 func (k *kcoin) Seek(blockNumber *big.Int) error {
-	k.latestBlock = blockNumber
+	k.latestBlock = new(big.Int).Set(blockNumber)
+	k.latestHash = common.Hash{}
 	return nil
 }
 
@@ -92,6 +97,15 @@ func (k *kcoin) getBlock(blockNumber *big.Int) (*types.Block, error) {
 	return k.client.BlockByNumber(ctx, blockNumber)
 }
 
+func (k *kcoin) getLogs(blockNumber *big.Int) ([]types.Log, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+	return k.client.FilterLogs(ctx, kcoinLib.FilterQuery{
+		FromBlock: blockNumber,
+		ToBlock:   blockNumber,
+	})
+}
+
 func (k *kcoin) pollingLoop() {
 	k.logger.Debug("Running main loop...")
 
@@ -104,30 +118,58 @@ func (k *kcoin) pollingLoop() {
 			return
 		default:
 		}
-		rawBlock, err := k.getBlock(k.latestBlock)
-		if err != nil {
-			if err == kcoinLib.NotFound {
-				k.logger.Debug("No new block found")
-				time.Sleep(k.pollingInterval)
-			} else {
-				k.logger.WithError(err).Error("Error fetching new block")
-				time.Sleep(k.pollingInterval)
-			}
-		} else {
-			k.logger.WithField("blockNum", rawBlock.Number().Int64()).Info("New block found")
-
-			block := k.wrapBlock(rawBlock)
-
-			for handler := range k.handlers {
-				handler.HandleBlock(block)
-			}
-
-			k.latestBlock.Add(k.latestBlock, common.Big1)
+
+		err := k.processNextBlock()
+		switch {
+		case err == kcoinLib.NotFound:
+			k.logger.Debug("No new block found")
+			time.Sleep(k.pollingInterval)
+		case err != nil:
+			k.logger.WithError(err).WithField("blockNum", k.latestBlock.Int64()).Error("Error processing block")
+			time.Sleep(k.pollingInterval)
+		}
+	}
+}
+
+// processNextBlock fetches the next block and hands it to every handler. The
+// cursor only moves forward once all of them succeed, so a failed block is
+// retried instead of skipped, and blocks that are behind the node's head are
+// backfilled one by one without waiting for the polling interval.
+func (k *kcoin) processNextBlock() error {
+	rawBlock, err := k.getBlock(k.latestBlock)
+	if err != nil {
+		return err
+	}
+
+	if k.latestHash != (common.Hash{}) && rawBlock.ParentHash() != k.latestHash {
+		// Nodes behind a load balancer can disagree on a block until they
+		// are in sync. Wait and fetch it again rather than skipping it.
+		return errUnexpectedParent
+	}
+
+	logs, err := k.getLogs(rawBlock.Number())
+	if err != nil {
+		return err
+	}
+
+	block, err := k.wrapBlock(rawBlock, logs)
+	if err != nil {
+		return err
+	}
+
+	k.logger.WithField("blockNum", block.Number.Int64()).Info("New block found")
+	for handler := range k.handlers {
+		if err := handler.HandleBlock(block); err != nil {
+			return err
 		}
 	}
+
+	k.latestHash = block.Hash
+	k.latestBlock = new(big.Int).Add(block.Number, common.Big1)
+	return nil
 }
 
-func (k *kcoin) wrapBlock(block *types.Block) *Block {
+func (k *kcoin) wrapBlock(block *types.Block, logs []types.Log) (*Block, error) {
 	inTransactions := block.Transactions()
 	transactions := make([]*protocolbuffer.Transaction, len(inTransactions))
 	for i, tx := range inTransactions {
@@ -140,7 +182,7 @@ func (k *kcoin) wrapBlock(block *types.Block) *Block {
 
 		from, err := tx.From()
 		if err != nil {
-			return nil
+			return nil, err
 		}
 
 		transactions[i] = &protocolbuffer.Transaction{
@@ -152,10 +194,28 @@ func (k *kcoin) wrapBlock(block *types.Block) *Block {
 			GasUsed:     int64(block.GasUsed()),
 			GasPrice:    tx.GasPrice().Int64(),
 			BlockHeight: block.Number().Int64(),
+			BlockHash:   block.Hash().String(),
+			Index:       int64(i),
 		}
 	}
+	for _, log := range logs {
+		if int(log.TxIndex) >= len(transactions) {
+			continue
+		}
+		topics := make([]string, len(log.Topics))
+		for i, topic := range log.Topics {
+			topics[i] = topic.String()
+		}
+		tx := transactions[log.TxIndex]
+		tx.Events = append(tx.Events, &protocolbuffer.Event{
+			Address: log.Address.String(),
+			Topics:  topics,
+			Data:    log.Data,
+		})
+	}
 	return &Block{
 		Number:       block.Number(),
+		Hash:         block.Hash(),
 		Transactions: transactions,
-	}
+	}, nil
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/kowala-tech/kcoin/notifications/keyvalue"
 	"github.com/kowala-tech/kcoin/notifications/persistence"
 	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/kowala-tech/kcoin/notifications/rules"
 )
 
 func main() {
@@ -50,13 +51,16 @@ func main() {
 	log := logrus.NewEntry(logger)
 	emailMappingServer := api.NewEmailMappingServer(log)
 	transactionService := api.NewTransactionServiceServer(log)
+	notificationRules := api.NewNotificationRulesServer(log)
 
 	g := inj.NewGraph()
 	g.Provide(
 		emailMappingServer,
 		transactionService,
+		notificationRules,
 		keyvalue.NewRedisNamespacedKeyValue(redisClient, "emails"),
 		persistence.NewRedisPersistence(redisClient),
+		rules.NewRedisRepository(redisClient),
 	)
 
 	if valid, errors := g.Assert(); !valid {
@@ -71,6 +75,7 @@ func main() {
 	grpcServer := grpc.NewServer()
 	protocolbuffer.RegisterEmailMappingServer(grpcServer, emailMappingServer)
 	protocolbuffer.RegisterTransactionServiceServer(grpcServer, transactionService)
+	protocolbuffer.RegisterNotificationRulesServer(grpcServer, notificationRules)
 	err = grpcServer.Serve(lis)
 	if err != nil {
 		panic(err)
@@ -18,10 +18,11 @@ import (
 	"github.com/kowala-tech/kcoin/notifications/environment"
 	"github.com/kowala-tech/kcoin/notifications/keyvalue"
 	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/set"
 )
 
 func main() {
-	exitSignal := make(chan os.Signal)
+	exitSignal := make(chan os.Signal, 1)
 	signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
 
 	envReader := environment.NewReaderOs()
@@ -78,6 +79,7 @@ func main() {
 		keyvalue.NewRedisNamespacedKeyValue(redisClient, "emails"),
 		sub,
 		notif,
+		set.NewRedisSet(redisClient, "emailer:processed"),
 	)
 
 	if valid, errors := g.Assert(); !valid {
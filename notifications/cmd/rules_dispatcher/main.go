@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-redis/redis"
+	"github.com/yourheropaul/inj"
+
+	"github.com/kowala-tech/kcoin/notifications/core"
+	"github.com/kowala-tech/kcoin/notifications/environment"
+	"github.com/kowala-tech/kcoin/notifications/notifier"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/rules"
+	"github.com/kowala-tech/kcoin/notifications/set"
+)
+
+const (
+	webhookTimeout = 10 * time.Second
+	retryDelay     = 10 * time.Second
+)
+
+func main() {
+	exitSignal := make(chan os.Signal, 1)
+	signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
+
+	envReader := environment.NewReaderOs()
+	redisAddr := envReader.Read("REDIS_ADDR")
+	nsqAddr := envReader.Read("NSQ_ADDR")
+	logLevelRaw := envReader.Read("LOG_LEVEL")
+	smtpFrom := envReader.Read("SMTP_FROM")
+	smtpHost := envReader.Read("SMTP_HOST")
+	smtpPortRaw := envReader.Read("SMTP_PORT")
+	smtpUsername := envReader.Read("SMTP_USERNAME")
+	smtpPassword := envReader.Read("SMTP_PASSWORD")
+	webhookAttemptsRaw := envReader.Read("WEBHOOK_ATTEMPTS")
+	if logLevelRaw == "" {
+		logLevelRaw = "info"
+	}
+	if webhookAttemptsRaw == "" {
+		webhookAttemptsRaw = "5"
+	}
+
+	smtpPort, err := strconv.Atoi(smtpPortRaw)
+	if err != nil {
+		panic(err)
+	}
+
+	webhookAttempts, err := strconv.ParseUint(webhookAttemptsRaw, 10, 16)
+	if err != nil {
+		panic(err)
+	}
+
+	logLevel, err := logrus.ParseLevel(logLevelRaw)
+	if err != nil {
+		panic(err)
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logLevel)
+	logger.Out = os.Stdout
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     redisAddr,
+		Password: "", // no password set
+		DB:       0,  // use default DB
+	})
+
+	_, err = redisClient.Ping().Result()
+	if err != nil {
+		panic(err)
+	}
+
+	pub, err := pubsub.NewNSQPublisher(nsqAddr, logrus.NewEntry(logger))
+	if err != nil {
+		panic(err)
+	}
+
+	sub := pubsub.NewNSQRetryingSubscriber("transactions", "rules", nsqAddr, uint16(webhookAttempts), retryDelay, logrus.NewEntry(logger))
+
+	dialer := notifier.NewSMTPDialer(smtpHost, smtpPort, smtpUsername, smtpPassword)
+	notif := notifier.NewGomailMessage(logrus.NewEntry(logger), dialer, "Kowala notification")
+
+	worker := core.NewRulesDispatcher(logrus.NewEntry(logger), map[protocolbuffer.Channel]rules.Channel{
+		protocolbuffer.Channel_EMAIL: rules.NewEmailChannel(smtpFrom, notif),
+		protocolbuffer.Channel_WEBHOOK: rules.NewWebhookChannel(
+			logrus.NewEntry(logger),
+			rules.NewWebhookClient(webhookTimeout),
+		),
+		protocolbuffer.Channel_NSQ: rules.NewNSQChannel(pub),
+	})
+
+	g := inj.NewGraph()
+	g.Provide(
+		worker,
+		rules.NewRedisRepository(redisClient),
+		sub,
+		set.NewRedisSet(redisClient, "rules:processed"),
+	)
+
+	if valid, errors := g.Assert(); !valid {
+		panic(strings.Join(errors, ", "))
+	}
+
+	worker.Register()
+	err = sub.Start()
+	if err != nil {
+		panic(err)
+	}
+
+	<-exitSignal
+	sub.Stop()
+	pub.Stop()
+	redisClient.Close()
+}
@@ -11,12 +11,13 @@ import (
 	"github.com/kowala-tech/kcoin/notifications/environment"
 	"github.com/kowala-tech/kcoin/notifications/persistence"
 	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/set"
 	"os/signal"
 	"syscall"
 )
 
 func main() {
-	exitSignal := make(chan os.Signal)
+	exitSignal := make(chan os.Signal, 1)
 	signal.Notify(exitSignal, syscall.SIGINT, syscall.SIGTERM)
 
 	envReader := environment.NewReaderOs()
@@ -54,6 +55,7 @@ func main() {
 		worker,
 		persistence.NewRedisPersistence(redisClient),
 		pubsub.NewNSQSubscriber("transactions", "db-persistance", nsqAddr, logrus.NewEntry(logger)),
+		set.NewRedisSet(redisClient, "db-persistance:processed"),
 	)
 
 	if valid, errors := g.Assert(); !valid {
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/kowala-tech/kcoin/notifications/rules"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type notificationRulesServer struct {
+	Rules rules.Repository `inj:""`
+
+	now    func() time.Time
+	logger *logrus.Entry
+}
+
+// NewNotificationRulesServer returns the rules service. Every request must be
+// signed by the wallet owning the rules, see AddRuleMessage, RemoveRuleMessage
+// and ListRulesMessage.
+func NewNotificationRulesServer(logger *logrus.Entry) protocolbuffer.NotificationRulesServer {
+	return &notificationRulesServer{
+		now:    time.Now,
+		logger: logger.WithField("app", "core/api"),
+	}
+}
+
+func (s *notificationRulesServer) AddRule(ctx context.Context, data *protocolbuffer.AddRuleRequest) (*protocolbuffer.AddRuleReply, error) {
+	if data.GetRule() == nil {
+		return &protocolbuffer.AddRuleReply{}, status.Error(codes.InvalidArgument, "Missing rule")
+	}
+	rule := proto.Clone(data.GetRule()).(*protocolbuffer.Rule)
+	if err := validateRule(rule); err != nil {
+		return &protocolbuffer.AddRuleReply{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	message := AddRuleMessage(rule, data.GetTimestamp())
+	if err := verifyOwnership(rule.GetWallet(), message, data.GetTimestamp(), data.GetSignature(), s.now()); err != nil {
+		return &protocolbuffer.AddRuleReply{}, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	id, err := newRuleID()
+	if err != nil {
+		s.logger.WithError(err).Error("Error generating rule id")
+		return &protocolbuffer.AddRuleReply{}, status.Error(codes.Internal, "Error generating rule id")
+	}
+	rule.Id = id
+	rule.Wallet = strings.ToLower(rule.GetWallet())
+
+	err = s.Rules.Add(rule)
+	if err != nil {
+		s.logger.WithError(err).Error("Error storing rule")
+		return &protocolbuffer.AddRuleReply{}, status.Error(codes.Internal, "Error storing rule")
+	}
+	return &protocolbuffer.AddRuleReply{Id: id}, nil
+}
+
+func (s *notificationRulesServer) RemoveRule(ctx context.Context, data *protocolbuffer.RemoveRuleRequest) (*protocolbuffer.RemoveRuleReply, error) {
+	message := RemoveRuleMessage(data.GetWallet(), data.GetId(), data.GetTimestamp())
+	if err := verifyOwnership(data.GetWallet(), message, data.GetTimestamp(), data.GetSignature(), s.now()); err != nil {
+		return &protocolbuffer.RemoveRuleReply{}, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	err := s.Rules.Remove(data.GetWallet(), data.GetId())
+	if err == rules.ErrRuleNotFound {
+		return &protocolbuffer.RemoveRuleReply{}, status.Error(codes.NotFound, "There's no such rule registered to this wallet")
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Error deleting rule")
+		return &protocolbuffer.RemoveRuleReply{}, status.Error(codes.Internal, "Error deleting rule")
+	}
+	return &protocolbuffer.RemoveRuleReply{}, nil
+}
+
+func (s *notificationRulesServer) ListRules(ctx context.Context, data *protocolbuffer.ListRulesRequest) (*protocolbuffer.ListRulesReply, error) {
+	message := ListRulesMessage(data.GetWallet(), data.GetTimestamp())
+	if err := verifyOwnership(data.GetWallet(), message, data.GetTimestamp(), data.GetSignature(), s.now()); err != nil {
+		return &protocolbuffer.ListRulesReply{}, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	walletRules, err := s.Rules.List(data.GetWallet())
+	if err != nil {
+		s.logger.WithError(err).Error("Error reading rules")
+		return &protocolbuffer.ListRulesReply{}, status.Error(codes.Internal, "Error reading rules")
+	}
+
+	// the secrets are only known to the wallet and the webhook receivers
+	replies := make([]*protocolbuffer.Rule, len(walletRules))
+	for i, rule := range walletRules {
+		replies[i] = proto.Clone(rule).(*protocolbuffer.Rule)
+		replies[i].Secret = ""
+	}
+	return &protocolbuffer.ListRulesReply{Rules: replies}, nil
+}
+
+func validateRule(rule *protocolbuffer.Rule) error {
+	if !common.IsHexAddress(rule.GetWallet()) {
+		return errInvalidField("wallet")
+	}
+	if rule.GetMinAmount() < 0 {
+		return errInvalidField("min_amount")
+	}
+	if rule.GetToken() != "" && !common.IsHexAddress(rule.GetToken()) {
+		return errInvalidField("token")
+	}
+	if rule.GetContract() != "" && !common.IsHexAddress(rule.GetContract()) {
+		return errInvalidField("contract")
+	}
+	if rule.GetDestination() == "" {
+		return errInvalidField("destination")
+	}
+	switch rule.GetChannel() {
+	case protocolbuffer.Channel_WEBHOOK:
+		if !validWebhook(rule.GetDestination()) {
+			return errInvalidField("destination")
+		}
+	case protocolbuffer.Channel_NSQ:
+		if !rules.ValidNSQTopic(rule.GetDestination()) {
+			return errInvalidField("destination")
+		}
+	}
+	if _, err := rules.ParseTemplate(rule.GetTemplate()); err != nil {
+		return errInvalidField("template")
+	}
+	return nil
+}
+
+// validWebhook returns whether the destination is an http(s) URL that doesn't
+// name a local or private host. Hosts resolving to such addresses are refused
+// when the webhook is delivered.
+func validWebhook(destination string) bool {
+	u, err := url.Parse(destination)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil && !rules.IsPublicIP(ip) {
+		return false
+	}
+	return true
+}
+
+func errInvalidField(field string) error {
+	return fmt.Errorf("invalid %s", field)
+}
+
+func newRuleID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yourheropaul/inj"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/kowala-tech/kcoin/notifications/rules"
+)
+
+var (
+	ruleKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	ruleWallet  = crypto.PubkeyToAddress(ruleKey.PublicKey).Hex()
+	otherKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+)
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, message string) []byte {
+	sig, err := crypto.Sign(SignHash(message), key)
+	require.NoError(t, err)
+	sig[64] += 27
+	return sig
+}
+
+func addRuleRequest(t *testing.T, rule *protocolbuffer.Rule) *protocolbuffer.AddRuleRequest {
+	timestamp := time.Now().Unix()
+	return &protocolbuffer.AddRuleRequest{
+		Rule:      rule,
+		Timestamp: timestamp,
+		Signature: sign(t, ruleKey, AddRuleMessage(rule, timestamp)),
+	}
+}
+
+func removeRuleRequest(t *testing.T, id string) *protocolbuffer.RemoveRuleRequest {
+	timestamp := time.Now().Unix()
+	return &protocolbuffer.RemoveRuleRequest{
+		Wallet:    ruleWallet,
+		Id:        id,
+		Timestamp: timestamp,
+		Signature: sign(t, ruleKey, RemoveRuleMessage(ruleWallet, id, timestamp)),
+	}
+}
+
+func listRulesRequest(t *testing.T) *protocolbuffer.ListRulesRequest {
+	timestamp := time.Now().Unix()
+	return &protocolbuffer.ListRulesRequest{
+		Wallet:    ruleWallet,
+		Timestamp: timestamp,
+		Signature: sign(t, ruleKey, ListRulesMessage(ruleWallet, timestamp)),
+	}
+}
+
+func setupRules(t *testing.T) (rules.Repository, protocolbuffer.NotificationRulesClient, *grpc.Server, *grpc.ClientConn) {
+	repository := rules.NewMemoryRepository()
+	apiServer := NewNotificationRulesServer(logger)
+
+	gr := inj.NewGraph()
+	gr.Provide(
+		apiServer,
+		repository,
+	)
+
+	valid, messages := gr.Assert()
+	require.True(t, valid, messages)
+
+	port := getFreePort(t)
+	addr := fmt.Sprintf(":%d", port)
+	lis, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	protocolbuffer.RegisterNotificationRulesServer(grpcServer, apiServer)
+	go grpcServer.Serve(lis)
+	time.Sleep(10 * time.Millisecond)
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	require.NoError(t, err)
+
+	client := protocolbuffer.NewNotificationRulesClient(conn)
+
+	return repository, client, grpcServer, conn
+}
+
+func TestNotificationRules_AddsAndListsRules(t *testing.T) {
+	repository, apiClient, grpcServer, grpcClient := setupRules(t)
+	defer grpcServer.Stop()
+	defer grpcClient.Close()
+
+	reply, err := apiClient.AddRule(context.Background(), addRuleRequest(t, &protocolbuffer.Rule{
+		Wallet:      ruleWallet,
+		Direction:   protocolbuffer.Direction_INCOMING,
+		MinAmount:   100,
+		Channel:     protocolbuffer.Channel_WEBHOOK,
+		Destination: "https://example.com/hook",
+		Secret:      "secret",
+	}))
+	require.NoError(t, err)
+	require.NotEmpty(t, reply.GetId())
+
+	stored, err := repository.List(ruleWallet)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "secret", stored[0].GetSecret())
+
+	list, err := apiClient.ListRules(context.Background(), listRulesRequest(t))
+	require.NoError(t, err)
+	require.Len(t, list.GetRules(), 1)
+	require.Equal(t, reply.GetId(), list.GetRules()[0].GetId())
+	require.Equal(t, int64(100), list.GetRules()[0].GetMinAmount())
+	require.Empty(t, list.GetRules()[0].GetSecret())
+}
+
+func TestNotificationRules_RejectsInvalidRules(t *testing.T) {
+	_, apiClient, grpcServer, grpcClient := setupRules(t)
+	defer grpcServer.Stop()
+	defer grpcClient.Close()
+
+	invalid := []*protocolbuffer.Rule{
+		{Wallet: "abcde", Destination: "to@test.com"},
+		{Wallet: ruleWallet},
+		{Wallet: ruleWallet, Destination: "to@test.com", MinAmount: -1},
+		{Wallet: ruleWallet, Destination: "ftp://example.com", Channel: protocolbuffer.Channel_WEBHOOK},
+		{Wallet: ruleWallet, Destination: "http://localhost:8080/hook", Channel: protocolbuffer.Channel_WEBHOOK},
+		{Wallet: ruleWallet, Destination: "http://127.0.0.1/hook", Channel: protocolbuffer.Channel_WEBHOOK},
+		{Wallet: ruleWallet, Destination: "http://10.0.0.1/hook", Channel: protocolbuffer.Channel_WEBHOOK},
+		{Wallet: ruleWallet, Destination: "http://169.254.169.254/latest/meta-data", Channel: protocolbuffer.Channel_WEBHOOK},
+		{Wallet: ruleWallet, Destination: "http://[::1]/hook", Channel: protocolbuffer.Channel_WEBHOOK},
+		{Wallet: ruleWallet, Destination: "transactions#ephemeral", Channel: protocolbuffer.Channel_NSQ},
+		{Wallet: ruleWallet, Destination: "to@test.com", Template: "{{.Amount"},
+	}
+	for _, rule := range invalid {
+		_, err := apiClient.AddRule(context.Background(), addRuleRequest(t, rule))
+		require.Equal(t, codes.InvalidArgument, status.Code(err), rule.String())
+	}
+}
+
+func TestNotificationRules_RemovesRules(t *testing.T) {
+	repository, apiClient, grpcServer, grpcClient := setupRules(t)
+	defer grpcServer.Stop()
+	defer grpcClient.Close()
+
+	reply, err := apiClient.AddRule(context.Background(), addRuleRequest(t, &protocolbuffer.Rule{Wallet: ruleWallet, Destination: "to@test.com"}))
+	require.NoError(t, err)
+
+	_, err = apiClient.RemoveRule(context.Background(), removeRuleRequest(t, reply.GetId()))
+	require.NoError(t, err)
+
+	stored, err := repository.List(ruleWallet)
+	require.NoError(t, err)
+	require.Len(t, stored, 0)
+
+	_, err = apiClient.RemoveRule(context.Background(), removeRuleRequest(t, reply.GetId()))
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestNotificationRules_RequiresWalletSignature(t *testing.T) {
+	repository, apiClient, grpcServer, grpcClient := setupRules(t)
+	defer grpcServer.Stop()
+	defer grpcClient.Close()
+
+	rule := &protocolbuffer.Rule{Id: "rule", Wallet: ruleWallet, Destination: "to@test.com"}
+	require.NoError(t, repository.Add(rule))
+	ctx := context.Background()
+
+	// unsigned requests
+	_, err := apiClient.AddRule(ctx, &protocolbuffer.AddRuleRequest{Rule: rule, Timestamp: time.Now().Unix()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = apiClient.RemoveRule(ctx, &protocolbuffer.RemoveRuleRequest{Wallet: ruleWallet, Id: "rule", Timestamp: time.Now().Unix()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = apiClient.ListRules(ctx, &protocolbuffer.ListRulesRequest{Wallet: ruleWallet, Timestamp: time.Now().Unix()})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// requests signed by another wallet
+	list := listRulesRequest(t)
+	list.Signature = sign(t, otherKey, ListRulesMessage(ruleWallet, list.Timestamp))
+	_, err = apiClient.ListRules(ctx, list)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	remove := removeRuleRequest(t, "rule")
+	remove.Signature = sign(t, otherKey, RemoveRuleMessage(ruleWallet, "rule", remove.Timestamp))
+	_, err = apiClient.RemoveRule(ctx, remove)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// signatures of other requests
+	remove = removeRuleRequest(t, "other")
+	remove.Id = "rule"
+	_, err = apiClient.RemoveRule(ctx, remove)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	add := addRuleRequest(t, &protocolbuffer.Rule{Wallet: ruleWallet, Destination: "to@test.com"})
+	add.Rule.Destination = "attacker@test.com"
+	_, err = apiClient.AddRule(ctx, add)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// requests relayed with another rule than the signed one
+	for _, tamper := range []func(*protocolbuffer.Rule){
+		func(rule *protocolbuffer.Rule) { rule.MinAmount = 1 },
+		func(rule *protocolbuffer.Rule) { rule.Direction = protocolbuffer.Direction_OUTGOING },
+		func(rule *protocolbuffer.Rule) { rule.Template = "{{.Transaction}}" },
+		func(rule *protocolbuffer.Rule) { rule.Secret = "attacker" },
+	} {
+		add = addRuleRequest(t, &protocolbuffer.Rule{Wallet: ruleWallet, Destination: "to@test.com", MinAmount: 10})
+		tamper(add.Rule)
+		_, err = apiClient.AddRule(ctx, add)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	// expired signatures
+	expired := time.Now().Add(-time.Hour).Unix()
+	_, err = apiClient.ListRules(ctx, &protocolbuffer.ListRulesRequest{
+		Wallet:    ruleWallet,
+		Timestamp: expired,
+		Signature: sign(t, ruleKey, ListRulesMessage(ruleWallet, expired)),
+	})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stored, err := repository.List(ruleWallet)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+}
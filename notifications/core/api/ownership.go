@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+)
+
+// ownershipWindow is how far the timestamp of a signed request may be from the
+// server clock, which bounds the time a leaked signature can be replayed.
+const ownershipWindow = 5 * time.Minute
+
+// AddRuleMessage returns the message the wallet of a rule signs to add it. It
+// covers the whole rule through its hash, so that none of its filters, its
+// template or its secret can be changed by whoever relays the request.
+func AddRuleMessage(rule *protocolbuffer.Rule, timestamp int64) string {
+	return fmt.Sprintf("kowala-notifications:AddRule:%s:%s:%s:%s:%d", strings.ToLower(rule.GetWallet()), rule.GetChannel(), rule.GetDestination(), RuleHash(rule), timestamp)
+}
+
+// RuleHash returns the hex encoded keccak256 hash of the fields of a rule set by
+// its wallet, each one prefixed with its length, in the order of the Rule
+// message. The id is assigned by the server, so it isn't part of the hash.
+func RuleHash(rule *protocolbuffer.Rule) string {
+	fields := []string{
+		strings.ToLower(rule.GetWallet()),
+		rule.GetDirection().String(),
+		strconv.FormatInt(rule.GetMinAmount(), 10),
+		rule.GetToken(),
+		rule.GetContract(),
+		rule.GetEvent(),
+		rule.GetChannel().String(),
+		rule.GetDestination(),
+		rule.GetSecret(),
+		rule.GetTemplate(),
+	}
+	var encoded bytes.Buffer
+	for _, field := range fields {
+		fmt.Fprintf(&encoded, "%d:%s", len(field), field)
+	}
+	return hex.EncodeToString(crypto.Keccak256([]byte(encoded.String())))
+}
+
+// RemoveRuleMessage returns the message a wallet signs to remove one of its
+// rules.
+func RemoveRuleMessage(wallet, id string, timestamp int64) string {
+	return fmt.Sprintf("kowala-notifications:RemoveRule:%s:%s:%d", strings.ToLower(wallet), id, timestamp)
+}
+
+// ListRulesMessage returns the message a wallet signs to list its rules.
+func ListRulesMessage(wallet string, timestamp int64) string {
+	return fmt.Sprintf("kowala-notifications:ListRules:%s:%d", strings.ToLower(wallet), timestamp)
+}
+
+// SignHash returns the hash signed by the wallets for a message, as
+// personal_sign does, so that the requests can be signed by any wallet.
+func SignHash(message string) []byte {
+	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
+	return crypto.Keccak256([]byte(msg))
+}
+
+// verifyOwnership checks that the message was signed by the wallet within the
+// ownership window.
+func verifyOwnership(wallet, message string, timestamp int64, signature []byte, now time.Time) error {
+	if !common.IsHexAddress(wallet) {
+		return errInvalidField("wallet")
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if signedAt.Before(now.Add(-ownershipWindow)) || signedAt.After(now.Add(ownershipWindow)) {
+		return errInvalidField("timestamp")
+	}
+	if len(signature) != 65 {
+		return errInvalidField("signature")
+	}
+
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(SignHash(message), sig)
+	if err != nil {
+		return errInvalidField("signature")
+	}
+	if crypto.PubkeyToAddress(*pub) != common.HexToAddress(wallet) {
+		return errInvalidField("signature")
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kowala-tech/kcoin/notifications/notifier"
 	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
 	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/set"
 	"github.com/sirupsen/logrus"
 )
 
@@ -13,6 +14,7 @@ type Emailer struct {
 	Notifier   notifier.Notifier `inj:""`
 	Subscriber pubsub.Subscriber `inj:""`
 	KV         keyvalue.KeyValue `inj:""`
+	Processed  set.Set           `inj:""`
 
 	from   string
 	logger *logrus.Entry
@@ -44,6 +46,17 @@ func (emailer *Emailer) HandleMessage(topic string, data []byte) error {
 		return err
 	}
 
+	key := tx.IdempotencyKey()
+	processed, err := emailer.Processed.Contains(key)
+	if err != nil {
+		emailer.logger.WithError(err).Error("Error reading processed messages")
+		return err
+	}
+	if processed {
+		emailer.logger.WithField("key", key).Debug("Skipping already processed transaction")
+		return nil
+	}
+
 	email, err := emailer.KV.GetString(tx.To)
 	if err != nil {
 		emailer.logger.WithError(err).Error("Error reading keyvalue storage")
@@ -62,5 +75,5 @@ func (emailer *Emailer) HandleMessage(topic string, data []byte) error {
 		return err
 	}
 
-	return nil
+	return emailer.Processed.Add(key)
 }
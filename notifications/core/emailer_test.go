@@ -12,6 +12,7 @@ import (
 	"github.com/kowala-tech/kcoin/notifications/notifier"
 	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
 	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/set"
 )
 
 func setup_emailer(t *testing.T) (*Emailer, *notifier.NotifierMock, *pubsub.SubscriberMock, *keyvalue.KeyValueMock) {
@@ -36,6 +37,7 @@ func setup_emailer(t *testing.T) (*Emailer, *notifier.NotifierMock, *pubsub.Subs
 		notif,
 		subs,
 		kv,
+		set.NewMemorySet(),
 	)
 
 	valid, messages := gr.Assert()
@@ -107,3 +109,33 @@ func TestEmailer_DoesNotSendEmailsToNonRegisteredWallets(t *testing.T) {
 	require.Equal(t, kv.GetStringCalls()[0].Key, address)
 	require.Len(t, notif.SendCalls(), 0)
 }
+
+func TestEmailer_DoesNotSendEmailsTwiceForTheSameTransaction(t *testing.T) {
+	emailer, notif, subs, kv := setup_emailer(t)
+
+	kv.GetStringFunc = func(key string) (string, error) {
+		return "to@test.com", nil
+	}
+
+	var handler pubsub.MessageHandler
+	subs.AddHandlerFunc = func(in1 pubsub.MessageHandler) {
+		handler = in1
+	}
+
+	emailer.Register()
+	require.NotNil(t, handler)
+
+	tx := &protocolbuffer.Transaction{
+		Amount:    42,
+		To:        "0xabcd",
+		BlockHash: "0x1234",
+		Index:     3,
+	}
+	data, err := proto.Marshal(tx)
+	require.NoError(t, err)
+
+	require.NoError(t, handler.HandleMessage("transactions", data))
+	require.NoError(t, handler.HandleMessage("transactions", data))
+
+	require.Len(t, notif.SendCalls(), 1)
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/rules"
+	"github.com/kowala-tech/kcoin/notifications/set"
+	"github.com/sirupsen/logrus"
+)
+
+type RulesDispatcher struct {
+	Rules      rules.Repository  `inj:""`
+	Subscriber pubsub.Subscriber `inj:""`
+	Processed  set.Set           `inj:""`
+
+	channels map[protocolbuffer.Channel]rules.Channel
+	logger   *logrus.Entry
+}
+
+func NewRulesDispatcher(logger *logrus.Entry, channels map[protocolbuffer.Channel]rules.Channel) *RulesDispatcher {
+	return &RulesDispatcher{
+		channels: channels,
+		logger:   logger.WithField("app", "core/rules_dispatcher"),
+	}
+}
+
+func (dispatcher *RulesDispatcher) Register() {
+	dispatcher.logger.Debug("Registering handler...")
+	dispatcher.Subscriber.AddHandler(dispatcher)
+}
+
+func (dispatcher *RulesDispatcher) Stop() error {
+	dispatcher.logger.Debug("Stopping...")
+	dispatcher.Subscriber.Stop()
+	return nil
+}
+
+func (dispatcher *RulesDispatcher) HandleMessage(topic string, data []byte) error {
+	var tx protocolbuffer.Transaction
+	err := proto.Unmarshal(data, &tx)
+	if err != nil {
+		dispatcher.logger.WithError(err).Error("Error unmarshalling message")
+		return err
+	}
+
+	// every rule is dispatched even if another one fails: the rules already
+	// delivered are skipped when the message is redelivered
+	var errs []string
+	for _, wallet := range rules.Wallets(&tx) {
+		walletRules, err := dispatcher.Rules.List(wallet)
+		if err != nil {
+			dispatcher.logger.WithError(err).Error("Error reading rules")
+			errs = append(errs, err.Error())
+			continue
+		}
+		for _, rule := range walletRules {
+			if err := dispatcher.dispatch(rule, &tx); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to dispatch %d notification(s): %s", len(errs), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// dispatch delivers the notification of a single rule. Deliveries are tracked
+// per rule, so a failing destination does not notify the others twice when the
+// message is redelivered. Permanent delivery errors are not retried.
+func (dispatcher *RulesDispatcher) dispatch(rule *protocolbuffer.Rule, tx *protocolbuffer.Transaction) error {
+	logger := dispatcher.logger.WithField("rule", rule.GetId()).WithField("txHash", tx.GetHash())
+
+	key := tx.IdempotencyKey() + ":" + rule.GetId()
+	processed, err := dispatcher.Processed.Contains(key)
+	if err != nil {
+		logger.WithError(err).Error("Error reading processed messages")
+		return err
+	}
+	if processed {
+		logger.Debug("Skipping already delivered notification")
+		return nil
+	}
+
+	notification, ok := rules.Match(rule, tx)
+	if !ok {
+		return nil
+	}
+
+	channel, ok := dispatcher.channels[rule.GetChannel()]
+	if !ok {
+		logger.WithField("channel", rule.GetChannel()).Warn("Channel not available")
+		return nil
+	}
+
+	message, err := rules.Render(notification)
+	if err != nil {
+		logger.WithError(err).Error("Error rendering the rule template")
+		return nil
+	}
+
+	if err := channel.Deliver(notification, message); err != nil {
+		if !rules.IsPermanent(err) {
+			// the subscriber redelivers the transaction later on
+			logger.WithError(err).Warn("Error delivering notification, retrying")
+			return err
+		}
+		logger.WithError(err).Error("Error delivering notification, dropping it")
+	}
+
+	return dispatcher.Processed.Add(key)
+}
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"github.com/yourheropaul/inj"
+
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/rules"
+	"github.com/kowala-tech/kcoin/notifications/set"
+)
+
+const dispatcherWallet = "0x99429f64cf4d5837620dcc293c1a537d58729b68"
+
+func setup_rules_dispatcher(t *testing.T) (*RulesDispatcher, rules.Repository, chan rules.Payload, func()) {
+	received := make(chan rules.Payload, 10)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, rules.Sign("secret", body), r.Header.Get(rules.SignatureHeader))
+
+		var payload rules.Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		received <- payload
+	}))
+
+	repository := rules.NewMemoryRepository()
+	dispatcher := NewRulesDispatcher(logger, map[protocolbuffer.Channel]rules.Channel{
+		protocolbuffer.Channel_WEBHOOK: rules.NewWebhookChannel(logger, sink.Client()),
+	})
+	subs := &pubsub.SubscriberMock{
+		AddHandlerFunc: func(in1 pubsub.MessageHandler) {
+		},
+		StartFunc: func() error {
+			return nil
+		},
+		StopFunc: func() {
+		},
+	}
+
+	gr := inj.NewGraph()
+	gr.Provide(
+		dispatcher,
+		repository,
+		subs,
+		set.NewMemorySet(),
+	)
+
+	valid, messages := gr.Assert()
+	require.True(t, valid, messages)
+
+	require.NoError(t, repository.Add(&protocolbuffer.Rule{
+		Id:          "incoming",
+		Wallet:      dispatcherWallet,
+		Direction:   protocolbuffer.Direction_INCOMING,
+		MinAmount:   10,
+		Channel:     protocolbuffer.Channel_WEBHOOK,
+		Destination: sink.URL,
+		Secret:      "secret",
+		Template:    "Received {{.Amount}}",
+	}))
+
+	return dispatcher, repository, received, sink.Close
+}
+
+func dispatchTransaction(t *testing.T, dispatcher *RulesDispatcher, tx *protocolbuffer.Transaction) {
+	data, err := proto.Marshal(tx)
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.HandleMessage("transactions", data))
+}
+
+func TestRulesDispatcher_DeliversMatchingRules(t *testing.T) {
+	dispatcher, _, received, closeSink := setup_rules_dispatcher(t)
+	defer closeSink()
+
+	dispatchTransaction(t, dispatcher, &protocolbuffer.Transaction{
+		From:      "0x007ccffb7916f37f7aeef05e8096ecfbe55afc2f",
+		To:        "0x99429F64cf4d5837620dcc293c1a537d58729b68",
+		Amount:    42,
+		Hash:      "0xabcd",
+		BlockHash: "0x1234",
+	})
+
+	select {
+	case payload := <-received:
+		require.Equal(t, "incoming", payload.Rule)
+		require.Equal(t, "Received 42", payload.Message)
+		require.Equal(t, "0xabcd", payload.Transaction.Hash)
+	case <-time.After(time.Second):
+		t.Fatal("Timeout (1s). Webhook not received.")
+	}
+}
+
+func TestRulesDispatcher_SkipsNonMatchingRules(t *testing.T) {
+	dispatcher, _, received, closeSink := setup_rules_dispatcher(t)
+	defer closeSink()
+
+	dispatchTransaction(t, dispatcher, &protocolbuffer.Transaction{
+		From:   dispatcherWallet,
+		To:     "0x007ccffb7916f37f7aeef05e8096ecfbe55afc2f",
+		Amount: 42,
+	})
+	dispatchTransaction(t, dispatcher, &protocolbuffer.Transaction{
+		From:   "0x007ccffb7916f37f7aeef05e8096ecfbe55afc2f",
+		To:     dispatcherWallet,
+		Amount: 9,
+	})
+
+	require.Len(t, received, 0)
+}
+
+func TestRulesDispatcher_DeliversOnce(t *testing.T) {
+	dispatcher, _, received, closeSink := setup_rules_dispatcher(t)
+	defer closeSink()
+
+	tx := &protocolbuffer.Transaction{
+		From:      "0x007ccffb7916f37f7aeef05e8096ecfbe55afc2f",
+		To:        dispatcherWallet,
+		Amount:    42,
+		BlockHash: "0x1234",
+		Index:     1,
+	}
+	dispatchTransaction(t, dispatcher, tx)
+	dispatchTransaction(t, dispatcher, tx)
+
+	require.Len(t, received, 1)
+}
+
+type channelFunc func(notification *rules.Notification, message string) error
+
+func (f channelFunc) Deliver(notification *rules.Notification, message string) error {
+	return f(notification, message)
+}
+
+func TestRulesDispatcher_RetriesTemporaryErrorsOnly(t *testing.T) {
+	var (
+		deliveries int
+		deliverErr error
+	)
+	repository := rules.NewMemoryRepository()
+	dispatcher := NewRulesDispatcher(logger, map[protocolbuffer.Channel]rules.Channel{
+		protocolbuffer.Channel_WEBHOOK: channelFunc(func(*rules.Notification, string) error {
+			deliveries++
+			return deliverErr
+		}),
+	})
+
+	gr := inj.NewGraph()
+	gr.Provide(
+		dispatcher,
+		repository,
+		&pubsub.SubscriberMock{},
+		set.NewMemorySet(),
+	)
+	valid, messages := gr.Assert()
+	require.True(t, valid, messages)
+
+	require.NoError(t, repository.Add(&protocolbuffer.Rule{
+		Id:          "incoming",
+		Wallet:      dispatcherWallet,
+		Channel:     protocolbuffer.Channel_WEBHOOK,
+		Destination: "https://example.com/hook",
+	}))
+	data, err := proto.Marshal(&protocolbuffer.Transaction{To: dispatcherWallet, Amount: 42, BlockHash: "0x1234"})
+	require.NoError(t, err)
+
+	// temporary errors are returned for the message to be redelivered
+	deliverErr = errors.New("webhook responded with status 503")
+	require.Error(t, dispatcher.HandleMessage("transactions", data))
+
+	// permanent errors drop the notification
+	deliverErr = rules.Permanent(errors.New("webhook responded with status 400"))
+	require.NoError(t, dispatcher.HandleMessage("transactions", data))
+	require.NoError(t, dispatcher.HandleMessage("transactions", data))
+	require.Equal(t, 2, deliveries)
+}
+
+func TestRulesDispatcher_DispatchesEveryRule(t *testing.T) {
+	deliveries := make(map[string]int)
+	repository := rules.NewMemoryRepository()
+	dispatcher := NewRulesDispatcher(logger, map[protocolbuffer.Channel]rules.Channel{
+		protocolbuffer.Channel_WEBHOOK: channelFunc(func(notification *rules.Notification, _ string) error {
+			deliveries[notification.Rule.GetId()]++
+			if notification.Rule.GetId() == "broken" {
+				return errors.New("webhook responded with status 503")
+			}
+			return nil
+		}),
+	})
+
+	gr := inj.NewGraph()
+	gr.Provide(
+		dispatcher,
+		repository,
+		&pubsub.SubscriberMock{},
+		set.NewMemorySet(),
+	)
+	valid, messages := gr.Assert()
+	require.True(t, valid, messages)
+
+	for _, id := range []string{"broken", "working"} {
+		require.NoError(t, repository.Add(&protocolbuffer.Rule{
+			Id:          id,
+			Wallet:      dispatcherWallet,
+			Channel:     protocolbuffer.Channel_WEBHOOK,
+			Destination: "https://example.com/" + id,
+		}))
+	}
+	data, err := proto.Marshal(&protocolbuffer.Transaction{To: dispatcherWallet, Amount: 42, BlockHash: "0x1234"})
+	require.NoError(t, err)
+
+	// a failing rule doesn't prevent the delivery of the others, which aren't
+	// delivered again when the message is redelivered
+	require.Error(t, dispatcher.HandleMessage("transactions", data))
+	require.Error(t, dispatcher.HandleMessage("transactions", data))
+	require.Equal(t, 2, deliveries["broken"])
+	require.Equal(t, 1, deliveries["working"])
+}
@@ -1,16 +1,18 @@
 package core
 
 import (
+	"github.com/golang/protobuf/proto"
 	"github.com/kowala-tech/kcoin/notifications/persistence"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
 	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/set"
 	"github.com/sirupsen/logrus"
-	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
-	"github.com/golang/protobuf/proto"
-	)
+)
 
 type TransactionsPersistanceWorker struct {
 	Persistence persistence.TransactionRepository `inj:""`
 	Subscriber  pubsub.Subscriber                 `inj:""`
+	Processed   set.Set                           `inj:""`
 
 	logger *logrus.Entry
 }
@@ -26,12 +28,7 @@ func (tp *TransactionsPersistanceWorker) Start() error {
 	tp.logger.Debug("Getting blocks from queue...")
 
 	tp.Subscriber.AddHandler(tp)
-	err := tp.Subscriber.Start()
-	if err != nil {
-		return nil
-	}
-
-	return nil
+	return tp.Subscriber.Start()
 }
 
 func (tp *TransactionsPersistanceWorker) Stop() {
@@ -39,7 +36,6 @@ func (tp *TransactionsPersistanceWorker) Stop() {
 	tp.Subscriber.Stop()
 }
 
-
 func (tp *TransactionsPersistanceWorker) HandleMessage(topic string, data []byte) error {
 	var tx = new(protocolbuffer.Transaction)
 
@@ -48,8 +44,22 @@ func (tp *TransactionsPersistanceWorker) HandleMessage(topic string, data []byte
 		return err
 	}
 
-	tp.logger.Debug("Saving transaction received: %s", tx.Hash)
-	tp.Persistence.Save(tx)
+	key := tx.IdempotencyKey()
+	processed, err := tp.Processed.Contains(key)
+	if err != nil {
+		tp.logger.WithError(err).Error("Error reading processed messages")
+		return err
+	}
+	if processed {
+		tp.logger.WithField("key", key).Debug("Skipping already processed transaction")
+		return nil
+	}
+
+	tp.logger.Debugf("Saving transaction received: %s", tx.Hash)
+	if err := tp.Persistence.Save(tx); err != nil {
+		tp.logger.WithError(err).Error("Error saving transaction")
+		return err
+	}
 
-	return nil
+	return tp.Processed.Add(key)
 }
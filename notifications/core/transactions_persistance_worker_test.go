@@ -0,0 +1,80 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourheropaul/inj"
+
+	"github.com/kowala-tech/kcoin/notifications/persistence/mocks"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/kowala-tech/kcoin/notifications/pubsub"
+	"github.com/kowala-tech/kcoin/notifications/set"
+)
+
+func setup_transactions_persistance_worker(t *testing.T) (*TransactionsPersistanceWorker, *mocks.TransactionRepository) {
+	worker := NewTransactionsPersistanceWorker(logger)
+	repository := &mocks.TransactionRepository{}
+	subs := &pubsub.SubscriberMock{
+		AddHandlerFunc: func(in1 pubsub.MessageHandler) {
+		},
+		StartFunc: func() error {
+			return nil
+		},
+		StopFunc: func() {
+		},
+	}
+
+	gr := inj.NewGraph()
+	gr.Provide(
+		worker,
+		repository,
+		subs,
+		set.NewMemorySet(),
+	)
+
+	valid, messages := gr.Assert()
+	require.True(t, valid, messages)
+
+	return worker, repository
+}
+
+func TestTransactionsPersistanceWorker_SavesTransactionsOnce(t *testing.T) {
+	worker, repository := setup_transactions_persistance_worker(t)
+	repository.On("Save", mock.Anything).Return(nil)
+
+	tx := &protocolbuffer.Transaction{
+		Hash:      "0xabcd",
+		BlockHash: "0x1234",
+		Index:     0,
+	}
+	data, err := proto.Marshal(tx)
+	require.NoError(t, err)
+
+	require.NoError(t, worker.HandleMessage("transactions", data))
+	require.NoError(t, worker.HandleMessage("transactions", data))
+
+	repository.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestTransactionsPersistanceWorker_RetriesFailedTransactions(t *testing.T) {
+	worker, repository := setup_transactions_persistance_worker(t)
+	repository.On("Save", mock.Anything).Return(errors.New("redis unavailable")).Once()
+	repository.On("Save", mock.Anything).Return(nil).Once()
+
+	tx := &protocolbuffer.Transaction{
+		Hash:      "0xabcd",
+		BlockHash: "0x1234",
+		Index:     0,
+	}
+	data, err := proto.Marshal(tx)
+	require.NoError(t, err)
+
+	require.Error(t, worker.HandleMessage("transactions", data))
+	require.NoError(t, worker.HandleMessage("transactions", data))
+
+	repository.AssertNumberOfCalls(t, "Save", 2)
+}
@@ -26,14 +26,14 @@ func NewTransactionsPublisher(logger *logrus.Entry) *TransactionsPublisher {
 
 func (tp *TransactionsPublisher) Start() error {
 	tp.logger.Debug("Starting...")
-	tp.logger.Debug("Fetching last processed block...")
+	tp.logger.Debug("Fetching the ingestion checkpoint...")
 	blockNum, err := tp.ValueStorage.GetInt64()
 	if err != nil {
 		tp.logger.WithError(err).Error("Error getting last block number from the value storage")
 		return err
 	}
 	if blockNum > 0 {
-		tp.logger.WithField("blockNum", blockNum).Info("Seeking blockchain to last processed block.")
+		tp.logger.WithField("blockNum", blockNum).Info("Resuming ingestion from the checkpointed block.")
 		tp.Blockchain.Seek(big.NewInt(blockNum))
 	}
 	err = tp.Blockchain.OnBlock(tp)
@@ -51,18 +51,29 @@ func (tp *TransactionsPublisher) Stop() {
 	tp.Blockchain.Stop()
 }
 
-func (tp *TransactionsPublisher) HandleBlock(block *blockchain.Block) {
-	tp.logger.
-		WithField("blockNum", block.Number).
-		WithField("transactionsNum", len(block.Transactions)).
-		Info("Block received")
-	tp.ValueStorage.PutInt64(block.Number.Int64())
+// HandleBlock publishes every transaction in the block and only then stores the
+// number of the next block to process, so a crash mid-block replays the whole
+// block on restart instead of losing part of it.
+func (tp *TransactionsPublisher) HandleBlock(block *blockchain.Block) error {
+	logger := tp.logger.WithField("blockNum", block.Number)
+	logger.WithField("transactionsNum", len(block.Transactions)).Info("Block received")
+
 	for _, tx := range block.Transactions {
 		data, err := proto.Marshal(tx)
 		if err != nil {
-			// TODO: Handle this
-			continue
+			logger.WithError(err).WithField("txHash", tx.Hash).Error("Error marshalling transaction")
+			return err
+		}
+		if err := tp.Publisher.Publish("transactions", data); err != nil {
+			logger.WithError(err).WithField("txHash", tx.Hash).Error("Error publishing transaction")
+			return err
 		}
-		tp.Publisher.Publish("transactions", []byte(data))
 	}
+
+	next := new(big.Int).Add(block.Number, big.NewInt(1))
+	if err := tp.ValueStorage.PutInt64(next.Int64()); err != nil {
+		logger.WithError(err).Error("Error storing the next block number")
+		return err
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 	"time"
@@ -147,6 +148,59 @@ func TestTransactionsPublisher_UpdatesValueStore(t *testing.T) {
 
 	calls := mockedValueStorage.PutInt64Calls()
 	require.Equal(t, len(calls), 1)
+	require.Equal(t, calls[0].Value, int64(2))
+}
+
+func TestTransactionsPublisher_DoesNotUpdateValueStoreIfPublishFails(t *testing.T) {
+	published := make(chan bool)
+	tp, _, mockedPublisher, mockedValueStorage, txChn := setup_transactions_publisher(t)
+
+	mockedPublisher.PublishFunc = func(topic string, data []byte) error {
+		published <- true
+		return errors.New("nsq unavailable")
+	}
+
+	go tp.Start()
+	defer tp.Stop()
+
+	transaction := &protocolbuffer.Transaction{
+		To:     "abc",
+		Amount: 42,
+	}
+
+	select {
+	case txChn <- transaction:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout (1s). Transaction not processed.")
+	}
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout (1s). Transaction not published.")
+	}
+	time.Sleep(10 * time.Millisecond) // Give some time to run through the storing code
+
+	require.Len(t, mockedValueStorage.PutInt64Calls(), 0)
+}
+
+func TestTransactionsPublisher_ReturnsErrorIfPublishFails(t *testing.T) {
+	tp, _, mockedPublisher, mockedValueStorage, _ := setup_transactions_publisher(t)
+
+	mockedPublisher.PublishFunc = func(topic string, data []byte) error {
+		return errors.New("nsq unavailable")
+	}
+
+	err := tp.HandleBlock(&blockchain.Block{
+		Number: big.NewInt(7),
+		Transactions: []*protocolbuffer.Transaction{
+			{To: "abc", Amount: 42},
+			{To: "def", Amount: 43},
+		},
+	})
+	require.Error(t, err)
+	require.Len(t, mockedPublisher.PublishCalls(), 1)
+	require.Len(t, mockedValueStorage.PutInt64Calls(), 0)
 }
 
 func TestTransactionsPublisher_PublishesAllTransactions(t *testing.T) {
@@ -41,6 +41,13 @@ func NewGomailNewTransfer(logger *logrus.Entry, dialer Dialer) (Notifier, error)
 	return NewGomailTemplate(logger, dialer, subject, tpl), nil
 }
 
+// NewGomailMessage returns a Notifier whose e-mails only contain the message
+// passed in the EmailMessageKey variable.
+func NewGomailMessage(logger *logrus.Entry, dialer Dialer, subject string) Notifier {
+	tpl := template.Must(template.New("message").Parse("<p>{{." + EmailMessageKey + "}}</p>"))
+	return NewGomailTemplate(logger, dialer, subject, tpl)
+}
+
 func NewGomailTemplate(logger *logrus.Entry, dialer Dialer, subject string, htmlTemplate *template.Template) Notifier {
 	return &gomailNotifier{
 		dialer:       dialer,
@@ -1,8 +1,9 @@
 package notifier
 
 const (
-	EmailToKey   = "TO"
-	EmailFromKey = "FROM"
+	EmailToKey      = "TO"
+	EmailFromKey    = "FROM"
+	EmailMessageKey = "MESSAGE"
 )
 
 //go:generate moq -out notifier_mock.go . Notifier
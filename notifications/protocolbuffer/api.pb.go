@@ -23,6 +23,62 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type Direction int32
+
+const (
+	Direction_ANY      Direction = 0
+	Direction_INCOMING Direction = 1
+	Direction_OUTGOING Direction = 2
+)
+
+var Direction_name = map[int32]string{
+	0: "ANY",
+	1: "INCOMING",
+	2: "OUTGOING",
+}
+
+var Direction_value = map[string]int32{
+	"ANY":      0,
+	"INCOMING": 1,
+	"OUTGOING": 2,
+}
+
+func (x Direction) String() string {
+	return proto.EnumName(Direction_name, int32(x))
+}
+
+func (Direction) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+type Channel int32
+
+const (
+	Channel_EMAIL   Channel = 0
+	Channel_WEBHOOK Channel = 1
+	Channel_NSQ     Channel = 2
+)
+
+var Channel_name = map[int32]string{
+	0: "EMAIL",
+	1: "WEBHOOK",
+	2: "NSQ",
+}
+
+var Channel_value = map[string]int32{
+	"EMAIL":   0,
+	"WEBHOOK": 1,
+	"NSQ":     2,
+}
+
+func (x Channel) String() string {
+	return proto.EnumName(Channel_name, int32(x))
+}
+
+func (Channel) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
+}
+
 type RegisterRequest struct {
 	Wallet               string   `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
 	Email                string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
@@ -252,6 +308,9 @@ type Transaction struct {
 	BlockHeight          int64    `protobuf:"varint,6,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
 	GasUsed              int64    `protobuf:"varint,7,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
 	GasPrice             int64    `protobuf:"varint,8,opt,name=gas_price,json=gasPrice,proto3" json:"gas_price,omitempty"`
+	BlockHash            string   `protobuf:"bytes,9,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Index                int64    `protobuf:"varint,10,opt,name=index,proto3" json:"index,omitempty"`
+	Events               []*Event `protobuf:"bytes,11,rep,name=events,proto3" json:"events,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -337,7 +396,478 @@ func (m *Transaction) GetGasPrice() int64 {
 	return 0
 }
 
+func (m *Transaction) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *Transaction) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Transaction) GetEvents() []*Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type Event struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Topics               []string `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (dst *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(dst, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Event) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *Event) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type Rule struct {
+	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Wallet               string    `protobuf:"bytes,2,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	Direction            Direction `protobuf:"varint,3,opt,name=direction,proto3,enum=protocolbuffer.Direction" json:"direction,omitempty"`
+	MinAmount            int64     `protobuf:"varint,4,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`
+	Token                string    `protobuf:"bytes,5,opt,name=token,proto3" json:"token,omitempty"`
+	Contract             string    `protobuf:"bytes,6,opt,name=contract,proto3" json:"contract,omitempty"`
+	Event                string    `protobuf:"bytes,7,opt,name=event,proto3" json:"event,omitempty"`
+	Channel              Channel   `protobuf:"varint,8,opt,name=channel,proto3,enum=protocolbuffer.Channel" json:"channel,omitempty"`
+	Destination          string    `protobuf:"bytes,9,opt,name=destination,proto3" json:"destination,omitempty"`
+	Secret               string    `protobuf:"bytes,10,opt,name=secret,proto3" json:"secret,omitempty"`
+	Template             string    `protobuf:"bytes,11,opt,name=template,proto3" json:"template,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Rule) Reset()         { *m = Rule{} }
+func (m *Rule) String() string { return proto.CompactTextString(m) }
+func (*Rule) ProtoMessage()    {}
+func (*Rule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+func (m *Rule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rule.Unmarshal(m, b)
+}
+func (m *Rule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rule.Marshal(b, m, deterministic)
+}
+func (dst *Rule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rule.Merge(dst, src)
+}
+func (m *Rule) XXX_Size() int {
+	return xxx_messageInfo_Rule.Size(m)
+}
+func (m *Rule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rule proto.InternalMessageInfo
+
+func (m *Rule) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Rule) GetWallet() string {
+	if m != nil {
+		return m.Wallet
+	}
+	return ""
+}
+
+func (m *Rule) GetDirection() Direction {
+	if m != nil {
+		return m.Direction
+	}
+	return Direction_ANY
+}
+
+func (m *Rule) GetMinAmount() int64 {
+	if m != nil {
+		return m.MinAmount
+	}
+	return 0
+}
+
+func (m *Rule) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *Rule) GetContract() string {
+	if m != nil {
+		return m.Contract
+	}
+	return ""
+}
+
+func (m *Rule) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *Rule) GetChannel() Channel {
+	if m != nil {
+		return m.Channel
+	}
+	return Channel_EMAIL
+}
+
+func (m *Rule) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *Rule) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Rule) GetTemplate() string {
+	if m != nil {
+		return m.Template
+	}
+	return ""
+}
+
+type AddRuleRequest struct {
+	Rule                 *Rule    `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Timestamp            int64    `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddRuleRequest) Reset()         { *m = AddRuleRequest{} }
+func (m *AddRuleRequest) String() string { return proto.CompactTextString(m) }
+func (*AddRuleRequest) ProtoMessage()    {}
+func (*AddRuleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+func (m *AddRuleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddRuleRequest.Unmarshal(m, b)
+}
+func (m *AddRuleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddRuleRequest.Marshal(b, m, deterministic)
+}
+func (dst *AddRuleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddRuleRequest.Merge(dst, src)
+}
+func (m *AddRuleRequest) XXX_Size() int {
+	return xxx_messageInfo_AddRuleRequest.Size(m)
+}
+func (m *AddRuleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddRuleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddRuleRequest proto.InternalMessageInfo
+
+func (m *AddRuleRequest) GetRule() *Rule {
+	if m != nil {
+		return m.Rule
+	}
+	return nil
+}
+
+func (m *AddRuleRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *AddRuleRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type AddRuleReply struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddRuleReply) Reset()         { *m = AddRuleReply{} }
+func (m *AddRuleReply) String() string { return proto.CompactTextString(m) }
+func (*AddRuleReply) ProtoMessage()    {}
+func (*AddRuleReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+func (m *AddRuleReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddRuleReply.Unmarshal(m, b)
+}
+func (m *AddRuleReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddRuleReply.Marshal(b, m, deterministic)
+}
+func (dst *AddRuleReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddRuleReply.Merge(dst, src)
+}
+func (m *AddRuleReply) XXX_Size() int {
+	return xxx_messageInfo_AddRuleReply.Size(m)
+}
+func (m *AddRuleReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddRuleReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddRuleReply proto.InternalMessageInfo
+
+func (m *AddRuleReply) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type RemoveRuleRequest struct {
+	Wallet               string   `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Timestamp            int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRuleRequest) Reset()         { *m = RemoveRuleRequest{} }
+func (m *RemoveRuleRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveRuleRequest) ProtoMessage()    {}
+func (*RemoveRuleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+func (m *RemoveRuleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRuleRequest.Unmarshal(m, b)
+}
+func (m *RemoveRuleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRuleRequest.Marshal(b, m, deterministic)
+}
+func (dst *RemoveRuleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRuleRequest.Merge(dst, src)
+}
+func (m *RemoveRuleRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveRuleRequest.Size(m)
+}
+func (m *RemoveRuleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRuleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRuleRequest proto.InternalMessageInfo
+
+func (m *RemoveRuleRequest) GetWallet() string {
+	if m != nil {
+		return m.Wallet
+	}
+	return ""
+}
+
+func (m *RemoveRuleRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RemoveRuleRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *RemoveRuleRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type RemoveRuleReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRuleReply) Reset()         { *m = RemoveRuleReply{} }
+func (m *RemoveRuleReply) String() string { return proto.CompactTextString(m) }
+func (*RemoveRuleReply) ProtoMessage()    {}
+func (*RemoveRuleReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+func (m *RemoveRuleReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRuleReply.Unmarshal(m, b)
+}
+func (m *RemoveRuleReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRuleReply.Marshal(b, m, deterministic)
+}
+func (dst *RemoveRuleReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRuleReply.Merge(dst, src)
+}
+func (m *RemoveRuleReply) XXX_Size() int {
+	return xxx_messageInfo_RemoveRuleReply.Size(m)
+}
+func (m *RemoveRuleReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRuleReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRuleReply proto.InternalMessageInfo
+
+type ListRulesRequest struct {
+	Wallet               string   `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	Timestamp            int64    `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRulesRequest) Reset()         { *m = ListRulesRequest{} }
+func (m *ListRulesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRulesRequest) ProtoMessage()    {}
+func (*ListRulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+func (m *ListRulesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRulesRequest.Unmarshal(m, b)
+}
+func (m *ListRulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRulesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListRulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRulesRequest.Merge(dst, src)
+}
+func (m *ListRulesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRulesRequest.Size(m)
+}
+func (m *ListRulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRulesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRulesRequest proto.InternalMessageInfo
+
+func (m *ListRulesRequest) GetWallet() string {
+	if m != nil {
+		return m.Wallet
+	}
+	return ""
+}
+
+func (m *ListRulesRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *ListRulesRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type ListRulesReply struct {
+	Rules                []*Rule  `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRulesReply) Reset()         { *m = ListRulesReply{} }
+func (m *ListRulesReply) String() string { return proto.CompactTextString(m) }
+func (*ListRulesReply) ProtoMessage()    {}
+func (*ListRulesReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+func (m *ListRulesReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRulesReply.Unmarshal(m, b)
+}
+func (m *ListRulesReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRulesReply.Marshal(b, m, deterministic)
+}
+func (dst *ListRulesReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRulesReply.Merge(dst, src)
+}
+func (m *ListRulesReply) XXX_Size() int {
+	return xxx_messageInfo_ListRulesReply.Size(m)
+}
+func (m *ListRulesReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRulesReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRulesReply proto.InternalMessageInfo
+
+func (m *ListRulesReply) GetRules() []*Rule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterEnum("protocolbuffer.Direction", Direction_name, Direction_value)
+	proto.RegisterEnum("protocolbuffer.Channel", Channel_name, Channel_value)
 	proto.RegisterType((*RegisterRequest)(nil), "protocolbuffer.RegisterRequest")
 	proto.RegisterType((*UnregisterRequest)(nil), "protocolbuffer.UnregisterRequest")
 	proto.RegisterType((*RegisterReply)(nil), "protocolbuffer.RegisterReply")
@@ -345,6 +875,14 @@ func init() {
 	proto.RegisterType((*GetTransactionsRequest)(nil), "protocolbuffer.GetTransactionsRequest")
 	proto.RegisterType((*GetTransactionsReply)(nil), "protocolbuffer.GetTransactionsReply")
 	proto.RegisterType((*Transaction)(nil), "protocolbuffer.Transaction")
+	proto.RegisterType((*Event)(nil), "protocolbuffer.Event")
+	proto.RegisterType((*Rule)(nil), "protocolbuffer.Rule")
+	proto.RegisterType((*AddRuleRequest)(nil), "protocolbuffer.AddRuleRequest")
+	proto.RegisterType((*AddRuleReply)(nil), "protocolbuffer.AddRuleReply")
+	proto.RegisterType((*RemoveRuleRequest)(nil), "protocolbuffer.RemoveRuleRequest")
+	proto.RegisterType((*RemoveRuleReply)(nil), "protocolbuffer.RemoveRuleReply")
+	proto.RegisterType((*ListRulesRequest)(nil), "protocolbuffer.ListRulesRequest")
+	proto.RegisterType((*ListRulesReply)(nil), "protocolbuffer.ListRulesReply")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -516,34 +1054,194 @@ var _TransactionService_serviceDesc = grpc.ServiceDesc{
 	Metadata: "api.proto",
 }
 
+// NotificationRulesClient is the client API for NotificationRules service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NotificationRulesClient interface {
+	AddRule(ctx context.Context, in *AddRuleRequest, opts ...grpc.CallOption) (*AddRuleReply, error)
+	RemoveRule(ctx context.Context, in *RemoveRuleRequest, opts ...grpc.CallOption) (*RemoveRuleReply, error)
+	ListRules(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (*ListRulesReply, error)
+}
+
+type notificationRulesClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNotificationRulesClient(cc *grpc.ClientConn) NotificationRulesClient {
+	return &notificationRulesClient{cc}
+}
+
+func (c *notificationRulesClient) AddRule(ctx context.Context, in *AddRuleRequest, opts ...grpc.CallOption) (*AddRuleReply, error) {
+	out := new(AddRuleReply)
+	err := c.cc.Invoke(ctx, "/protocolbuffer.NotificationRules/AddRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationRulesClient) RemoveRule(ctx context.Context, in *RemoveRuleRequest, opts ...grpc.CallOption) (*RemoveRuleReply, error) {
+	out := new(RemoveRuleReply)
+	err := c.cc.Invoke(ctx, "/protocolbuffer.NotificationRules/RemoveRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationRulesClient) ListRules(ctx context.Context, in *ListRulesRequest, opts ...grpc.CallOption) (*ListRulesReply, error) {
+	out := new(ListRulesReply)
+	err := c.cc.Invoke(ctx, "/protocolbuffer.NotificationRules/ListRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotificationRulesServer is the server API for NotificationRules service.
+type NotificationRulesServer interface {
+	AddRule(context.Context, *AddRuleRequest) (*AddRuleReply, error)
+	RemoveRule(context.Context, *RemoveRuleRequest) (*RemoveRuleReply, error)
+	ListRules(context.Context, *ListRulesRequest) (*ListRulesReply, error)
+}
+
+func RegisterNotificationRulesServer(s *grpc.Server, srv NotificationRulesServer) {
+	s.RegisterService(&_NotificationRules_serviceDesc, srv)
+}
+
+func _NotificationRules_AddRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationRulesServer).AddRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protocolbuffer.NotificationRules/AddRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationRulesServer).AddRule(ctx, req.(*AddRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationRules_RemoveRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationRulesServer).RemoveRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protocolbuffer.NotificationRules/RemoveRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationRulesServer).RemoveRule(ctx, req.(*RemoveRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationRules_ListRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationRulesServer).ListRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protocolbuffer.NotificationRules/ListRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationRulesServer).ListRules(ctx, req.(*ListRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NotificationRules_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protocolbuffer.NotificationRules",
+	HandlerType: (*NotificationRulesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddRule",
+			Handler:    _NotificationRules_AddRule_Handler,
+		},
+		{
+			MethodName: "RemoveRule",
+			Handler:    _NotificationRules_RemoveRule_Handler,
+		},
+		{
+			MethodName: "ListRules",
+			Handler:    _NotificationRules_ListRules_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
+
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 405 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x5d, 0x8f, 0xd2, 0x40,
-	0x14, 0xb5, 0xb0, 0x0b, 0xf4, 0x82, 0xdb, 0xec, 0xcd, 0x66, 0x33, 0xb2, 0x9a, 0x5d, 0x1a, 0x63,
-	0x48, 0x4c, 0x78, 0xa8, 0x3f, 0x80, 0x27, 0xa3, 0x0f, 0x9a, 0x98, 0x2a, 0xf1, 0x91, 0x0c, 0x65,
-	0x68, 0x27, 0xb6, 0x9d, 0x71, 0x66, 0x2a, 0xe1, 0x5f, 0xf9, 0x6b, 0xfc, 0x3d, 0x66, 0x06, 0x1a,
-	0x4a, 0xf1, 0x63, 0x9f, 0x98, 0x73, 0xee, 0xb9, 0xe7, 0xd2, 0x7b, 0x2e, 0xf8, 0x54, 0xf2, 0x99,
-	0x54, 0xc2, 0x08, 0xbc, 0x72, 0x3f, 0x89, 0xc8, 0x57, 0xd5, 0x66, 0xc3, 0x54, 0x38, 0x87, 0x20,
-	0x66, 0x29, 0xd7, 0x86, 0xa9, 0x98, 0x7d, 0xaf, 0x98, 0x36, 0x78, 0x0b, 0xbd, 0x2d, 0xcd, 0x73,
-	0x66, 0x88, 0xf7, 0xe0, 0x4d, 0xfd, 0xf8, 0x80, 0xf0, 0x06, 0x2e, 0x59, 0x41, 0x79, 0x4e, 0x3a,
-	0x8e, 0xde, 0x83, 0xf0, 0x35, 0x5c, 0x2f, 0x4a, 0xf5, 0x38, 0x8b, 0x30, 0x80, 0xa7, 0xc7, 0x69,
-	0x32, 0xdf, 0x85, 0xd7, 0x10, 0x34, 0xbb, 0x2d, 0x15, 0xc1, 0xed, 0x3b, 0x66, 0xbe, 0x28, 0x5a,
-	0x6a, 0x9a, 0x18, 0x2e, 0x4a, 0x5d, 0xbb, 0x12, 0xe8, 0xd3, 0x24, 0x11, 0x55, 0x59, 0xdb, 0xd6,
-	0x30, 0xfc, 0x0a, 0x37, 0x67, 0x3d, 0x32, 0xdf, 0xe1, 0x1c, 0x46, 0xa6, 0x41, 0x12, 0xef, 0xa1,
-	0x3b, 0x1d, 0x46, 0x77, 0xb3, 0xd3, 0x25, 0xcc, 0x1a, 0x8d, 0xf1, 0x49, 0x43, 0xf8, 0xcb, 0x83,
-	0x61, 0xa3, 0x8a, 0x57, 0xd0, 0x31, 0xe2, 0x30, 0xbd, 0x63, 0x84, 0xfd, 0x50, 0x5a, 0xb8, 0x7f,
-	0x64, 0x97, 0xd2, 0x8d, 0x0f, 0x08, 0x11, 0x2e, 0x36, 0x4a, 0x14, 0xa4, 0xeb, 0x94, 0xee, 0x6d,
-	0xb9, 0x8c, 0xea, 0x8c, 0x5c, 0xec, 0x39, 0xfb, 0xc6, 0xe7, 0xe0, 0x1b, 0x5e, 0x30, 0x6d, 0x68,
-	0x21, 0xc9, 0xa5, 0xb3, 0x38, 0x12, 0x38, 0x81, 0xd1, 0x2a, 0x17, 0xc9, 0xb7, 0x65, 0xc6, 0x78,
-	0x9a, 0x19, 0xd2, 0x73, 0x82, 0xa1, 0xe3, 0xde, 0x3b, 0x0a, 0x9f, 0xc1, 0x20, 0xa5, 0x7a, 0x59,
-	0x69, 0xb6, 0x26, 0x7d, 0x57, 0xee, 0xa7, 0x54, 0x2f, 0x34, 0x5b, 0xe3, 0x1d, 0xf8, 0xb6, 0x24,
-	0x15, 0x4f, 0x18, 0x19, 0xb8, 0x9a, 0xd5, 0x7e, 0xb2, 0x38, 0xfa, 0xe9, 0xc1, 0xe8, 0xad, 0x0d,
-	0xf0, 0x23, 0x95, 0x92, 0x97, 0x29, 0x7e, 0x80, 0x41, 0x1d, 0x0d, 0xde, 0xb7, 0x17, 0xd4, 0x3a,
-	0x91, 0xf1, 0x8b, 0xbf, 0x0b, 0x6c, 0x84, 0x4f, 0x30, 0x06, 0x38, 0xe6, 0x8a, 0x93, 0xb6, 0xfc,
-	0xec, 0x62, 0xc6, 0xf7, 0xff, 0x92, 0x38, 0xcf, 0x68, 0x0b, 0xd8, 0x88, 0xe2, 0x33, 0x53, 0x3f,
-	0x78, 0xc2, 0x90, 0x42, 0xd0, 0x8a, 0x1e, 0x5f, 0xb5, 0xbd, 0xfe, 0x7c, 0x4f, 0xe3, 0x97, 0xff,
-	0xd5, 0xb9, 0xc1, 0xab, 0x9e, 0x93, 0xbd, 0xf9, 0x1d, 0x00, 0x00, 0xff, 0xff, 0x53, 0xf2, 0x8d,
-	0xc8, 0x47, 0x03, 0x00, 0x00,
+	// 889 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x5d, 0x6f, 0xdb, 0x36,
+	0x14, 0x8d, 0x65, 0x3b, 0xb2, 0xae, 0x3c, 0x7f, 0x10, 0x5e, 0xa7, 0xba, 0x69, 0xe2, 0x0a, 0xc3,
+	0x10, 0x64, 0x58, 0xb0, 0x79, 0x0f, 0x7b, 0x19, 0x50, 0x64, 0x5d, 0x90, 0x06, 0x4b, 0xec, 0x4d,
+	0x6d, 0x50, 0xec, 0x29, 0x60, 0x24, 0xda, 0x26, 0xaa, 0xaf, 0x89, 0x74, 0xb2, 0x3e, 0xef, 0x0f,
+	0xed, 0x79, 0x7f, 0x64, 0x7f, 0x67, 0xe0, 0xa5, 0x64, 0xcb, 0x72, 0x93, 0x0c, 0xe8, 0x93, 0x75,
+	0x2f, 0x0f, 0x2f, 0x8f, 0xcf, 0xb9, 0xbc, 0x04, 0x8b, 0xa6, 0xfc, 0x38, 0xcd, 0x12, 0x99, 0x90,
+	0x0e, 0xfe, 0xf8, 0x49, 0x78, 0xb3, 0x9c, 0xcd, 0x58, 0xe6, 0xbe, 0x84, 0xae, 0xc7, 0xe6, 0x5c,
+	0x48, 0x96, 0x79, 0xec, 0x8f, 0x25, 0x13, 0x92, 0x3c, 0x81, 0xdd, 0x3b, 0x1a, 0x86, 0x4c, 0x3a,
+	0xb5, 0x51, 0xed, 0xd0, 0xf2, 0xf2, 0x88, 0x0c, 0xa0, 0xc9, 0x22, 0xca, 0x43, 0xc7, 0xc0, 0xb4,
+	0x0e, 0xdc, 0xaf, 0xa1, 0x7f, 0x15, 0x67, 0xff, 0xaf, 0x84, 0xdb, 0x85, 0xcf, 0xd6, 0xa7, 0xa5,
+	0xe1, 0x07, 0xb7, 0x0f, 0xdd, 0xf2, 0x6e, 0x95, 0x1a, 0xc3, 0x93, 0x33, 0x26, 0xdf, 0x66, 0x34,
+	0x16, 0xd4, 0x97, 0x3c, 0x89, 0x45, 0x51, 0xd5, 0x01, 0x93, 0xfa, 0x7e, 0xb2, 0x8c, 0x8b, 0xb2,
+	0x45, 0xe8, 0xbe, 0x83, 0xc1, 0xd6, 0x9e, 0x34, 0xfc, 0x40, 0x5e, 0x42, 0x5b, 0x96, 0x92, 0x4e,
+	0x6d, 0x54, 0x3f, 0xb4, 0xc7, 0xcf, 0x8e, 0x37, 0x45, 0x38, 0x2e, 0x6d, 0xf4, 0x36, 0x36, 0xb8,
+	0xff, 0x18, 0x60, 0x97, 0x56, 0x49, 0x07, 0x0c, 0x99, 0xe4, 0xa7, 0x1b, 0x32, 0x51, 0x7f, 0x94,
+	0x46, 0xc8, 0x48, 0x89, 0x52, 0xf7, 0xf2, 0x88, 0x10, 0x68, 0xcc, 0xb2, 0x24, 0x72, 0xea, 0x88,
+	0xc4, 0x6f, 0x95, 0x5b, 0x50, 0xb1, 0x70, 0x1a, 0x3a, 0xa7, 0xbe, 0xc9, 0x1e, 0x58, 0x92, 0x47,
+	0x4c, 0x48, 0x1a, 0xa5, 0x4e, 0x13, 0x4b, 0xac, 0x13, 0xe4, 0x05, 0xb4, 0x6f, 0xc2, 0xc4, 0x7f,
+	0x7f, 0xbd, 0x60, 0x7c, 0xbe, 0x90, 0xce, 0x2e, 0x02, 0x6c, 0xcc, 0xbd, 0xc6, 0x14, 0x79, 0x0a,
+	0xad, 0x39, 0x15, 0xd7, 0x4b, 0xc1, 0x02, 0xc7, 0xc4, 0x65, 0x73, 0x4e, 0xc5, 0x95, 0x60, 0x01,
+	0x79, 0x06, 0x96, 0x5a, 0x4a, 0x33, 0xee, 0x33, 0xa7, 0x85, 0x6b, 0x0a, 0xfb, 0xab, 0x8a, 0xc9,
+	0x73, 0x80, 0xbc, 0xb4, 0xa2, 0x64, 0x21, 0x25, 0x4b, 0x17, 0x56, 0xbc, 0x06, 0xd0, 0xe4, 0x71,
+	0xc0, 0xfe, 0x74, 0x00, 0xf7, 0xe9, 0x80, 0x7c, 0x03, 0xbb, 0xec, 0x96, 0xc5, 0x52, 0x38, 0x36,
+	0x0a, 0xf9, 0x79, 0x55, 0xc8, 0x53, 0xb5, 0xea, 0xe5, 0x20, 0xf7, 0x12, 0x9a, 0x98, 0x40, 0xe3,
+	0x82, 0x20, 0x63, 0x42, 0xac, 0x8c, 0xd3, 0xa1, 0xd2, 0x4f, 0x26, 0x29, 0xf7, 0x85, 0x63, 0x8c,
+	0xea, 0xaa, 0x51, 0x74, 0xa4, 0xb4, 0x0a, 0xa8, 0xa4, 0xa8, 0x5f, 0xdb, 0xc3, 0x6f, 0xf7, 0x5f,
+	0x03, 0x1a, 0xde, 0x32, 0x64, 0xca, 0x04, 0x1e, 0x14, 0x26, 0xf0, 0xa0, 0xd4, 0x6d, 0xc6, 0x46,
+	0xc3, 0xfe, 0x00, 0x56, 0xc0, 0x33, 0x86, 0xce, 0x61, 0xa5, 0xce, 0xf8, 0x69, 0x95, 0xf1, 0xcf,
+	0x05, 0xc0, 0x5b, 0x63, 0x95, 0x38, 0x11, 0x8f, 0xaf, 0x73, 0x67, 0x1b, 0xda, 0x96, 0x88, 0xc7,
+	0x27, 0xda, 0xdc, 0x01, 0x34, 0x65, 0xf2, 0x9e, 0xc5, 0x68, 0x98, 0xe5, 0xe9, 0x80, 0x0c, 0xa1,
+	0xe5, 0x27, 0xb1, 0xcc, 0xa8, 0xaf, 0x8d, 0xb2, 0xbc, 0x55, 0xac, 0x76, 0xa0, 0x26, 0x8e, 0x99,
+	0x5f, 0x1d, 0x15, 0x90, 0xef, 0xc0, 0xf4, 0x17, 0x34, 0x8e, 0x59, 0x88, 0xf6, 0x74, 0xc6, 0x5f,
+	0x54, 0xd9, 0xbd, 0xd2, 0xcb, 0x5e, 0x81, 0x23, 0x23, 0xb0, 0x03, 0x26, 0x24, 0x8f, 0x29, 0xfe,
+	0x29, 0xed, 0x5b, 0x39, 0xa5, 0xc4, 0x10, 0xcc, 0xcf, 0x98, 0x44, 0xeb, 0x2c, 0x2f, 0x8f, 0x14,
+	0x3d, 0xc9, 0xa2, 0x34, 0xa4, 0x92, 0x39, 0xb6, 0xa6, 0x57, 0xc4, 0xee, 0x2d, 0x74, 0x4e, 0x82,
+	0x40, 0x69, 0x5b, 0x5c, 0xb5, 0x43, 0x68, 0x64, 0xcb, 0x90, 0xa1, 0xc8, 0xf6, 0x78, 0x50, 0xe5,
+	0x85, 0x50, 0x44, 0x6c, 0x76, 0xb0, 0x51, 0xed, 0xe0, 0x3d, 0xb0, 0x04, 0x9f, 0xc7, 0x54, 0x2e,
+	0x33, 0x96, 0x9b, 0xb9, 0x4e, 0xb8, 0xfb, 0xd0, 0x5e, 0x9d, 0xab, 0xae, 0x6b, 0xc5, 0x58, 0xf7,
+	0x0e, 0xfa, 0x1e, 0x8b, 0x92, 0x5b, 0x56, 0xa6, 0x76, 0xdf, 0x78, 0xd2, 0x9b, 0x8d, 0x55, 0x57,
+	0x6c, 0x10, 0xab, 0x3f, 0x48, 0xac, 0x51, 0x25, 0xd6, 0x87, 0x6e, 0xf9, 0x60, 0x35, 0x96, 0x66,
+	0xd0, 0xbb, 0xe0, 0x42, 0xaa, 0x84, 0x78, 0x8c, 0xca, 0xa7, 0x68, 0xf2, 0x23, 0x74, 0x4a, 0xe7,
+	0x28, 0x55, 0x8e, 0xa0, 0xa9, 0x94, 0x2e, 0xa6, 0xd7, 0xc7, 0xcd, 0xd0, 0x90, 0xa3, 0x6f, 0xc1,
+	0x5a, 0x75, 0x34, 0x31, 0xa1, 0x7e, 0x32, 0xf9, 0xbd, 0xb7, 0x43, 0xda, 0xd0, 0x3a, 0x9f, 0xbc,
+	0x9a, 0x5e, 0x9e, 0x4f, 0xce, 0x7a, 0x35, 0x15, 0x4d, 0xaf, 0xde, 0x9e, 0x4d, 0x55, 0x64, 0x1c,
+	0x1d, 0x81, 0x99, 0x77, 0x19, 0xb1, 0xa0, 0x79, 0x7a, 0x79, 0x72, 0x7e, 0xd1, 0xdb, 0x21, 0x36,
+	0x98, 0xef, 0x4e, 0x7f, 0x7a, 0x3d, 0x9d, 0xfe, 0xd2, 0xab, 0xa9, 0x3a, 0x93, 0x37, 0xbf, 0xf5,
+	0x8c, 0xf1, 0xdf, 0x35, 0x68, 0x9f, 0xaa, 0xa9, 0x7f, 0x49, 0xd3, 0x94, 0xc7, 0x73, 0x72, 0x01,
+	0xad, 0x62, 0x9e, 0x93, 0x83, 0x2d, 0x5e, 0x9b, 0x8f, 0xc2, 0xf0, 0xf9, 0xfd, 0x00, 0x25, 0xf0,
+	0x0e, 0xf1, 0x00, 0xd6, 0x8f, 0x01, 0x79, 0x51, 0x85, 0x6f, 0x3d, 0x33, 0xc3, 0x83, 0x87, 0x20,
+	0x58, 0x73, 0x7c, 0x07, 0xa4, 0x34, 0xbf, 0xdf, 0xb0, 0xec, 0x56, 0x4d, 0x3f, 0x0a, 0xdd, 0xca,
+	0x7b, 0x41, 0xbe, 0xaa, 0xd6, 0xfa, 0xf8, 0x23, 0x34, 0xfc, 0xf2, 0x51, 0x9c, 0x3e, 0xf8, 0x2f,
+	0x03, 0xfa, 0x93, 0x44, 0xf2, 0x19, 0xf7, 0xf1, 0x62, 0xa2, 0xa1, 0xe4, 0x1c, 0xcc, 0xbc, 0xe3,
+	0xc9, 0x7e, 0xb5, 0xd0, 0xe6, 0x15, 0x1c, 0xee, 0xdd, 0xbb, 0xbe, 0x52, 0x6b, 0xdd, 0xa3, 0xdb,
+	0x6a, 0x6d, 0x5d, 0x9c, 0xe1, 0xc1, 0x43, 0x10, 0x5d, 0x73, 0x0a, 0xd6, 0xaa, 0xf9, 0xc8, 0xa8,
+	0x8a, 0xaf, 0xf6, 0xff, 0x70, 0xff, 0x01, 0x04, 0x16, 0xbc, 0xd9, 0x45, 0xc0, 0xf7, 0xff, 0x0d,
+	0x00, 0xc5, 0x09, 0x61, 0x87, 0x82, 0x08, 0x00, 0x00,
 }
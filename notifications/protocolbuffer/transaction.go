@@ -0,0 +1,9 @@
+package protocolbuffer
+
+import "fmt"
+
+// IdempotencyKey identifies a transaction message by its position in the chain, so
+// consumers can safely ignore messages that are delivered more than once.
+func (m *Transaction) IdempotencyKey() string {
+	return fmt.Sprintf("%s:%d", m.GetBlockHash(), m.GetIndex())
+}
@@ -1,7 +1,11 @@
 package pubsub
 
-import nsq "github.com/nsqio/go-nsq"
-import "github.com/sirupsen/logrus"
+import (
+	"time"
+
+	nsq "github.com/nsqio/go-nsq"
+	"github.com/sirupsen/logrus"
+)
 
 type nsqSubscriber struct {
 	handlers map[MessageHandler]struct{}
@@ -9,6 +13,7 @@ type nsqSubscriber struct {
 	address  string
 	channel  string
 	topic    string
+	config   *nsq.Config
 	consumer *nsq.Consumer
 	logger   *logrus.Entry
 }
@@ -19,13 +24,34 @@ func NewNSQSubscriber(topic, channel, address string, logger *logrus.Entry) Subs
 		topic:    topic,
 		address:  address,
 		channel:  channel,
+		config:   nsq.NewConfig(),
+		logger:   logger.WithField("app", "pubsub/nsq_subscriber"),
+	}
+}
+
+// NewNSQRetryingSubscriber returns a Subscriber that requeues the messages its
+// handlers fail, up to `attempts` times. The requeue delay grows linearly with
+// the attempts, starting at `delay`. Failed messages don't slow down the
+// consumer, so a single failing message doesn't hold the others back.
+func NewNSQRetryingSubscriber(topic, channel, address string, attempts uint16, delay time.Duration, logger *logrus.Entry) Subscriber {
+	config := nsq.NewConfig()
+	config.MaxAttempts = attempts
+	config.DefaultRequeueDelay = delay
+	config.MaxBackoffDuration = 0
+
+	return &nsqSubscriber{
+		handlers: map[MessageHandler]struct{}{},
+		topic:    topic,
+		address:  address,
+		channel:  channel,
+		config:   config,
 		logger:   logger.WithField("app", "pubsub/nsq_subscriber"),
 	}
 }
 
 func (s *nsqSubscriber) Start() error {
 	s.logger.Debug("Starting...")
-	consumer, err := nsq.NewConsumer(s.topic, s.channel, nsq.NewConfig())
+	consumer, err := nsq.NewConsumer(s.topic, s.channel, s.config)
 	if err != nil {
 		s.logger.WithError(err).Error("Error starting the nsq consumer")
 		return err
@@ -62,3 +88,8 @@ func (s *nsqSubscriber) HandleMessage(message *nsq.Message) error {
 	}
 	return nil
 }
+
+// LogFailedMessage is called for the messages that exhausted their attempts.
+func (s *nsqSubscriber) LogFailedMessage(message *nsq.Message) {
+	s.logger.WithField("attempts", message.Attempts).Error("Giving up on message")
+}
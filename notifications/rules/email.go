@@ -0,0 +1,25 @@
+package rules
+
+import "github.com/kowala-tech/kcoin/notifications/notifier"
+
+type emailChannel struct {
+	from     string
+	notifier notifier.Notifier
+}
+
+// NewEmailChannel returns a Channel that e-mails the message to the destination
+// of the rule.
+func NewEmailChannel(from string, notifier notifier.Notifier) Channel {
+	return &emailChannel{
+		from:     from,
+		notifier: notifier,
+	}
+}
+
+func (channel *emailChannel) Deliver(notification *Notification, message string) error {
+	return channel.notifier.Send(map[string]string{
+		notifier.EmailFromKey:    channel.from,
+		notifier.EmailToKey:      notification.Rule.GetDestination(),
+		notifier.EmailMessageKey: message,
+	})
+}
@@ -0,0 +1,5 @@
+package rules
+
+import "github.com/sirupsen/logrus"
+
+var logger = logrus.WithField("env", "test")
@@ -0,0 +1,183 @@
+package rules
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/client/crypto"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+)
+
+// transferEventTopic is the topic of the KRC223 token Transfer event.
+var transferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256,bytes)")).String()
+
+// paddedAddressPrefix is shared by all the indexed topics that hold an address.
+const paddedAddressPrefix = "0x000000000000000000000000"
+
+// Notification is the outcome of a rule matching a transaction. It is also the
+// data used to render the rule template.
+type Notification struct {
+	Rule        *protocolbuffer.Rule
+	Transaction *protocolbuffer.Transaction
+	Direction   protocolbuffer.Direction
+	Amount      *big.Int
+	Event       *protocolbuffer.Event
+}
+
+// Wallets returns the addresses involved in a transaction, either as sender,
+// recipient or as an indexed address of one of its events.
+func Wallets(tx *protocolbuffer.Transaction) []string {
+	seen := make(map[string]struct{})
+	var wallets []string
+	add := func(address string) {
+		address = strings.ToLower(address)
+		if _, ok := seen[address]; ok || address == "" {
+			return
+		}
+		seen[address] = struct{}{}
+		wallets = append(wallets, address)
+	}
+
+	add(tx.GetFrom())
+	add(tx.GetTo())
+	for _, event := range tx.GetEvents() {
+		for _, topic := range indexedTopics(event) {
+			if isAddressTopic(topic) {
+				add(topicToAddress(topic))
+			}
+		}
+	}
+	return wallets
+}
+
+// Match checks a rule against a transaction. Rules with an event match any event
+// with that topic that involves the wallet, rules with a token match the token
+// transfers of the wallet and the remaining rules match kUSD transfers.
+func Match(rule *protocolbuffer.Rule, tx *protocolbuffer.Transaction) (*Notification, bool) {
+	switch {
+	case rule.GetEvent() != "":
+		return matchEvent(rule, tx)
+	case rule.GetToken() != "":
+		return matchTokenTransfer(rule, tx)
+	default:
+		return matchTransfer(rule, tx)
+	}
+}
+
+func matchTransfer(rule *protocolbuffer.Rule, tx *protocolbuffer.Transaction) (*Notification, bool) {
+	direction, ok := directionOf(rule.GetWallet(), tx.GetFrom(), tx.GetTo())
+	if !ok {
+		return nil, false
+	}
+	return accept(rule, tx, direction, big.NewInt(tx.GetAmount()), nil)
+}
+
+func matchTokenTransfer(rule *protocolbuffer.Rule, tx *protocolbuffer.Transaction) (*Notification, bool) {
+	for _, event := range tx.GetEvents() {
+		topics := event.GetTopics()
+		if !sameAddress(event.GetAddress(), rule.GetToken()) || len(topics) < 3 || !strings.EqualFold(topics[0], transferEventTopic) {
+			continue
+		}
+		direction, ok := directionOf(rule.GetWallet(), topicToAddress(topics[1]), topicToAddress(topics[2]))
+		if !ok {
+			continue
+		}
+		if notification, ok := accept(rule, tx, direction, transferAmount(event), event); ok {
+			return notification, true
+		}
+	}
+	return nil, false
+}
+
+func matchEvent(rule *protocolbuffer.Rule, tx *protocolbuffer.Transaction) (*Notification, bool) {
+	for _, event := range tx.GetEvents() {
+		topics := event.GetTopics()
+		if len(topics) == 0 || !strings.EqualFold(topics[0], rule.GetEvent()) {
+			continue
+		}
+		if rule.GetContract() != "" && !sameAddress(event.GetAddress(), rule.GetContract()) {
+			continue
+		}
+		if !involves(rule.GetWallet(), tx, event) {
+			continue
+		}
+		return &Notification{
+			Rule:        rule,
+			Transaction: tx,
+			Direction:   protocolbuffer.Direction_ANY,
+			Event:       event,
+		}, true
+	}
+	return nil, false
+}
+
+func accept(rule *protocolbuffer.Rule, tx *protocolbuffer.Transaction, direction protocolbuffer.Direction, amount *big.Int, event *protocolbuffer.Event) (*Notification, bool) {
+	if rule.GetDirection() != protocolbuffer.Direction_ANY && rule.GetDirection() != direction {
+		return nil, false
+	}
+	if amount.Cmp(big.NewInt(rule.GetMinAmount())) < 0 {
+		return nil, false
+	}
+	return &Notification{
+		Rule:        rule,
+		Transaction: tx,
+		Direction:   direction,
+		Amount:      amount,
+		Event:       event,
+	}, true
+}
+
+func directionOf(wallet, from, to string) (protocolbuffer.Direction, bool) {
+	switch {
+	case sameAddress(wallet, to):
+		return protocolbuffer.Direction_INCOMING, true
+	case sameAddress(wallet, from):
+		return protocolbuffer.Direction_OUTGOING, true
+	default:
+		return protocolbuffer.Direction_ANY, false
+	}
+}
+
+func involves(wallet string, tx *protocolbuffer.Transaction, event *protocolbuffer.Event) bool {
+	if sameAddress(wallet, tx.GetFrom()) || sameAddress(wallet, tx.GetTo()) {
+		return true
+	}
+	for _, topic := range indexedTopics(event) {
+		if isAddressTopic(topic) && sameAddress(wallet, topicToAddress(topic)) {
+			return true
+		}
+	}
+	return false
+}
+
+// transferAmount decodes the value of a KRC223 Transfer event, which is the
+// first word of the event data.
+func transferAmount(event *protocolbuffer.Event) *big.Int {
+	data := event.GetData()
+	if len(data) < common.HashLength {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(data[:common.HashLength])
+}
+
+// indexedTopics returns the event topics without the event signature.
+func indexedTopics(event *protocolbuffer.Event) []string {
+	topics := event.GetTopics()
+	if len(topics) == 0 {
+		return nil
+	}
+	return topics[1:]
+}
+
+func isAddressTopic(topic string) bool {
+	return len(topic) == 2+2*common.HashLength && strings.HasPrefix(topic, paddedAddressPrefix)
+}
+
+func topicToAddress(topic string) string {
+	return common.HexToAddress(topic).String()
+}
+
+func sameAddress(a, b string) bool {
+	return a != "" && strings.EqualFold(a, b)
+}
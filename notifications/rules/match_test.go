@@ -0,0 +1,127 @@
+package rules
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/kowala-tech/kcoin/client/common"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wallet = "0x99429f64cf4d5837620dcc293c1a537d58729b68"
+	other  = "0x007ccffb7916f37f7aeef05e8096ecfbe55afc2f"
+	token  = "0x4c55b59340fa8f8f3a2a2d8a1b6f8d5d9c9b1c3e"
+)
+
+func addressTopic(address string) string {
+	return common.BytesToHash(common.HexToAddress(address).Bytes()).String()
+}
+
+func tokenTransfer(from, to string, amount int64) *protocolbuffer.Event {
+	return &protocolbuffer.Event{
+		Address: token,
+		Topics:  []string{transferEventTopic, addressTopic(from), addressTopic(to)},
+		Data:    common.BigToHash(big.NewInt(amount)).Bytes(),
+	}
+}
+
+func TestMatch_Transfers(t *testing.T) {
+	tx := &protocolbuffer.Transaction{From: other, To: common.HexToAddress(wallet).String(), Amount: 42}
+
+	tests := []struct {
+		name  string
+		rule  *protocolbuffer.Rule
+		match bool
+	}{
+		{"any direction", &protocolbuffer.Rule{Wallet: wallet}, true},
+		{"incoming", &protocolbuffer.Rule{Wallet: wallet, Direction: protocolbuffer.Direction_INCOMING}, true},
+		{"outgoing", &protocolbuffer.Rule{Wallet: wallet, Direction: protocolbuffer.Direction_OUTGOING}, false},
+		{"amount above minimum", &protocolbuffer.Rule{Wallet: wallet, MinAmount: 42}, true},
+		{"amount below minimum", &protocolbuffer.Rule{Wallet: wallet, MinAmount: 43}, false},
+		{"other wallet", &protocolbuffer.Rule{Wallet: token}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notification, ok := Match(test.rule, tx)
+			require.Equal(t, test.match, ok)
+			if ok {
+				require.Equal(t, protocolbuffer.Direction_INCOMING, notification.Direction)
+				require.Equal(t, big.NewInt(42), notification.Amount)
+			}
+		})
+	}
+}
+
+func TestMatch_TokenTransfers(t *testing.T) {
+	tx := &protocolbuffer.Transaction{
+		From:   other,
+		To:     token,
+		Events: []*protocolbuffer.Event{tokenTransfer(wallet, other, 100)},
+	}
+
+	notification, ok := Match(&protocolbuffer.Rule{Wallet: wallet, Token: token}, tx)
+	require.True(t, ok)
+	require.Equal(t, protocolbuffer.Direction_OUTGOING, notification.Direction)
+	require.Equal(t, big.NewInt(100), notification.Amount)
+
+	_, ok = Match(&protocolbuffer.Rule{Wallet: wallet, Token: token, Direction: protocolbuffer.Direction_INCOMING}, tx)
+	require.False(t, ok)
+
+	_, ok = Match(&protocolbuffer.Rule{Wallet: wallet, Token: token, MinAmount: 101}, tx)
+	require.False(t, ok)
+
+	_, ok = Match(&protocolbuffer.Rule{Wallet: wallet, Token: other}, tx)
+	require.False(t, ok)
+}
+
+func TestMatch_Events(t *testing.T) {
+	event := common.HexToHash("0x1234").String()
+	tx := &protocolbuffer.Transaction{
+		From: other,
+		To:   token,
+		Events: []*protocolbuffer.Event{
+			{Address: token, Topics: []string{event, addressTopic(wallet)}},
+		},
+	}
+
+	notification, ok := Match(&protocolbuffer.Rule{Wallet: wallet, Event: event}, tx)
+	require.True(t, ok)
+	require.Equal(t, tx.Events[0], notification.Event)
+
+	_, ok = Match(&protocolbuffer.Rule{Wallet: wallet, Event: event, Contract: token}, tx)
+	require.True(t, ok)
+
+	_, ok = Match(&protocolbuffer.Rule{Wallet: wallet, Event: event, Contract: other}, tx)
+	require.False(t, ok)
+
+	_, ok = Match(&protocolbuffer.Rule{Wallet: wallet, Event: transferEventTopic}, tx)
+	require.False(t, ok)
+}
+
+func TestWallets(t *testing.T) {
+	tx := &protocolbuffer.Transaction{
+		From:   other,
+		To:     token,
+		Events: []*protocolbuffer.Event{tokenTransfer(other, wallet, 1)},
+	}
+
+	require.Equal(t, []string{other, token, wallet}, Wallets(tx))
+}
+
+func TestRender(t *testing.T) {
+	tx := &protocolbuffer.Transaction{From: other, To: wallet, Amount: 42, Hash: "0xabcd"}
+
+	notification, ok := Match(&protocolbuffer.Rule{Wallet: wallet}, tx)
+	require.True(t, ok)
+	message, err := Render(notification)
+	require.NoError(t, err)
+	require.Equal(t, "INCOMING transfer of 42 in transaction 0xabcd", message)
+
+	notification.Rule = &protocolbuffer.Rule{Wallet: wallet, Template: "Received {{.Amount}} from {{.Transaction.From}}"}
+	message, err = Render(notification)
+	require.NoError(t, err)
+	require.Equal(t, "Received 42 from "+other, message)
+}
@@ -0,0 +1,55 @@
+package rules
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+)
+
+type memoryRepository struct {
+	mtx   sync.RWMutex
+	rules map[string]map[string]*protocolbuffer.Rule
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		rules: make(map[string]map[string]*protocolbuffer.Rule),
+	}
+}
+
+func (repo *memoryRepository) Add(rule *protocolbuffer.Rule) error {
+	repo.mtx.Lock()
+	defer repo.mtx.Unlock()
+
+	wallet := strings.ToLower(rule.GetWallet())
+	if repo.rules[wallet] == nil {
+		repo.rules[wallet] = make(map[string]*protocolbuffer.Rule)
+	}
+	repo.rules[wallet][rule.GetId()] = proto.Clone(rule).(*protocolbuffer.Rule)
+	return nil
+}
+
+func (repo *memoryRepository) Remove(wallet, id string) error {
+	repo.mtx.Lock()
+	defer repo.mtx.Unlock()
+
+	wallet = strings.ToLower(wallet)
+	if _, ok := repo.rules[wallet][id]; !ok {
+		return ErrRuleNotFound
+	}
+	delete(repo.rules[wallet], id)
+	return nil
+}
+
+func (repo *memoryRepository) List(wallet string) ([]*protocolbuffer.Rule, error) {
+	repo.mtx.RLock()
+	defer repo.mtx.RUnlock()
+
+	var rules []*protocolbuffer.Rule
+	for _, rule := range repo.rules[strings.ToLower(wallet)] {
+		rules = append(rules, proto.Clone(rule).(*protocolbuffer.Rule))
+	}
+	return rules, nil
+}
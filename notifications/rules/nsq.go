@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"regexp"
+
+	"github.com/kowala-tech/kcoin/notifications/pubsub"
+)
+
+// NSQTopicPrefix is prepended to the destination of the rules, so that rules
+// can only publish to topics reserved for them.
+const NSQTopicPrefix = "rules."
+
+// nsqTopic matches the destinations that make a valid NSQ topic once
+// prefixed, which are at most 64 characters long.
+var nsqTopic = regexp.MustCompile(`^[.a-zA-Z0-9_-]{1,58}$`)
+
+type nsqChannel struct {
+	publisher pubsub.Publisher
+}
+
+// NewNSQChannel returns a Channel that publishes the notification to the NSQ
+// topic named by the destination of the rule, prefixed with NSQTopicPrefix.
+func NewNSQChannel(publisher pubsub.Publisher) Channel {
+	return &nsqChannel{
+		publisher: publisher,
+	}
+}
+
+// ValidNSQTopic returns whether the destination of a rule names a valid topic.
+func ValidNSQTopic(destination string) bool {
+	return nsqTopic.MatchString(destination)
+}
+
+func (channel *nsqChannel) Deliver(notification *Notification, message string) error {
+	if !ValidNSQTopic(notification.Rule.GetDestination()) {
+		return Permanent(errInvalidNSQTopic)
+	}
+	data, err := encodePayload(notification, message)
+	if err != nil {
+		return Permanent(err)
+	}
+	return channel.publisher.Publish(NSQTopicPrefix+notification.Rule.GetDestination(), data)
+}
@@ -0,0 +1,24 @@
+package rules
+
+import (
+	"encoding/json"
+
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+)
+
+// Payload is the JSON document sent to webhooks and NSQ topics.
+type Payload struct {
+	Rule        string                      `json:"rule"`
+	Wallet      string                      `json:"wallet"`
+	Message     string                      `json:"message"`
+	Transaction *protocolbuffer.Transaction `json:"transaction"`
+}
+
+func encodePayload(notification *Notification, message string) ([]byte, error) {
+	return json.Marshal(&Payload{
+		Rule:        notification.Rule.GetId(),
+		Wallet:      notification.Rule.GetWallet(),
+		Message:     message,
+		Transaction: notification.Transaction,
+	})
+}
@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/gogo/protobuf/proto"
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+)
+
+const RuleKeyPrefix = "rules:"
+
+type redisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository returns a redis based Repository that keeps the rules of
+// each wallet in a hash indexed by rule id.
+func NewRedisRepository(client *redis.Client) Repository {
+	return &redisRepository{
+		client: client,
+	}
+}
+
+func (repo *redisRepository) Add(rule *protocolbuffer.Rule) error {
+	enc, err := proto.Marshal(rule)
+	if err != nil {
+		return err
+	}
+	return repo.client.HSet(getKeyFromWallet(rule.GetWallet()), rule.GetId(), enc).Err()
+}
+
+func (repo *redisRepository) Remove(wallet, id string) error {
+	removed, err := repo.client.HDel(getKeyFromWallet(wallet), id).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func (repo *redisRepository) List(wallet string) ([]*protocolbuffer.Rule, error) {
+	values, err := repo.client.HVals(getKeyFromWallet(wallet)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*protocolbuffer.Rule, 0, len(values))
+	for _, value := range values {
+		rule := new(protocolbuffer.Rule)
+		if err := proto.Unmarshal([]byte(value), rule); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func getKeyFromWallet(wallet string) string {
+	return fmt.Sprintf("%s%s", RuleKeyPrefix, strings.ToLower(wallet))
+}
@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"errors"
+
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+)
+
+var (
+	ErrRuleNotFound = errors.New("rule not found")
+
+	errInvalidNSQTopic = errors.New("invalid nsq topic")
+)
+
+// Repository stores the notification rules registered for each wallet.
+type Repository interface {
+	Add(rule *protocolbuffer.Rule) error
+	Remove(wallet, id string) error
+	List(wallet string) ([]*protocolbuffer.Rule, error)
+}
+
+// Channel delivers a rendered notification to the destination of its rule.
+// Failed deliveries are retried by redelivering the transaction, unless the
+// error is permanent.
+type Channel interface {
+	Deliver(notification *Notification, message string) error
+}
+
+// permanentError marks a delivery error that retrying won't fix.
+type permanentError struct {
+	error
+}
+
+// Permanent marks a delivery error as not worth retrying.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// IsPermanent returns whether a delivery error is not worth retrying.
+func IsPermanent(err error) bool {
+	_, ok := err.(permanentError)
+	return ok
+}
@@ -0,0 +1,33 @@
+package rules
+
+import (
+	"bytes"
+	"text/template"
+)
+
+const defaultTemplate = `{{if .Event}}Event {{index .Event.Topics 0}} emitted by {{.Event.Address}}` +
+	`{{else}}{{.Direction}} transfer of {{.Amount}}{{if .Rule.Token}} {{.Rule.Token}}{{end}}{{end}}` +
+	` in transaction {{.Transaction.Hash}}`
+
+// ParseTemplate parses the template of a rule, falling back to a generic message
+// when the rule does not define one.
+func ParseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultTemplate
+	}
+	return template.New("rule").Option("missingkey=error").Parse(text)
+}
+
+// Render returns the message of a notification using the template of its rule.
+func Render(notification *Notification) (string, error) {
+	tpl, err := ParseTemplate(notification.Rule.GetTemplate())
+	if err != nil {
+		return "", err
+	}
+
+	var message bytes.Buffer
+	if err := tpl.Execute(&message, notification); err != nil {
+		return "", err
+	}
+	return message.String(), nil
+}
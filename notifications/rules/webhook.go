@@ -0,0 +1,155 @@
+package rules
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureHeader holds the hex encoded HMAC-SHA256 of the request body, keyed
+// with the secret of the rule.
+const SignatureHeader = "X-Kowala-Signature"
+
+var errNonPublicAddress = errors.New("webhook destination is not a public address")
+
+// nonPublicNetworks are the loopback, private, link-local and otherwise
+// reserved networks webhooks are never delivered to.
+var nonPublicNetworks = parseNetworks(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+type webhookChannel struct {
+	client *http.Client
+	logger *logrus.Entry
+}
+
+// NewWebhookChannel returns a Channel that posts a signed JSON payload to the
+// destination of the rule. Each delivery makes a single request; server errors
+// are left to be retried by redelivering the transaction, while client errors
+// are permanent.
+func NewWebhookChannel(logger *logrus.Entry, client *http.Client) Channel {
+	return &webhookChannel{
+		client: client,
+		logger: logger.WithField("app", "rules/webhook"),
+	}
+}
+
+// NewWebhookClient returns an HTTP client that refuses to connect to
+// non-public addresses, whatever the destination host resolves or redirects
+// to.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return errNonPublicAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+}
+
+// IsPublicIP returns whether webhooks may be delivered to the address.
+func IsPublicIP(ip net.IP) bool {
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (channel *webhookChannel) Deliver(notification *Notification, message string) error {
+	body, err := encodePayload(notification, message)
+	if err != nil {
+		return Permanent(err)
+	}
+	signature := Sign(notification.Rule.GetSecret(), body)
+
+	err = channel.post(notification.Rule.GetDestination(), body, signature)
+	if err != nil {
+		channel.logger.WithError(err).WithField("permanent", IsPermanent(err)).Debug("Error delivering webhook")
+	}
+	return err
+}
+
+// post sends the payload. The errors not worth retrying are permanent.
+func (channel *webhookChannel) post(url string, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+
+	res, err := channel.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return nil
+	case res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	default:
+		return Permanent(fmt.Errorf("webhook responded with status %d", res.StatusCode))
+	}
+}
+
+// Sign returns the signature of a webhook body, so receivers can check that it
+// was sent by us.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
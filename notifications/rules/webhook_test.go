@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kowala-tech/kcoin/notifications/protocolbuffer"
+	"github.com/stretchr/testify/require"
+)
+
+type sink struct {
+	mtx      sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+// newSink returns a local HTTP server that answers with the given statuses in
+// order, repeating the last one.
+func newSink(statuses ...int) (*sink, *httptest.Server) {
+	s := &sink{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		s.mtx.Lock()
+		defer s.mtx.Unlock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+		status := s.statuses[0]
+		if len(s.statuses) > 1 {
+			s.statuses = s.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	return s, server
+}
+
+func (s *sink) received() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.requests)
+}
+
+func webhookNotification(url string) *Notification {
+	return &Notification{
+		Rule: &protocolbuffer.Rule{
+			Id:          "rule",
+			Wallet:      wallet,
+			Channel:     protocolbuffer.Channel_WEBHOOK,
+			Destination: url,
+			Secret:      "secret",
+		},
+		Transaction: &protocolbuffer.Transaction{To: wallet, Amount: 42, Hash: "0xabcd"},
+	}
+}
+
+func TestWebhook_PostsSignedPayload(t *testing.T) {
+	s, server := newSink(http.StatusOK)
+	defer server.Close()
+
+	channel := NewWebhookChannel(logger, server.Client())
+	require.NoError(t, channel.Deliver(webhookNotification(server.URL), "hello"))
+
+	require.Equal(t, 1, s.received())
+	require.Equal(t, Sign("secret", s.bodies[0]), s.requests[0].Header.Get(SignatureHeader))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(s.bodies[0], &payload))
+	require.Equal(t, "rule", payload.Rule)
+	require.Equal(t, "hello", payload.Message)
+	require.Equal(t, "0xabcd", payload.Transaction.Hash)
+}
+
+func TestWebhook_ServerErrorsAreRetried(t *testing.T) {
+	s, server := newSink(http.StatusInternalServerError, http.StatusOK)
+	defer server.Close()
+
+	channel := NewWebhookChannel(logger, server.Client())
+	err := channel.Deliver(webhookNotification(server.URL), "hello")
+	require.Error(t, err)
+	require.False(t, IsPermanent(err))
+	require.Equal(t, 1, s.received())
+
+	require.NoError(t, channel.Deliver(webhookNotification(server.URL), "hello"))
+	require.Equal(t, 2, s.received())
+}
+
+func TestWebhook_ClientErrorsArePermanent(t *testing.T) {
+	s, server := newSink(http.StatusBadRequest)
+	defer server.Close()
+
+	channel := NewWebhookChannel(logger, server.Client())
+	err := channel.Deliver(webhookNotification(server.URL), "hello")
+	require.True(t, IsPermanent(err))
+	require.Equal(t, 1, s.received())
+}
+
+func TestWebhook_ClientRefusesNonPublicAddresses(t *testing.T) {
+	s, server := newSink(http.StatusOK)
+	defer server.Close()
+
+	channel := NewWebhookChannel(logger, NewWebhookClient(time.Second))
+	require.Error(t, channel.Deliver(webhookNotification(server.URL), "hello"))
+	require.Equal(t, 0, s.received())
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "::1", "fd00::1", "::ffff:127.0.0.1"} {
+		require.False(t, IsPublicIP(net.ParseIP(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "2001:4860:4860::8888"} {
+		require.True(t, IsPublicIP(net.ParseIP(addr)), addr)
+	}
+}